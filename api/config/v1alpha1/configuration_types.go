@@ -0,0 +1,234 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+)
+
+// +kubebuilder:object:root=true
+
+// Configuration is the Schema for the lws manager configuration, it is loaded from the
+// file given by the --config flag. The fields documented as reloadable are applied when the
+// file changes, the others on the next start of the manager.
+type Configuration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ControllerManager returns the configurations for controllers
+	ControllerManager `json:",inline"`
+
+	// Namespaces are the namespaces the controllers reconcile the LeaderWorkerSets and their pods in,
+	// all the namespaces if empty.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// ExcludeNamespaces are the namespaces the controllers ignore, e.g. the system namespaces or the
+	// namespaces of the tenants served by other lws controllers. Mutually exclusive with namespaces.
+	// +optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// ShardSelector selects the LeaderWorkerSets owned by this controller manager, so that a large
+	// fleet can be split between several controller managers, each one with its own leader election
+	// resourceName and a disjoint shardSelector. All the LeaderWorkerSets if unset.
+	// +optional
+	ShardSelector *metav1.LabelSelector `json:"shardSelector,omitempty"`
+
+	// ClientConnection provides additional configuration options for Kubernetes
+	// API server client.
+	// +optional
+	ClientConnection *ClientConnection `json:"clientConnection,omitempty"`
+
+	// FeatureGates is a map of feature names to bools that enable or disable
+	// alpha or beta features.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
+	// Tracing configures the export of the traces of the reconcilers and the webhooks.
+	// +optional
+	Tracing *Tracing `json:"tracing,omitempty"`
+
+	// InternalCertManagement configures the provisioning of the certs of the webhooks.
+	// +optional
+	InternalCertManagement *InternalCertManagement `json:"internalCertManagement,omitempty"`
+}
+
+// InternalCertManagement configures the certs of the webhooks.
+type InternalCertManagement struct {
+	// Enable provisions the certs of the webhooks with a self-signed CA, rotates them in the
+	// lws-webhook-server-cert Secret and patches the CA bundle of the webhook configurations and
+	// the CRD conversion. Set it to false to provision them with cert-manager instead, see
+	// config/certmanager. Defaults to true.
+	// +optional
+	Enable *bool `json:"enable,omitempty"`
+}
+
+type ControllerManager struct {
+	// Webhook contains the controllers webhook configuration
+	// +optional
+	Webhook ControllerWebhook `json:"webhook,omitempty"`
+
+	// LeaderElection is the LeaderElection config to be used when configuring
+	// the manager.Manager leader election
+	// +optional
+	LeaderElection *configv1alpha1.LeaderElectionConfiguration `json:"leaderElection,omitempty"`
+
+	// Metrics contains the controller metrics configuration
+	// +optional
+	Metrics ControllerMetrics `json:"metrics,omitempty"`
+
+	// Health contains the controller health configuration
+	// +optional
+	Health ControllerHealth `json:"health,omitempty"`
+
+	// Controller contains global configuration options for controllers
+	// registered within this manager.
+	// +optional
+	Controller *ControllerConfigurationSpec `json:"controller,omitempty"`
+}
+
+// ControllerWebhook defines the webhook server for the controller.
+type ControllerWebhook struct {
+	// Port is the port that the webhook server serves at.
+	// It is used to set webhook.Server.Port.
+	// +optional
+	Port *int `json:"port,omitempty"`
+
+	// Host is the hostname that the webhook server binds to.
+	// It is used to set webhook.Server.Host.
+	// +optional
+	Host string `json:"host,omitempty"`
+
+	// CertDir is the directory that contains the server key and certificate.
+	// if not set, webhook server would look up the server key and certificate in
+	// {TempDir}/k8s-webhook-server/serving-certs. The server key and certificate
+	// must be named tls.key and tls.crt, respectively.
+	// +optional
+	CertDir string `json:"certDir,omitempty"`
+
+	// Pod configures the pod webhooks.
+	// +optional
+	Pod PodWebhook `json:"pod,omitempty"`
+}
+
+// PodWebhook configures the pod webhooks.
+type PodWebhook struct {
+	// Disable labels the pods and stamps the StatefulSet pod templates in the controllers instead
+	// of the pod webhook, the pod webhooks must be removed from the webhook configurations then.
+	// It requires the StatefulSet pod index label, and the named worker templates, the subgroups,
+	// the gang scheduling and the preemption fallback node selector are not supported.
+	// +optional
+	Disable bool `json:"disable,omitempty"`
+
+	// FailurePolicy is the failure policy of the pod webhooks, Fail or Ignore, defaults to Fail.
+	// With Ignore, the pods are created without the env vars of LWS when the webhook is unavailable,
+	// the controller labels them instead. It is reloadable.
+	// +optional
+	FailurePolicy *admissionregistrationv1.FailurePolicyType `json:"failurePolicy,omitempty"`
+
+	// NamespaceSelector selects the namespaces the pod webhooks apply to, all the namespaces if unset.
+	// The pods of the other namespaces are labeled by the controller, without the env vars of LWS.
+	// It is reloadable.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
+// ControllerMetrics defines the metrics configs.
+type ControllerMetrics struct {
+	// BindAddress is the TCP address that the controller should bind to
+	// for serving prometheus metrics.
+	// It can be set to "0" to disable the metrics serving.
+	// +optional
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// ControllerHealth defines the health configs.
+type ControllerHealth struct {
+	// HealthProbeBindAddress is the TCP address that the controller should bind to
+	// for serving health probes
+	// It can be set to "0" or "" to disable serving the health probe.
+	// +optional
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+}
+
+// ControllerConfigurationSpec defines the global configuration for
+// controllers registered with the manager.
+type ControllerConfigurationSpec struct {
+	// GroupKindConcurrency is a map from the Kind to the number of concurrent reconciliations
+	// allowed for that controller, e.g. LeaderWorkerSet.leaderworkerset.x-k8s.io or Pod, 1 if unset
+	// except for Pod which defaults to 10.
+	// Large fleets with hundreds of LeaderWorkerSets and thousands of groups need more.
+	// +optional
+	GroupKindConcurrency map[string]int `json:"groupKindConcurrency,omitempty"`
+
+	// RateLimiter configures the workqueues of the controllers.
+	// +optional
+	RateLimiter *RateLimiter `json:"rateLimiter,omitempty"`
+
+	// MaxConcurrentGroupRestarts is the maximum number of groups across all the LeaderWorkerSets
+	// which can be recreated at the same time, unlimited if 0. It is reloadable.
+	// +optional
+	MaxConcurrentGroupRestarts *int32 `json:"maxConcurrentGroupRestarts,omitempty"`
+}
+
+// RateLimiter configures the workqueue rate limiter of each controller, the requeues of a request
+// are delayed by the max of its exponential backoff and of the overall token bucket.
+type RateLimiter struct {
+	// BaseDelay is the delay of the first requeue of a failed request, doubled on every failure,
+	// defaults to 5ms.
+	// +optional
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay is the maximum delay of the requeues of a failed request, defaults to 1000s.
+	// +optional
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+
+	// QPS is the overall rate of the requeues, defaults to 10.
+	// +optional
+	QPS *float32 `json:"qps,omitempty"`
+
+	// Burst is the burst of the requeues above the QPS, defaults to 100.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+type ClientConnection struct {
+	// QPS controls the number of queries per second allowed for K8S api server
+	// connection.
+	// +optional
+	QPS *float32 `json:"qps,omitempty"`
+
+	// Burst allows extra queries to accumulate when a client is exceeding its rate.
+	// +optional
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// Tracing configures the OpenTelemetry traces.
+type Tracing struct {
+	// Endpoint is the host:port of the OTLP gRPC collector, tracing is disabled if empty.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Insecure connects to the collector without TLS.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+
+	// SampleRatio is the ratio of the reconciles and admissions traced, defaults to 0.1.
+	// +optional
+	SampleRatio *float64 `json:"sampleRatio,omitempty"`
+}
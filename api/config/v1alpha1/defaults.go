@@ -0,0 +1,144 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DefaultWebhookPort                         = 9443
+	DefaultWebhookCertDir                      = "/tmp/k8s-webhook-server/serving-certs"
+	DefaultHealthProbeBindAddress              = ":8081"
+	DefaultMetricsBindAddress                  = ":8080"
+	DefaultLeaderElectionID                    = "b8b2488c.x-k8s.io"
+	DefaultLeaderElectionLeaseDuration         = 15 * time.Second
+	DefaultLeaderElectionRenewDeadline         = 10 * time.Second
+	DefaultLeaderElectionRetryPeriod           = 2 * time.Second
+	DefaultResourceLock                        = "leases"
+	DefaultClientConnectionQPS         float32 = 500
+	DefaultClientConnectionBurst       int32   = 500
+	DefaultTraceSampleRatio                    = 0.1
+	DefaultRateLimiterBaseDelay                = 5 * time.Millisecond
+	DefaultRateLimiterMaxDelay                 = 1000 * time.Second
+	DefaultRateLimiterQPS              float32 = 10
+	DefaultRateLimiterBurst            int32   = 100
+	// DefaultPodConcurrency bounds the leader pods reconciled in parallel, each one creates the worker
+	// statefulset and the services of its group, so the groups of a large LeaderWorkerSet are created
+	// by a pool of workers instead of one by one.
+	DefaultPodConcurrency = 10
+)
+
+// SetDefaults_Configuration sets default values for ComponentConfig.
+func SetDefaults_Configuration(cfg *Configuration) {
+	if cfg.Webhook.Port == nil {
+		cfg.Webhook.Port = ptr.To(DefaultWebhookPort)
+	}
+	if len(cfg.Webhook.CertDir) == 0 {
+		cfg.Webhook.CertDir = DefaultWebhookCertDir
+	}
+	if cfg.Webhook.Pod.FailurePolicy == nil {
+		cfg.Webhook.Pod.FailurePolicy = ptr.To(admissionregistrationv1.Fail)
+	}
+	if len(cfg.Metrics.BindAddress) == 0 {
+		cfg.Metrics.BindAddress = DefaultMetricsBindAddress
+	}
+	if len(cfg.Health.HealthProbeBindAddress) == 0 {
+		cfg.Health.HealthProbeBindAddress = DefaultHealthProbeBindAddress
+	}
+
+	if cfg.LeaderElection == nil {
+		cfg.LeaderElection = &configv1alpha1.LeaderElectionConfiguration{}
+	}
+	// The leader election is disabled by default to run the manager out of the cluster,
+	// the shipped configuration enables it.
+	if cfg.LeaderElection.LeaderElect == nil {
+		cfg.LeaderElection.LeaderElect = ptr.To(false)
+	}
+	if len(cfg.LeaderElection.ResourceName) == 0 {
+		cfg.LeaderElection.ResourceName = DefaultLeaderElectionID
+	}
+	if len(cfg.LeaderElection.ResourceLock) == 0 {
+		cfg.LeaderElection.ResourceLock = DefaultResourceLock
+	}
+	if cfg.LeaderElection.LeaseDuration.Duration == 0 {
+		cfg.LeaderElection.LeaseDuration = metav1.Duration{Duration: DefaultLeaderElectionLeaseDuration}
+	}
+	if cfg.LeaderElection.RenewDeadline.Duration == 0 {
+		cfg.LeaderElection.RenewDeadline = metav1.Duration{Duration: DefaultLeaderElectionRenewDeadline}
+	}
+	if cfg.LeaderElection.RetryPeriod.Duration == 0 {
+		cfg.LeaderElection.RetryPeriod = metav1.Duration{Duration: DefaultLeaderElectionRetryPeriod}
+	}
+
+	if cfg.Controller == nil {
+		cfg.Controller = &ControllerConfigurationSpec{}
+	}
+	if _, ok := cfg.Controller.GroupKindConcurrency["Pod"]; !ok {
+		if cfg.Controller.GroupKindConcurrency == nil {
+			cfg.Controller.GroupKindConcurrency = map[string]int{}
+		}
+		cfg.Controller.GroupKindConcurrency["Pod"] = DefaultPodConcurrency
+	}
+	if cfg.Controller.MaxConcurrentGroupRestarts == nil {
+		cfg.Controller.MaxConcurrentGroupRestarts = ptr.To[int32](0)
+	}
+	if cfg.Controller.RateLimiter == nil {
+		cfg.Controller.RateLimiter = &RateLimiter{}
+	}
+	if cfg.Controller.RateLimiter.BaseDelay == nil {
+		cfg.Controller.RateLimiter.BaseDelay = &metav1.Duration{Duration: DefaultRateLimiterBaseDelay}
+	}
+	if cfg.Controller.RateLimiter.MaxDelay == nil {
+		cfg.Controller.RateLimiter.MaxDelay = &metav1.Duration{Duration: DefaultRateLimiterMaxDelay}
+	}
+	if cfg.Controller.RateLimiter.QPS == nil {
+		cfg.Controller.RateLimiter.QPS = ptr.To(DefaultRateLimiterQPS)
+	}
+	if cfg.Controller.RateLimiter.Burst == nil {
+		cfg.Controller.RateLimiter.Burst = ptr.To(DefaultRateLimiterBurst)
+	}
+
+	if cfg.ClientConnection == nil {
+		cfg.ClientConnection = &ClientConnection{}
+	}
+	if cfg.ClientConnection.QPS == nil {
+		cfg.ClientConnection.QPS = ptr.To(DefaultClientConnectionQPS)
+	}
+	if cfg.ClientConnection.Burst == nil {
+		cfg.ClientConnection.Burst = ptr.To(DefaultClientConnectionBurst)
+	}
+
+	if cfg.Tracing == nil {
+		cfg.Tracing = &Tracing{}
+	}
+	if cfg.Tracing.SampleRatio == nil {
+		cfg.Tracing.SampleRatio = ptr.To(DefaultTraceSampleRatio)
+	}
+
+	if cfg.InternalCertManagement == nil {
+		cfg.InternalCertManagement = &InternalCertManagement{}
+	}
+	if cfg.InternalCertManagement.Enable == nil {
+		cfg.InternalCertManagement.Enable = ptr.To(true)
+	}
+}
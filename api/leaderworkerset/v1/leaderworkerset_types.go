@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -31,6 +33,15 @@ const (
 	// which will be used for 1:1 exclusive scheduling in a given subgroup.
 	SubGroupExclusiveKeyAnnotationKey string = "leaderworkerset.sigs.k8s.io/subgroup-exclusive-topology"
 
+	// Exclusive placement policy annotation is used to specify how the exclusive-topology
+	// is enforced, one of Affinity or NodeSelector, defaults to Affinity.
+	ExclusivePlacementPolicyAnnotationKey string = "leaderworkerset.sigs.k8s.io/exclusive-placement-policy"
+
+	// Exclusive domain label will be added to the leader pods once scheduled with the
+	// NodeSelector exclusive placement policy, it records the hash of the topology domain
+	// claimed by the group.
+	ExclusiveDomainLabelKey string = "leaderworkerset.sigs.k8s.io/exclusive-domain"
+
 	// Set name label will record the leaderworkerset name that those resources
 	// (Pod/Service/StatefulSets) belong to.
 	SetNameLabelKey string = "leaderworkerset.sigs.k8s.io/name"
@@ -51,6 +62,10 @@ const (
 	// LeaderWorkerSet.Spec.Replicas
 	ReplicasAnnotationKey string = "leaderworkerset.sigs.k8s.io/replicas"
 
+	// Blue groups annotation is added to the leader statefulset during a BlueGreen rollout,
+	// it records the first and the last indexes of the groups being replaced, e.g. "0-3".
+	BlueGroupsAnnotationKey string = "leaderworkerset.sigs.k8s.io/blue-groups"
+
 	// Pods that are in the same group will have an annotation that is a unique
 	// hash value.
 	GroupUniqueHashLabelKey string = "leaderworkerset.sigs.k8s.io/group-key"
@@ -67,6 +82,67 @@ const (
 	// address the leader via the headless service.
 	LwsLeaderAddress string = "LWS_LEADER_ADDRESS"
 
+	// Environment variable added to all containers in the LeaderWorkerSet with
+	// the number of pods in the group, including the leader.
+	LwsGroupSize string = "LWS_GROUP_SIZE"
+
+	// Environment variable added to all containers in the LeaderWorkerSet with
+	// the index of the pod within its group. The leader has index 0.
+	LwsWorkerIndex string = "LWS_WORKER_INDEX"
+
+	// Environment variable added to the containers of the pods created without the pod
+	// webhook with the pod name, the env vars referring to the pod name are expanded from it.
+	LwsPodName string = "LWS_POD_NAME"
+
+	// Environment variable added to all containers of the workers created from the
+	// LeaderWorkerSet.Spec.LeaderWorkerTemplate.WorkerTemplates with the template name.
+	LwsWorkerTemplate string = "LWS_WORKER_TEMPLATE"
+
+	// Environment variable added to all containers of the workers created from the
+	// LeaderWorkerSet.Spec.LeaderWorkerTemplate.WorkerTemplates with the index of the
+	// pod among the workers of the same template, starting from 0.
+	LwsWorkerTemplateIndex string = "LWS_WORKER_TEMPLATE_INDEX"
+
+	// Environment variable added to all containers when the model prefetch is enabled,
+	// with the path the model artifacts are downloaded to.
+	LwsModelPath string = "LWS_MODEL_PATH"
+
+	// Environment variable added to the model prefetch init container with the URI
+	// of the model artifacts.
+	LwsModelURI string = "LWS_MODEL_URI"
+
+	// Environment variable added to the model prefetch init container with the index
+	// of the group the pod belongs to.
+	LwsGroupIndex string = "LWS_GROUP_INDEX"
+
+	// RolloutHookLabelKey is added to the Jobs of the rollout hooks with the hook name,
+	// i.e. preGroupUpdate or postGroupUpdate.
+	RolloutHookLabelKey string = "leaderworkerset.sigs.k8s.io/rollout-hook"
+
+	// ModelPrefetchContainerName is the name of the init container downloading the model artifacts.
+	ModelPrefetchContainerName string = "lws-model-prefetch"
+
+	// ModelPrefetchVolumeName is the name of the volume the model artifacts are downloaded to,
+	// it is mounted to all the containers.
+	ModelPrefetchVolumeName string = "lws-model"
+
+	// DefaultModelPath is the default mount path of the model prefetch volume.
+	DefaultModelPath string = "/models"
+
+	// Worker template label will be added to the workers created from the
+	// LeaderWorkerSet.Spec.LeaderWorkerTemplate.WorkerTemplates with the template name.
+	WorkerTemplateLabelKey string = "leaderworkerset.sigs.k8s.io/worker-template"
+
+	// Worker template index label will be added to the workers created from the
+	// LeaderWorkerSet.Spec.LeaderWorkerTemplate.WorkerTemplates with the index of the
+	// pod among the workers of the same template, starting from 0.
+	WorkerTemplateIndexLabelKey string = "leaderworkerset.sigs.k8s.io/worker-template-index"
+
+	// WorkerTemplates will be added to worker pods as an annotation which corresponds to the JSON
+	// encoded LeaderWorkerSet.Spec.LeaderWorkerTemplate.WorkerTemplates, it is removed once the
+	// template of the worker is applied.
+	WorkerTemplatesAnnotationKey string = "leaderworkerset.sigs.k8s.io/worker-templates"
+
 	// Subgroup index tracks which subgroup the pod is part of. It will be added
 	// as a label to the pod only if LeaderWorkerSet.Spec.SubGroupSize is set.
 	SubGroupIndexLabelKey string = "leaderworkerset.sigs.k8s.io/subgroup-index"
@@ -77,6 +153,109 @@ const (
 
 	// Pods that are part of the same subgroup will have the same unique hash value.
 	SubGroupUniqueHashLabelKey string = "leaderworkerset.sigs.k8s.io/subgroup-key"
+
+	// Rollback annotation is used to roll the leaderWorkerTemplate back to the
+	// given revision number recorded in ControllerRevisions. The annotation is
+	// removed once the rollback is processed.
+	RollbackToAnnotationKey string = "leaderworkerset.sigs.k8s.io/rollback-to"
+
+	// Restart group annotation is used to recreate the group of the given index,
+	// e.g. to bounce a single wedged group without touching the others. The
+	// annotation is moved to the leader pod of the group, which is deleted once
+	// the restart budgets allow it.
+	RestartGroupAnnotationKey string = "leaderworkerset.sigs.k8s.io/restart-group"
+
+	// Restarted at annotation is used to recreate all the groups following the
+	// rolling update strategy once updated, e.g. to the current time, without
+	// changing the templates. It works like `kubectl rollout restart`.
+	RestartedAtAnnotationKey string = "leaderworkerset.sigs.k8s.io/restartedAt"
+
+	// Gang scheduling annotation is used to specify the scheduler provider which
+	// will be used for all-or-nothing scheduling of each group, one of scheduler-plugins or volcano.
+	GangSchedulingAnnotationKey string = "leaderworkerset.sigs.k8s.io/gang-scheduling"
+
+	// Group scheduling gate annotation is used to hold the scheduling of all the pods
+	// of a group until all of them are created, when set to "true".
+	GroupSchedulingGateAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-scheduling-gate"
+
+	// GroupSchedulingGateName is the scheduling gate injected to the pods of a group,
+	// it is removed once all the pods of the group are created.
+	GroupSchedulingGateName string = "leaderworkerset.sigs.k8s.io/group-complete"
+
+	// Provisioning class name annotation is used to create a cluster autoscaler
+	// ProvisioningRequest of the given class for each group, the group scheduling
+	// gate is removed only once the capacity of the whole group is provisioned.
+	ProvisioningClassNameAnnotationKey string = "leaderworkerset.sigs.k8s.io/provisioning-class-name"
+
+	// Disable accelerator env annotation is used to opt out of the injection of the
+	// accelerator specific environment variables when set to "true", the LWS_* variables
+	// are still injected.
+	DisableAcceleratorEnvAnnotationKey string = "leaderworkerset.sigs.k8s.io/disable-accelerator-env"
+
+	// EnvVarNaming will be added to pods as an annotation which corresponds to the JSON
+	// encoded LeaderWorkerSet.Spec.LeaderWorkerTemplate.EnvVarNaming.
+	EnvVarNamingAnnotationKey string = "leaderworkerset.sigs.k8s.io/env-var-naming"
+
+	// TemplatedEnv will be added to pods as an annotation which corresponds to the JSON
+	// encoded LeaderWorkerSet.Spec.LeaderWorkerTemplate.TemplatedEnv.
+	TemplatedEnvAnnotationKey string = "leaderworkerset.sigs.k8s.io/templated-env"
+
+	// Subdomain policy will be added to pods as an annotation which corresponds to
+	// LeaderWorkerSet.Spec.NetworkConfig.SubdomainPolicy.
+	SubdomainPolicyAnnotationKey string = "leaderworkerset.sigs.k8s.io/subdomain-policy"
+
+	// Group ready label will be added to the leader pods of the ready serving groups when
+	// LeaderWorkerSet.Spec.LeaderService is set, which the leader Service selects. Warm groups
+	// are only labeled in place of the serving groups not ready.
+	GroupReadyLabelKey string = "leaderworkerset.sigs.k8s.io/group-ready"
+
+	// Group readiness gate annotation is used to add a readiness gate to the leader pods
+	// when set to "true", so that the leader pod is ready only once all the workers of
+	// the group are ready.
+	GroupReadinessGateAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-readiness-gate"
+
+	// WorkersReadyConditionType is the readiness gate condition of the leader pods, it
+	// is set to true by the controller once all the workers of the group are ready.
+	WorkersReadyConditionType corev1.PodConditionType = "leaderworkerset.sigs.k8s.io/workers-ready"
+
+	// ServingConditionType is the readiness gate condition of the leader pods when
+	// LeaderWorkerSet.Spec.DrainSeconds is set, it is set to false by the controller
+	// while the group is drained before the deletion.
+	ServingConditionType corev1.PodConditionType = "leaderworkerset.sigs.k8s.io/serving"
+
+	// Group eviction annotation is used to recreate the whole group when set to "true" once
+	// any pod of the group is evicted, e.g. by a node drain, regardless of the restart policy,
+	// so that partial groups are not left running.
+	GroupEvictionAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-eviction"
+
+	// Group preemption annotation is used to recreate the whole group when set to "true" once
+	// any pod of the group is preempted by the scheduler for higher priority pods, so that the
+	// lower priority groups are preempted as a unit instead of being left partially broken.
+	GroupPreemptionAnnotationKey string = "leaderworkerset.sigs.k8s.io/group-preemption"
+
+	// Mutations annotation is added to the pods by the pod webhook at creation, it records what
+	// the webhook changed as JSON: the labels, nodeSelector entries, env vars and gates added,
+	// and whether the affinity is changed.
+	MutationsAnnotationKey string = "leaderworkerset.sigs.k8s.io/mutations"
+
+	// Skip injection annotation can be set to "true" on the pods carrying the set label, e.g. the
+	// debug clones, to opt out of the mutations and the validations of the pod webhook. The controllers
+	// ignore these pods as well, they're never counted as the leaders or the workers of a group.
+	SkipInjectionAnnotationKey string = "leaderworkerset.sigs.k8s.io/skip-injection"
+
+	// Rendered hash annotation is added to the leader and the worker statefulsets, it records the
+	// hash of the configuration the controller last applied, so that the statefulsets are only
+	// updated when the rendered configuration changes.
+	RenderedHashAnnotationKey string = "leaderworkerset.sigs.k8s.io/rendered-hash"
+
+	// Cleanup finalizer is added to the leaderworkersets by the controller, it's removed once the
+	// statefulsets and the services of the groups are deleted and the canary HTTPRoute no longer
+	// routes to the services of the leaderworkerset.
+	CleanupFinalizer string = "leaderworkerset.sigs.k8s.io/cleanup"
+
+	// Teardown annotation is added to the leader pods of the groups being deleted with the WorkersFirst
+	// termination order, the worker statefulsets of these groups are not created again.
+	TeardownAnnotationKey string = "leaderworkerset.sigs.k8s.io/teardown"
 )
 
 // One group consists of a single leader and M workers, and the total number of pods in a group is M+1.
@@ -89,6 +268,9 @@ const (
 // Each worker pod in the group has a unique workerIndex between 1 and M. The leader also
 // gets a workerIndex, and it is always set to 0.
 // Worker pods are named using the format: leaderWorkerSetName-leaderIndex-workerIndex.
+// +kubebuilder:validation:XValidation:rule="!has(self.ttlSecondsAfterFinished) || (has(self.terminationPolicy) && self.terminationPolicy == 'LeaderSucceeded')",message="ttlSecondsAfterFinished is only supported with the LeaderSucceeded terminationPolicy"
+// +kubebuilder:validation:XValidation:rule="!has(self.warmReplicas) || self.warmReplicas == 0 || !has(self.terminationPolicy) || self.terminationPolicy != 'LeaderSucceeded'",message="warmReplicas is not supported with the LeaderSucceeded terminationPolicy"
+// +kubebuilder:validation:XValidation:rule="self.leaderWorkerTemplate.size == oldSelf.leaderWorkerTemplate.size || (has(self.allowDisruption) && self.allowDisruption)",message="size is immutable unless allowDisruption is set"
 type LeaderWorkerSetSpec struct {
 	// Number of leader-workers groups. A scale subresource is available to enable HPA. The
 	// selector for HPA will be that of the leader pod, and so practically HPA will be looking up the
@@ -96,12 +278,26 @@ type LeaderWorkerSetSpec struct {
 	// the rest of the group and expose them as a summary custom metric representing the whole
 	// group.
 	// On scale down, the leader pod as well as the workers statefulset will be deleted.
+	// The groups are removed from the lowest or the highest indexes, preferring the groups not ready,
+	// then the ones whose leader pods have the lowest controller.kubernetes.io/pod-deletion-cost.
 	// Default to 1.
 	//
 	// +optional
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// WarmReplicas is the number of standby groups created on top of the replicas, they're
+	// fully scheduled and running but excluded from the leader Service. A ready warm group
+	// is selected by the leader Service in place of a serving group not ready, and becomes
+	// a serving group once the replicas is scaled up, hiding the cold start of the groups.
+	// Warm groups take the highest group indexes.
+	// Default to 0.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	WarmReplicas int32 `json:"warmReplicas,omitempty"`
+
 	// LeaderWorkerTemplate defines the template for leader/worker pods
 	LeaderWorkerTemplate LeaderWorkerTemplate `json:"leaderWorkerTemplate"`
 
@@ -115,14 +311,222 @@ type LeaderWorkerSetSpec struct {
 	// +kubebuilder:validation:Enum={LeaderCreated,LeaderReady}
 	// +optional
 	StartupPolicy StartupPolicyType `json:"startupPolicy"`
+
+	// RevisionHistoryLimit is the number of old ControllerRevisions to retain
+	// to allow rollback. The current revision is not counted.
+	// Default to 10.
+	//
+	// +optional
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=0
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// ProgressDeadlineSeconds is the maximum time in seconds for a rolling update to make
+	// progress before it is considered to be failed. A stuck rolling update will be surfaced
+	// with a Progressing condition of status false and reason ProgressDeadlineExceeded.
+	// Progress is not checked if not specified.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds for which all the pods of a newly
+	// ready group should be ready without any of their containers crashing, for the group
+	// to be considered available. The rolling update waits on the availability of the
+	// updated groups. Defaults to 0 (groups are available as soon as they are ready).
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+
+	// Suspend specifies whether the lws controller should create the groups or not.
+	// If a lws is created with suspend set to true, no statefulsets are created. If a
+	// lws is suspended after creation, the statefulsets together with all the pods
+	// are deleted. Groups are created again once resumed. Defaults to false.
+	//
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// FailurePolicy limits how many times a group can be recreated on failures, it only
+	// takes effect when RestartPolicy is RecreateGroupOnPodRestart or RecreateWorkerOnPodRestart.
+	// +optional
+	FailurePolicy *FailurePolicy `json:"failurePolicy,omitempty"`
+
+	// TerminationPolicy determines whether the groups run to completion. With LeaderSucceeded,
+	// a group is succeeded once the first container of the leader pod exits with 0, its workers
+	// are then deleted and the group is never restarted again. The lws is Complete once all the
+	// groups are succeeded, or Failed once all the groups are finished and any of them is failed
+	// by the FailurePolicy, the leader statefulset is deleted then.
+	// Defaults to Never, i.e. the groups are long-running.
+	//
+	// +kubebuilder:validation:Enum={Never,LeaderSucceeded}
+	// +optional
+	TerminationPolicy TerminationPolicyType `json:"terminationPolicy,omitempty"`
+
+	// TerminationOrder determines the order the pods of a group are deleted in when the group is
+	// removed by scaling down or with the lws. With WorkersFirst, the worker pods are deleted first
+	// and the leader pod only once all of them are gone, so that the leader can flush its state and
+	// deregister from the request routers while its workers are still running. With LeaderFirst,
+	// the worker pods are deleted once the leader pod is gone.
+	// Defaults to LeaderFirst.
+	//
+	// +kubebuilder:validation:Enum={LeaderFirst,WorkersFirst}
+	// +optional
+	TerminationOrder TerminationOrderType `json:"terminationOrder,omitempty"`
+
+	// DrainSeconds is how long the leader pod of a group is kept NotReady before the group is
+	// deleted by scaling down or by the rollout, so that the load balancers stop sending requests
+	// to the group first. The leader pods get the serving readiness gate, whose condition is set
+	// to false on the groups about to be deleted. It can't be set or unset after creation, since
+	// the readiness gates of the existing pods can't be updated. No group is drained if not specified.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DrainSeconds *int32 `json:"drainSeconds,omitempty"`
+
+	// TTLSecondsAfterFinished limits the lifetime of the lws once finished with the LeaderSucceeded
+	// termination policy, i.e. Complete or Failed. The lws is deleted together with its statefulsets
+	// after the given seconds once finished, it is kept forever if not specified.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// NetworkConfig defines the network configuration of the groups.
+	// +optional
+	NetworkConfig *NetworkConfig `json:"networkConfig,omitempty"`
+
+	// LeaderService creates a Service named <lws-name>-leader selecting only the leader
+	// pods of the ready groups, which request routers can send the traffic to.
+	// +optional
+	LeaderService *LeaderService `json:"leaderService,omitempty"`
+
+	// DisruptionBudget creates a PodDisruptionBudget named after the lws covering the leader
+	// pods, which limits how many groups voluntary disruptions, e.g. node drains, can take
+	// down at the same time. Together with the group readiness gate, the leader pods are
+	// only available once all the workers of their groups are ready.
+	// +optional
+	DisruptionBudget *DisruptionBudget `json:"disruptionBudget,omitempty"`
+
+	// AllowDisruption allows the updates of the size, the subGroupSize and the exclusive-topology
+	// annotation, which are rejected otherwise. These updates recreate all the groups, the leader
+	// statefulset rolls out the leader pods regardless of the rolloutStrategy.
+	// Defaults to false.
+	// +optional
+	AllowDisruption bool `json:"allowDisruption,omitempty"`
+}
+
+// DisruptionBudget defines the PodDisruptionBudget of the groups.
+type DisruptionBudget struct {
+	// MaxUnavailable is the maximum number of groups which can be unavailable after the
+	// eviction. Value can be an absolute number (ex: 1) or a percentage of the groups (ex: 10%).
+	// Defaults to 1.
+	//
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:default=1
+	// +optional
+	MaxUnavailable intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
+// LeaderService defines the Service selecting the leader pods of the ready groups.
+type LeaderService struct {
+	// Type of the Service. Defaults to ClusterIP.
+	// +kubebuilder:default=ClusterIP
+	// +kubebuilder:validation:Enum={ClusterIP,NodePort,LoadBalancer}
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Ports exposed by the Service.
+	// +listType=atomic
+	Ports []corev1.ServicePort `json:"ports"`
+}
+
+// NetworkConfig defines the network configuration of the groups.
+type NetworkConfig struct {
+	// SubdomainPolicy determines the headless services the pods are addressed by,
+	// this value is immutable. Defaults to Shared.
+	// +kubebuilder:default=Shared
+	// +kubebuilder:validation:Enum={Shared,UniquePerReplica}
+	// +optional
+	SubdomainPolicy SubdomainPolicy `json:"subdomainPolicy,omitempty"`
+
+	// HeadlessService customizes the headless services created for the groups.
+	// +optional
+	HeadlessService *HeadlessService `json:"headlessService,omitempty"`
+
+	// IPFamilyPolicy of the headless and leader services created by the controller,
+	// set for dual-stack clusters. Defaults to the cluster default.
+	// +kubebuilder:validation:Enum={SingleStack,PreferDualStack,RequireDualStack}
+	// +optional
+	IPFamilyPolicy *corev1.IPFamilyPolicy `json:"ipFamilyPolicy,omitempty"`
+
+	// IPFamilies of the headless and leader services created by the controller,
+	// e.g. IPv6 for IPv6-only clusters. The first family is the primary one.
+	// +listType=atomic
+	// +kubebuilder:validation:MaxItems=2
+	// +optional
+	IPFamilies []corev1.IPFamily `json:"ipFamilies,omitempty"`
+}
+
+// HeadlessService customizes the headless services created for the groups.
+type HeadlessService struct {
+	// Disabled skips the creation of the headless services for users bringing their own,
+	// e.g. with a service mesh. The pods still use the headless service names as their
+	// subdomains, and existing headless services are not deleted.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// PublishNotReadyAddresses indicates whether the addresses of the pods which are
+	// not ready are published. Defaults to true.
+	// +optional
+	PublishNotReadyAddresses *bool `json:"publishNotReadyAddresses,omitempty"`
+
+	// Ports exposed by the headless services.
+	// +listType=atomic
+	// +optional
+	Ports []corev1.ServicePort `json:"ports,omitempty"`
+
+	// Labels added to the headless services.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations added to the headless services.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ExclusivePlacementPolicy string
+
+const (
+	// Affinity injects the pod affinity and anti-affinity to the leader pods, so that the scheduler
+	// places each group exclusively on a topology domain.
+	ExclusivePlacementAffinity ExclusivePlacementPolicy = "Affinity"
+
+	// NodeSelector leaves the leader pods free of inter-pod affinities, which are expensive to
+	// schedule on very large clusters. Once the leader pod is scheduled, the controller claims the
+	// topology domain for the group, or recreates the leader pod if the domain is already claimed
+	// by another group.
+	ExclusivePlacementNodeSelector ExclusivePlacementPolicy = "NodeSelector"
+)
+
+type SubdomainPolicy string
+
+const (
+	// Shared creates a single headless service for all the groups, named after the lws.
+	SubdomainShared SubdomainPolicy = "Shared"
+
+	// UniquePerReplica creates a headless service per group, named after the leader pod
+	// of the group, which gives the groups isolated DNS domains.
+	SubdomainUniquePerReplica SubdomainPolicy = "UniquePerReplica"
+)
+
 // Template of the leader/worker pods, the group will include at least one leader pod.
 // Defaults to the worker template if not specified. The idea is to allow users to create a
 // group with identical templates without needing to specify the template in both places.
 // For the leader it represents the id of the group, while for the workers it represents the
 // index within the group. For this reason, users should depend on the labels injected by this
 // API whenever possible.
+// +kubebuilder:validation:XValidation:rule="!has(self.subGroupPolicy) || !has(self.subGroupPolicy.subGroupSize) || self.subGroupPolicy.subGroupSize < 1 || (self.subGroupPolicy.subGroupSize <= self.size && (self.size % self.subGroupPolicy.subGroupSize == 0 || (self.size - 1) % self.subGroupPolicy.subGroupSize == 0))",message="subGroupSize cannot be larger than size, and size or size - 1 must be divisible by subGroupSize"
 type LeaderWorkerTemplate struct {
 	// LeaderTemplate defines the pod template for leader pods.
 	LeaderTemplate *corev1.PodTemplateSpec `json:"leaderTemplate,omitempty"`
@@ -130,6 +534,50 @@ type LeaderWorkerTemplate struct {
 	// WorkerTemplate defines the pod template for worker pods.
 	WorkerTemplate corev1.PodTemplateSpec `json:"workerTemplate"`
 
+	// WorkerTemplates defines additional named templates of the workers in a group, e.g. to mix
+	// the prefill and the decode workers with different resources. The workers are assigned to
+	// the templates in order by the worker index starting from 1, the remaining workers are
+	// created from the workerTemplate. The total count must not exceed size - 1.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	WorkerTemplates []NamedWorkerTemplate `json:"workerTemplates,omitempty"`
+
+	// GroupOverrides override the worker pods of the groups in the given index ranges, e.g. to
+	// place the groups 0-3 on H100 node pools and the groups 4-7 on A100 ones. The first override
+	// covering the group index applies. The leader pods are not overridden.
+	// +optional
+	GroupOverrides []GroupOverride `json:"groupOverrides,omitempty"`
+
+	// VolumeClaimTemplates is a list of claims that the leader and worker pods are allowed to
+	// reference. Every pod gets stable PersistentVolumeClaims named <claim name>-<pod name>,
+	// which are kept across the recreation of the pod, e.g. for the model weights. The claims
+	// must be mounted by a volumeMount of the same name in the templates. Immutable.
+	// +optional
+	// +listType=atomic
+	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+
+	// PersistentVolumeClaimRetentionPolicy describes the lifecycle of the claims created from
+	// the volumeClaimTemplates, passed through to the underlying statefulsets. The claims of the
+	// workers are deleted with the group if WhenDeleted is Delete, the claims of the leaders once
+	// scaled down if WhenScaled is Delete. By default the claims are retained.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+
+	// ModelPrefetch injects an init container into all the pods which downloads the model
+	// artifacts into a volume shared with the other containers before they start.
+	// +optional
+	ModelPrefetch *ModelPrefetch `json:"modelPrefetch,omitempty"`
+
+	// GroupSpreadConstraints spread the groups across the topology domains, e.g. zones, by
+	// injecting topologySpreadConstraints selecting the leader pods of the lws into the leader
+	// pods. With the exclusive placement, the workers follow their leaders, so do the groups.
+	// The constraints of the same topology key in the leader template take precedence.
+	// +listType=map
+	// +listMapKey=topologyKey
+	// +optional
+	GroupSpreadConstraints []GroupSpreadConstraint `json:"groupSpreadConstraints,omitempty"`
+
 	// Number of pods to create. It is the total number of pods in each group.
 	// The minimum is 1 which represent the leader. When set to 1, the leader
 	// pod is created for each group as well as a 0-replica StatefulSet for the workers.
@@ -137,35 +585,303 @@ type LeaderWorkerTemplate struct {
 	//
 	// +optional
 	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
 	Size *int32 `json:"size,omitempty"`
 
 	// RestartPolicy defines the restart policy when pod failures happen.
 	// +kubebuilder:default=Default
-	// +kubebuilder:validation:Enum={Default,RecreateGroupOnPodRestart}
+	// +kubebuilder:validation:Enum={Default,RecreateGroupOnPodRestart,RecreateWorkerOnPodRestart}
 	// +optional
 	RestartPolicy RestartPolicyType `json:"restartPolicy,omitempty"`
 
+	// LeaderHealthPolicy recreates the whole group when the leader pod is persistently
+	// unhealthy, since the workers usually can't recover from a restart of the leader alone.
+	// +optional
+	LeaderHealthPolicy *LeaderHealthPolicy `json:"leaderHealthPolicy,omitempty"`
+
+	// PreemptionPolicy recreates the whole group proactively once any of its pods is about
+	// to be preempted, e.g. on spot instance terminations, instead of waiting for it to fail.
+	// +optional
+	PreemptionPolicy *PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+
+	// PriorityPolicy assigns distinct PriorityClasses to the leader and worker pods, e.g. a
+	// higher one to the leader so that under resource pressure the scheduler never preempts
+	// the leader pod while keeping its workers.
+	// +optional
+	PriorityPolicy *PriorityPolicy `json:"priorityPolicy,omitempty"`
+
+	// PropagatePlacement copies the nodeSelector, tolerations and affinity of the leader template
+	// to the worker pods, so that the placement is only declared once. The nodeSelector entries
+	// and the affinity of the worker templates take precedence, the tolerations are merged.
+	// It takes no effect without the leader template.
+	// +optional
+	PropagatePlacement bool `json:"propagatePlacement,omitempty"`
+
 	// SubGroupPolicy describes the policy that will be applied when creating subgroups
 	// in each replica.
 	// +optional
 	SubGroupPolicy *SubGroupPolicy `json:"subGroupPolicy,omitempty"`
+
+	// EnvVarNaming customizes the names of the environment variables injected
+	// to the containers, e.g. LWS_LEADER_ADDRESS or the accelerator specific ones.
+	// +optional
+	EnvVarNaming *EnvVarNaming `json:"envVarNaming,omitempty"`
+
+	// TemplatedEnv defines the environment variables injected to all the containers,
+	// whose values are templates over the group metadata, i.e. {{.GroupIndex}},
+	// {{.WorkerIndex}}, {{.Size}} and {{.LeaderAddress}}. Variables defined in the
+	// containers take precedence.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	TemplatedEnv []TemplatedEnvVar `json:"templatedEnv,omitempty"`
+}
+
+// NamedWorkerTemplate defines a named template of the workers in a group.
+type NamedWorkerTemplate struct {
+	// Name of the template, the workers created from it are labeled with it.
+	Name string `json:"name"`
+
+	// Count is the number of workers created from the template in each group.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+
+	// Template of the workers.
+	Template corev1.PodTemplateSpec `json:"template"`
+}
+
+// GroupOverride defines the overrides of the worker pods of the groups in an index range.
+type GroupOverride struct {
+	// StartIndex is the first group index overridden.
+	// +kubebuilder:validation:Minimum=0
+	StartIndex int32 `json:"startIndex"`
+
+	// EndIndex is the last group index overridden, inclusive.
+	// +kubebuilder:validation:Minimum=0
+	EndIndex int32 `json:"endIndex"`
+
+	// NodeSelector is merged into the node selector of the worker pods.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Containers override the worker containers of the same name.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Containers []ContainerOverride `json:"containers,omitempty"`
+}
+
+// ContainerOverride defines the overrides of a container.
+type ContainerOverride struct {
+	// Name of the container.
+	Name string `json:"name"`
+
+	// Image replaces the image of the container if set.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Resources replace the resources of the container if set.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Env is merged into the environment variables of the container, replacing the ones
+	// of the same name.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// ModelPrefetch defines the init container pre-downloading the model artifacts.
+type ModelPrefetch struct {
+	// URI of the model artifacts, either an OCI image, e.g. oci://registry.example.com/models/llama:v1,
+	// or an object storage location, e.g. s3://bucket/llama or gs://bucket/llama.
+	URI string `json:"uri"`
+
+	// Image of the downloader, it is run with the LWS_MODEL_URI and LWS_MODEL_PATH environment
+	// variables, as well as LWS_GROUP_INDEX, LWS_WORKER_INDEX and LWS_GROUP_SIZE to e.g. only
+	// download the shards of the pod.
+	Image string `json:"image"`
+
+	// MountPath of the shared volume in all the containers.
+	// +kubebuilder:default=/models
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// SizeLimit of the emptyDir volume the model artifacts are downloaded to.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+}
+
+// GroupSpreadConstraint describes how the groups are spread across the domains of a topology.
+type GroupSpreadConstraint struct {
+	// TopologyKey is the key of the node labels, nodes with the same value are in the same domain.
+	TopologyKey string `json:"topologyKey"`
+
+	// MaxSkew is the maximum difference of the number of groups between any two domains.
+	// Defaults to 1.
+	//
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable indicates how to deal with a leader pod if it doesn't satisfy the
+	// constraint, DoNotSchedule keeps it pending while ScheduleAnyway spreads the groups
+	// on a best effort basis. Defaults to ScheduleAnyway.
+	//
+	// +kubebuilder:default=ScheduleAnyway
+	// +kubebuilder:validation:Enum={DoNotSchedule,ScheduleAnyway}
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// LeaderHealthPolicy defines when the leader pod is considered persistently unhealthy,
+// the group is recreated once any of the thresholds is exceeded, subject to the FailurePolicy.
+type LeaderHealthPolicy struct {
+	// RestartThreshold is the number of restarts of the leader containers, e.g. on failed
+	// liveness probes, after which the group is recreated.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+
+	// NotReadySeconds is the time in seconds the leader pod can stay not ready once all
+	// its containers are started, e.g. on failed readiness probes, before the group is recreated.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	NotReadySeconds *int32 `json:"notReadySeconds,omitempty"`
+}
+
+// PreemptionPolicy defines how the preemption of the group pods is detected and where the
+// preempted groups are recreated. Pods terminated by the graceful node shutdown or by the
+// taint manager, which is surfaced as the DisruptionTarget pod condition, are always detected.
+type PreemptionPolicy struct {
+	// NodeTaintKeys are the keys of the node taints signaling an imminent preemption of the
+	// node, e.g. the taints added by the cloud providers on spot instance termination notices.
+	// +optional
+	// +listType=set
+	NodeTaintKeys []string `json:"nodeTaintKeys,omitempty"`
+
+	// FallbackNodeSelector is merged into the node selector of the pods of the preempted groups
+	// once recreated, e.g. to move them to an on-demand node pool. The groups stay on the fallback
+	// nodes until updated to a new revision.
+	// +optional
+	FallbackNodeSelector map[string]string `json:"fallbackNodeSelector,omitempty"`
+}
+
+// PriorityPolicy defines the PriorityClasses of the leader and worker pods, which take precedence
+// over the ones of the templates.
+type PriorityPolicy struct {
+	// LeaderPriorityClassName is the PriorityClass of the leader pods, the one of the
+	// leader template is used if empty.
+	// +optional
+	LeaderPriorityClassName string `json:"leaderPriorityClassName,omitempty"`
+
+	// WorkerPriorityClassName is the PriorityClass of the worker pods, the one of the
+	// worker template is used if empty.
+	// +optional
+	WorkerPriorityClassName string `json:"workerPriorityClassName,omitempty"`
+}
+
+// TemplatedEnvVar represents an environment variable whose value is expanded per pod.
+type TemplatedEnvVar struct {
+	// Name of the environment variable.
+	Name string `json:"name"`
+
+	// Value is a Go template expanded with the group metadata of the pod,
+	// e.g. "{{.LeaderAddress}}:6379".
+	Value string `json:"value"`
+}
+
+// EnvVarNaming describes how the injected environment variables are named.
+type EnvVarNaming struct {
+	// Prefix is prepended to the names of the injected variables which are not renamed.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Renames maps the names of the injected variables to custom names,
+	// e.g. LWS_LEADER_ADDRESS to RAY_HEAD_ADDRESS, the prefix doesn't apply to them.
+	// +optional
+	Renames map[string]string `json:"renames,omitempty"`
 }
 
 // RolloutStrategy defines the strategy that the leaderWorkerSet controller
 // will use to perform replica updates.
 type RolloutStrategy struct {
-	// Type defines the rollout strategy, it can only be “RollingUpdate” for now.
+	// Type defines the rollout strategy, one of “RollingUpdate”, “OnDelete”, “BlueGreen” or “Canary”.
+	// With OnDelete, the template updates are only applied to a group once its leader
+	// pod is deleted, e.g. by the operators orchestrating the upgrades externally.
+	// With BlueGreen, a complete new set of groups is created with the new template, the
+	// leader Service is switched to them once they are all ready, then the old groups are
+	// torn down, so the revisions are never mixed behind the leader Service. The new groups
+	// are indexed next to the old ones, below them if possible, so the indexes alternate.
+	// With Canary, the groups are updated step by step per the canaryConfiguration, and the
+	// traffic is shifted to the new revision with the weights of a Gateway API HTTPRoute.
 	//
-	// +kubebuilder:validation:Enum={RollingUpdate}
+	// +kubebuilder:validation:Enum={RollingUpdate,OnDelete,BlueGreen,Canary}
 	// +kubebuilder:default=RollingUpdate
 	Type RolloutStrategyType `json:"type"`
 
 	// RollingUpdateConfiguration defines the parameters to be used when type is RollingUpdateStrategyType.
 	// +optional
 	RollingUpdateConfiguration *RollingUpdateConfiguration `json:"rollingUpdateConfiguration,omitempty"`
+
+	// CanaryConfiguration defines the parameters to be used when type is CanaryStrategyType.
+	// +optional
+	CanaryConfiguration *CanaryConfiguration `json:"canaryConfiguration,omitempty"`
+
+	// Paused indicates that the rolling update is paused, no more groups will be
+	// updated until it is resumed. Scaling is still processed while paused.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Hooks run Jobs for every group updated by the RollingUpdate rollout strategy, e.g. to
+	// warm up the caches or validate the new groups during model upgrades.
+	// +optional
+	Hooks *RolloutHooks `json:"hooks,omitempty"`
+}
+
+// RolloutHooks defines the Jobs run for every group updated during a rolling update. The Jobs are
+// run with the LWS_GROUP_INDEX and LWS_LEADER_ADDRESS environment variables of the group, e.g. to
+// probe the leader. The rollout doesn't proceed until the Jobs succeed, a failed Job blocks the
+// rollout until it is deleted to be retried.
+type RolloutHooks struct {
+	// PreGroupUpdate runs before an old group is torn down to be updated.
+	// +optional
+	PreGroupUpdate *RolloutHook `json:"preGroupUpdate,omitempty"`
+
+	// PostGroupUpdate runs once an updated group is ready, the group is not considered
+	// ready by the rollout until the Job succeeds.
+	// +optional
+	PostGroupUpdate *RolloutHook `json:"postGroupUpdate,omitempty"`
+}
+
+// RolloutHook defines the Job run by a rollout hook. The schema of the pod template is left out of
+// the CRD, which would otherwise get close to the size limit of etcd, so the template is validated
+// once the Job is created.
+type RolloutHook struct {
+	// Template of the pods of the Job, the restartPolicy defaults to Never.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Template corev1.PodTemplateSpec `json:"template"`
+
+	// BackoffLimit is the number of retries before the Job is considered failed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// ActiveDeadlineSeconds is the duration in seconds the Job may be active before it is
+	// considered failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
 }
 
 // SubGroupPolicy describes the policy that will be applied when creating subgroups.
+// +kubebuilder:validation:XValidation:rule="has(self.subGroupSize)",message="subGroupSize must be set when subGroupPolicy is specified"
 type SubGroupPolicy struct {
 	// The number of pods per subgroup. This value is immutable,
 	// and must not be greater than LeaderWorkerSet.Spec.Size.
@@ -173,10 +889,45 @@ type SubGroupPolicy struct {
 	// subgroups will be of equal size. Or size - 1 is divisible
 	// by subGroupSize, in which case the leader is considered as
 	// the extra pod, and will be part of the first subgroup.
+	// +kubebuilder:validation:Minimum=1
 	SubGroupSize *int32 `json:"subGroupSize,omitempty"`
 }
 
+// CanaryConfiguration defines the parameters to be used for CanaryStrategyType.
+// The leader pods of the stable and the new revisions are selected by the Services named
+// <lws-name>-stable and <lws-name>-canary, the backendRefs of all the rules of the HTTPRoute
+// are set to the two Services with the weights of the current step.
+type CanaryConfiguration struct {
+	// Steps of the canary rollout, the groups are all updated after the last step.
+	// +listType=atomic
+	// +kubebuilder:validation:MinItems=1
+	Steps []CanaryStep `json:"steps"`
+
+	// HTTPRoute is the name of the Gateway API HTTPRoute in the namespace of the lws
+	// sending the traffic to the leader pods.
+	HTTPRoute string `json:"httpRoute"`
+
+	// Port of the stable and canary Services the HTTPRoute sends the traffic to.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
+// CanaryStep defines a step of the canary rollout.
+type CanaryStep struct {
+	// Weight is the percentage of the groups updated in this step, the same percentage of
+	// the traffic is shifted to the new revision once the updated groups are ready.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Weight int32 `json:"weight"`
+
+	// PauseSeconds is the time to wait after the traffic is shifted before moving to the next step.
+	// +optional
+	PauseSeconds int32 `json:"pauseSeconds,omitempty"`
+}
+
 // RollingUpdateConfiguration defines the parameters to be used for RollingUpdateStrategyType.
+// +kubebuilder:validation:XValidation:rule="!has(self.maxUnavailable) || !has(self.maxSurge) || !(string(self.maxUnavailable) in ['0', '0%'] && string(self.maxSurge) in ['0', '0%'])",message="maxUnavailable must not be 0 when maxSurge is 0"
 type RollingUpdateConfiguration struct {
 	// The maximum number of replicas that can be unavailable during the update.
 	// Value can be an absolute number (ex: 5) or a percentage of total replicas at the start of update (ex: 10%).
@@ -190,6 +941,7 @@ type RollingUpdateConfiguration struct {
 	// during the update.
 	//
 	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 0 : self.matches('^(100|[1-9]?[0-9])%$')",message="must be a non-negative integer or a percentage not more than 100%"
 	// +kubebuilder:default=1
 	MaxUnavailable intstr.IntOrString `json:"maxUnavailable,omitempty"`
 
@@ -206,8 +958,19 @@ type RollingUpdateConfiguration struct {
 	// When rolling update completes, replicas will fall back to the original replicas.
 	//
 	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 0 : self.matches('^(100|[1-9]?[0-9])%$')",message="must be a non-negative integer or a percentage not more than 100%"
 	// +kubebuilder:default=0
 	MaxSurge intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// Partition indicates the group index at which the rolling update is partitioned.
+	// Only the groups with index >= Partition are updated to the new template, groups with
+	// index < Partition keep running the old one, which is useful for canary updates.
+	// By default, a value of 0 is used, i.e. all the groups are updated.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=0
+	// +optional
+	Partition int32 `json:"partition,omitempty"`
 }
 
 type RolloutStrategyType string
@@ -217,6 +980,18 @@ const (
 	// by RollingUpdateConfiguration), the latter one will not start the update until the
 	// former one(leader+workers) is ready.
 	RollingUpdateStrategyType RolloutStrategyType = "RollingUpdate"
+
+	// OnDeleteStrategyType indicates that replicas will only be updated once their leader
+	// pods are deleted manually, the old groups are kept as is otherwise.
+	OnDeleteStrategyType RolloutStrategyType = "OnDelete"
+
+	// BlueGreenStrategyType indicates that a complete new set of replicas will be created
+	// on template updates, and the old replicas will only be removed once all the new ones are ready.
+	BlueGreenStrategyType RolloutStrategyType = "BlueGreen"
+
+	// CanaryStrategyType indicates that replicas will be updated step by step, with the traffic
+	// shifted to the new revision by the weights of each step.
+	CanaryStrategyType RolloutStrategyType = "Canary"
 )
 
 type RestartPolicyType string
@@ -224,15 +999,112 @@ type RestartPolicyType string
 const (
 	// RecreateGroupOnPodRestart will recreate all the pods in the group if
 	// 1. Any individual pod in the group is recreated; 2. Any containers/init-containers
-	// in a pod is restarted. This is to ensure all pods/containers in the group will be
-	// started in the same time.
+	// in a pod is restarted; 3. Any individual pod in the group is failed. This is to
+	// ensure all pods/containers in the group will be started in the same time.
 	RecreateGroupOnPodRestart RestartPolicyType = "RecreateGroupOnPodRestart"
 
+	// RecreateWorkerOnPodRestart will only recreate the worker pod whose containers are
+	// restarted or which is failed, the other pods in the group are not impacted, for
+	// frameworks tolerating workers rejoining the group. The group is still recreated if
+	// the leader pod is restarted, deleted or failed.
+	RecreateWorkerOnPodRestart RestartPolicyType = "RecreateWorkerOnPodRestart"
+
 	// Default will follow the same behavior as the StatefulSet where only the failed pod
 	// will be restarted on failure and other pods in the group will not be impacted.
 	DefaultRestartPolicy RestartPolicyType = "Default"
 )
 
+// FailurePolicy describes how to handle a group which keeps failing.
+type FailurePolicy struct {
+	// MaxRestarts is the maximum number of times a group can be recreated, once exceeded,
+	// the group will not be recreated anymore and the Action will be taken.
+	// Unlimited if not specified.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// RestartBackoffSeconds is the minimum time in seconds to wait before recreating
+	// the same group again. Groups are recreated immediately if not specified.
+	//
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RestartBackoffSeconds *int32 `json:"restartBackoffSeconds,omitempty"`
+
+	// MaxConcurrentRestarts is the maximum number of groups of the lws which can be
+	// recreated at the same time, other failed groups wait until the recreation of the
+	// former ones completes. Unlimited if not specified.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentRestarts *int32 `json:"maxConcurrentRestarts,omitempty"`
+
+	// Action defines what to do when a group exceeds the MaxRestarts.
+	//
+	// +kubebuilder:default=FailGroup
+	// +kubebuilder:validation:Enum={FailGroup,FailLeaderWorkerSet}
+	// +optional
+	Action FailurePolicyAction `json:"action,omitempty"`
+
+	// Rules define the actions to take on specific failures, they are evaluated in order
+	// and the first matched one is applied. If no rule matches, the group is recreated
+	// and counted towards MaxRestarts.
+	// +optional
+	Rules []FailurePolicyRule `json:"rules,omitempty"`
+}
+
+// FailurePolicyRule matches the failed pod by the container exit codes or the pod condition
+// reasons, a rule matches if any of them matches.
+type FailurePolicyRule struct {
+	// Action defines what to do when the rule matches. RestartGroup recreates the group
+	// without counting towards MaxRestarts, e.g. for infrastructure preemptions.
+	//
+	// +kubebuilder:validation:Enum={RestartGroup,FailGroup,FailLeaderWorkerSet}
+	Action FailurePolicyAction `json:"action"`
+
+	// OnExitCodes matches the exit code of any terminated container in the pod.
+	// +optional
+	OnExitCodes []int32 `json:"onExitCodes,omitempty"`
+
+	// OnPodConditionReasons matches the reason of any condition of the pod, as well as the reason of the pod status.
+	// +optional
+	OnPodConditionReasons []string `json:"onPodConditionReasons,omitempty"`
+}
+
+type FailurePolicyAction string
+
+const (
+	// RestartGroup recreates the group, it can only be used in the failure policy rules.
+	RestartGroupAction FailurePolicyAction = "RestartGroup"
+
+	// FailGroup marks the group as Failed in status, other groups are not impacted.
+	FailGroupAction FailurePolicyAction = "FailGroup"
+
+	// FailLeaderWorkerSet marks the group as Failed in status, and the lws as Failed,
+	// no group will be recreated anymore.
+	FailLeaderWorkerSetAction FailurePolicyAction = "FailLeaderWorkerSet"
+)
+
+type TerminationPolicyType string
+
+const (
+	// NeverTerminationPolicy keeps the groups running, they're restarted on exits.
+	NeverTerminationPolicy TerminationPolicyType = "Never"
+
+	// LeaderSucceededTerminationPolicy completes the group once the leader container exits with 0.
+	LeaderSucceededTerminationPolicy TerminationPolicyType = "LeaderSucceeded"
+)
+
+type TerminationOrderType string
+
+const (
+	// LeaderFirstTerminationOrder deletes the worker pods of the group once the leader pod is gone.
+	LeaderFirstTerminationOrder TerminationOrderType = "LeaderFirst"
+
+	// WorkersFirstTerminationOrder deletes the leader pod of the group once the worker pods are gone.
+	WorkersFirstTerminationOrder TerminationOrderType = "WorkersFirst"
+)
+
 type StartupPolicyType string
 
 const (
@@ -251,9 +1123,24 @@ type LeaderWorkerSetStatus struct {
 	// ReadyReplicas track the number of groups that are in ready state (updated or not).
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
 
+	// AvailableReplicas track the number of groups that have been ready for at least
+	// minReadySeconds (updated or not).
+	// +optional
+	AvailableReplicas int32 `json:"availableReplicas,omitempty"`
+
 	// UpdatedReplicas track the number of groups that have been updated (ready or not).
 	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
 
+	// CurrentRevision is the template revision hash of the groups not updated yet,
+	// it equals the UpdateRevision once all the groups are updated.
+	// +optional
+	CurrentRevision string `json:"currentRevision,omitempty"`
+
+	// UpdateRevision is the template revision hash the groups are updated to,
+	// i.e. the hash of the current leaderWorkerTemplate.
+	// +optional
+	UpdateRevision string `json:"updateRevision,omitempty"`
+
 	// Replicas track the total number of groups that have been created (updated or not, ready or not)
 	Replicas int32 `json:"replicas,omitempty"`
 
@@ -261,8 +1148,100 @@ type LeaderWorkerSetStatus struct {
 	// needed for HPA to know what pods belong to the LeaderWorkerSet object. Here
 	// we only select the leader pods.
 	HPAPodSelector string `json:"hpaPodSelector,omitempty"`
+
+	// ReplicaStatuses track the observed state of each group, sorted by the group index.
+	// +listType=map
+	// +listMapKey=index
+	// +optional
+	ReplicaStatuses []ReplicaStatus `json:"replicaStatuses,omitempty"`
+
+	// LastProgressTime is the last time the rolling update made progress, i.e. the number
+	// of updated groups changed. It is only set when a rolling update is in progress.
+	// +optional
+	LastProgressTime *metav1.Time `json:"lastProgressTime,omitempty"`
+
+	// Canary tracks the progress of the canary rollout, only set with the Canary rollout strategy.
+	// +optional
+	Canary *CanaryStatus `json:"canary,omitempty"`
+
+	// Succeeded is the number of groups succeeded with the LeaderSucceeded termination policy.
+	// +optional
+	Succeeded int32 `json:"succeeded,omitempty"`
+
+	// Failed is the number of groups failed by the failure policy, which are not recreated anymore.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+}
+
+// CanaryStatus describes the progress of the canary rollout.
+type CanaryStatus struct {
+	// StableRevision is the template revision hash of the groups the stable Service selects.
+	StableRevision string `json:"stableRevision"`
+
+	// Step is the index of the current step of the canary rollout.
+	// +optional
+	Step int32 `json:"step,omitempty"`
+
+	// StepStartTime is the time the traffic is shifted in the current step, unset until
+	// the updated groups of the step are ready.
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+}
+
+// ReplicaStatus describes the observed state of a single group.
+type ReplicaStatus struct {
+	// Index is the index of the group.
+	Index int32 `json:"index"`
+
+	// Phase is the observed phase of the group, one of Pending, Ready, Updating, Succeeded or Failed.
+	Phase ReplicaPhase `json:"phase"`
+
+	// ReadyWorkers is the number of ready worker pods in the group, not including the leader.
+	ReadyWorkers int32 `json:"readyWorkers"`
+
+	// Revision is the template revision hash of the group.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+
+	// Restarts is the number of times the group has been recreated on failures.
+	// +optional
+	Restarts int32 `json:"restarts,omitempty"`
+
+	// LastRestartTime is the last time the group was recreated on failures.
+	// +optional
+	LastRestartTime *metav1.Time `json:"lastRestartTime,omitempty"`
+
+	// Preempted is true once the group is recreated on preemption, the pods of the group are
+	// then scheduled with the fallback node selector of the preemption policy.
+	// +optional
+	Preempted bool `json:"preempted,omitempty"`
+
+	// Reason is a brief CamelCase message indicating why the group is failed
+	// by the failure policy.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
+type ReplicaPhase string
+
+const (
+	// ReplicaPending means the group is created but not all the pods are ready yet.
+	ReplicaPending ReplicaPhase = "Pending"
+
+	// ReplicaReady means the group is updated to the latest revision and all the pods are ready.
+	ReplicaReady ReplicaPhase = "Ready"
+
+	// ReplicaUpdating means the group is still running an old revision and waiting to be updated.
+	ReplicaUpdating ReplicaPhase = "Updating"
+
+	// ReplicaFailed means the leader pod of the group is failed.
+	ReplicaFailed ReplicaPhase = "Failed"
+
+	// ReplicaSucceeded means the leader container exited with 0 with the LeaderSucceeded termination
+	// policy, the group stays succeeded until updated.
+	ReplicaSucceeded ReplicaPhase = "Succeeded"
+)
+
 type LeaderWorkerSetConditionType string
 
 // These are built-in conditions of a LWS.
@@ -282,13 +1261,49 @@ const (
 	// is true when the lws is in upgrade process after the (leader/worker) template is updated. If only replicas is modified, it will
 	// not be considered as UpgradeInProgress.
 	LeaderWorkerSetUpgradeInProgress LeaderWorkerSetConditionType = "UpgradeInProgress"
+
+	// LeaderWorkerSetReplicaFailure means at least one group of the lws is failed,
+	// i.e. its leader pod is in the Failed phase. It is independent of the other
+	// conditions and is set back to false once no group is failed.
+	LeaderWorkerSetReplicaFailure LeaderWorkerSetConditionType = "ReplicaFailure"
+
+	// LeaderWorkerSetSuspended means the lws is suspended, i.e. all the groups are
+	// deleted and will not be created until the lws is resumed.
+	LeaderWorkerSetSuspended LeaderWorkerSetConditionType = "Suspended"
+
+	// LeaderWorkerSetTopologyKeyMissing means no node has the label key of the exclusive-topology
+	// or subgroup-exclusive-topology annotations, so that the groups can't be scheduled. It is
+	// removed once the label keys exist on the nodes.
+	LeaderWorkerSetTopologyKeyMissing LeaderWorkerSetConditionType = "TopologyKeyMissing"
+
+	// LeaderWorkerSetAdmitted means the Kueue Workload of the queued lws is admitted. No group
+	// is created while it is false, regardless of the suspend of the lws.
+	LeaderWorkerSetAdmitted LeaderWorkerSetConditionType = "Admitted"
 )
 
+// LeaderWorkerSetFailed means the lws is failed since a group is failed by the
+// FailurePolicy with the FailLeaderWorkerSet action.
+// Once failed, no group will be recreated anymore.
+const LeaderWorkerSetFailed LeaderWorkerSetConditionType = "Failed"
+
+// LeaderWorkerSetComplete means all the groups of the lws are succeeded with the
+// LeaderSucceeded termination policy.
+const LeaderWorkerSetComplete LeaderWorkerSetConditionType = "Complete"
+
+// ProgressDeadlineExceededReason is the reason of the Progressing condition when the
+// rolling update makes no progress within the ProgressDeadlineSeconds.
+const ProgressDeadlineExceededReason string = "ProgressDeadlineExceeded"
+
 // +genclient
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
 //+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.hpaPodSelector
 //+kubebuilder:resource:shortName={lws}
+//+kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.spec.replicas`,description="The desired number of groups"
+//+kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`,description="The number of ready groups"
+//+kubebuilder:printcolumn:name="Up-to-date",type=integer,JSONPath=`.status.updatedReplicas`,description="The number of groups updated to the latest template"
+//+kubebuilder:printcolumn:name="Available",type=integer,JSONPath=`.status.availableReplicas`,description="The number of available groups"
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // LeaderWorkerSet is the Schema for the leaderworkersets API
 type LeaderWorkerSet struct {
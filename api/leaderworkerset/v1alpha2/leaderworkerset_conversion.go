@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// ConvertTo converts the v1alpha2 LeaderWorkerSet to the hub version v1, the exclusive
+// topology is converted to the exclusive-topology annotation.
+func (src *LeaderWorkerSet) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1.LeaderWorkerSet)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = *src.Spec.LeaderWorkerSetSpec.DeepCopy()
+	dst.Status = *src.Status.DeepCopy()
+	if src.Spec.ExclusiveTopology != "" {
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[v1.ExclusiveKeyAnnotationKey] = src.Spec.ExclusiveTopology
+	}
+	return nil
+}
+
+// ConvertFrom converts the hub version v1 LeaderWorkerSet to v1alpha2, the exclusive-topology
+// annotation is converted to the exclusive topology.
+func (dst *LeaderWorkerSet) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1.LeaderWorkerSet)
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = LeaderWorkerSetSpec{LeaderWorkerSetSpec: *src.Spec.DeepCopy()}
+	dst.Status = *src.Status.DeepCopy()
+	if topology, found := dst.Annotations[v1.ExclusiveKeyAnnotationKey]; found {
+		dst.Spec.ExclusiveTopology = topology
+		delete(dst.Annotations, v1.ExclusiveKeyAnnotationKey)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	return nil
+}
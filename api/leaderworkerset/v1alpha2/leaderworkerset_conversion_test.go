@@ -0,0 +1,75 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestConversionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name                  string
+		annotations           map[string]string
+		wantExclusiveTopology string
+	}{
+		{
+			name:        "without exclusive topology",
+			annotations: map[string]string{"foo": "bar"},
+		},
+		{
+			name:                  "with exclusive topology",
+			annotations:           map[string]string{v1.ExclusiveKeyAnnotationKey: "topology.kubernetes.io/zone"},
+			wantExclusiveTopology: "topology.kubernetes.io/zone",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			hub := &v1.LeaderWorkerSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", Annotations: tc.annotations},
+				Spec: v1.LeaderWorkerSetSpec{
+					Replicas:             ptr.To[int32](2),
+					LeaderWorkerTemplate: v1.LeaderWorkerTemplate{Size: ptr.To[int32](4)},
+				},
+				Status: v1.LeaderWorkerSetStatus{Replicas: 2},
+			}
+			var spoke LeaderWorkerSet
+			if err := spoke.ConvertFrom(hub); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, found := spoke.Annotations[v1.ExclusiveKeyAnnotationKey]; found {
+				t.Errorf("Unexpected exclusive-topology annotation in v1alpha2")
+			}
+			if spoke.Spec.ExclusiveTopology != tc.wantExclusiveTopology {
+				t.Errorf("Unexpected exclusive topology, want %q, got %q", tc.wantExclusiveTopology, spoke.Spec.ExclusiveTopology)
+			}
+			var got v1.LeaderWorkerSet
+			if err := spoke.ConvertTo(&got); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(hub, &got); diff != "" {
+				t.Errorf("Unexpected round trip (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// LeaderWorkerSetSpec defines the desired state of LeaderWorkerSet.
+type LeaderWorkerSetSpec struct {
+	v1.LeaderWorkerSetSpec `json:",inline"`
+
+	// ExclusiveTopology is the topology key of the 1:1 exclusive scheduling of the groups,
+	// it replaces the exclusive-topology annotation of v1.
+	// +optional
+	ExclusiveTopology string `json:"exclusiveTopology,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.hpaPodSelector
+//+kubebuilder:resource:shortName={lws}
+
+// LeaderWorkerSet is the Schema for the leaderworkersets API
+type LeaderWorkerSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LeaderWorkerSetSpec      `json:"spec,omitempty"`
+	Status v1.LeaderWorkerSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// LeaderWorkerSetList contains a list of LeaderWorkerSet.
+type LeaderWorkerSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LeaderWorkerSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LeaderWorkerSet{}, &LeaderWorkerSetList{})
+}
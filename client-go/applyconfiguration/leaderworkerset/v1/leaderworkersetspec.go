@@ -17,12 +17,31 @@ limitations under the License.
 
 package v1
 
+import (
+	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
 // LeaderWorkerSetSpecApplyConfiguration represents an declarative configuration of the LeaderWorkerSetSpec type for use
 // with apply.
 type LeaderWorkerSetSpecApplyConfiguration struct {
-	Replicas             *int32                                  `json:"replicas,omitempty"`
-	LeaderWorkerTemplate *LeaderWorkerTemplateApplyConfiguration `json:"leaderWorkerTemplate,omitempty"`
-	RolloutStrategy      *RolloutStrategyApplyConfiguration      `json:"rolloutStrategy,omitempty"`
+	Replicas                *int32                                   `json:"replicas,omitempty"`
+	WarmReplicas            *int32                                   `json:"warmReplicas,omitempty"`
+	LeaderWorkerTemplate    *LeaderWorkerTemplateApplyConfiguration  `json:"leaderWorkerTemplate,omitempty"`
+	RolloutStrategy         *RolloutStrategyApplyConfiguration       `json:"rolloutStrategy,omitempty"`
+	StartupPolicy           *leaderworkersetv1.StartupPolicyType     `json:"startupPolicy,omitempty"`
+	RevisionHistoryLimit    *int32                                   `json:"revisionHistoryLimit,omitempty"`
+	ProgressDeadlineSeconds *int32                                   `json:"progressDeadlineSeconds,omitempty"`
+	MinReadySeconds         *int32                                   `json:"minReadySeconds,omitempty"`
+	Suspend                 *bool                                    `json:"suspend,omitempty"`
+	FailurePolicy           *FailurePolicyApplyConfiguration         `json:"failurePolicy,omitempty"`
+	TerminationPolicy       *leaderworkersetv1.TerminationPolicyType `json:"terminationPolicy,omitempty"`
+	TerminationOrder        *leaderworkersetv1.TerminationOrderType  `json:"terminationOrder,omitempty"`
+	DrainSeconds            *int32                                   `json:"drainSeconds,omitempty"`
+	TTLSecondsAfterFinished *int32                                   `json:"ttlSecondsAfterFinished,omitempty"`
+	NetworkConfig           *NetworkConfigApplyConfiguration         `json:"networkConfig,omitempty"`
+	LeaderService           *LeaderServiceApplyConfiguration         `json:"leaderService,omitempty"`
+	DisruptionBudget        *DisruptionBudgetApplyConfiguration      `json:"disruptionBudget,omitempty"`
+	AllowDisruption         *bool                                    `json:"allowDisruption,omitempty"`
 }
 
 // LeaderWorkerSetSpecApplyConfiguration constructs an declarative configuration of the LeaderWorkerSetSpec type for use with
@@ -39,6 +58,14 @@ func (b *LeaderWorkerSetSpecApplyConfiguration) WithReplicas(value int32) *Leade
 	return b
 }
 
+// WithWarmReplicas sets the WarmReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the WarmReplicas field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithWarmReplicas(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.WarmReplicas = &value
+	return b
+}
+
 // WithLeaderWorkerTemplate sets the LeaderWorkerTemplate field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the LeaderWorkerTemplate field is set to the value of the last call.
@@ -54,3 +81,115 @@ func (b *LeaderWorkerSetSpecApplyConfiguration) WithRolloutStrategy(value *Rollo
 	b.RolloutStrategy = value
 	return b
 }
+
+// WithStartupPolicy sets the StartupPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartupPolicy field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithStartupPolicy(value leaderworkersetv1.StartupPolicyType) *LeaderWorkerSetSpecApplyConfiguration {
+	b.StartupPolicy = &value
+	return b
+}
+
+// WithRevisionHistoryLimit sets the RevisionHistoryLimit field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RevisionHistoryLimit field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithRevisionHistoryLimit(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.RevisionHistoryLimit = &value
+	return b
+}
+
+// WithProgressDeadlineSeconds sets the ProgressDeadlineSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ProgressDeadlineSeconds field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithProgressDeadlineSeconds(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.ProgressDeadlineSeconds = &value
+	return b
+}
+
+// WithMinReadySeconds sets the MinReadySeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MinReadySeconds field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithMinReadySeconds(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.MinReadySeconds = &value
+	return b
+}
+
+// WithSuspend sets the Suspend field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Suspend field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithSuspend(value bool) *LeaderWorkerSetSpecApplyConfiguration {
+	b.Suspend = &value
+	return b
+}
+
+// WithFailurePolicy sets the FailurePolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FailurePolicy field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithFailurePolicy(value *FailurePolicyApplyConfiguration) *LeaderWorkerSetSpecApplyConfiguration {
+	b.FailurePolicy = value
+	return b
+}
+
+// WithTerminationPolicy sets the TerminationPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TerminationPolicy field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithTerminationPolicy(value leaderworkersetv1.TerminationPolicyType) *LeaderWorkerSetSpecApplyConfiguration {
+	b.TerminationPolicy = &value
+	return b
+}
+
+// WithTerminationOrder sets the TerminationOrder field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TerminationOrder field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithTerminationOrder(value leaderworkersetv1.TerminationOrderType) *LeaderWorkerSetSpecApplyConfiguration {
+	b.TerminationOrder = &value
+	return b
+}
+
+// WithDrainSeconds sets the DrainSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DrainSeconds field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithDrainSeconds(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.DrainSeconds = &value
+	return b
+}
+
+// WithTTLSecondsAfterFinished sets the TTLSecondsAfterFinished field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TTLSecondsAfterFinished field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithTTLSecondsAfterFinished(value int32) *LeaderWorkerSetSpecApplyConfiguration {
+	b.TTLSecondsAfterFinished = &value
+	return b
+}
+
+// WithNetworkConfig sets the NetworkConfig field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkConfig field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithNetworkConfig(value *NetworkConfigApplyConfiguration) *LeaderWorkerSetSpecApplyConfiguration {
+	b.NetworkConfig = value
+	return b
+}
+
+// WithLeaderService sets the LeaderService field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LeaderService field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithLeaderService(value *LeaderServiceApplyConfiguration) *LeaderWorkerSetSpecApplyConfiguration {
+	b.LeaderService = value
+	return b
+}
+
+// WithDisruptionBudget sets the DisruptionBudget field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DisruptionBudget field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithDisruptionBudget(value *DisruptionBudgetApplyConfiguration) *LeaderWorkerSetSpecApplyConfiguration {
+	b.DisruptionBudget = value
+	return b
+}
+
+// WithAllowDisruption sets the AllowDisruption field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AllowDisruption field is set to the value of the last call.
+func (b *LeaderWorkerSetSpecApplyConfiguration) WithAllowDisruption(value bool) *LeaderWorkerSetSpecApplyConfiguration {
+	b.AllowDisruption = &value
+	return b
+}
@@ -24,11 +24,19 @@ import (
 // LeaderWorkerSetStatusApplyConfiguration represents an declarative configuration of the LeaderWorkerSetStatus type for use
 // with apply.
 type LeaderWorkerSetStatusApplyConfiguration struct {
-	Conditions      []v1.Condition `json:"conditions,omitempty"`
-	ReadyReplicas   *int32         `json:"readyReplicas,omitempty"`
-	UpdatedReplicas *int32         `json:"updatedReplicas,omitempty"`
-	Replicas        *int32         `json:"replicas,omitempty"`
-	HPAPodSelector  *string        `json:"hpaPodSelector,omitempty"`
+	Conditions        []v1.Condition                    `json:"conditions,omitempty"`
+	ReadyReplicas     *int32                            `json:"readyReplicas,omitempty"`
+	AvailableReplicas *int32                            `json:"availableReplicas,omitempty"`
+	UpdatedReplicas   *int32                            `json:"updatedReplicas,omitempty"`
+	CurrentRevision   *string                           `json:"currentRevision,omitempty"`
+	UpdateRevision    *string                           `json:"updateRevision,omitempty"`
+	Replicas          *int32                            `json:"replicas,omitempty"`
+	HPAPodSelector    *string                           `json:"hpaPodSelector,omitempty"`
+	ReplicaStatuses   []ReplicaStatusApplyConfiguration `json:"replicaStatuses,omitempty"`
+	LastProgressTime  *v1.Time                          `json:"lastProgressTime,omitempty"`
+	Canary            *CanaryStatusApplyConfiguration   `json:"canary,omitempty"`
+	Succeeded         *int32                            `json:"succeeded,omitempty"`
+	Failed            *int32                            `json:"failed,omitempty"`
 }
 
 // LeaderWorkerSetStatusApplyConfiguration constructs an declarative configuration of the LeaderWorkerSetStatus type for use with
@@ -55,6 +63,14 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithReadyReplicas(value int32)
 	return b
 }
 
+// WithAvailableReplicas sets the AvailableReplicas field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AvailableReplicas field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithAvailableReplicas(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.AvailableReplicas = &value
+	return b
+}
+
 // WithUpdatedReplicas sets the UpdatedReplicas field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the UpdatedReplicas field is set to the value of the last call.
@@ -63,6 +79,22 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithUpdatedReplicas(value int3
 	return b
 }
 
+// WithCurrentRevision sets the CurrentRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CurrentRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCurrentRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.CurrentRevision = &value
+	return b
+}
+
+// WithUpdateRevision sets the UpdateRevision field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the UpdateRevision field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithUpdateRevision(value string) *LeaderWorkerSetStatusApplyConfiguration {
+	b.UpdateRevision = &value
+	return b
+}
+
 // WithReplicas sets the Replicas field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Replicas field is set to the value of the last call.
@@ -78,3 +110,48 @@ func (b *LeaderWorkerSetStatusApplyConfiguration) WithHPAPodSelector(value strin
 	b.HPAPodSelector = &value
 	return b
 }
+
+// WithReplicaStatuses adds the given value to the ReplicaStatuses field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the ReplicaStatuses field.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithReplicaStatuses(values ...*ReplicaStatusApplyConfiguration) *LeaderWorkerSetStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithReplicaStatuses")
+		}
+		b.ReplicaStatuses = append(b.ReplicaStatuses, *values[i])
+	}
+	return b
+}
+
+// WithLastProgressTime sets the LastProgressTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastProgressTime field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithLastProgressTime(value v1.Time) *LeaderWorkerSetStatusApplyConfiguration {
+	b.LastProgressTime = &value
+	return b
+}
+
+// WithCanary sets the Canary field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Canary field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithCanary(value *CanaryStatusApplyConfiguration) *LeaderWorkerSetStatusApplyConfiguration {
+	b.Canary = value
+	return b
+}
+
+// WithSucceeded sets the Succeeded field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Succeeded field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithSucceeded(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.Succeeded = &value
+	return b
+}
+
+// WithFailed sets the Failed field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Failed field is set to the value of the last call.
+func (b *LeaderWorkerSetStatusApplyConfiguration) WithFailed(value int32) *LeaderWorkerSetStatusApplyConfiguration {
+	b.Failed = &value
+	return b
+}
@@ -18,18 +18,31 @@ limitations under the License.
 package v1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
-	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	apileaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 // LeaderWorkerTemplateApplyConfiguration represents an declarative configuration of the LeaderWorkerTemplate type for use
 // with apply.
 type LeaderWorkerTemplateApplyConfiguration struct {
-	LeaderTemplate *v1.PodTemplateSpec                  `json:"leaderTemplate,omitempty"`
-	WorkerTemplate *v1.PodTemplateSpec                  `json:"workerTemplate,omitempty"`
-	Size           *int32                               `json:"size,omitempty"`
-	RestartPolicy  *leaderworkersetv1.RestartPolicyType `json:"restartPolicy,omitempty"`
-	SubGroupPolicy *SubGroupPolicyApplyConfiguration    `json:"subGroupPolicy,omitempty"`
+	LeaderTemplate                       *v1.PodTemplateSpec                                     `json:"leaderTemplate,omitempty"`
+	WorkerTemplate                       *v1.PodTemplateSpec                                     `json:"workerTemplate,omitempty"`
+	WorkerTemplates                      []NamedWorkerTemplateApplyConfiguration                 `json:"workerTemplates,omitempty"`
+	GroupOverrides                       []GroupOverrideApplyConfiguration                       `json:"groupOverrides,omitempty"`
+	VolumeClaimTemplates                 []v1.PersistentVolumeClaim                              `json:"volumeClaimTemplates,omitempty"`
+	PersistentVolumeClaimRetentionPolicy *appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+	ModelPrefetch                        *ModelPrefetchApplyConfiguration                        `json:"modelPrefetch,omitempty"`
+	GroupSpreadConstraints               []GroupSpreadConstraintApplyConfiguration               `json:"groupSpreadConstraints,omitempty"`
+	Size                                 *int32                                                  `json:"size,omitempty"`
+	RestartPolicy                        *apileaderworkersetv1.RestartPolicyType                 `json:"restartPolicy,omitempty"`
+	LeaderHealthPolicy                   *LeaderHealthPolicyApplyConfiguration                   `json:"leaderHealthPolicy,omitempty"`
+	PreemptionPolicy                     *PreemptionPolicyApplyConfiguration                     `json:"preemptionPolicy,omitempty"`
+	PriorityPolicy                       *PriorityPolicyApplyConfiguration                       `json:"priorityPolicy,omitempty"`
+	PropagatePlacement                   *bool                                                   `json:"propagatePlacement,omitempty"`
+	SubGroupPolicy                       *SubGroupPolicyApplyConfiguration                       `json:"subGroupPolicy,omitempty"`
+	EnvVarNaming                         *EnvVarNamingApplyConfiguration                         `json:"envVarNaming,omitempty"`
+	TemplatedEnv                         []TemplatedEnvVarApplyConfiguration                     `json:"templatedEnv,omitempty"`
 }
 
 // LeaderWorkerTemplateApplyConfiguration constructs an declarative configuration of the LeaderWorkerTemplate type for use with
@@ -54,6 +67,71 @@ func (b *LeaderWorkerTemplateApplyConfiguration) WithWorkerTemplate(value v1.Pod
 	return b
 }
 
+// WithWorkerTemplates adds the given value to the WorkerTemplates field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the WorkerTemplates field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithWorkerTemplates(values ...*NamedWorkerTemplateApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithWorkerTemplates")
+		}
+		b.WorkerTemplates = append(b.WorkerTemplates, *values[i])
+	}
+	return b
+}
+
+// WithGroupOverrides adds the given value to the GroupOverrides field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the GroupOverrides field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithGroupOverrides(values ...*GroupOverrideApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroupOverrides")
+		}
+		b.GroupOverrides = append(b.GroupOverrides, *values[i])
+	}
+	return b
+}
+
+// WithVolumeClaimTemplates adds the given value to the VolumeClaimTemplates field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the VolumeClaimTemplates field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithVolumeClaimTemplates(values ...v1.PersistentVolumeClaim) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		b.VolumeClaimTemplates = append(b.VolumeClaimTemplates, values[i])
+	}
+	return b
+}
+
+// WithPersistentVolumeClaimRetentionPolicy sets the PersistentVolumeClaimRetentionPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PersistentVolumeClaimRetentionPolicy field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithPersistentVolumeClaimRetentionPolicy(value appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy) *LeaderWorkerTemplateApplyConfiguration {
+	b.PersistentVolumeClaimRetentionPolicy = &value
+	return b
+}
+
+// WithModelPrefetch sets the ModelPrefetch field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ModelPrefetch field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithModelPrefetch(value *ModelPrefetchApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.ModelPrefetch = value
+	return b
+}
+
+// WithGroupSpreadConstraints adds the given value to the GroupSpreadConstraints field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the GroupSpreadConstraints field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithGroupSpreadConstraints(values ...*GroupSpreadConstraintApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithGroupSpreadConstraints")
+		}
+		b.GroupSpreadConstraints = append(b.GroupSpreadConstraints, *values[i])
+	}
+	return b
+}
+
 // WithSize sets the Size field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Size field is set to the value of the last call.
@@ -65,11 +143,43 @@ func (b *LeaderWorkerTemplateApplyConfiguration) WithSize(value int32) *LeaderWo
 // WithRestartPolicy sets the RestartPolicy field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the RestartPolicy field is set to the value of the last call.
-func (b *LeaderWorkerTemplateApplyConfiguration) WithRestartPolicy(value leaderworkersetv1.RestartPolicyType) *LeaderWorkerTemplateApplyConfiguration {
+func (b *LeaderWorkerTemplateApplyConfiguration) WithRestartPolicy(value apileaderworkersetv1.RestartPolicyType) *LeaderWorkerTemplateApplyConfiguration {
 	b.RestartPolicy = &value
 	return b
 }
 
+// WithLeaderHealthPolicy sets the LeaderHealthPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LeaderHealthPolicy field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithLeaderHealthPolicy(value *LeaderHealthPolicyApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.LeaderHealthPolicy = value
+	return b
+}
+
+// WithPreemptionPolicy sets the PreemptionPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PreemptionPolicy field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithPreemptionPolicy(value *PreemptionPolicyApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.PreemptionPolicy = value
+	return b
+}
+
+// WithPriorityPolicy sets the PriorityPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PriorityPolicy field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithPriorityPolicy(value *PriorityPolicyApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.PriorityPolicy = value
+	return b
+}
+
+// WithPropagatePlacement sets the PropagatePlacement field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PropagatePlacement field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithPropagatePlacement(value bool) *LeaderWorkerTemplateApplyConfiguration {
+	b.PropagatePlacement = &value
+	return b
+}
+
 // WithSubGroupPolicy sets the SubGroupPolicy field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the SubGroupPolicy field is set to the value of the last call.
@@ -77,3 +187,24 @@ func (b *LeaderWorkerTemplateApplyConfiguration) WithSubGroupPolicy(value *SubGr
 	b.SubGroupPolicy = value
 	return b
 }
+
+// WithEnvVarNaming sets the EnvVarNaming field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EnvVarNaming field is set to the value of the last call.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithEnvVarNaming(value *EnvVarNamingApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	b.EnvVarNaming = value
+	return b
+}
+
+// WithTemplatedEnv adds the given value to the TemplatedEnv field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the TemplatedEnv field.
+func (b *LeaderWorkerTemplateApplyConfiguration) WithTemplatedEnv(values ...*TemplatedEnvVarApplyConfiguration) *LeaderWorkerTemplateApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTemplatedEnv")
+		}
+		b.TemplatedEnv = append(b.TemplatedEnv, *values[i])
+	}
+	return b
+}
@@ -26,6 +26,9 @@ import (
 type RolloutStrategyApplyConfiguration struct {
 	Type                       *v1.RolloutStrategyType                       `json:"type,omitempty"`
 	RollingUpdateConfiguration *RollingUpdateConfigurationApplyConfiguration `json:"rollingUpdateConfiguration,omitempty"`
+	CanaryConfiguration        *CanaryConfigurationApplyConfiguration        `json:"canaryConfiguration,omitempty"`
+	Paused                     *bool                                         `json:"paused,omitempty"`
+	Hooks                      *RolloutHooksApplyConfiguration               `json:"hooks,omitempty"`
 }
 
 // RolloutStrategyApplyConfiguration constructs an declarative configuration of the RolloutStrategy type for use with
@@ -49,3 +52,27 @@ func (b *RolloutStrategyApplyConfiguration) WithRollingUpdateConfiguration(value
 	b.RollingUpdateConfiguration = value
 	return b
 }
+
+// WithCanaryConfiguration sets the CanaryConfiguration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CanaryConfiguration field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithCanaryConfiguration(value *CanaryConfigurationApplyConfiguration) *RolloutStrategyApplyConfiguration {
+	b.CanaryConfiguration = value
+	return b
+}
+
+// WithPaused sets the Paused field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Paused field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithPaused(value bool) *RolloutStrategyApplyConfiguration {
+	b.Paused = &value
+	return b
+}
+
+// WithHooks sets the Hooks field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Hooks field is set to the value of the last call.
+func (b *RolloutStrategyApplyConfiguration) WithHooks(value *RolloutHooksApplyConfiguration) *RolloutStrategyApplyConfiguration {
+	b.Hooks = value
+	return b
+}
@@ -28,6 +28,32 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=leaderworkerset.x-k8s.io, Version=v1
+	case v1.SchemeGroupVersion.WithKind("CanaryConfiguration"):
+		return &leaderworkersetv1.CanaryConfigurationApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("CanaryStatus"):
+		return &leaderworkersetv1.CanaryStatusApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("CanaryStep"):
+		return &leaderworkersetv1.CanaryStepApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ContainerOverride"):
+		return &leaderworkersetv1.ContainerOverrideApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("DisruptionBudget"):
+		return &leaderworkersetv1.DisruptionBudgetApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("EnvVarNaming"):
+		return &leaderworkersetv1.EnvVarNamingApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("FailurePolicy"):
+		return &leaderworkersetv1.FailurePolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("FailurePolicyRule"):
+		return &leaderworkersetv1.FailurePolicyRuleApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupOverride"):
+		return &leaderworkersetv1.GroupOverrideApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("GroupSpreadConstraint"):
+		return &leaderworkersetv1.GroupSpreadConstraintApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("HeadlessService"):
+		return &leaderworkersetv1.HeadlessServiceApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("LeaderHealthPolicy"):
+		return &leaderworkersetv1.LeaderHealthPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("LeaderService"):
+		return &leaderworkersetv1.LeaderServiceApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSet"):
 		return &leaderworkersetv1.LeaderWorkerSetApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerSetSpec"):
@@ -36,12 +62,30 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &leaderworkersetv1.LeaderWorkerSetStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("LeaderWorkerTemplate"):
 		return &leaderworkersetv1.LeaderWorkerTemplateApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ModelPrefetch"):
+		return &leaderworkersetv1.ModelPrefetchApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("NamedWorkerTemplate"):
+		return &leaderworkersetv1.NamedWorkerTemplateApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("NetworkConfig"):
+		return &leaderworkersetv1.NetworkConfigApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PreemptionPolicy"):
+		return &leaderworkersetv1.PreemptionPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("PriorityPolicy"):
+		return &leaderworkersetv1.PriorityPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("ReplicaStatus"):
+		return &leaderworkersetv1.ReplicaStatusApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RollingUpdateConfiguration"):
 		return &leaderworkersetv1.RollingUpdateConfigurationApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RolloutHook"):
+		return &leaderworkersetv1.RolloutHookApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("RolloutHooks"):
+		return &leaderworkersetv1.RolloutHooksApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("RolloutStrategy"):
 		return &leaderworkersetv1.RolloutStrategyApplyConfiguration{}
 	case v1.SchemeGroupVersion.WithKind("SubGroupPolicy"):
 		return &leaderworkersetv1.SubGroupPolicyApplyConfiguration{}
+	case v1.SchemeGroupVersion.WithKind("TemplatedEnvVar"):
+		return &leaderworkersetv1.TemplatedEnvVarApplyConfiguration{}
 
 	}
 	return nil
@@ -0,0 +1,199 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/client-go/clientset/versioned"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+)
+
+// options holds the clients shared by the commands, all the commands work
+// through the fields and annotations the controller already reconciles.
+type options struct {
+	namespace  string
+	client     versioned.Interface
+	kubeClient kubernetes.Interface
+	out        io.Writer
+}
+
+// status prints the groups of the lws as a tree, with the phase of every
+// group and the readiness of every pod of the group.
+func (o *options) status(ctx context.Context, name string) error {
+	lws, err := o.client.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	podList, err := o.kubeClient.CoreV1().Pods(o.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{leaderworkerset.SetNameLabelKey: name}).String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// The groups are not always indexed from 0, e.g. after a BlueGreen rollout, so only the observed ones are listed.
+	indexes := sets.New[int]()
+	groups := map[int][]corev1.Pod{}
+	for _, pod := range podList.Items {
+		groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			continue
+		}
+		indexes.Insert(groupIndex)
+		groups[groupIndex] = append(groups[groupIndex], pod)
+	}
+	replicaStatuses := map[int]leaderworkerset.ReplicaStatus{}
+	for _, status := range lws.Status.ReplicaStatuses {
+		indexes.Insert(int(status.Index))
+		replicaStatuses[int(status.Index)] = status
+	}
+
+	fmt.Fprintf(o.out, "%s/%s: %d/%d groups ready, %d updated\n", lws.Namespace, lws.Name,
+		lws.Status.ReadyReplicas, *lws.Spec.Replicas, lws.Status.UpdatedReplicas)
+	sortedIndexes := sets.List(indexes)
+	for n, i := range sortedIndexes {
+		groupBranch, podIndent := "├──", "│   "
+		if n == len(sortedIndexes)-1 {
+			groupBranch, podIndent = "└──", "    "
+		}
+		phase, revision := "Unknown", ""
+		if status, found := replicaStatuses[i]; found {
+			phase = string(status.Phase)
+			if status.Revision != "" {
+				revision = fmt.Sprintf(" (revision %s)", status.Revision)
+			}
+		}
+		fmt.Fprintf(o.out, "%s group %d: %s%s\n", groupBranch, i, phase, revision)
+
+		pods := groups[i]
+		sort.Slice(pods, func(a, b int) bool {
+			indexA, _ := strconv.Atoi(pods[a].Labels[leaderworkerset.WorkerIndexLabelKey])
+			indexB, _ := strconv.Atoi(pods[b].Labels[leaderworkerset.WorkerIndexLabelKey])
+			return indexA < indexB
+		})
+		for j, pod := range pods {
+			podBranch := "├──"
+			if j == len(pods)-1 {
+				podBranch = "└──"
+			}
+			ready := "NotReady"
+			if podutils.PodRunningAndReady(pod) {
+				ready = "Ready"
+			}
+			role := ""
+			if podutils.LeaderPod(pod) {
+				role = " (leader)"
+			}
+			fmt.Fprintf(o.out, "%s%s %s%s: %s %s\n", podIndent, podBranch, pod.Name, role, pod.Status.Phase, ready)
+		}
+	}
+	return nil
+}
+
+// restart sets the restart group annotation, the controller recreates the group and removes the annotation.
+func (o *options) restart(ctx context.Context, name string, group string) error {
+	if _, err := strconv.Atoi(group); err != nil {
+		return fmt.Errorf("invalid group index %q", group)
+	}
+	if err := o.patch(ctx, name, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{leaderworkerset.RestartGroupAnnotationKey: group},
+		},
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "leaderworkerset %s group %s restarted\n", name, group)
+	return nil
+}
+
+// setPaused pauses or resumes the rolling update.
+func (o *options) setPaused(ctx context.Context, name string, paused bool) error {
+	if err := o.patch(ctx, name, map[string]interface{}{
+		"spec": map[string]interface{}{
+			"rolloutStrategy": map[string]interface{}{"paused": paused},
+		},
+	}); err != nil {
+		return err
+	}
+	verb := "resumed"
+	if paused {
+		verb = "paused"
+	}
+	fmt.Fprintf(o.out, "leaderworkerset %s %s\n", name, verb)
+	return nil
+}
+
+// undo sets the rollback annotation to the given revision, or to the previous one when zero.
+func (o *options) undo(ctx context.Context, name string, toRevision int64) error {
+	if toRevision == 0 {
+		revisionList, err := o.kubeClient.AppsV1().ControllerRevisions(o.namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{leaderworkerset.SetNameLabelKey: name}).String(),
+		})
+		if err != nil {
+			return err
+		}
+		revisions := revisionList.Items
+		if len(revisions) < 2 {
+			return fmt.Errorf("no previous revision of leaderworkerset %s to roll back to", name)
+		}
+		sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision > revisions[j].Revision })
+		toRevision = revisions[1].Revision
+	}
+	if err := o.patch(ctx, name, map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{leaderworkerset.RollbackToAnnotationKey: strconv.FormatInt(toRevision, 10)},
+		},
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "leaderworkerset %s rolled back to revision %d\n", name, toRevision)
+	return nil
+}
+
+// scale sets the number of groups.
+func (o *options) scale(ctx context.Context, name string, replicas int32) error {
+	if err := o.patch(ctx, name, map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": replicas},
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(o.out, "leaderworkerset %s scaled to %d\n", name, replicas)
+	return nil
+}
+
+func (o *options) patch(ctx context.Context, name string, patch map[string]interface{}) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = o.client.LeaderworkersetV1().LeaderWorkerSets(o.namespace).Patch(ctx, name, types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
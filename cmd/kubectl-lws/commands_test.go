@@ -0,0 +1,198 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	lwstesting "sigs.k8s.io/lws/pkg/testing"
+)
+
+func newOptions(lws *leaderworkerset.LeaderWorkerSet, objs ...runtime.Object) (*options, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &options{
+		namespace:  lws.Namespace,
+		client:     lwstesting.NewFakeClientset(lws),
+		kubeClient: kubefake.NewSimpleClientset(objs...),
+		out:        out,
+	}, out
+}
+
+func TestStatus(t *testing.T) {
+	lws := lwstesting.BuildLeaderWorkerSet("default").Obj()
+	lws.Status.ReadyReplicas = 1
+	lws.Status.ReplicaStatuses = []leaderworkerset.ReplicaStatus{
+		{Index: 0, Phase: leaderworkerset.ReplicaPhase("Ready"), Revision: "abc"},
+	}
+	var objs []runtime.Object
+	for _, pod := range lwstesting.MakeGroupPods(lws, 0) {
+		pod.Status = corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		}
+		objs = append(objs, pod.DeepCopy())
+	}
+	for _, pod := range lwstesting.MakeGroupPods(lws, 1) {
+		pod.Status.Phase = corev1.PodPending
+		objs = append(objs, pod.DeepCopy())
+	}
+
+	o, out := newOptions(lws, objs...)
+	if err := o.status(context.Background(), lws.Name); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := `default/test-sample: 1/2 groups ready, 0 updated
+├── group 0: Ready (revision abc)
+│   ├── test-sample-0 (leader): Running Ready
+│   └── test-sample-0-1: Running Ready
+└── group 1: Unknown
+    ├── test-sample-1 (leader): Pending NotReady
+    └── test-sample-1-1: Pending NotReady
+`
+	if diff := cmp.Diff(want, out.String()); diff != "" {
+		t.Errorf("Unexpected status (-want +got):\n%s", diff)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	revision := func(number int64) runtime.Object {
+		return &appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("test-sample-%d", number),
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+			},
+			Revision: number,
+		}
+	}
+
+	testCases := []struct {
+		name      string
+		objs      []runtime.Object
+		run       func(*options) error
+		wantErr   bool
+		check     func(*leaderworkerset.LeaderWorkerSet) bool
+		wantWrite string
+	}{
+		{
+			name: "restart sets the restart group annotation",
+			run:  func(o *options) error { return o.restart(context.Background(), "test-sample", "1") },
+			check: func(lws *leaderworkerset.LeaderWorkerSet) bool {
+				return lws.Annotations[leaderworkerset.RestartGroupAnnotationKey] == "1"
+			},
+			wantWrite: "leaderworkerset test-sample group 1 restarted\n",
+		},
+		{
+			name:    "restart rejects an invalid group index",
+			run:     func(o *options) error { return o.restart(context.Background(), "test-sample", "first") },
+			wantErr: true,
+		},
+		{
+			name: "rollout pause",
+			run:  func(o *options) error { return o.setPaused(context.Background(), "test-sample", true) },
+			check: func(lws *leaderworkerset.LeaderWorkerSet) bool {
+				return lws.Spec.RolloutStrategy.Paused
+			},
+			wantWrite: "leaderworkerset test-sample paused\n",
+		},
+		{
+			name: "rollout undo to the previous revision",
+			objs: []runtime.Object{revision(1), revision(3), revision(2)},
+			run:  func(o *options) error { return o.undo(context.Background(), "test-sample", 0) },
+			check: func(lws *leaderworkerset.LeaderWorkerSet) bool {
+				return lws.Annotations[leaderworkerset.RollbackToAnnotationKey] == "2"
+			},
+			wantWrite: "leaderworkerset test-sample rolled back to revision 2\n",
+		},
+		{
+			name: "rollout undo to the given revision",
+			run:  func(o *options) error { return o.undo(context.Background(), "test-sample", 1) },
+			check: func(lws *leaderworkerset.LeaderWorkerSet) bool {
+				return lws.Annotations[leaderworkerset.RollbackToAnnotationKey] == "1"
+			},
+			wantWrite: "leaderworkerset test-sample rolled back to revision 1\n",
+		},
+		{
+			name:    "rollout undo without a previous revision",
+			objs:    []runtime.Object{revision(1)},
+			run:     func(o *options) error { return o.undo(context.Background(), "test-sample", 0) },
+			wantErr: true,
+		},
+		{
+			name: "scale",
+			run:  func(o *options) error { return o.scale(context.Background(), "test-sample", 4) },
+			check: func(lws *leaderworkerset.LeaderWorkerSet) bool {
+				return *lws.Spec.Replicas == 4
+			},
+			wantWrite: "leaderworkerset test-sample scaled to 4\n",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := lwstesting.BuildLeaderWorkerSet("default").Obj()
+			o, out := newOptions(lws, tc.objs...)
+			err := tc.run(o)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Expected error %t, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			got, err := o.client.LeaderworkersetV1().LeaderWorkerSets("default").Get(context.Background(), lws.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !tc.check(got) {
+				t.Errorf("Unexpected leaderworkerset after the command: %+v", got)
+			}
+			if out.String() != tc.wantWrite {
+				t.Errorf("Expected output %q, got %q", tc.wantWrite, out.String())
+			}
+		})
+	}
+}
+
+func TestParseInterspersed(t *testing.T) {
+	var namespace string
+	var replicas int
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&namespace, "n", "", "")
+	fs.IntVar(&replicas, "replicas", 0, "")
+
+	positional, err := parseInterspersed(fs, []string{"scale", "test-sample", "-n", "ns", "--replicas=3"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if diff := cmp.Diff([]string{"scale", "test-sample"}, positional); diff != "" {
+		t.Errorf("Unexpected positional args (-want +got):\n%s", diff)
+	}
+	if namespace != "ns" || replicas != 3 {
+		t.Errorf("Expected namespace ns and replicas 3, got %s and %d", namespace, replicas)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-lws is a kubectl plugin offering day-2 commands over LeaderWorkerSets,
+// install it on the PATH and run it as `kubectl lws <command>`.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/lws/client-go/clientset/versioned"
+)
+
+const usage = `Usage: kubectl lws <command> [flags] <args>
+
+Commands:
+  status <name>                          Print the readiness tree of the groups.
+  restart <name> <group>                 Recreate the group of the given index.
+  rollout pause|resume <name>            Pause or resume the rolling update.
+  rollout undo <name> [--to-revision=N]  Roll back to the previous or the given revision.
+  scale <name> --replicas=N              Set the number of groups.
+
+Flags:
+  -n, --namespace   The namespace of the LeaderWorkerSet, defaults to the one of the current context.
+  --kubeconfig      Path to the kubeconfig file.
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	var (
+		namespace  string
+		kubeconfig string
+		replicas   int
+		toRevision int64
+	)
+	fs := flag.NewFlagSet("kubectl-lws", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	fs.StringVar(&namespace, "namespace", "", "")
+	fs.StringVar(&namespace, "n", "", "")
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "")
+	fs.IntVar(&replicas, "replicas", -1, "")
+	fs.Int64Var(&toRevision, "to-revision", 0, "")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return fmt.Errorf("no command given")
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return err
+		}
+	}
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	o := &options{namespace: namespace, out: os.Stdout}
+	if o.client, err = versioned.NewForConfig(config); err != nil {
+		return err
+	}
+	if o.kubeClient, err = kubernetes.NewForConfig(config); err != nil {
+		return err
+	}
+
+	command, args := positional[0], positional[1:]
+	switch command {
+	case "status":
+		if len(args) != 1 {
+			return fmt.Errorf("status expects the name of the LeaderWorkerSet")
+		}
+		return o.status(ctx, args[0])
+	case "restart":
+		if len(args) != 2 {
+			return fmt.Errorf("restart expects the name of the LeaderWorkerSet and the group index")
+		}
+		return o.restart(ctx, args[0], args[1])
+	case "rollout":
+		if len(args) != 2 {
+			return fmt.Errorf("rollout expects one of pause, resume or undo and the name of the LeaderWorkerSet")
+		}
+		switch args[0] {
+		case "pause":
+			return o.setPaused(ctx, args[1], true)
+		case "resume":
+			return o.setPaused(ctx, args[1], false)
+		case "undo":
+			return o.undo(ctx, args[1], toRevision)
+		}
+		return fmt.Errorf("unknown rollout command %q", args[0])
+	case "scale":
+		if len(args) != 1 || replicas < 0 {
+			return fmt.Errorf("scale expects the name of the LeaderWorkerSet and --replicas")
+		}
+		return o.scale(ctx, args[0], int32(replicas))
+	}
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// parseInterspersed parses the flags wherever they are in the args like kubectl does,
+// the flag package stops at the first positional argument otherwise.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
@@ -17,7 +17,9 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -30,11 +32,18 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
-	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
 	leaderworkersetv1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	leaderworkersetv1alpha2 "sigs.k8s.io/lws/api/leaderworkerset/v1alpha2"
 	"sigs.k8s.io/lws/pkg/cert"
+	"sigs.k8s.io/lws/pkg/config"
 	"sigs.k8s.io/lws/pkg/controllers"
+	"sigs.k8s.io/lws/pkg/debug"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/health"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/tracing"
 	"sigs.k8s.io/lws/pkg/webhooks"
 	//+kubebuilder:scaffold:imports
 )
@@ -48,22 +57,30 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(leaderworkersetv1.AddToScheme(scheme))
+	utilruntime.Must(leaderworkersetv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(configapi.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
+
+	metrics.Register()
 }
 
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
-	var qps float64
-	var burst int
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	flag.Float64Var(&qps, "kube-api-qps", 500, "Maximum QPS to use while talking with Kubernetes API")
-	flag.IntVar(&burst, "kube-api-burst", 500, "Maximum burst for throttle while talking with Kubernetes API")
+	var configFile string
+	var featureGates string
+	var leaderElectionNamespace string
+	var debugBindAddress string
+	flag.StringVar(&configFile, "config", "",
+		"The controller will load its initial configuration from this file. "+
+			"Omit this flag to use the default configuration values.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"A set of key=value pairs that describe feature gates for alpha/experimental features, e.g. FailurePolicy=true,SubGroups=false. "+
+			"It overrides the featureGates of the configuration file.")
+	flag.StringVar(&leaderElectionNamespace, "leader-elect-resource-namespace", "",
+		"The namespace of the resource object used for the leader election, the namespace the manager runs in if unset. "+
+			"It overrides the leaderElection.resourceNamespace of the configuration file.")
+	flag.StringVar(&debugBindAddress, "debug-bind-address", "",
+		"The localhost address the pprof profiles and the expvar variables are served on, e.g. 127.0.0.1:6060, "+
+			"under /debug/pprof/ and /debug/vars. Disabled if unset.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -72,76 +89,132 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	kubeConfig := ctrl.GetConfigOrDie()
-	kubeConfig.QPS = float32(qps)
-	kubeConfig.Burst = burst
-
-	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "b8b2488c.x-k8s.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+	options, cfg, err := config.Load(scheme, configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load the configuration")
+		os.Exit(1)
+	}
+	if leaderElectionNamespace != "" {
+		options.LeaderElectionNamespace = leaderElectionNamespace
+	}
+	if err := features.SetFromMap(cfg.FeatureGates); err != nil {
+		setupLog.Error(err, "unable to set the feature gates")
+		os.Exit(1)
+	}
+	if err := features.Set(featureGates); err != nil {
+		setupLog.Error(err, "unable to set the feature gates")
+		os.Exit(1)
+	}
+	if cfg.Webhook.Pod.Disable && !features.Enabled(features.ControllerSideInjection) {
+		setupLog.Error(fmt.Errorf("webhook.pod.disable requires the %s feature gate", features.ControllerSideInjection), "invalid configuration")
+		os.Exit(1)
+	}
+	metrics.FeatureGatesObserved()
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: *cfg.Tracing.SampleRatio,
 	})
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush the traces")
+		}
+	}()
+
+	kubeConfig := ctrl.GetConfigOrDie()
+	kubeConfig.QPS = *cfg.ClientConnection.QPS
+	kubeConfig.Burst = int(*cfg.ClientConnection.Burst)
 
+	mgr, err := ctrl.NewManager(kubeConfig, options)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if debugBindAddress != "" {
+		debugServer, err := debug.NewServer(debugBindAddress)
+		if err != nil {
+			setupLog.Error(err, "unable to set up the debug server")
+			os.Exit(1)
+		}
+		if err := mgr.Add(debugServer); err != nil {
+			setupLog.Error(err, "unable to add the debug server")
+			os.Exit(1)
+		}
+	}
+
 	certsReady := make(chan struct{})
 
-	if err = cert.CertsManager(mgr, certsReady); err != nil {
-		setupLog.Error(err, "unable to setup cert rotation")
-		os.Exit(1)
+	if *cfg.InternalCertManagement.Enable {
+		if err = cert.CertsManager(mgr, cfg.Webhook.CertDir, certsReady); err != nil {
+			setupLog.Error(err, "unable to setup cert rotation")
+			os.Exit(1)
+		}
+	} else {
+		// The certs are mounted in the certDir and the CA bundles injected by cert-manager.
+		setupLog.Info("internal cert management is disabled, the certs of the webhooks are provisioned externally")
+		close(certsReady)
 	}
 
 	if err := controllers.SetupIndexes(mgr.GetFieldIndexer()); err != nil {
 		setupLog.Error(err, "unable to setup indexes")
 	}
 
+	podController := controllers.NewPodReconciler(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetScheme(), mgr.GetEventRecorderFor("leaderworkerset"))
+	podController.MaxConcurrentGroupRestarts.Store(*cfg.Controller.MaxConcurrentGroupRestarts)
+	podController.PodWebhookDisabled = cfg.Webhook.Pod.Disable
+	podController.RateLimiter = config.NewRateLimiter(cfg.Controller.RateLimiter)
+
 	// Cert won't be ready until manager starts, so start a goroutine here which
 	// will block until the cert is ready before setting up the controllers.
 	// Controllers who register after manager starts will start directly.
-	go setupControllers(mgr, certsReady)
+	go setupControllers(mgr, certsReady, cfg, podController)
+
+	ctx := ctrl.SetupSignalHandler()
+	if configFile != "" {
+		if err := config.Watch(ctx, scheme, configFile, cfg, func(newCfg configapi.Configuration) {
+			reloadConfig(ctx, mgr, certsReady, newCfg, podController)
+		}); err != nil {
+			setupLog.Error(err, "unable to watch the configuration file")
+			os.Exit(1)
+		}
+	}
 
-	setupHealthzAndReadyzCheck(mgr)
+	setupHealthzAndReadyzCheck(mgr, certsReady)
 	setupLog.Info("starting manager")
 
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
+		// os.Exit skips the deferred flush of the traces.
+		_ = shutdownTracing(context.Background())
 		os.Exit(1)
 	}
 
 }
-func setupControllers(mgr ctrl.Manager, certsReady chan struct{}) {
+func setupControllers(mgr ctrl.Manager, certsReady chan struct{}, cfg configapi.Configuration, podController *controllers.PodReconciler) {
 	// The controllers won't work until the webhooks are operating,
 	// and the webhook won't work until the certs are all in places.
 	setupLog.Info("waiting for the cert generation to complete")
 	<-certsReady
 	setupLog.Info("certs ready")
 
-	if err := controllers.NewLeaderWorkerSetReconciler(
+	lwsController := controllers.NewLeaderWorkerSetReconciler(
 		mgr.GetClient(),
 		mgr.GetScheme(),
 		mgr.GetEventRecorderFor("leaderworkerset"),
-	).SetupWithManager(mgr); err != nil {
+	)
+	lwsController.PodWebhookDisabled = cfg.Webhook.Pod.Disable
+	lwsController.RateLimiter = config.NewRateLimiter(cfg.Controller.RateLimiter)
+	if err := lwsController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LeaderWorkerSet")
 		os.Exit(1)
 	}
 	// Set up pod reconciler.
-	podController := controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme())
 	if err := podController.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
@@ -151,22 +224,63 @@ func setupControllers(mgr ctrl.Manager, certsReady chan struct{}) {
 			setupLog.Error(err, "unable to create leaderworkerset webhook", "webhook", "LeaderWorkerSet")
 			os.Exit(1)
 		}
-		if err := webhooks.SetupPodWebhook(mgr); err != nil {
-			setupLog.Error(err, "unable to create pod webhook", "webhook", "LeaderWorkerSet")
-			os.Exit(1)
+		if !cfg.Webhook.Pod.Disable {
+			if err := webhooks.SetupPodWebhook(mgr); err != nil {
+				setupLog.Error(err, "unable to create pod webhook", "webhook", "LeaderWorkerSet")
+				os.Exit(1)
+			}
+			if err := cert.ConfigurePodWebhooks(context.Background(), mgr.GetClient(), mgr.GetAPIReader(), *cfg.Webhook.Pod.FailurePolicy, cfg.Webhook.Pod.NamespaceSelector); err != nil {
+				setupLog.Error(err, "unable to configure pod webhooks", "webhook", "LeaderWorkerSet")
+				os.Exit(1)
+			}
 		}
 	}
 	//+kubebuilder:scaffold:builder
 }
 
-func setupHealthzAndReadyzCheck(mgr ctrl.Manager) {
+// reloadConfig applies the reloadable fields of the configuration, the others take effect on restart.
+func reloadConfig(ctx context.Context, mgr ctrl.Manager, certsReady chan struct{}, cfg configapi.Configuration, podController *controllers.PodReconciler) {
+	podController.MaxConcurrentGroupRestarts.Store(*cfg.Controller.MaxConcurrentGroupRestarts)
+	if cfg.Webhook.Pod.Disable || os.Getenv("ENABLE_WEBHOOKS") == "false" {
+		return
+	}
+	// The pod webhooks are configured by setupControllers until the certs are ready.
+	select {
+	case <-certsReady:
+	default:
+		return
+	}
+	if err := cert.ConfigurePodWebhooks(ctx, mgr.GetClient(), mgr.GetAPIReader(), *cfg.Webhook.Pod.FailurePolicy, cfg.Webhook.Pod.NamespaceSelector); err != nil {
+		setupLog.Error(err, "unable to reconfigure pod webhooks", "webhook", "LeaderWorkerSet")
+	}
+}
+
+func setupHealthzAndReadyzCheck(mgr ctrl.Manager, certsReady chan struct{}) {
 	defer setupLog.Info("both healthz and readyz check are finished and configured")
-	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up health check")
-		os.Exit(1)
+	healthzChecks := map[string]healthz.Checker{
+		"healthz": healthz.Ping,
 	}
-	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
-		setupLog.Error(err, "unable to set up ready check")
-		os.Exit(1)
+	// The replica is only ready once it can serve the admission requests and reconcile, so that
+	// a rollout of the manager stops instead of dropping the requests of the webhooks.
+	readyzChecks := map[string]healthz.Checker{
+		"readyz":     healthz.Ping,
+		"cache-sync": health.CacheSynced(mgr.GetCache()),
+		"crds":       health.CRDsInstalled(mgr.GetAPIReader()),
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		healthzChecks["webhook"] = health.WebhookServerAlive(certsReady, mgr.GetWebhookServer().StartedChecker())
+		readyzChecks["webhook"] = health.WebhookServerStarted(certsReady, mgr.GetWebhookServer().StartedChecker())
+	}
+	for name, check := range healthzChecks {
+		if err := mgr.AddHealthzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up health check", "check", name)
+			os.Exit(1)
+		}
+	}
+	for name, check := range readyzChecks {
+		if err := mgr.AddReadyzCheck(name, check); err != nil {
+			setupLog.Error(err, "unable to set up ready check", "check", name)
+			os.Exit(1)
+		}
 	}
 }
@@ -14,22 +14,31 @@ limitations under the License.
 package cert
 
 import (
+	"context"
 	"fmt"
 
 	cert "github.com/open-policy-agent/cert-controller/pkg/rotator"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	serviceName             = "lws-webhook-service"
 	secretName              = "lws-webhook-server-cert"
 	secretNamespace         = "lws-system"
-	certDir                 = "/tmp/k8s-webhook-server/serving-certs"
 	validateWebhookConfName = "lws-validating-webhook-configuration"
 	mutatingWebhookConfName = "lws-mutating-webhook-configuration"
+	crdName                 = "leaderworkersets.leaderworkerset.x-k8s.io"
 	caName                  = "lws-ca"
 	caOrg                   = "lws"
+	mutatingPodWebhookName  = "mpod.kb.io"
+	validatePodWebhookName  = "vpod.kb.io"
 )
 
 // dnsName is the format of <service name>.<namespace>.svc
@@ -38,9 +47,10 @@ var dnsName = fmt.Sprintf("%s.%s.svc", serviceName, secretNamespace)
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=mutatingwebhookconfigurations,verbs=get;list;watch;update
 //+kubebuilder:rbac:groups="admissionregistration.k8s.io",resources=validatingwebhookconfigurations,verbs=get;list;watch;update
+//+kubebuilder:rbac:groups="apiextensions.k8s.io",resources=customresourcedefinitions,verbs=get;list;watch;update
 
-// CertsManager creates certs for webhooks.
-func CertsManager(mgr ctrl.Manager, setupFinish chan struct{}) error {
+// CertsManager creates certs for webhooks in the certDir the webhook server serves them from.
+func CertsManager(mgr ctrl.Manager, certDir string, setupFinish chan struct{}) error {
 	return cert.AddRotator(mgr, &cert.CertRotator{
 		SecretKey: types.NamespacedName{
 			Namespace: secretNamespace,
@@ -60,6 +70,65 @@ func CertsManager(mgr ctrl.Manager, setupFinish chan struct{}) error {
 				Type: cert.Mutating,
 				Name: mutatingWebhookConfName,
 			},
+			{
+				Type: cert.CRDConversion,
+				Name: crdName,
+			},
 		},
 	})
 }
+
+// ConfigurePodWebhooks sets the failure policy and the namespace selector of the pod webhooks, so that cluster
+// admins can bound the blast radius of the admission of the pods on shared clusters. The leaderworkerset
+// webhooks are left untouched. The webhook configurations are read through the apiReader, since the cert
+// rotator updates them as well and the cache may lag behind, and only updated if changed.
+func ConfigurePodWebhooks(ctx context.Context, c client.Client, apiReader client.Reader, failurePolicy admissionregistrationv1.FailurePolicyType, namespaceSelector *metav1.LabelSelector) error {
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var conf admissionregistrationv1.MutatingWebhookConfiguration
+		if err := apiReader.Get(ctx, types.NamespacedName{Name: mutatingWebhookConfName}, &conf); err != nil {
+			return err
+		}
+		changed := false
+		for i := range conf.Webhooks {
+			if conf.Webhooks[i].Name == mutatingPodWebhookName {
+				changed = configureWebhook(&conf.Webhooks[i].FailurePolicy, &conf.Webhooks[i].NamespaceSelector, failurePolicy, namespaceSelector) || changed
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return c.Update(ctx, &conf)
+	}); err != nil {
+		return err
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var conf admissionregistrationv1.ValidatingWebhookConfiguration
+		if err := apiReader.Get(ctx, types.NamespacedName{Name: validateWebhookConfName}, &conf); err != nil {
+			return err
+		}
+		changed := false
+		for i := range conf.Webhooks {
+			if conf.Webhooks[i].Name == validatePodWebhookName {
+				changed = configureWebhook(&conf.Webhooks[i].FailurePolicy, &conf.Webhooks[i].NamespaceSelector, failurePolicy, namespaceSelector) || changed
+			}
+		}
+		if !changed {
+			return nil
+		}
+		return c.Update(ctx, &conf)
+	})
+}
+
+// configureWebhook sets the failure policy and the namespace selector of a webhook, and returns true if changed.
+// An unset namespace selector matches all the namespaces, which is what the API server defaults it to.
+func configureWebhook(failurePolicy **admissionregistrationv1.FailurePolicyType, namespaceSelector **metav1.LabelSelector, wantFailurePolicy admissionregistrationv1.FailurePolicyType, wantNamespaceSelector *metav1.LabelSelector) bool {
+	if wantNamespaceSelector == nil {
+		wantNamespaceSelector = &metav1.LabelSelector{}
+	}
+	if ptr.Deref(*failurePolicy, "") == wantFailurePolicy && apiequality.Semantic.DeepEqual(ptr.Deref(*namespaceSelector, metav1.LabelSelector{}), *wantNamespaceSelector) {
+		return false
+	}
+	*failurePolicy = &wantFailurePolicy
+	*namespaceSelector = wantNamespaceSelector
+	return true
+}
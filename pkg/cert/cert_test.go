@@ -0,0 +1,93 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cert
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigurePodWebhooks(t *testing.T) {
+	mutating := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: mutatingWebhookConfName},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{Name: "mleaderworkerset.kb.io", FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+			{Name: mutatingPodWebhookName, FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+		},
+	}
+	validating := &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: validateWebhookConfName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{Name: "vleaderworkerset.kb.io", FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+			{Name: validatePodWebhookName, FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(mutating, validating).Build()
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"lws.x-k8s.io/enabled": "true"}}
+
+	if err := ConfigurePodWebhooks(context.Background(), c, c, admissionregistrationv1.Ignore, selector); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var gotMutating admissionregistrationv1.MutatingWebhookConfiguration
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(mutating), &gotMutating); err != nil {
+		t.Fatal(err)
+	}
+	var gotValidating admissionregistrationv1.ValidatingWebhookConfiguration
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(validating), &gotValidating); err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		failurePolicy     admissionregistrationv1.FailurePolicyType
+		namespaceSelector *metav1.LabelSelector
+	}{
+		{failurePolicy: admissionregistrationv1.Fail},
+		{failurePolicy: admissionregistrationv1.Ignore, namespaceSelector: selector},
+	}
+	for i, w := range want {
+		if got := *gotMutating.Webhooks[i].FailurePolicy; got != w.failurePolicy {
+			t.Errorf("Expected failure policy %s of mutating webhook %d, got %s", w.failurePolicy, i, got)
+		}
+		if diff := cmp.Diff(w.namespaceSelector, gotMutating.Webhooks[i].NamespaceSelector); diff != "" {
+			t.Errorf("unexpected namespace selector of mutating webhook %d: (-want, +got) %s", i, diff)
+		}
+		if got := *gotValidating.Webhooks[i].FailurePolicy; got != w.failurePolicy {
+			t.Errorf("Expected failure policy %s of validating webhook %d, got %s", w.failurePolicy, i, got)
+		}
+		if diff := cmp.Diff(w.namespaceSelector, gotValidating.Webhooks[i].NamespaceSelector); diff != "" {
+			t.Errorf("unexpected namespace selector of validating webhook %d: (-want, +got) %s", i, diff)
+		}
+	}
+
+	// The namespace selector is reset to match all the namespaces once unset.
+	if err := ConfigurePodWebhooks(context.Background(), c, c, admissionregistrationv1.Fail, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(mutating), &gotMutating); err != nil {
+		t.Fatal(err)
+	}
+	if got := *gotMutating.Webhooks[1].FailurePolicy; got != admissionregistrationv1.Fail {
+		t.Errorf("Expected failure policy %s of mutating webhook 1, got %s", admissionregistrationv1.Fail, got)
+	}
+	if diff := cmp.Diff(&metav1.LabelSelector{}, gotMutating.Webhooks[1].NamespaceSelector); diff != "" {
+		t.Errorf("unexpected namespace selector of mutating webhook 1: (-want, +got) %s", diff)
+	}
+}
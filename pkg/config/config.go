@@ -0,0 +1,212 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/time/rate"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/util/workqueue"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/config"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// Load returns the manager options and the configuration read from the file, or the
+// defaults when the file is empty.
+func Load(scheme *runtime.Scheme, configFile string) (manager.Options, configapi.Configuration, error) {
+	var cfg configapi.Configuration
+	if configFile == "" {
+		scheme.Default(&cfg)
+	} else if err := fromFile(configFile, scheme, &cfg); err != nil {
+		return manager.Options{}, cfg, err
+	}
+	if errs := validate(&cfg); len(errs) > 0 {
+		return manager.Options{}, cfg, errs.ToAggregate()
+	}
+	return managerOptions(scheme, &cfg), cfg, nil
+}
+
+func fromFile(path string, scheme *runtime.Scheme, cfg *configapi.Configuration) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	codecs := serializer.NewCodecFactory(scheme, serializer.EnableStrict)
+	// The universal decoder defaults the configuration as well.
+	if err := runtime.DecodeInto(codecs.UniversalDecoder(), content, cfg); err != nil {
+		return fmt.Errorf("could not decode file %s: %w", path, err)
+	}
+	return nil
+}
+
+func validate(cfg *configapi.Configuration) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(cfg.Namespaces) > 0 && len(cfg.ExcludeNamespaces) > 0 {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("excludeNamespaces"), "may not be set together with namespaces"))
+	}
+	if cfg.ShardSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cfg.ShardSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("shardSelector"), cfg.ShardSelector, err.Error()))
+		}
+	}
+	podPath := field.NewPath("webhook", "pod")
+	if policy := *cfg.Webhook.Pod.FailurePolicy; policy != admissionregistrationv1.Fail && policy != admissionregistrationv1.Ignore {
+		allErrs = append(allErrs, field.NotSupported(podPath.Child("failurePolicy"), policy,
+			[]string{string(admissionregistrationv1.Fail), string(admissionregistrationv1.Ignore)}))
+	}
+	if cfg.Webhook.Pod.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(cfg.Webhook.Pod.NamespaceSelector); err != nil {
+			allErrs = append(allErrs, field.Invalid(podPath.Child("namespaceSelector"), cfg.Webhook.Pod.NamespaceSelector, err.Error()))
+		}
+	}
+	if ptr.Deref(cfg.LeaderElection.LeaderElect, false) {
+		allErrs = append(allErrs, validateLeaderElection(cfg.LeaderElection)...)
+	}
+	controllerPath := field.NewPath("controller")
+	if restarts := *cfg.Controller.MaxConcurrentGroupRestarts; restarts < 0 {
+		allErrs = append(allErrs, field.Invalid(controllerPath.Child("maxConcurrentGroupRestarts"), restarts, "must be greater than or equal to 0"))
+	}
+	rateLimiterPath := controllerPath.Child("rateLimiter")
+	if limiter := cfg.Controller.RateLimiter; limiter.BaseDelay.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(rateLimiterPath.Child("baseDelay"), limiter.BaseDelay, "must be greater than 0"))
+	} else if limiter.MaxDelay.Duration < limiter.BaseDelay.Duration {
+		allErrs = append(allErrs, field.Invalid(rateLimiterPath.Child("maxDelay"), limiter.MaxDelay, "must be greater than or equal to baseDelay"))
+	}
+	if qps := *cfg.Controller.RateLimiter.QPS; qps <= 0 {
+		allErrs = append(allErrs, field.Invalid(rateLimiterPath.Child("qps"), qps, "must be greater than 0"))
+	}
+	if burst := *cfg.Controller.RateLimiter.Burst; burst <= 0 {
+		allErrs = append(allErrs, field.Invalid(rateLimiterPath.Child("burst"), burst, "must be greater than 0"))
+	}
+	for kind, concurrency := range cfg.Controller.GroupKindConcurrency {
+		if concurrency <= 0 {
+			allErrs = append(allErrs, field.Invalid(controllerPath.Child("groupKindConcurrency").Key(kind), concurrency, "must be greater than 0"))
+		}
+	}
+	clientPath := field.NewPath("clientConnection")
+	if qps := *cfg.ClientConnection.QPS; qps <= 0 {
+		allErrs = append(allErrs, field.Invalid(clientPath.Child("qps"), qps, "must be greater than 0"))
+	}
+	if burst := *cfg.ClientConnection.Burst; burst <= 0 {
+		allErrs = append(allErrs, field.Invalid(clientPath.Child("burst"), burst, "must be greater than 0"))
+	}
+	if ratio := *cfg.Tracing.SampleRatio; ratio < 0 || ratio > 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("tracing", "sampleRatio"), ratio, "must be between 0 and 1"))
+	}
+	return allErrs
+}
+
+// NewRateLimiter returns the workqueue rate limiter of a controller, every controller needs its own.
+func NewRateLimiter(cfg *configapi.RateLimiter) ratelimiter.RateLimiter {
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(cfg.BaseDelay.Duration, cfg.MaxDelay.Duration),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(*cfg.QPS), int(*cfg.Burst))},
+	)
+}
+
+// validateLeaderElection validates the leader election the way the leader elector does on start,
+// so that mistuned timings are reported along the other fields of the configuration.
+func validateLeaderElection(le *configv1alpha1.LeaderElectionConfiguration) field.ErrorList {
+	var allErrs field.ErrorList
+	lePath := field.NewPath("leaderElection")
+	if le.ResourceLock != resourcelock.LeasesResourceLock {
+		allErrs = append(allErrs, field.NotSupported(lePath.Child("resourceLock"), le.ResourceLock, []string{resourcelock.LeasesResourceLock}))
+	}
+	if le.RetryPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(lePath.Child("retryPeriod"), le.RetryPeriod, "must be greater than 0"))
+	}
+	if le.RenewDeadline.Duration <= time.Duration(leaderelection.JitterFactor*float64(le.RetryPeriod.Duration)) {
+		allErrs = append(allErrs, field.Invalid(lePath.Child("renewDeadline"), le.RenewDeadline,
+			fmt.Sprintf("must be greater than %v times retryPeriod", leaderelection.JitterFactor)))
+	}
+	if le.LeaseDuration.Duration <= le.RenewDeadline.Duration {
+		allErrs = append(allErrs, field.Invalid(lePath.Child("leaseDuration"), le.LeaseDuration, "must be greater than renewDeadline"))
+	}
+	return allErrs
+}
+
+func managerOptions(scheme *runtime.Scheme, cfg *configapi.Configuration) manager.Options {
+	options := manager.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsserver.Options{BindAddress: cfg.Metrics.BindAddress},
+		HealthProbeBindAddress: cfg.Health.HealthProbeBindAddress,
+		WebhookServer: webhook.NewServer(webhook.Options{
+			Host:    cfg.Webhook.Host,
+			Port:    *cfg.Webhook.Port,
+			CertDir: cfg.Webhook.CertDir,
+		}),
+		LeaderElection:             ptr.Deref(cfg.LeaderElection.LeaderElect, false),
+		LeaderElectionID:           cfg.LeaderElection.ResourceName,
+		LeaderElectionNamespace:    cfg.LeaderElection.ResourceNamespace,
+		LeaderElectionResourceLock: cfg.LeaderElection.ResourceLock,
+		LeaseDuration:              &cfg.LeaderElection.LeaseDuration.Duration,
+		RenewDeadline:              &cfg.LeaderElection.RenewDeadline.Duration,
+		RetryPeriod:                &cfg.LeaderElection.RetryPeriod.Duration,
+		Controller:                 ctrlconfig.Controller{GroupKindConcurrency: cfg.Controller.GroupKindConcurrency},
+		// The pods and the statefulsets are cached in full, the reconcilers read their specs and statuses
+		// from the same informers, only the fields never read are dropped from the cached objects.
+		Cache: cache.Options{
+			DefaultTransform: stripManagedFields,
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Node{}: {Transform: stripNodeImages},
+			},
+		},
+	}
+	if len(cfg.Namespaces) > 0 {
+		options.Cache.DefaultNamespaces = make(map[string]cache.Config, len(cfg.Namespaces))
+		for _, namespace := range cfg.Namespaces {
+			options.Cache.DefaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	if cfg.ShardSelector != nil {
+		// The LeaderWorkerSets of the other shards are left out of the cache, the reconcilers ignore
+		// their pods and statefulsets as for the deleted LeaderWorkerSets.
+		selector, _ := metav1.LabelSelectorAsSelector(cfg.ShardSelector)
+		options.Cache.ByObject[&leaderworkerset.LeaderWorkerSet{}] = cache.ByObject{Label: selector}
+	}
+	if len(cfg.ExcludeNamespaces) > 0 {
+		selectors := make([]fields.Selector, 0, len(cfg.ExcludeNamespaces))
+		for _, namespace := range cfg.ExcludeNamespaces {
+			selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.namespace", namespace))
+		}
+		options.Cache.DefaultNamespaces = map[string]cache.Config{
+			cache.AllNamespaces: {FieldSelector: fields.AndSelectors(selectors...)},
+		}
+	}
+	return options
+}
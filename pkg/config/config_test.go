@@ -0,0 +1,452 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	configv1alpha1 "k8s.io/component-base/config/v1alpha1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func testScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(configapi.AddToScheme(scheme))
+	return scheme
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func defaultConfiguration() configapi.Configuration {
+	return configapi.Configuration{
+		ControllerManager: configapi.ControllerManager{
+			Webhook: configapi.ControllerWebhook{
+				Port:    ptr.To(configapi.DefaultWebhookPort),
+				CertDir: configapi.DefaultWebhookCertDir,
+				Pod:     configapi.PodWebhook{FailurePolicy: ptr.To(admissionregistrationv1.Fail)},
+			},
+			LeaderElection: &configv1alpha1.LeaderElectionConfiguration{
+				LeaderElect:   ptr.To(false),
+				ResourceName:  configapi.DefaultLeaderElectionID,
+				ResourceLock:  configapi.DefaultResourceLock,
+				LeaseDuration: metav1.Duration{Duration: configapi.DefaultLeaderElectionLeaseDuration},
+				RenewDeadline: metav1.Duration{Duration: configapi.DefaultLeaderElectionRenewDeadline},
+				RetryPeriod:   metav1.Duration{Duration: configapi.DefaultLeaderElectionRetryPeriod},
+			},
+			Metrics: configapi.ControllerMetrics{BindAddress: configapi.DefaultMetricsBindAddress},
+			Health:  configapi.ControllerHealth{HealthProbeBindAddress: configapi.DefaultHealthProbeBindAddress},
+			Controller: &configapi.ControllerConfigurationSpec{
+				GroupKindConcurrency:       map[string]int{"Pod": configapi.DefaultPodConcurrency},
+				MaxConcurrentGroupRestarts: ptr.To[int32](0),
+				RateLimiter: &configapi.RateLimiter{
+					BaseDelay: &metav1.Duration{Duration: configapi.DefaultRateLimiterBaseDelay},
+					MaxDelay:  &metav1.Duration{Duration: configapi.DefaultRateLimiterMaxDelay},
+					QPS:       ptr.To(configapi.DefaultRateLimiterQPS),
+					Burst:     ptr.To(configapi.DefaultRateLimiterBurst),
+				},
+			},
+		},
+		ClientConnection: &configapi.ClientConnection{
+			QPS:   ptr.To(configapi.DefaultClientConnectionQPS),
+			Burst: ptr.To(configapi.DefaultClientConnectionBurst),
+		},
+		Tracing:                &configapi.Tracing{SampleRatio: ptr.To(configapi.DefaultTraceSampleRatio)},
+		InternalCertManagement: &configapi.InternalCertManagement{Enable: ptr.To(true)},
+	}
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+		wantCfg func() configapi.Configuration
+		wantErr bool
+	}{
+		{
+			name:    "no file",
+			wantCfg: defaultConfiguration,
+		},
+		{
+			name: "pod concurrency defaulted along the other kinds",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+controller:
+  groupKindConcurrency:
+    LeaderWorkerSet.leaderworkerset.x-k8s.io: 5
+`,
+			wantCfg: func() configapi.Configuration {
+				cfg := defaultConfiguration()
+				cfg.TypeMeta = metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "Configuration"}
+				cfg.Controller.GroupKindConcurrency = map[string]int{
+					"LeaderWorkerSet.leaderworkerset.x-k8s.io": 5,
+					"Pod": configapi.DefaultPodConcurrency,
+				}
+				return cfg
+			},
+		},
+		{
+			name: "file overriding the defaults",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+namespaces:
+- team-a
+leaderElection:
+  leaderElect: true
+webhook:
+  pod:
+    failurePolicy: Ignore
+controller:
+  groupKindConcurrency:
+    Pod: 5
+  maxConcurrentGroupRestarts: 2
+  rateLimiter:
+    maxDelay: 60s
+    qps: 50
+clientConnection:
+  qps: 50
+featureGates:
+  SomeFeature: true
+`,
+			wantCfg: func() configapi.Configuration {
+				cfg := defaultConfiguration()
+				cfg.TypeMeta = metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "Configuration"}
+				cfg.Namespaces = []string{"team-a"}
+				cfg.LeaderElection.LeaderElect = ptr.To(true)
+				cfg.Webhook.Pod.FailurePolicy = ptr.To(admissionregistrationv1.Ignore)
+				cfg.Controller.GroupKindConcurrency = map[string]int{"Pod": 5}
+				cfg.Controller.MaxConcurrentGroupRestarts = ptr.To[int32](2)
+				cfg.Controller.RateLimiter.MaxDelay = &metav1.Duration{Duration: time.Minute}
+				cfg.Controller.RateLimiter.QPS = ptr.To[float32](50)
+				cfg.ClientConnection.QPS = ptr.To[float32](50)
+				cfg.FeatureGates = map[string]bool{"SomeFeature": true}
+				return cfg
+			},
+		},
+		{
+			name: "unknown field",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+maxConcurrentGroupRestarts: 2
+`,
+			wantErr: true,
+		},
+		{
+			name: "namespaces and excluded namespaces",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+namespaces:
+- team-a
+excludeNamespaces:
+- kube-system
+`,
+			wantErr: true,
+		},
+		{
+			name: "invalid shard selector",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+shardSelector:
+  matchExpressions:
+  - key: shard
+    operator: Equals
+`,
+			wantErr: true,
+		},
+		{
+			name: "leader election tuned for slow api servers",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  leaderElect: true
+  leaseDuration: 60s
+  renewDeadline: 40s
+  retryPeriod: 5s
+  resourceNamespace: lws-system
+`,
+			wantCfg: func() configapi.Configuration {
+				cfg := defaultConfiguration()
+				cfg.TypeMeta = metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "Configuration"}
+				cfg.LeaderElection.LeaderElect = ptr.To(true)
+				cfg.LeaderElection.LeaseDuration = metav1.Duration{Duration: time.Minute}
+				cfg.LeaderElection.RenewDeadline = metav1.Duration{Duration: 40 * time.Second}
+				cfg.LeaderElection.RetryPeriod = metav1.Duration{Duration: 5 * time.Second}
+				cfg.LeaderElection.ResourceNamespace = "lws-system"
+				return cfg
+			},
+		},
+		{
+			name: "certs provisioned by cert-manager",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+internalCertManagement:
+  enable: false
+`,
+			wantCfg: func() configapi.Configuration {
+				cfg := defaultConfiguration()
+				cfg.TypeMeta = metav1.TypeMeta{APIVersion: configapi.GroupVersion.String(), Kind: "Configuration"}
+				cfg.InternalCertManagement.Enable = ptr.To(false)
+				return cfg
+			},
+		},
+		{
+			name: "renew deadline above the lease duration",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  leaderElect: true
+  leaseDuration: 15s
+  renewDeadline: 20s
+`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported resource lock",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+leaderElection:
+  leaderElect: true
+  resourceLock: configmaps
+`,
+			wantErr: true,
+		},
+		{
+			name: "invalid failure policy",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+webhook:
+  pod:
+    failurePolicy: Retry
+`,
+			wantErr: true,
+		},
+		{
+			name: "max delay below the base delay",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+controller:
+  rateLimiter:
+    baseDelay: 1s
+    maxDelay: 100ms
+`,
+			wantErr: true,
+		},
+		{
+			name: "negative group restarts",
+			content: `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+controller:
+  maxConcurrentGroupRestarts: -1
+`,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var configFile string
+			if tc.content != "" {
+				configFile = filepath.Join(t.TempDir(), "config.yaml")
+				writeConfig(t, configFile, tc.content)
+			}
+			_, cfg, err := Load(testScheme(), configFile)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Expected error %t, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tc.wantCfg(), cfg); diff != "" {
+				t.Errorf("Unexpected configuration (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestManagerOptions(t *testing.T) {
+	cfg := defaultConfiguration()
+	cfg.Namespaces = []string{"team-a", "team-b"}
+	cfg.LeaderElection.LeaderElect = ptr.To(true)
+	cfg.Controller.GroupKindConcurrency = map[string]int{"Pod": 5}
+
+	options := managerOptions(testScheme(), &cfg)
+	if !options.LeaderElection || options.LeaderElectionID != configapi.DefaultLeaderElectionID {
+		t.Errorf("Unexpected leader election %t with the id %q", options.LeaderElection, options.LeaderElectionID)
+	}
+	if diff := cmp.Diff(map[string]cache.Config{"team-a": {}, "team-b": {}}, options.Cache.DefaultNamespaces); diff != "" {
+		t.Errorf("Unexpected cache namespaces (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int{"Pod": 5}, options.Controller.GroupKindConcurrency); diff != "" {
+		t.Errorf("Unexpected group kind concurrency (-want +got):\n%s", diff)
+	}
+}
+
+func TestManagerOptionsExcludeNamespaces(t *testing.T) {
+	cfg := defaultConfiguration()
+	cfg.ExcludeNamespaces = []string{"kube-system", "team-b"}
+
+	options := managerOptions(testScheme(), &cfg)
+	if len(options.Cache.DefaultNamespaces) != 1 {
+		t.Fatalf("Unexpected cache namespaces %v", options.Cache.DefaultNamespaces)
+	}
+	selector := options.Cache.DefaultNamespaces[cache.AllNamespaces].FieldSelector
+	if selector == nil {
+		t.Fatal("Expected a field selector for all the namespaces")
+	}
+	for namespace, want := range map[string]bool{"kube-system": false, "team-b": false, "team-a": true} {
+		if got := selector.Matches(fields.Set{"metadata.namespace": namespace}); got != want {
+			t.Errorf("Unexpected match of namespace %q: want %t, got %t", namespace, want, got)
+		}
+	}
+}
+
+func TestManagerOptionsShardSelector(t *testing.T) {
+	cfg := defaultConfiguration()
+	cfg.ShardSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"shard": "a"}}
+
+	options := managerOptions(testScheme(), &cfg)
+	for obj, byObject := range options.Cache.ByObject {
+		if _, ok := obj.(*leaderworkerset.LeaderWorkerSet); !ok {
+			continue
+		}
+		if !byObject.Label.Matches(labels.Set{"shard": "a"}) || byObject.Label.Matches(labels.Set{"shard": "b"}) {
+			t.Errorf("Unexpected label selector of the cached LeaderWorkerSets %q", byObject.Label)
+		}
+		return
+	}
+	t.Error("Expected the cached LeaderWorkerSets filtered by the shard selector")
+}
+
+func TestCacheTransforms(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				lastAppliedConfigAnnotation: "{}",
+				"foo":                       "bar",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}}},
+		Status: corev1.NodeStatus{
+			Images: []corev1.ContainerImage{{Names: []string{"busybox"}}},
+		},
+	}
+	want := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node",
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: corev1.NodeSpec{Taints: []corev1.Taint{{Key: "foo", Effect: corev1.TaintEffectNoSchedule}}},
+	}
+	got, err := stripNodeImages(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected node (-want +got):\n%s", diff)
+	}
+
+	// The last applied configuration is kept on the objects written back by the controllers.
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:          "pod",
+		Annotations:   map[string]string{lastAppliedConfigAnnotation: "{}"},
+		ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+	}}
+	wantPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "pod",
+		Annotations: map[string]string{lastAppliedConfigAnnotation: "{}"},
+	}}
+	gotPod, err := stripManagedFields(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantPod, gotPod); diff != "" {
+		t.Errorf("Unexpected pod (-want +got):\n%s", diff)
+	}
+
+	tombstone := "not an object"
+	if got, err := stripManagedFields(tombstone); err != nil || got != tombstone {
+		t.Errorf("Unexpected transform of a tombstone: %v, %v", got, err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, configFile, `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+`)
+	scheme := testScheme()
+	_, cfg, err := Load(scheme, configFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan configapi.Configuration, 10)
+	if err := Watch(ctx, scheme, configFile, cfg, func(cfg configapi.Configuration) { reloaded <- cfg }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	writeConfig(t, configFile, `apiVersion: config.lws.x-k8s.io/v1alpha1
+kind: Configuration
+controller:
+  maxConcurrentGroupRestarts: 3
+`)
+	select {
+	case cfg := <-reloaded:
+		if got := *cfg.Controller.MaxConcurrentGroupRestarts; got != 3 {
+			t.Errorf("Expected 3 concurrent group restarts, got %d", got)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the configuration to be reloaded")
+	}
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	cfg := defaultConfiguration()
+	cfg.Controller.RateLimiter.BaseDelay = &metav1.Duration{Duration: time.Second}
+	cfg.Controller.RateLimiter.MaxDelay = &metav1.Duration{Duration: 3 * time.Second}
+	limiter := NewRateLimiter(cfg.Controller.RateLimiter)
+
+	var delays []time.Duration
+	for i := 0; i < 3; i++ {
+		delays = append(delays, limiter.When("item"))
+	}
+	if diff := cmp.Diff([]time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, delays); diff != "" {
+		t.Errorf("Unexpected delays (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+)
+
+// lastAppliedConfigAnnotation is set by kubectl apply, it holds a copy of the whole object.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// stripManagedFields is the transform of the cached objects. It drops the managed fields, which are
+// never read by the controllers and often make up the most of the pods and the statefulsets. The
+// managed fields are kept by the server on the updates of the cached objects without them, unlike
+// the annotations, so the last applied configuration is kept since the objects are written back.
+func stripManagedFields(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// Tombstones of the deleted objects are passed through unchanged.
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+	return obj, nil
+}
+
+// stripNodeImages is the transform of the cached nodes, the images on the nodes and the last applied
+// configuration are dropped as well since only the labels, the taints and the conditions of the nodes
+// are read, and the nodes are never written back.
+func stripNodeImages(obj interface{}) (interface{}, error) {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return stripManagedFields(obj)
+	}
+	node.Status.Images = nil
+	delete(node.Annotations, lastAppliedConfigAnnotation)
+	return stripManagedFields(obj)
+}
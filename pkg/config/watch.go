@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	configapi "sigs.k8s.io/lws/api/config/v1alpha1"
+)
+
+// Watch calls onChange with the configuration reloaded from the file every time the file changes,
+// until the ctx is done. Only the fields documented as reloadable are meant to be applied by onChange,
+// the others take effect on the next start of the manager. An invalid file is logged and skipped.
+func Watch(ctx context.Context, scheme *runtime.Scheme, configFile string, current configapi.Configuration, onChange func(configapi.Configuration)) error {
+	log := ctrl.LoggerFrom(ctx).WithValues("config", configFile)
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	// Watch the directory as the kubelet updates the mounted ConfigMaps by swapping a symlink.
+	if err := watcher.Add(filepath.Dir(configFile)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-watcher.Errors:
+				log.Error(err, "Watching the configuration file")
+			case <-watcher.Events:
+				_, cfg, err := Load(scheme, configFile)
+				if err != nil {
+					log.Error(err, "Reloading the configuration file")
+					continue
+				}
+				if equality.Semantic.DeepEqual(cfg, current) {
+					continue
+				}
+				log.Info("Configuration file changed")
+				current = cfg
+				onChange(cfg)
+			}
+		}
+	}()
+	return nil
+}
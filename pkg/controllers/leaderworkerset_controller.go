@@ -18,17 +18,29 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
@@ -37,12 +49,22 @@ import (
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/schedulerprovider"
+	"sigs.k8s.io/lws/pkg/tracing"
 	"sigs.k8s.io/lws/pkg/utils"
+	gatewayutils "sigs.k8s.io/lws/pkg/utils/gateway"
+	hookutils "sigs.k8s.io/lws/pkg/utils/hook"
+	kueueutils "sigs.k8s.io/lws/pkg/utils/kueue"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
@@ -51,6 +73,15 @@ type LeaderWorkerSetReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	Record record.EventRecorder
+	// PodWebhookDisabled stamps the leader statefulsets with what the pod webhook injects into the pods
+	// otherwise, for the clusters which can't run the pod webhook.
+	PodWebhookDisabled bool
+	// RateLimiter is the rate limiter of the workqueue, the default one of controller-runtime if nil.
+	RateLimiter ratelimiter.RateLimiter
+	// updatingGroups tracks the indexes of the groups recreated at a new revision but not ready yet per lws UID,
+	// to tell the update completion apart from the readiness of the group. It's kept in memory only, since
+	// the events are best effort.
+	updatingGroups sync.Map
 }
 
 var (
@@ -58,16 +89,39 @@ var (
 )
 
 const (
-	lwsOwnerKey  = ".metadata.controller"
-	fieldManager = "lws"
+	lwsOwnerKey    = ".metadata.controller"
+	podNodeNameKey = "spec.nodeName"
+	// podGroupKey indexes the pods of the leaderworkerset by "<lws name>/<group index>".
+	podGroupKey = "lws.group"
+	// leaderPodKey indexes the leader pods by the name of their leaderworkerset.
+	leaderPodKey = "lws.leader"
+	// statefulSetLwsKey indexes the leader and the worker statefulsets by the name of their leaderworkerset.
+	statefulSetLwsKey = "lws.name"
+	fieldManager      = "lws"
 )
 
 const (
 	// FailedCreate Event reason used when a resource creation fails.
 	// The event uses the error(s) as the reason.
 	FailedCreate = "FailedCreate"
+
+	// Event reasons of the group lifecycle transitions, the messages start with the group index.
+	GroupCreated         = "GroupCreated"
+	GroupReady           = "GroupReady"
+	GroupRecreated       = "GroupRecreated"
+	GroupUpdateStarted   = "GroupUpdateStarted"
+	GroupUpdateCompleted = "GroupUpdateCompleted"
+	GroupFailed          = "GroupFailed"
 )
 
+// topologyKeyRecheckInterval is the interval to check again whether the exclusive topology label keys
+// exist on the nodes once missing.
+const topologyKeyRecheckInterval = time.Minute
+
+// cleanupRecheckInterval is the interval to check again whether the statefulsets or the worker pods of the
+// groups being deleted are gone, the deletions of the statefulsets trigger a reconciliation as well.
+const cleanupRecheckInterval = 5 * time.Second
+
 func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme, record record.EventRecorder) *LeaderWorkerSetReconciler {
 	return &LeaderWorkerSetReconciler{
 		Client: client,
@@ -82,117 +136,1057 @@ func NewLeaderWorkerSetReconciler(client client.Client, scheme *runtime.Scheme,
 //+kubebuilder:rbac:groups=leaderworkerset.x-k8s.io,resources=leaderworkersets/finalizers,verbs=update
 //+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=kueue.x-k8s.io,resources=workloads,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;update;patch
 //+kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 
-func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *LeaderWorkerSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() {
+		if err != nil {
+			metrics.ReconcileFailed("leaderworkerset")
+		}
+	}()
+	ctx, span := tracing.Start(ctx, "Reconcile LeaderWorkerSet", tracing.ObjectAttributes("LeaderWorkerSet", req.Namespace, req.Name)...)
+	defer func() { tracing.End(span, err) }()
 	// Get leaderworkerset object
 	lws := &leaderworkerset.LeaderWorkerSet{}
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, lws); err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.LeaderWorkerSetDeleted(req.Namespace, req.Name)
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	log := ctrl.LoggerFrom(ctx).WithValues("leaderworkerset", klog.KObj(lws))
-	ctx = ctrl.LoggerInto(ctx, log)
+	defer func() {
+		if err == nil {
+			metrics.LeaderWorkerSetObserved(lws)
+		}
+	}()
+	log := ctrl.LoggerFrom(ctx).WithValues("leaderworkerset", klog.KObj(lws))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	if !lws.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, lws)
+	}
+	// Adding the finalizer updates the lws, which will trigger another reconciliation.
+	if controllerutil.AddFinalizer(lws, leaderworkerset.CleanupFinalizer) {
+		return ctrl.Result{}, r.Update(ctx, lws)
+	}
+
+	// Rolling back updates the lws, which will trigger another reconciliation.
+	rolledBack, err := r.rollbackIfRequested(ctx, lws)
+	if err != nil || rolledBack {
+		return ctrl.Result{}, err
+	}
+	// So does restarting a group, to remove the annotation.
+	restarted, err := r.restartGroupIfRequested(ctx, lws)
+	if err != nil || restarted {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncRevisions(ctx, lws); err != nil {
+		log.Error(err, "Syncing controller revisions")
+		return ctrl.Result{}, err
+	}
+
+	// No group is created anymore once the lws is finished.
+	if leaderWorkerSetFinished(lws) {
+		if err := r.finish(ctx, lws); err != nil {
+			log.Error(err, "Finishing leaderworkerset")
+			return ctrl.Result{}, err
+		}
+		expired, requeueAfter := ttlAfterFinished(lws, time.Now())
+		if expired {
+			log.V(2).Info("Deleting leaderworkerset since the TTL after finished expired")
+			return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, lws, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+		}
+		log.V(2).Info("Leader Reconcile completed, leaderworkerset is finished.")
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	// Queued lws creates no group until the Kueue Workload is admitted, the admission is tracked by the
+	// Admitted condition so that spec.suspend is left to the user.
+	admitted := true
+	var admittedReplicas *int32
+	if kueueutils.QueueName(lws) != "" {
+		if admitted, admittedReplicas, err = r.reconcileWorkload(ctx, lws); err != nil {
+			log.Error(err, "Reconciling kueue workload")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if ptr.Deref(lws.Spec.Suspend, false) || !admitted {
+		if err := r.suspend(ctx, lws); err != nil {
+			log.Error(err, "Suspending leaderworkerset")
+			return ctrl.Result{}, err
+		}
+		log.V(2).Info("Leader Reconcile completed, leaderworkerset is suspended.")
+		return ctrl.Result{}, nil
+	}
+
+	start, partition, replicas, err := r.rollingUpdateParameters(ctx, lws)
+	if err != nil {
+		log.Error(err, "Rolling partition error")
+		return ctrl.Result{}, err
+	}
+	// The groups beyond the admitted Workload are not created until Kueue admits the resize.
+	if admittedReplicas != nil && replicas > *admittedReplicas {
+		log.V(2).Info("Waiting for kueue to admit the resized workload", "replicas", replicas, "admittedReplicas", *admittedReplicas)
+		replicas = *admittedReplicas
+	}
+	// The groups about to be deleted are kept until drained.
+	drainStart, drainPartition, drainReplicas, drainAfter, err := r.drainGroups(ctx, lws, start, partition, replicas)
+	if err != nil {
+		log.Error(err, "Draining the groups about to be deleted")
+		return ctrl.Result{}, err
+	}
+	if drainAfter > 0 {
+		log.V(2).Info("Draining the groups about to be deleted", "start", start, "partition", partition, "replicas", replicas)
+		start, partition, replicas = drainStart, drainPartition, drainReplicas
+	}
+	// The groups removed by scaling down are kept until their workers are gone with the WorkersFirst termination order.
+	teardownStart, teardownReplicas, err := r.tearDownWorkers(ctx, lws, start, replicas)
+	if err != nil {
+		log.Error(err, "Tearing down the workers of the removed groups")
+		return ctrl.Result{}, err
+	}
+	teardownPending := teardownStart != start || teardownReplicas != replicas
+	if teardownPending {
+		log.V(2).Info("Waiting for the workers of the removed groups to be deleted", "start", start, "replicas", replicas)
+		start, replicas = teardownStart, teardownReplicas
+	}
+
+	if err := r.SSAWithStatefulset(ctx, lws, start, partition, replicas); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Create headless service if it does not exist, the headless services are created per group
+	// by the pod controller with the UniquePerReplica subdomain policy.
+	if utils.SubdomainPolicy(lws) == leaderworkerset.SubdomainShared && !headlessServiceDisabled(lws) {
+		if err := reconcileHeadlessService(ctx, r.Client, r.Scheme, lws, lws, lws.Name, map[string]string{
+			leaderworkerset.SetNameLabelKey: lws.Name,
+		}); err != nil {
+			log.Error(err, "Creating headless service.")
+			r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate,
+				fmt.Sprintf("Failed to create headless service for error: %v", err))
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.reconcileLeaderService(ctx, lws); err != nil {
+		log.Error(err, "Reconciling leader service")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcileCanaryTraffic(ctx, lws); err != nil {
+		log.Error(err, "Reconciling canary traffic")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.reconcilePodDisruptionBudget(ctx, lws); err != nil {
+		log.Error(err, "Reconciling pod disruption budget")
+		return ctrl.Result{}, err
+	}
+
+	err = r.updateStatus(ctx, lws)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	log.V(2).Info("Leader Reconcile completed.")
+	// Requeue to check whether the rolling update exceeds the progress deadline.
+	_, requeueAfter := progressDeadline(lws, time.Now())
+	// Requeue to move to the next step once the pause of the canary step is over.
+	if canary := lws.Status.Canary; canary != nil && lws.Spec.RolloutStrategy.CanaryConfiguration != nil && int(canary.Step) < len(lws.Spec.RolloutStrategy.CanaryConfiguration.Steps) {
+		if pause := canaryPauseRemaining(lws.Spec.RolloutStrategy.CanaryConfiguration.Steps[canary.Step], canary.StepStartTime, time.Now()); pause > 0 && (requeueAfter == 0 || pause < requeueAfter) {
+			requeueAfter = pause
+		}
+	}
+	// Requeue to check whether the ready groups become available.
+	if lws.Status.AvailableReplicas < lws.Status.ReadyReplicas {
+		minReadyDuration := time.Duration(lws.Spec.MinReadySeconds) * time.Second
+		if requeueAfter == 0 || minReadyDuration < requeueAfter {
+			requeueAfter = minReadyDuration
+		}
+	}
+	// Requeue to check whether the nodes with the exclusive topology label keys are added.
+	if meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetTopologyKeyMissing)) && (requeueAfter == 0 || topologyKeyRecheckInterval < requeueAfter) {
+		requeueAfter = topologyKeyRecheckInterval
+	}
+	// Requeue to delete the groups once drained.
+	if drainAfter > 0 && (requeueAfter == 0 || drainAfter < requeueAfter) {
+		requeueAfter = drainAfter
+	}
+	// Requeue to scale down once the workers of the removed groups are gone.
+	if teardownPending && (requeueAfter == 0 || cleanupRecheckInterval < requeueAfter) {
+		requeueAfter = cleanupRecheckInterval
+	}
+	if requeueAfter > 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// finalize cleans up what the garbage collector may leave behind once the lws is removed: the backendRefs of
+// the canary HTTPRoute to the services of the lws, which the lws doesn't own, and the headless services, the
+// PodGroups, the PodDisruptionBudget and the statefulsets of the groups, e.g. those whose owner references were
+// removed. The finalizer is removed once the statefulsets are gone.
+func (r *LeaderWorkerSetReconciler) finalize(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(lws, leaderworkerset.CleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	if err := r.detachCanaryTraffic(ctx, lws); err != nil {
+		log.Error(err, "Detaching the canary traffic")
+		return ctrl.Result{}, err
+	}
+
+	var leaders corev1.PodList
+	if err := r.List(ctx, &leaders, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	leaderUIDs := sets.New[types.UID]()
+	for i := range leaders.Items {
+		leaderUIDs.Insert(leaders.Items[i].UID)
+	}
+	var services corev1.ServiceList
+	if err := r.List(ctx, &services, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range services.Items {
+		if !ownedByLeaderWorkerSet(&services.Items[i], lws, leaderUIDs) {
+			continue
+		}
+		if err := r.Delete(ctx, &services.Items[i]); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if providerType, found := lws.Annotations[leaderworkerset.GangSchedulingAnnotationKey]; found {
+		provider, err := schedulerprovider.NewSchedulerProvider(schedulerprovider.ProviderType(providerType))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := provider.DeletePodGroups(ctx, r.Client, lws); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	var pdb policyv1.PodDisruptionBudget
+	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &pdb); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	} else if err == nil && metav1.IsControlledBy(&pdb, lws) {
+		if err := r.Delete(ctx, &pdb); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var stsList appsv1.StatefulSetList
+	if err := r.List(ctx, &stsList, client.InNamespace(lws.Namespace), client.MatchingFields{statefulSetLwsKey: lws.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	workersGone := true
+	if lws.Spec.TerminationOrder == leaderworkerset.WorkersFirstTerminationOrder {
+		var err error
+		if workersGone, err = r.workerPodsGone(ctx, lws, client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	for i := range stsList.Items {
+		// The leader statefulset is deleted once the worker pods are gone with the WorkersFirst termination order.
+		if stsList.Items[i].DeletionTimestamp != nil || (stsList.Items[i].Name == lws.Name && !workersGone) {
+			continue
+		}
+		if err := r.Delete(ctx, &stsList.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	if len(stsList.Items) > 0 {
+		log.V(2).Info("Waiting for the statefulsets of the groups to be deleted", "statefulsets", len(stsList.Items))
+		return ctrl.Result{RequeueAfter: cleanupRecheckInterval}, nil
+	}
+
+	controllerutil.RemoveFinalizer(lws, leaderworkerset.CleanupFinalizer)
+	return ctrl.Result{}, r.Update(ctx, lws)
+}
+
+// drainGroups sets the serving condition of the leader pods of the groups about to be deleted to false, either
+// removed by scaling the groups [start, start+replicas) down or recreated by the rollout lowering the partition, so
+// that the load balancers stop sending requests to them first. It returns the start, the partition and the replicas
+// keeping the groups not drained for the drainSeconds yet, and the time until the next group is drained. The leader
+// pods of the groups not deleted anymore, e.g. scaled up again in the meantime, are set serving again.
+func (r *LeaderWorkerSetReconciler) drainGroups(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, partition, replicas int32) (int32, int32, int32, time.Duration, error) {
+	if lws.Spec.DrainSeconds == nil {
+		return start, partition, replicas, 0, nil
+	}
+	var leaders corev1.PodList
+	if err := r.List(ctx, &leaders, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+		return 0, 0, 0, 0, err
+	}
+	drainDuration := time.Duration(*lws.Spec.DrainSeconds) * time.Second
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	// The outdated leader pods are only recreated by the leader statefulset above the partition with the rolling updates,
+	// the leader statefulset is updated on delete with BlueGreen.
+	recreatedAbovePartition := lws.Spec.RolloutStrategy.Type != leaderworkerset.OnDeleteStrategyType && lws.Spec.RolloutStrategy.Type != leaderworkerset.BlueGreenStrategyType
+	now := time.Now()
+	drainStart, drainPartition, drainEnd := start, partition, start+replicas
+	var drainAfter time.Duration
+	for i := range leaders.Items {
+		leader := &leaders.Items[i]
+		if leader.DeletionTimestamp != nil {
+			continue
+		}
+		groupIndex, err := strconv.Atoi(leader.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		removed := int32(groupIndex) < start || int32(groupIndex) >= start+replicas
+		recreated := recreatedAbovePartition && int32(groupIndex) >= partition && leader.Labels[leaderworkerset.TemplateRevisionHashKey] != templateHash
+		remaining, err := r.drainLeaderPod(ctx, leader, removed || recreated, drainDuration, now)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		if remaining <= 0 {
+			continue
+		}
+		if removed {
+			drainStart = min(drainStart, int32(groupIndex))
+			drainEnd = max(drainEnd, int32(groupIndex)+1)
+		} else {
+			drainPartition = max(drainPartition, int32(groupIndex)+1)
+		}
+		if drainAfter == 0 || remaining < drainAfter {
+			drainAfter = remaining
+		}
+	}
+	return drainStart, drainPartition, drainEnd - drainStart, drainAfter, nil
+}
+
+// drainLeaderPod sets the serving condition of the leader pod to false to drain the group, or to true otherwise.
+// It returns the remaining time to drain the group.
+func (r *LeaderWorkerSetReconciler) drainLeaderPod(ctx context.Context, leader *corev1.Pod, drain bool, drainDuration time.Duration, now time.Time) (time.Duration, error) {
+	status, reason := corev1.ConditionTrue, "Serving"
+	if drain {
+		status, reason = corev1.ConditionFalse, "Draining"
+	}
+	if podutils.SetPodCondition(leader, leaderworkerset.ServingConditionType, status, reason) {
+		ctrl.LoggerFrom(ctx).V(2).Info("Setting the serving condition of the leader pod", "pod", klog.KObj(leader), "status", status)
+		if err := r.Status().Update(ctx, leader); err != nil {
+			return 0, err
+		}
+	}
+	if !drain {
+		return 0, nil
+	}
+	condition := podutils.GetPodCondition(*leader, leaderworkerset.ServingConditionType)
+	return condition.LastTransitionTime.Add(drainDuration).Sub(now), nil
+}
+
+// tearDownWorkers deletes the worker statefulsets of the groups removed by scaling the leader statefulset down to
+// the groups [start, start+replicas) with the WorkersFirst termination order, and marks their leader pods with the
+// teardown annotation so that the worker statefulsets are not created again. It returns the start and the replicas
+// the leader statefulset can be scaled down to, i.e. the groups whose worker pods are not gone yet are kept. The
+// annotation is removed from the leader pods of the groups kept otherwise, e.g. scaled up again in the meantime.
+func (r *LeaderWorkerSetReconciler) tearDownWorkers(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, replicas int32) (int32, int32, error) {
+	var leaders corev1.PodList
+	if err := r.List(ctx, &leaders, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+		return 0, 0, err
+	}
+	teardownStart, teardownEnd := start, start+replicas
+	for i := range leaders.Items {
+		leader := &leaders.Items[i]
+		groupIndex, err := strconv.Atoi(leader.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			return 0, 0, err
+		}
+		removed := int32(groupIndex) < start || int32(groupIndex) >= start+replicas
+		teardown := lws.Spec.TerminationOrder == leaderworkerset.WorkersFirstTerminationOrder && removed && leader.DeletionTimestamp == nil
+		if err := r.setTeardownAnnotation(ctx, leader, teardown); err != nil {
+			return 0, 0, err
+		}
+		if !teardown {
+			continue
+		}
+		var sts appsv1.StatefulSet
+		err = r.Get(ctx, types.NamespacedName{Name: leader.Name, Namespace: leader.Namespace}, &sts)
+		if client.IgnoreNotFound(err) != nil {
+			return 0, 0, err
+		}
+		if err == nil && sts.DeletionTimestamp == nil {
+			if err := r.Delete(ctx, &sts, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+				return 0, 0, err
+			}
+		}
+		gone, err := r.workerPodsGone(ctx, lws, client.MatchingFields{podGroupKey: groupIndexValue(lws.Name, leader.Labels[leaderworkerset.GroupIndexLabelKey])})
+		if err != nil {
+			return 0, 0, err
+		}
+		if !gone {
+			teardownStart = min(teardownStart, int32(groupIndex))
+			teardownEnd = max(teardownEnd, int32(groupIndex)+1)
+		}
+	}
+	return teardownStart, teardownEnd - teardownStart, nil
+}
+
+func (r *LeaderWorkerSetReconciler) setTeardownAnnotation(ctx context.Context, leaderPod *corev1.Pod, teardown bool) error {
+	if _, annotated := leaderPod.Annotations[leaderworkerset.TeardownAnnotationKey]; annotated == teardown {
+		return nil
+	}
+	patch := client.MergeFrom(leaderPod.DeepCopy())
+	if teardown {
+		if leaderPod.Annotations == nil {
+			leaderPod.Annotations = map[string]string{}
+		}
+		leaderPod.Annotations[leaderworkerset.TeardownAnnotationKey] = "true"
+	} else {
+		delete(leaderPod.Annotations, leaderworkerset.TeardownAnnotationKey)
+	}
+	return r.Patch(ctx, leaderPod, patch)
+}
+
+// workerPodsGone returns true if no worker pod of the lws matching the list options is left, including the
+// terminating ones.
+func (r *LeaderWorkerSetReconciler) workerPodsGone(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, opts ...client.ListOption) (bool, error) {
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods, append(opts, client.InNamespace(lws.Namespace))...); err != nil {
+		return false, err
+	}
+	for i := range pods.Items {
+		if !podutils.LeaderPod(pods.Items[i]) && !podutils.SkipInjection(pods.Items[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ownedByLeaderWorkerSet returns true if the object is controlled by the lws or by one of its leader pods,
+// given by their UIDs, like the headless services of the groups with the UniquePerReplica subdomain policy.
+func ownedByLeaderWorkerSet(obj metav1.Object, lws *leaderworkerset.LeaderWorkerSet, leaderUIDs sets.Set[types.UID]) bool {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil {
+		return false
+	}
+	switch owner.Kind {
+	case "LeaderWorkerSet":
+		return owner.APIVersion == apiGVStr && owner.UID == lws.UID
+	case "Pod":
+		return owner.APIVersion == "v1" && leaderUIDs.Has(owner.UID)
+	}
+	return false
+}
+
+// detachCanaryTraffic removes the backendRefs to the canary services of the lws from the HTTPRoute of the
+// canary rollout, the HTTPRoute isn't owned by the lws and would otherwise route to the deleted services.
+func (r *LeaderWorkerSetReconciler) detachCanaryTraffic(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	config := lws.Spec.RolloutStrategy.CanaryConfiguration
+	if config == nil || config.HTTPRoute == "" {
+		return nil
+	}
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gatewayutils.HTTPRouteGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: config.HTTPRoute, Namespace: lws.Namespace}, route); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	changed, err := gatewayutils.RemoveBackendRefs(route, canaryServiceName(lws, "stable"), canaryServiceName(lws, "canary"))
+	if err != nil || !changed {
+		return err
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Removing the backendRefs of the canary services from the HTTPRoute", "httpRoute", config.HTTPRoute)
+	return r.Update(ctx, route)
+}
+
+// reconcileHeadlessService applies the headless service selecting the given pods, owned by the owner,
+// the customizable fields of the headless service are kept in sync with the lws afterwards.
+func reconcileHeadlessService(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, owner metav1.Object, serviceName string, selector map[string]string) error {
+	log := ctrl.LoggerFrom(ctx)
+	var headlessService corev1.Service
+	err := k8sClient.Get(ctx, types.NamespacedName{Name: serviceName, Namespace: owner.GetNamespace()}, &headlessService)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	if err == nil && !metav1.IsControlledBy(&headlessService, owner) {
+		return nil
+	}
+	log.V(2).Info("Applying headless service.", "service", serviceName)
+	return applyService(ctx, k8sClient, scheme, owner, constructHeadlessService(lws, serviceName, owner.GetNamespace(), selector))
+}
+
+// applyService applies the desired Service owned by the owner with server-side apply, so that lws only owns
+// and updates the fields it sets. The fields set by others, e.g. the annotations of the cloud providers,
+// the node ports or the IP families defaulted by the apiserver, are kept without conflicts.
+func applyService(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, owner metav1.Object, desired *corev1.Service) error {
+	service := desired.DeepCopy()
+	service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+	if err := ctrl.SetControllerReference(owner, service, scheme); err != nil {
+		return err
+	}
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(service)
+	if err != nil {
+		return err
+	}
+	patch := &unstructured.Unstructured{Object: obj}
+	// The zero status and creation timestamp of the typed Service are not part of the desired state.
+	unstructured.RemoveNestedField(patch.Object, "status")
+	unstructured.RemoveNestedField(patch.Object, "metadata", "creationTimestamp")
+	return k8sClient.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        ptr.To(true),
+	})
+}
+
+// headlessServiceDisabled returns true if the lws opts out of the creation of the headless services.
+func headlessServiceDisabled(lws *leaderworkerset.LeaderWorkerSet) bool {
+	return lws.Spec.NetworkConfig != nil && lws.Spec.NetworkConfig.HeadlessService != nil && lws.Spec.NetworkConfig.HeadlessService.Disabled
+}
+
+// constructHeadlessService returns the headless service selecting the given pods, customized per the lws.
+// The service is labeled with the lws name, so that it's found when the lws is finalized.
+func constructHeadlessService(lws *leaderworkerset.LeaderWorkerSet, serviceName, namespace string, selector map[string]string) *corev1.Service {
+	headlessService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: namespace,
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lws.Name},
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP:                "None", // defines service as headless
+			Selector:                 selector,
+			PublishNotReadyAddresses: true,
+		},
+	}
+	setIPFamilies(headlessService, lws)
+	if lws.Spec.NetworkConfig == nil || lws.Spec.NetworkConfig.HeadlessService == nil {
+		return headlessService
+	}
+	config := lws.Spec.NetworkConfig.HeadlessService
+	for k, v := range config.Labels {
+		if k != leaderworkerset.SetNameLabelKey {
+			headlessService.Labels[k] = v
+		}
+	}
+	headlessService.Annotations = config.Annotations
+	headlessService.Spec.PublishNotReadyAddresses = ptr.Deref(config.PublishNotReadyAddresses, true)
+	headlessService.Spec.Ports = defaultServicePorts(config.Ports)
+	return headlessService
+}
+
+// reconcileLeaderService applies the Service selecting the leader pods of the ready groups,
+// the Service is deleted once the leaderService is removed from the spec.
+func (r *LeaderWorkerSetReconciler) reconcileLeaderService(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	log := ctrl.LoggerFrom(ctx)
+	var service corev1.Service
+	err := r.Get(ctx, types.NamespacedName{Name: leaderServiceName(lws), Namespace: lws.Namespace}, &service)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	exists := err == nil
+
+	if lws.Spec.LeaderService == nil {
+		if exists && metav1.IsControlledBy(&service, lws) {
+			log.V(2).Info("Deleting leader service")
+			return client.IgnoreNotFound(r.Delete(ctx, &service))
+		}
+		return nil
+	}
+
+	desired := constructLeaderService(lws)
+	if lws.Spec.RolloutStrategy.Type == leaderworkerset.BlueGreenStrategyType {
+		servingHash, err := r.blueGreenServingHash(ctx, lws, service.Spec.Selector[leaderworkerset.TemplateRevisionHashKey])
+		if err != nil {
+			return err
+		}
+		desired.Spec.Selector[leaderworkerset.TemplateRevisionHashKey] = servingHash
+	}
+	log.V(2).Info("Applying leader service")
+	return applyService(ctx, r.Client, r.Scheme, lws, desired)
+}
+
+// reconcilePodDisruptionBudget creates or updates the PodDisruptionBudget covering the leader pods,
+// or deletes it once the disruption budget is removed from the spec.
+func (r *LeaderWorkerSetReconciler) reconcilePodDisruptionBudget(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	log := ctrl.LoggerFrom(ctx)
+	var pdb policyv1.PodDisruptionBudget
+	err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &pdb)
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	exists := err == nil
+
+	if lws.Spec.DisruptionBudget == nil {
+		if exists && metav1.IsControlledBy(&pdb, lws) {
+			log.V(2).Info("Deleting pod disruption budget")
+			return client.IgnoreNotFound(r.Delete(ctx, &pdb))
+		}
+		return nil
+	}
+
+	desired := constructPodDisruptionBudget(lws)
+	if !exists {
+		if err := ctrl.SetControllerReference(lws, desired, r.Scheme); err != nil {
+			return err
+		}
+		log.V(2).Info("Creating pod disruption budget")
+		return r.Create(ctx, desired)
+	}
+	if equality.Semantic.DeepEqual(pdb.Spec, desired.Spec) {
+		return nil
+	}
+	pdb.Spec = desired.Spec
+	log.V(2).Info("Updating pod disruption budget")
+	return r.Update(ctx, &pdb)
+}
+
+// setGroupReadyLabel labels the leader pod of a ready group so that it's selected by the leader service.
+func (r *LeaderWorkerSetReconciler) setGroupReadyLabel(ctx context.Context, leaderPod *corev1.Pod, ready bool) error {
+	if _, labeled := leaderPod.Labels[leaderworkerset.GroupReadyLabelKey]; labeled == ready {
+		return nil
+	}
+	patch := client.MergeFrom(leaderPod.DeepCopy())
+	if ready {
+		leaderPod.Labels[leaderworkerset.GroupReadyLabelKey] = "true"
+	} else {
+		delete(leaderPod.Labels, leaderworkerset.GroupReadyLabelKey)
+	}
+	return r.Patch(ctx, leaderPod, patch)
+}
+
+// servingGroups returns the groups selected by the leader service, which are the ready groups except the warm
+// ones, e.g. the bursted groups are selected as well. A ready warm group is selected in place of each group below
+// the replicas not ready, from the lowest index. The groups are positioned from the start.
+func servingGroups(lws *leaderworkerset.LeaderWorkerSet, start int, readyGroups map[int]bool) map[int]bool {
+	replicas, totalReplicas := start+int(*lws.Spec.Replicas), start+int(utils.TotalReplicas(lws))
+	serving := map[int]bool{}
+	unready := 0
+	for index := start; index < replicas; index++ {
+		if !readyGroups[index] {
+			unready++
+		}
+	}
+	for index, ready := range readyGroups {
+		if !ready {
+			continue
+		}
+		if index < replicas || index >= totalReplicas {
+			serving[index] = true
+		}
+	}
+	for index := replicas; index < totalReplicas && unready > 0; index++ {
+		if readyGroups[index] {
+			serving[index] = true
+			unready--
+		}
+	}
+	return serving
+}
+
+// deleteLeaderStatefulSet deletes the leader statefulset if exists, the worker statefulsets and pods will be
+// garbage collected together. An event of the given reason is recorded once deleted.
+func (r *LeaderWorkerSetReconciler) deleteLeaderStatefulSet(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, reason, message string) error {
+	var sts appsv1.StatefulSet
+	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &sts); err != nil || sts.DeletionTimestamp != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := r.Delete(ctx, &sts, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info(message)
+	r.Record.Eventf(lws, corev1.EventTypeNormal, reason, message)
+	return nil
+}
+
+// suspend deletes the leader statefulset, the worker statefulsets and pods will be garbage collected
+// together. The status is reset since there are no groups anymore.
+func (r *LeaderWorkerSetReconciler) suspend(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	if err := r.deleteLeaderStatefulSet(ctx, lws, "Suspended", "Deleted leader statefulset for suspension"); err != nil {
+		return err
+	}
+
+	updateStatus := lws.Status.Replicas != 0 || lws.Status.ReadyReplicas != 0 || lws.Status.AvailableReplicas != 0 || lws.Status.UpdatedReplicas != 0 ||
+		len(lws.Status.ReplicaStatuses) != 0 || lws.Status.LastProgressTime != nil
+	lws.Status.Replicas = 0
+	lws.Status.ReadyReplicas = 0
+	lws.Status.AvailableReplicas = 0
+	lws.Status.UpdatedReplicas = 0
+	lws.Status.ReplicaStatuses = nil
+	lws.Status.LastProgressTime = nil
+
+	conditions := []metav1.Condition{makeCondition(leaderworkerset.LeaderWorkerSetSuspended)}
+	for _, conditionType := range []leaderworkerset.LeaderWorkerSetConditionType{leaderworkerset.LeaderWorkerSetAvailable, leaderworkerset.LeaderWorkerSetProgressing, leaderworkerset.LeaderWorkerSetUpgradeInProgress} {
+		condition := makeCondition(conditionType)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Suspended"
+		condition.Message = "LeaderWorkerSet is suspended"
+		conditions = append(conditions, condition)
+	}
+	if setConditions(lws, conditions) || updateStatus {
+		return r.Status().Update(ctx, lws)
+	}
+	return nil
+}
+
+// leaderWorkerSetFinished returns true if all the groups are finished in the run-to-completion mode.
+func leaderWorkerSetFinished(lws *leaderworkerset.LeaderWorkerSet) bool {
+	return lws.Spec.TerminationPolicy == leaderworkerset.LeaderSucceededTerminationPolicy &&
+		(meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetComplete)) ||
+			meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetFailed)))
+}
+
+// ttlAfterFinished returns whether the TTL of the finished lws expired, if not expired yet, it also returns the
+// remaining time. The lws is finished since the last transition of the Complete or Failed condition.
+func ttlAfterFinished(lws *leaderworkerset.LeaderWorkerSet, now time.Time) (bool, time.Duration) {
+	if lws.Spec.TTLSecondsAfterFinished == nil {
+		return false, 0
+	}
+	condition := meta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetComplete))
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		condition = meta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetFailed))
+	}
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		return false, 0
+	}
+	expireAt := condition.LastTransitionTime.Add(time.Duration(*lws.Spec.TTLSecondsAfterFinished) * time.Second)
+	if !now.Before(expireAt) {
+		return true, 0
+	}
+	return false, expireAt.Sub(now)
+}
+
+// finish deletes the leader statefulset once the lws is finished in the run-to-completion mode. Unlike the
+// suspension, the statuses of the groups are kept.
+func (r *LeaderWorkerSetReconciler) finish(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	if err := r.deleteLeaderStatefulSet(ctx, lws, "Finished", "Deleted leader statefulset since the leaderworkerset is finished"); err != nil {
+		return err
+	}
+
+	updateStatus := lws.Status.ReadyReplicas != 0 || lws.Status.AvailableReplicas != 0 || lws.Status.LastProgressTime != nil
+	lws.Status.ReadyReplicas = 0
+	lws.Status.AvailableReplicas = 0
+	lws.Status.LastProgressTime = nil
+
+	var conditions []metav1.Condition
+	for _, conditionType := range []leaderworkerset.LeaderWorkerSetConditionType{leaderworkerset.LeaderWorkerSetAvailable, leaderworkerset.LeaderWorkerSetProgressing, leaderworkerset.LeaderWorkerSetUpgradeInProgress} {
+		condition := makeCondition(conditionType)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Finished"
+		condition.Message = "LeaderWorkerSet is finished"
+		conditions = append(conditions, condition)
+	}
+	if setConditions(lws, conditions) || updateStatus {
+		return r.Status().Update(ctx, lws)
+	}
+	return nil
+}
+
+// reconcileWorkload creates the Kueue Workload of the lws if not exists, and returns whether the Workload
+// is admitted. The Workload is resized once the lws is scaled, the number of groups admitted before the
+// resize is returned as well until the Workload matches the lws, nil otherwise.
+func (r *LeaderWorkerSetReconciler) reconcileWorkload(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, *int32, error) {
+	log := ctrl.LoggerFrom(ctx)
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(kueueutils.WorkloadGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: kueueutils.WorkloadName(lws), Namespace: lws.Namespace}, workload); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			return false, nil, err
+		}
+		workload, err := kueueutils.NewWorkload(lws)
+		if err != nil {
+			return false, nil, err
+		}
+		if err := ctrl.SetControllerReference(lws, workload, r.Scheme); err != nil {
+			return false, nil, err
+		}
+		log.V(2).Info("Creating kueue workload", "workload", workload.GetName())
+		if err := r.Create(ctx, workload); err != nil {
+			return false, nil, err
+		}
+		r.Record.Eventf(lws, corev1.EventTypeNormal, "CreatedWorkload", fmt.Sprintf("Created workload %s", workload.GetName()))
+		return false, nil, nil
+	}
+	// The Workload is resized in place, e.g. scaled by HPA, the groups keep running meanwhile.
+	var admittedReplicas *int32
+	if !kueueutils.WorkloadMatches(workload, lws) {
+		admittedReplicas = ptr.To(kueueutils.AdmittedReplicas(workload))
+		if err := kueueutils.UpdateWorkload(workload, lws); err != nil {
+			return false, nil, err
+		}
+		log.V(2).Info("Resizing kueue workload", "workload", workload.GetName())
+		if err := r.Update(ctx, workload); err != nil {
+			if !apierrors.IsInvalid(err) {
+				return false, nil, err
+			}
+			// Kueue rejects resizing the admitted Workloads unless it supports elastic jobs, the admission
+			// is kept for the groups admitted before.
+			r.Record.Eventf(lws, corev1.EventTypeWarning, "WorkloadResizeRejected", fmt.Sprintf("Kueue rejected resizing workload %s: %v", workload.GetName(), err))
+		}
+	}
+	admitted := kueueutils.WorkloadAdmitted(workload)
+	condition := metav1.Condition{Type: string(leaderworkerset.LeaderWorkerSetAdmitted), Status: metav1.ConditionTrue,
+		Reason: "WorkloadAdmitted", Message: fmt.Sprintf("Workload %s is admitted", workload.GetName())}
+	if !admitted {
+		condition.Status, condition.Reason, condition.Message = metav1.ConditionFalse, "WorkloadNotAdmitted", fmt.Sprintf("Workload %s is not admitted", workload.GetName())
+	} else if admittedReplicas != nil {
+		condition.Reason, condition.Message = "WorkloadResizePending", fmt.Sprintf("Workload %s is admitted for %d groups", workload.GetName(), *admittedReplicas)
+	}
+	if meta.SetStatusCondition(&lws.Status.Conditions, condition) {
+		if err := r.Status().Update(ctx, lws); err != nil {
+			return false, nil, err
+		}
+	}
+	return admitted, admittedReplicas, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr)
+	// Kueue is an optional dependency, only watch the workloads if installed.
+	if kueueutils.WorkloadCRDInstalled(mgr.GetRESTMapper()) {
+		workload := &unstructured.Unstructured{}
+		workload.SetGroupVersionKind(kueueutils.WorkloadGVK)
+		b = b.Owns(workload)
+	}
+	return b.
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter}).
+		For(&leaderworkerset.LeaderWorkerSet{}).
+		Owns(&appsv1.StatefulSet{}).
+		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&batchv1.Job{}).
+		Watches(&appsv1.StatefulSet{},
+			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, a client.Object) []reconcile.Request {
+				return []reconcile.Request{
+					{NamespacedName: types.NamespacedName{
+						Name:      a.GetLabels()[leaderworkerset.SetNameLabelKey],
+						Namespace: a.GetNamespace(),
+					}},
+				}
+			})).
+		Complete(r)
+}
+
+func SetupIndexes(indexer client.FieldIndexer) error {
+	if err := indexer.IndexField(context.Background(), &corev1.Pod{}, podNodeNameKey, func(rawObj client.Object) []string {
+		pod := rawObj.(*corev1.Pod)
+		if pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []string{pod.Spec.NodeName}
+	}); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &corev1.Pod{}, podGroupKey, indexPodGroup); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &corev1.Pod{}, leaderPodKey, indexLeaderPod); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(context.Background(), &appsv1.StatefulSet{}, statefulSetLwsKey, indexStatefulSetLws); err != nil {
+		return err
+	}
+	return indexer.IndexField(context.Background(), &appsv1.StatefulSet{}, lwsOwnerKey, func(rawObj client.Object) []string {
+		// grab the statefulSet object, extract the owner...
+		statefulSet := rawObj.(*appsv1.StatefulSet)
+		owner := metav1.GetControllerOf(statefulSet)
+		if owner == nil {
+			return nil
+		}
+		// ...make sure it's a LeaderWorkerSet...
+		if owner.APIVersion != apiGVStr || owner.Kind != "LeaderWorkerSet" {
+			return nil
+		}
+		// ...and if so, return it
+		return []string{owner.Name}
+	})
+}
+
+// groupIndexValue returns the value of the podGroupKey index for the group of the leaderworkerset.
+func groupIndexValue(lwsName, groupIndex string) string {
+	return lwsName + "/" + groupIndex
+}
+
+func indexPodGroup(obj client.Object) []string {
+	lwsName, groupIndex := obj.GetLabels()[leaderworkerset.SetNameLabelKey], obj.GetLabels()[leaderworkerset.GroupIndexLabelKey]
+	if lwsName == "" || groupIndex == "" || podutils.SkipInjection(*obj.(*corev1.Pod)) {
+		return nil
+	}
+	return []string{groupIndexValue(lwsName, groupIndex)}
+}
+
+func indexLeaderPod(obj client.Object) []string {
+	lwsName := obj.GetLabels()[leaderworkerset.SetNameLabelKey]
+	if lwsName == "" || obj.GetLabels()[leaderworkerset.WorkerIndexLabelKey] != "0" || podutils.SkipInjection(*obj.(*corev1.Pod)) {
+		return nil
+	}
+	return []string{lwsName}
+}
+
+func indexStatefulSetLws(obj client.Object) []string {
+	lwsName := obj.GetLabels()[leaderworkerset.SetNameLabelKey]
+	if lwsName == "" {
+		return nil
+	}
+	return []string{lwsName}
+}
+
+// rollbackIfRequested rolls the leaderWorkerTemplate back to the revision specified by the rollback
+// annotation, the annotation is removed once processed. Returns true if the lws is updated.
+func (r *LeaderWorkerSetReconciler) rollbackIfRequested(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	rollbackTo, found := lws.Annotations[leaderworkerset.RollbackToAnnotationKey]
+	if !found {
+		return false, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	delete(lws.Annotations, leaderworkerset.RollbackToAnnotationKey)
+
+	revisionNumber, err := strconv.ParseInt(rollbackTo, 10, 64)
+	if err != nil {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, "RollbackFailed", fmt.Sprintf("Invalid revision %q to roll back to", rollbackTo))
+		return true, r.Update(ctx, lws)
+	}
+
+	revisions, err := r.listRevisions(ctx, lws)
+	if err != nil {
+		return false, err
+	}
+	var target *appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Revision == revisionNumber {
+			target = &revisions[i]
+			break
+		}
+	}
+	if target == nil {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, "RollbackFailed", fmt.Sprintf("Unable to find revision %d to roll back to", revisionNumber))
+		return true, r.Update(ctx, lws)
+	}
+
+	if err := revisionutils.ApplyRevision(lws, target); err != nil {
+		return false, err
+	}
+	log.V(2).Info("Rolling back leaderWorkerTemplate", "revision", revisionNumber)
+	if err := r.Update(ctx, lws); err != nil {
+		return false, err
+	}
+	r.Record.Eventf(lws, corev1.EventTypeNormal, "RolledBack", fmt.Sprintf("Rolled back to revision %d", revisionNumber))
+	return true, nil
+}
 
-	partition, replicas, err := r.rollingUpdateParameters(ctx, lws)
-	if err != nil {
-		log.Error(err, "Rolling partition error")
-		return ctrl.Result{}, err
+// restartGroupIfRequested moves the restart group annotation to the leader pod of the group, the pod controller
+// then recreates the group like the other restarts. The annotation is removed once processed. Returns true if the
+// lws is updated.
+func (r *LeaderWorkerSetReconciler) restartGroupIfRequested(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	restartGroup, found := lws.Annotations[leaderworkerset.RestartGroupAnnotationKey]
+	if !found {
+		return false, nil
 	}
+	log := ctrl.LoggerFrom(ctx)
+	delete(lws.Annotations, leaderworkerset.RestartGroupAnnotationKey)
 
-	if err := r.SSAWithStatefulset(ctx, lws, partition, replicas); err != nil {
-		return ctrl.Result{}, err
+	// Only the groups of the leader statefulset can be restarted.
+	start, end := int32(0), utils.TotalReplicas(lws)
+	var sts appsv1.StatefulSet
+	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &sts); client.IgnoreNotFound(err) != nil {
+		return false, err
+	} else if err == nil {
+		start, end = statefulSetStart(&sts), statefulSetStart(&sts)+*sts.Spec.Replicas
+	}
+	groupIndex, err := strconv.Atoi(restartGroup)
+	if err != nil || groupIndex < int(start) || groupIndex >= int(end) {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, "RestartGroupFailed", fmt.Sprintf("Invalid group index %q to restart", restartGroup))
+		return true, r.Update(ctx, lws)
 	}
 
-	// Create headless service if it does not exist.
-	if err := r.createHeadlessServiceIfNotExists(ctx, lws); err != nil {
-		log.Error(err, "Creating headless service.")
-		r.Record.Eventf(lws, corev1.EventTypeWarning, FailedCreate,
-			fmt.Sprintf("Failed to create headless service for error: %v", err))
-		return ctrl.Result{}, err
+	var leaderPod corev1.Pod
+	err = r.Get(ctx, types.NamespacedName{Name: fmt.Sprintf("%s-%d", lws.Name, groupIndex), Namespace: lws.Namespace}, &leaderPod)
+	if client.IgnoreNotFound(err) != nil {
+		return false, err
+	}
+	// The group is being recreated already if the leader pod is missing or being deleted.
+	if err == nil && leaderPod.DeletionTimestamp == nil {
+		log.V(2).Info("Requesting the restart of the group", "group", groupIndex)
+		patch := client.MergeFrom(leaderPod.DeepCopy())
+		metav1.SetMetaDataAnnotation(&leaderPod.ObjectMeta, leaderworkerset.RestartGroupAnnotationKey, restartGroup)
+		if err := r.Patch(ctx, &leaderPod, patch); client.IgnoreNotFound(err) != nil {
+			return false, err
+		}
 	}
+	return true, r.Update(ctx, lws)
+}
 
-	err = r.updateStatus(ctx, lws)
+// syncRevisions makes sure the current leaderWorkerTemplate is recorded as the latest ControllerRevision,
+// and truncates the history revisions exceeding the revisionHistoryLimit.
+func (r *LeaderWorkerSetReconciler) syncRevisions(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	revisions, err := r.listRevisions(ctx, lws)
 	if err != nil {
-		return ctrl.Result{}, err
+		return err
 	}
 
-	log.V(2).Info("Leader Reconcile completed.")
-	return ctrl.Result{}, nil
-}
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	maxRevision := revisionutils.MaxRevision(revisions)
+	var current *appsv1.ControllerRevision
+	for i := range revisions {
+		if revisions[i].Labels[leaderworkerset.TemplateRevisionHashKey] == templateHash {
+			current = &revisions[i]
+			break
+		}
+	}
 
-func (r *LeaderWorkerSetReconciler) createHeadlessServiceIfNotExists(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
-	log := ctrl.LoggerFrom(ctx)
-	// If the headless service does not exist in the namespace, create it.
-	var headlessService corev1.Service
-	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &headlessService); err != nil {
-		if client.IgnoreNotFound(err) != nil {
+	if current == nil {
+		revision, err := revisionutils.NewRevision(lws, maxRevision+1)
+		if err != nil {
 			return err
 		}
-		headlessService := corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      lws.Name,
-				Namespace: lws.Namespace,
-			},
-			Spec: corev1.ServiceSpec{
-				ClusterIP: "None", // defines service as headless
-				Selector: map[string]string{
-					leaderworkerset.SetNameLabelKey: lws.Name,
-				},
-				PublishNotReadyAddresses: true,
-			},
+		if err := ctrl.SetControllerReference(lws, revision, r.Scheme); err != nil {
+			return err
 		}
-		// Set the controller owner reference for garbage collection and reconciliation.
-		if err := ctrl.SetControllerReference(lws, &headlessService, r.Scheme); err != nil {
+		if err := r.Create(ctx, revision); err != nil && !apierrors.IsAlreadyExists(err) {
 			return err
 		}
-		// create the service in the cluster
-		log.V(2).Info("Creating headless service.")
-		if err := r.Create(ctx, &headlessService); err != nil {
+	} else if current.Revision < maxRevision {
+		// The template is rolled back to a former revision, bump it to be the latest one.
+		current.Revision = maxRevision + 1
+		if err := r.Update(ctx, current); err != nil {
 			return err
 		}
 	}
-	return nil
-}
-
-// SetupWithManager sets up the controller with the Manager.
-func (r *LeaderWorkerSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&leaderworkerset.LeaderWorkerSet{}).
-		Owns(&appsv1.StatefulSet{}).
-		Owns(&corev1.Service{}).
-		Watches(&appsv1.StatefulSet{},
-			handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, a client.Object) []reconcile.Request {
-				return []reconcile.Request{
-					{NamespacedName: types.NamespacedName{
-						Name:      a.GetLabels()[leaderworkerset.SetNameLabelKey],
-						Namespace: a.GetNamespace(),
-					}},
-				}
-			})).
-		Complete(r)
-}
 
-func SetupIndexes(indexer client.FieldIndexer) error {
-	return indexer.IndexField(context.Background(), &appsv1.StatefulSet{}, lwsOwnerKey, func(rawObj client.Object) []string {
-		// grab the statefulSet object, extract the owner...
-		statefulSet := rawObj.(*appsv1.StatefulSet)
-		owner := metav1.GetControllerOf(statefulSet)
-		if owner == nil {
-			return nil
+	liveHashes := sets.New(templateHash)
+	// With OnDelete and BlueGreen, the groups not recreated yet still build their workers from the former revisions.
+	if lws.Spec.RolloutStrategy.Type != leaderworkerset.RollingUpdateStrategyType {
+		var leaderPods corev1.PodList
+		if err := r.List(ctx, &leaderPods, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+			return err
 		}
-		// ...make sure it's a LeaderWorkerSet...
-		if owner.APIVersion != apiGVStr || owner.Kind != "LeaderWorkerSet" {
-			return nil
+		for _, pod := range leaderPods.Items {
+			liveHashes.Insert(pod.Labels[leaderworkerset.TemplateRevisionHashKey])
 		}
-		// ...and if so, return it
-		return []string{owner.Name}
-	})
+	}
+	limit := ptr.Deref(lws.Spec.RevisionHistoryLimit, 10)
+	for _, revision := range revisionutils.RevisionsToTruncate(revisions, liveHashes, limit) {
+		if err := r.Delete(ctx, &revision); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *LeaderWorkerSetReconciler) listRevisions(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) ([]appsv1.ControllerRevision, error) {
+	var revisionList appsv1.ControllerRevisionList
+	if err := r.List(ctx, &revisionList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey: lws.Name,
+	}); err != nil {
+		return nil, err
+	}
+	return revisionList.Items, nil
 }
 
 // Rolling update will always wait for the former replica to be ready then process the next one,
-// we didn't consider rollout strategy type here since we only support rollingUpdate now,
-// once we have more policies, we should update the logic here.
+// with the OnDelete rollout strategy, partition is always 0 and Replicas is always spec.Replicas.
 // Possible scenarios for Partition:
 //   - When sts is under creation, partition is always 0 because pods are created in parallel, rolling update is not relevant here.
 //   - When sts is in rolling update, the partition will start from the last index to the index 0 processing in maxUnavailable step.
@@ -204,6 +1198,7 @@ func SetupIndexes(indexer client.FieldIndexer) error {
 //     the scaling up is done.
 //   - When sts is ready for a rolling update and Replicas decreases at the same time, we'll start the rolling update
 //     together with scaling down.
+//   - When the rolling update is paused, Partition will not move until resumed.
 //
 // At rest, Partition should always be zero.
 //
@@ -212,8 +1207,112 @@ func SetupIndexes(indexer client.FieldIndexer) error {
 //   - Otherwise, Replicas is equal to spec.Replicas
 //   - One exception here is when unready replicas of leaderWorkerSet is equal to MaxSurge,
 //     we should reclaim the extra replicas gradually to accommodate for the new replicas.
-func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (int32, int32, error) {
-	lwsReplicas := *lws.Spec.Replicas
+//
+// Partition will never be smaller than the user specified partition, see rollingUpdatePartition.
+//
+// The groups are indexed from Start, the first ordinal of the leader statefulset, e.g. moved by the BlueGreen rollouts
+// or by scaling down, see scaleDownStart. The Partition above is relative to Start, the returned one is the index of
+// the first group to update.
+func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (int32, int32, int32, error) {
+	start, partition, replicas, err := r.calculatePartitionAndReplicas(ctx, lws)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	start, err = r.scaleDownStart(ctx, lws, start, replicas)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return start, start + max(partition, rollingUpdatePartition(lws)), replicas, nil
+}
+
+// scaleDownStart returns the start of the leader statefulset scaled down to the replicas at rest. The removed groups
+// are taken from the lowest indexes by raising the start, and from the highest ones, so that the fewest ready groups
+// are removed, then the lowest sum of the pod deletion costs of the leader pods, then the fewest ready workers. The
+// highest indexes are preferred on ties. The start is kept while a rollout is in progress.
+func (r *LeaderWorkerSetReconciler) scaleDownStart(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, replicas int32) (int32, error) {
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, sts); err != nil {
+		return start, client.IgnoreNotFound(err)
+	}
+	stsReplicas := *sts.Spec.Replicas
+	if start != statefulSetStart(sts) || replicas >= stsReplicas || templateUpdated(sts, lws) {
+		return start, nil
+	}
+	if _, _, found := blueGroups(sts); found {
+		return start, nil
+	}
+
+	var leaders corev1.PodList
+	if err := r.List(ctx, &leaders, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+		return 0, err
+	}
+	var stsList appsv1.StatefulSetList
+	if err := r.List(ctx, &stsList, client.InNamespace(lws.Namespace), client.MatchingFields{statefulSetLwsKey: lws.Name}); err != nil {
+		return 0, err
+	}
+	workers := map[string]appsv1.StatefulSet{}
+	for _, workerSts := range stsList.Items {
+		workers[workerSts.Name] = workerSts
+	}
+
+	// The removal costs of the groups, by the position from the start.
+	type removalCost struct {
+		readyGroups, deletionCost, readyWorkers int64
+	}
+	costs := make([]removalCost, stsReplicas)
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	for _, leader := range leaders.Items {
+		groupIndex, err := strconv.Atoi(leader.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			return 0, err
+		}
+		position := int32(groupIndex) - start
+		if position < 0 || position >= stsReplicas {
+			continue
+		}
+		// The outdated groups are still rolled out.
+		if leader.Labels[leaderworkerset.TemplateRevisionHashKey] != templateHash {
+			return start, nil
+		}
+		deletionCost, _ := strconv.ParseInt(leader.Annotations[corev1.PodDeletionCost], 10, 32)
+		costs[position].deletionCost = deletionCost
+		workerSts, found := workers[leader.Name]
+		if !found {
+			continue
+		}
+		costs[position].readyWorkers = int64(workerSts.Status.ReadyReplicas)
+		if statefulsetutils.StatefulsetReady(workerSts) && podutils.PodRunningAndReady(leader) {
+			costs[position].readyGroups = 1
+		}
+	}
+
+	removed := stsReplicas - replicas
+	var bottom int32
+	var best removalCost
+	for d := int32(0); d <= removed; d++ {
+		var cost removalCost
+		for position := int32(0); position < stsReplicas; position++ {
+			if position >= d && position < stsReplicas-removed+d {
+				continue
+			}
+			cost.readyGroups += costs[position].readyGroups
+			cost.deletionCost += costs[position].deletionCost
+			cost.readyWorkers += costs[position].readyWorkers
+		}
+		if d == 0 || cost.readyGroups < best.readyGroups ||
+			(cost.readyGroups == best.readyGroups && cost.deletionCost < best.deletionCost) ||
+			(cost.readyGroups == best.readyGroups && cost.deletionCost == best.deletionCost && cost.readyWorkers < best.readyWorkers) {
+			bottom, best = d, cost
+		}
+	}
+	if bottom > 0 {
+		ctrl.LoggerFrom(ctx).V(2).Info("Scaling down from the lowest groups", "start", start+bottom, "removed", bottom)
+	}
+	return start + bottom, nil
+}
+
+func (r *LeaderWorkerSetReconciler) calculatePartitionAndReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (int32, int32, int32, error) {
+	lwsReplicas := utils.TotalReplicas(lws)
 
 	sts := &appsv1.StatefulSet{}
 	err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, sts)
@@ -222,15 +1321,28 @@ func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context,
 		// If sts not created yet, all partitions should be updated,
 		// replicas should not change.
 		if apierrors.IsNotFound(err) {
-			return 0, lwsReplicas, nil
+			return 0, 0, lwsReplicas, nil
 		}
-		return 0, 0, err
+		return 0, 0, 0, err
+	}
+	start := statefulSetStart(sts)
+
+	switch lws.Spec.RolloutStrategy.Type {
+	// Groups are only updated once deleted with OnDelete, there's no rolling update to orchestrate.
+	case leaderworkerset.OnDeleteStrategyType:
+		return start, 0, lwsReplicas, nil
+	case leaderworkerset.BlueGreenStrategyType:
+		start, replicas, err := r.blueGreenStartAndReplicas(ctx, lws, sts)
+		return start, 0, replicas, err
+	case leaderworkerset.CanaryStrategyType:
+		partition, replicas, err := r.canaryPartitionAndReplicas(ctx, lws, sts)
+		return start, partition, replicas, err
 	}
 
 	stsReplicas := *sts.Spec.Replicas
 	maxSurge, err := intstr.GetValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge, int(lwsReplicas), true)
 	if err != nil {
-		return 0, 0, err
+		return 0, 0, 0, err
 	}
 	// No need to burst more than the replicas.
 	if maxSurge > int(lwsReplicas) {
@@ -249,82 +1361,408 @@ func (r *LeaderWorkerSetReconciler) rollingUpdateParameters(ctx context.Context,
 		return burstReplicas
 	}
 
+	// When the rolling update is paused, keep the partition as it is so that no more groups
+	// are updated, a template update while paused will not touch the existing groups either.
+	// Scaling is still processed, and the bursted replicas are kept until resumed.
+	if lws.Spec.RolloutStrategy.Paused {
+		partition := statefulSetPartition(sts)
+		if templateUpdated(sts, lws) {
+			partition = stsReplicas
+		}
+		return start, min(partition, lwsReplicas), max(lwsReplicas, min(stsReplicas, burstReplicas)), nil
+	}
+
 	// Case 2:
 	// Indicates a new rolling update here.
 	if templateUpdated(sts, lws) {
 		// Processing scaling up/down first prior to rolling update.
-		return min(lwsReplicas, stsReplicas), wantReplicas(lwsReplicas), nil
+		return start, min(lwsReplicas, stsReplicas), wantReplicas(lwsReplicas), nil
 	}
 
-	partition := *sts.Spec.UpdateStrategy.RollingUpdate.Partition
-	rollingUpdateCompleted := partition == 0 && stsReplicas == lwsReplicas
+	partition := statefulSetPartition(sts)
+	rollingUpdateCompleted := partition <= rollingUpdatePartition(lws) && stsReplicas == lwsReplicas
 	// Case 3:
 	// In normal cases, return the values directly.
 	if rollingUpdateCompleted {
-		return 0, lwsReplicas, nil
+		// The post update hooks still run for the groups coming up at rest, e.g. the last updated ones.
+		if hookutils.Hook(lws, hookutils.PostGroupUpdate) != nil {
+			if _, _, err := r.iterateReplicas(ctx, lws, start, stsReplicas); err != nil {
+				return 0, 0, 0, err
+			}
+		}
+		if err := r.cleanupRolloutHooks(ctx, lws); err != nil {
+			return 0, 0, 0, err
+		}
+		return start, 0, lwsReplicas, nil
 	}
 
-	continuousReadyReplicas, lwsUnreadyReplicas, err := r.iterateReplicas(ctx, lws, stsReplicas)
+	continuousReadyReplicas, lwsUnreadyReplicas, err := r.iterateReplicas(ctx, lws, start, stsReplicas)
 	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	originalLwsReplicas, err := strconv.Atoi(sts.Annotations[leaderworkerset.ReplicasAnnotationKey])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	replicasUpdated := originalLwsReplicas != int(utils.TotalReplicas(lws))
+	// Case 4:
+	// Replicas changed during rolling update.
+	if replicasUpdated {
+		return start, min(partition, burstReplicas), wantReplicas(lwsUnreadyReplicas), nil
+	}
+
+	// Case 5:
+	// Calculating the Partition during rolling update, no leaderWorkerSet updates happens.
+
+	rollingStep, err := intstr.GetValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, int(lwsReplicas), false)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	// Make sure that we always respect the maxUnavailable, or
+	// we'll violate it when reclaiming bursted replicas.
+	rollingStep += maxSurge - (int(burstReplicas) - int(stsReplicas))
+
+	// When updated replicas become not ready again or scaled up replicas are not ready yet,
+	// we'll not modify the Partition field. That means Partition moves in one direction to make it simple.
+	newPartition := min(partition, utils.NonZeroValue(stsReplicas-int32(rollingStep)-continuousReadyReplicas))
+	newPartition, err = r.preGroupUpdateHooksPartition(ctx, lws, start, partition, newPartition)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return start, newPartition, wantReplicas(lwsUnreadyReplicas), nil
+}
+
+// preGroupUpdateHooksPartition runs the pre update hooks of the groups about to be torn down by lowering the
+// partition from the current one to the new one, both relative to the start, and returns the partition the hooks
+// allow, i.e. above the highest group whose hook has not succeeded yet. The hooks of all the groups run in parallel.
+func (r *LeaderWorkerSetReconciler) preGroupUpdateHooksPartition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, currentPartition, newPartition int32) (int32, error) {
+	if hookutils.Hook(lws, hookutils.PreGroupUpdate) == nil {
+		return newPartition, nil
+	}
+	partition := currentPartition
+	blocked := false
+	for index := currentPartition - 1; index >= newPartition; index-- {
+		completed, err := r.rolloutHookCompleted(ctx, lws, hookutils.PreGroupUpdate, start+index)
+		if err != nil {
+			return 0, err
+		}
+		blocked = blocked || !completed
+		if !blocked {
+			partition = index
+		}
+	}
+	return partition, nil
+}
+
+// rolloutHookCompleted runs the Job of the rollout hook for the group if not run yet with the current
+// revision, and returns true once the Job succeeds. A failed Job blocks the rollout until deleted.
+func (r *LeaderWorkerSetReconciler) rolloutHookCompleted(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, name string, groupIndex int32) (bool, error) {
+	hook := hookutils.Hook(lws, name)
+	if hook == nil {
+		return true, nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	desired := hookutils.NewJob(lws, name, hook, groupIndex, utils.LeaderWorkerTemplateHash(lws))
+	job := &batchv1.Job{}
+	if err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		if err := ctrl.SetControllerReference(lws, desired, r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, desired); err != nil && !apierrors.IsAlreadyExists(err) {
+			return false, err
+		}
+		log.V(2).Info("Created rollout hook job", "hook", name, "job", klog.KObj(desired))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, "RolloutHookStarted", fmt.Sprintf("Started %s hook job %s", name, desired.Name))
+		return false, nil
+	}
+	finished, conditionType := hookutils.JobFinished(job)
+	if finished && conditionType == batchv1.JobFailed {
+		r.Record.Eventf(lws, corev1.EventTypeWarning, "RolloutHookFailed", fmt.Sprintf("The %s hook job %s failed, delete it to retry", name, job.Name))
+	}
+	return finished && conditionType == batchv1.JobComplete, nil
+}
+
+// cleanupRolloutHooks deletes the Jobs of the rollout hooks of the previous revisions once the rollout completes.
+func (r *LeaderWorkerSetReconciler) cleanupRolloutHooks(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	var jobs batchv1.JobList
+	if err := r.List(ctx, &jobs, client.InNamespace(lws.Namespace),
+		client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}, client.HasLabels{leaderworkerset.RolloutHookLabelKey}); err != nil {
+		return err
+	}
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	for i := range jobs.Items {
+		if jobs.Items[i].Labels[leaderworkerset.TemplateRevisionHashKey] == templateHash {
+			continue
+		}
+		if err := r.Delete(ctx, &jobs.Items[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blueGreenStartAndReplicas returns the start and the replicas of the leader statefulset with the BlueGreen rollout
+// strategy. The leader statefulset is updated on delete, so that only the new groups, named green, are created with
+// the new template. They're created next to the old ones, named blue, below them if there's room, otherwise above
+// them, and the blue groups are recorded on the leader statefulset. Once all the green groups are ready and the
+// leader Service is switched to them, the blue groups are torn down by scaling the leader statefulset down to the
+// green groups, e.g. the first group alternates between 0 and the replicas across the rollouts.
+func (r *LeaderWorkerSetReconciler) blueGreenStartAndReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet) (int32, int32, error) {
+	lwsReplicas := utils.TotalReplicas(lws)
+	start, stsReplicas := statefulSetStart(sts), *sts.Spec.Replicas
+	blueStart, blueEnd, found := blueGroups(sts)
+	// The rollout completes once the blue groups are out of the leader statefulset.
+	if found && (blueEnd <= start || blueStart >= start+stsReplicas) {
+		if err := r.setBlueGroups(ctx, sts, ""); err != nil {
+			return 0, 0, err
+		}
+		found = false
+	}
+	if !found {
+		if !templateUpdated(sts, lws) || stsReplicas == 0 {
+			return start, lwsReplicas, nil
+		}
+		blueStart, blueEnd = start, start+stsReplicas
+		if err := r.setBlueGroups(ctx, sts, fmt.Sprintf("%d-%d", blueStart, blueEnd-1)); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	greenStart, greenEnd := greenGroups(sts, blueStart, blueEnd)
+	// Stand up the green groups.
+	if greenStart == greenEnd {
+		if blueStart >= lwsReplicas {
+			return blueStart - lwsReplicas, blueEnd - blueStart + lwsReplicas, nil
+		}
+		return blueStart, blueEnd - blueStart + lwsReplicas, nil
+	}
+	// A template update during the rollout recreates the green groups.
+	if err := r.recreateOutdatedGroups(ctx, lws, greenStart, greenEnd); err != nil {
 		return 0, 0, err
 	}
+	// Tear down the blue groups only once the leader Service selects the green ones.
+	continuousReadyReplicas, _, err := r.iterateReplicas(ctx, lws, greenStart, greenEnd-greenStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	if continuousReadyReplicas < greenEnd-greenStart {
+		return start, stsReplicas, nil
+	}
+	switched, err := r.leaderServiceSwitched(ctx, lws)
+	if err != nil || !switched {
+		return start, stsReplicas, err
+	}
+	return greenStart, greenEnd - greenStart, nil
+}
+
+// setBlueGroups records the blue groups of the BlueGreen rollout on the leader statefulset, or removes them if empty.
+func (r *LeaderWorkerSetReconciler) setBlueGroups(ctx context.Context, sts *appsv1.StatefulSet, blueGroups string) error {
+	patch := client.MergeFrom(sts.DeepCopy())
+	if blueGroups == "" {
+		delete(sts.Annotations, leaderworkerset.BlueGroupsAnnotationKey)
+	} else {
+		metav1.SetMetaDataAnnotation(&sts.ObjectMeta, leaderworkerset.BlueGroupsAnnotationKey, blueGroups)
+	}
+	return r.Patch(ctx, sts, patch)
+}
+
+// recreateOutdatedGroups deletes the leader pods of the groups [start, end) not created with the current template,
+// the leader statefulset recreates them with the current template once updated on delete.
+func (r *LeaderWorkerSetReconciler) recreateOutdatedGroups(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, end int32) error {
+	var leaders corev1.PodList
+	if err := r.List(ctx, &leaders, client.InNamespace(lws.Namespace), client.MatchingFields{leaderPodKey: lws.Name}); err != nil {
+		return err
+	}
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	for i := range leaders.Items {
+		leader := &leaders.Items[i]
+		groupIndex, err := strconv.Atoi(leader.Labels[leaderworkerset.GroupIndexLabelKey])
+		if err != nil {
+			return err
+		}
+		if int32(groupIndex) < start || int32(groupIndex) >= end || leader.DeletionTimestamp != nil || leader.Labels[leaderworkerset.TemplateRevisionHashKey] == templateHash {
+			continue
+		}
+		ctrl.LoggerFrom(ctx).V(2).Info("Recreating the outdated group", "group", groupIndex)
+		if err := r.Delete(ctx, leader, client.PropagationPolicy(metav1.DeletePropagationForeground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaderServiceSwitched returns true if the leader Service selects the leader pods of the current template,
+// always true if there's no leader Service.
+func (r *LeaderWorkerSetReconciler) leaderServiceSwitched(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	if lws.Spec.LeaderService == nil {
+		return true, nil
+	}
+	var service corev1.Service
+	if err := r.Get(ctx, types.NamespacedName{Name: leaderServiceName(lws), Namespace: lws.Namespace}, &service); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return service.Spec.Selector[leaderworkerset.TemplateRevisionHashKey] == utils.LeaderWorkerTemplateHash(lws), nil
+}
+
+// blueGreenServingHash returns the template hash of the groups the leader Service selects with the BlueGreen
+// rollout strategy, the leader Service is only switched to the current template once all its groups are ready.
+func (r *LeaderWorkerSetReconciler) blueGreenServingHash(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, servingHash string) (string, error) {
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	if servingHash == "" || servingHash == templateHash {
+		return templateHash, nil
+	}
+	sts := &appsv1.StatefulSet{}
+	if err := r.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, sts); err != nil {
+		return servingHash, client.IgnoreNotFound(err)
+	}
+	start, end := statefulSetStart(sts), statefulSetStart(sts)+*sts.Spec.Replicas
+	if blueStart, blueEnd, found := blueGroups(sts); found {
+		start, end = greenGroups(sts, blueStart, blueEnd)
+	}
+	continuousReadyReplicas, _, err := r.iterateReplicas(ctx, lws, start, end-start)
+	if err != nil {
+		return servingHash, err
+	}
+	if templateUpdated(sts, lws) || end == start || continuousReadyReplicas < end-start {
+		return servingHash, nil
+	}
+	return templateHash, nil
+}
+
+// canaryPartitionAndReplicas returns the partition and replicas of the leader statefulset with the Canary rollout
+// strategy, and moves the canary rollout forward. The groups of each step are updated from the highest index, the
+// traffic is shifted once they are ready, and the next step starts after the pause of the step. A new revision
+// during the canary rollout restarts it from the first step.
+func (r *LeaderWorkerSetReconciler) canaryPartitionAndReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet) (int32, int32, error) {
+	lwsReplicas := utils.TotalReplicas(lws)
+	config := lws.Spec.RolloutStrategy.CanaryConfiguration
+	if config == nil {
+		return 0, lwsReplicas, nil
+	}
+
+	status := lws.Status.Canary.DeepCopy()
+	if status == nil {
+		// The existing groups are the stable ones once switched to the Canary rollout strategy.
+		status = &leaderworkerset.CanaryStatus{StableRevision: sts.Labels[leaderworkerset.TemplateRevisionHashKey]}
+	} else if templateUpdated(sts, lws) {
+		status = &leaderworkerset.CanaryStatus{StableRevision: status.StableRevision}
+	}
+
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	if status.StableRevision != templateHash {
+		continuousReadyReplicas, _, err := r.iterateReplicas(ctx, lws, statefulSetStart(sts), *sts.Spec.Replicas)
+		if err != nil {
+			return 0, 0, err
+		}
+		now := metav1.Now()
+		for progressed := true; progressed; {
+			progressed = false
+			switch {
+			// All the groups are updated and ready, promote the current revision to be the stable one.
+			case int(status.Step) >= len(config.Steps):
+				if continuousReadyReplicas >= lwsReplicas {
+					status = &leaderworkerset.CanaryStatus{StableRevision: templateHash}
+				}
+			case status.StepStartTime == nil:
+				if continuousReadyReplicas >= canaryReplicas(lwsReplicas, config.Steps[status.Step].Weight) {
+					status.StepStartTime = &now
+					progressed = true
+				}
+			case canaryPauseRemaining(config.Steps[status.Step], status.StepStartTime, now.Time) == 0:
+				status.Step++
+				status.StepStartTime = nil
+				progressed = true
+			}
+		}
+	}
+
+	if !equality.Semantic.DeepEqual(lws.Status.Canary, status) {
+		lws.Status.Canary = status
+		if err := r.Status().Update(ctx, lws); err != nil {
+			return 0, 0, err
+		}
+	}
+	if status.StableRevision == templateHash || int(status.Step) >= len(config.Steps) {
+		return 0, lwsReplicas, nil
+	}
+	return lwsReplicas - canaryReplicas(lwsReplicas, config.Steps[status.Step].Weight), lwsReplicas, nil
+}
+
+// reconcileCanaryTraffic creates the stable and canary Services selecting the leader pods of the stable and the
+// current revisions with the Canary rollout strategy, and splits the traffic of the HTTPRoute between them.
+func (r *LeaderWorkerSetReconciler) reconcileCanaryTraffic(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) error {
+	config := lws.Spec.RolloutStrategy.CanaryConfiguration
+	if lws.Spec.RolloutStrategy.Type != leaderworkerset.CanaryStrategyType || config == nil || lws.Status.Canary == nil {
+		return nil
+	}
+	log := ctrl.LoggerFrom(ctx)
+	stableService := constructCanaryService(lws, canaryServiceName(lws, "stable"), lws.Status.Canary.StableRevision)
+	canaryService := constructCanaryService(lws, canaryServiceName(lws, "canary"), utils.LeaderWorkerTemplateHash(lws))
+	for _, desired := range []*corev1.Service{stableService, canaryService} {
+		if err := r.reconcileCanaryService(ctx, lws, desired); err != nil {
+			return err
+		}
+	}
 
-	originalLwsReplicas, err := strconv.Atoi(sts.Annotations[leaderworkerset.ReplicasAnnotationKey])
-	if err != nil {
-		return 0, 0, err
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(gatewayutils.HTTPRouteGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: config.HTTPRoute, Namespace: lws.Namespace}, route); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			log.V(2).Info("HTTPRoute of the canary rollout not found", "httpRoute", config.HTTPRoute)
+			return nil
+		}
+		return err
 	}
-	replicasUpdated := originalLwsReplicas != int(*lws.Spec.Replicas)
-	// Case 4:
-	// Replicas changed during rolling update.
-	if replicasUpdated {
-		return min(partition, burstReplicas), wantReplicas(lwsUnreadyReplicas), nil
+	weight := canaryWeight(lws)
+	changed, err := gatewayutils.SetBackendRefs(route, []gatewayutils.BackendRef{
+		{Name: stableService.Name, Port: config.Port, Weight: 100 - weight},
+		{Name: canaryService.Name, Port: config.Port, Weight: weight},
+	})
+	if err != nil || !changed {
+		return err
 	}
+	log.V(2).Info("Updating the traffic weights of the HTTPRoute", "httpRoute", config.HTTPRoute, "canaryWeight", weight)
+	return r.Update(ctx, route)
+}
 
-	// Case 5:
-	// Calculating the Partition during rolling update, no leaderWorkerSet updates happens.
-
-	rollingStep, err := intstr.GetValueFromIntOrPercent(&lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable, int(lwsReplicas), false)
-	if err != nil {
-		return 0, 0, err
+func (r *LeaderWorkerSetReconciler) reconcileCanaryService(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, desired *corev1.Service) error {
+	var service corev1.Service
+	err := r.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, &service)
+	if client.IgnoreNotFound(err) != nil {
+		return err
 	}
-	// Make sure that we always respect the maxUnavailable, or
-	// we'll violate it when reclaiming bursted replicas.
-	rollingStep += maxSurge - (int(burstReplicas) - int(stsReplicas))
-
-	// When updated replicas become not ready again or scaled up replicas are not ready yet,
-	// we'll not modify the Partition field. That means Partition moves in one direction to make it simple.
-	return min(partition, utils.NonZeroValue(stsReplicas-int32(rollingStep)-continuousReadyReplicas)), wantReplicas(lwsUnreadyReplicas), nil
+	if err == nil && !metav1.IsControlledBy(&service, lws) {
+		return nil
+	}
+	return applyService(ctx, r.Client, r.Scheme, lws, desired)
 }
 
-func (r *LeaderWorkerSetReconciler) SSAWithStatefulset(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32) error {
+func (r *LeaderWorkerSetReconciler) SSAWithStatefulset(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, partition, replicas int32) error {
 	log := ctrl.LoggerFrom(ctx)
 
 	// construct the statefulset apply configuration
-	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, partition, replicas)
+	leaderStatefulSetApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(lws, start, partition, replicas)
 	if err != nil {
 		log.Error(err, "Constructing StatefulSet apply configuration.")
 		return err
 	}
+	if r.PodWebhookDisabled {
+		if err := stampPodTemplate(leaderStatefulSetApplyConfig, leaderworkerset.GroupIndexLabelKey); err != nil {
+			return err
+		}
+	}
 	if err := setControllerReferenceWithStatefulSet(lws, leaderStatefulSetApplyConfig, r.Scheme); err != nil {
 		log.Error(err, "Setting controller reference.")
 		return err
 	}
-	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(leaderStatefulSetApplyConfig)
-	if err != nil {
-		log.Error(err, "Converting StatefulSet configuration to json.")
-		return err
-	}
-	patch := &unstructured.Unstructured{
-		Object: obj,
-	}
-	// Use server side apply and add fieldmanager to the lws owned fields
-	// If there are conflicts in the fields owned by the lws controller, lws will obtain the ownership and force override
-	// these fields to the ones desired by the lws controller
 	// TODO b/316776287 add E2E test for SSA
-	err = r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
-		FieldManager: fieldManager,
-		Force:        ptr.To[bool](true),
-	})
+	ctx, span := tracing.Start(ctx, "Apply leader StatefulSet", tracing.ObjectAttributes("StatefulSet", lws.Namespace, lws.Name)...)
+	err = applyStatefulSet(ctx, r.Client, leaderStatefulSetApplyConfig)
+	tracing.End(span, err)
 	if err != nil {
 		log.Error(err, "Using server side apply to update leader statefulset")
 		return err
@@ -334,22 +1772,24 @@ func (r *LeaderWorkerSetReconciler) SSAWithStatefulset(ctx context.Context, lws
 }
 
 // updates the condition of the leaderworkerset to either Progressing or Available.
-func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+// updateConditions updates the status of the groups, which are positioned from the start, e.g. the groups below
+// the start or above the replicas are not counted as the non bursted groups.
+func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start int32) (bool, error) {
 	log := ctrl.LoggerFrom(ctx)
-	stsSelector := client.MatchingLabels(map[string]string{
-		leaderworkerset.SetNameLabelKey: lws.Name,
-	})
-
 	// update the condition based on the status of all statefulsets owned by the lws.
 	var lwssts appsv1.StatefulSetList
-	if err := r.List(ctx, &lwssts, stsSelector, client.InNamespace(lws.Namespace)); err != nil {
+	if err := r.List(ctx, &lwssts, client.MatchingFields{statefulSetLwsKey: lws.Name}, client.InNamespace(lws.Namespace)); err != nil {
 		log.Error(err, "Fetching statefulsets managed by leaderworkerset instance")
 		return false, err
 	}
 
 	updateStatus := false
-	readyCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndReadyCount := 0, 0, 0, 0, 0
+	readyCount, availableCount, updatedCount, updatedNonBurstWorkerCount, currentNonBurstWorkerCount, updatedAndAvailableCount, failedCount := 0, 0, 0, 0, 0, 0, 0
+	now := time.Now()
 	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	var replicaStatuses []leaderworkerset.ReplicaStatus
+	leaderPods := map[int]corev1.Pod{}
+	readyGroups := map[int]bool{}
 
 	// Iterate through all statefulsets.
 	for _, sts := range lwssts.Items {
@@ -361,7 +1801,8 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 		if err != nil {
 			return false, err
 		}
-		if index < int(*lws.Spec.Replicas) {
+		nonBurst := index >= int(start) && index < int(start+utils.TotalReplicas(lws))
+		if nonBurst {
 			currentNonBurstWorkerCount++
 		}
 
@@ -371,54 +1812,181 @@ func (r *LeaderWorkerSetReconciler) updateConditions(ctx context.Context, lws *l
 			return false, err
 		}
 
-		var ready, updated bool
+		var ready, available, updated bool
 		if statefulsetutils.StatefulsetReady(sts) && podutils.PodRunningAndReady(leaderPod) {
 			ready = true
 			readyCount++
+			if groupAvailable(lws, sts, leaderPod, now) {
+				available = true
+				availableCount++
+			}
 		}
 		if sts.Labels[leaderworkerset.TemplateRevisionHashKey] == templateHash && leaderPod.Labels[leaderworkerset.TemplateRevisionHashKey] == templateHash {
 			updated = true
 			updatedCount++
-			if index < int(*lws.Spec.Replicas) {
-				// Bursted replicas do not count when determining if rollingUpdate has been completed.
-				updatedNonBurstWorkerCount++
-			}
+		}
+		// Replicas below the partition are not expected to be updated, so they don't block the rolling update,
+		// neither do the replicas with OnDelete, which are only updated once deleted.
+		expectedUpdated := updated || index < int(start+rollingUpdatePartition(lws)) || lws.Spec.RolloutStrategy.Type == leaderworkerset.OnDeleteStrategyType
+		if expectedUpdated && nonBurst {
+			// Bursted replicas do not count when determining if rollingUpdate has been completed.
+			updatedNonBurstWorkerCount++
 		}
 
-		if ready && updated {
+		if available && expectedUpdated {
 			// Bursted replicas should not be counted here.
-			if index < int(*lws.Spec.Replicas) {
-				updatedAndReadyCount++
+			if nonBurst {
+				updatedAndAvailableCount++
+			}
+		}
+		leaderPods[index] = leaderPod
+		readyGroups[index] = ready
+		replicaStatuses = append(replicaStatuses, makeReplicaStatus(int32(index), sts, leaderPod, ready, updated))
+	}
+
+	if lws.Spec.LeaderService != nil {
+		serving := servingGroups(lws, int(start), readyGroups)
+		for index := range leaderPods {
+			leaderPod := leaderPods[index]
+			if err := r.setGroupReadyLabel(ctx, &leaderPod, serving[index]); err != nil {
+				log.Error(err, "Setting group ready label on leader pod")
+				return false, err
+			}
+		}
+	}
+
+	replicaStatuses = carryOverRestarts(lws, start, replicaStatuses)
+	var succeededCount, finishedFailedCount int32
+	for _, replicaStatus := range replicaStatuses {
+		if replicaStatus.Phase == leaderworkerset.ReplicaFailed {
+			failedCount++
+		}
+		if groupFinished(replicaStatus) {
+			if replicaStatus.Phase == leaderworkerset.ReplicaSucceeded {
+				succeededCount++
+			} else {
+				finishedFailedCount++
 			}
 		}
 	}
+	if lws.Status.Succeeded != succeededCount || lws.Status.Failed != finishedFailedCount {
+		lws.Status.Succeeded = succeededCount
+		lws.Status.Failed = finishedFailedCount
+		updateStatus = true
+	}
+
+	sort.Slice(replicaStatuses, func(i, j int) bool {
+		return replicaStatuses[i].Index < replicaStatuses[j].Index
+	})
+	updating, _ := r.updatingGroups.LoadOrStore(lws.UID, sets.New[int32]())
+	for _, event := range groupEvents(lws.Status.ReplicaStatuses, replicaStatuses, updating.(sets.Set[int32])) {
+		r.Record.Eventf(lws, event.eventType, event.reason, event.message)
+		if leaderPod, found := leaderPods[int(event.index)]; found && (event.reason == GroupReady || event.reason == GroupUpdateCompleted) {
+			metrics.GroupReady(lws.Namespace, lws.Name, now.Sub(leaderPod.CreationTimestamp.Time))
+		}
+	}
+	if updating.(sets.Set[int32]).Len() == 0 {
+		r.updatingGroups.Delete(lws.UID)
+	}
+	if !equality.Semantic.DeepEqual(lws.Status.ReplicaStatuses, replicaStatuses) {
+		lws.Status.ReplicaStatuses = replicaStatuses
+		updateStatus = true
+	}
 
 	if lws.Status.ReadyReplicas != int32(readyCount) {
 		lws.Status.ReadyReplicas = int32(readyCount)
 		updateStatus = true
 	}
 
-	if lws.Status.UpdatedReplicas != int32(updatedCount) {
+	if lws.Status.AvailableReplicas != int32(availableCount) {
+		lws.Status.AvailableReplicas = int32(availableCount)
+		updateStatus = true
+	}
+
+	if current := currentRevision(lws, start, replicaStatuses, templateHash); lws.Status.CurrentRevision != current || lws.Status.UpdateRevision != templateHash {
+		lws.Status.CurrentRevision = current
+		lws.Status.UpdateRevision = templateHash
+		updateStatus = true
+	}
+
+	progressed := lws.Status.UpdatedReplicas != int32(updatedCount)
+	if progressed {
 		lws.Status.UpdatedReplicas = int32(updatedCount)
 		updateStatus = true
 	}
 
+	// upgradeInProgress is true when the upgrade replicas is smaller than the expected
+	// number of total replicas not including the burst replicas
+	upgradeInProgress := updatedNonBurstWorkerCount < currentNonBurstWorkerCount
+	if upgradeInProgress && (progressed || lws.Status.LastProgressTime == nil) {
+		lws.Status.LastProgressTime = ptr.To(metav1.Now())
+		updateStatus = true
+	} else if !upgradeInProgress && lws.Status.LastProgressTime != nil {
+		lws.Status.LastProgressTime = nil
+		updateStatus = true
+	}
+
 	var conditions []metav1.Condition
-	if updatedNonBurstWorkerCount < currentNonBurstWorkerCount {
-		// upgradeInProgress is true when the upgrade replicas is smaller than the expected
-		// number of total replicas not including the burst replicas
-		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetProgressing))
+	if upgradeInProgress {
+		progressingCondition := makeCondition(leaderworkerset.LeaderWorkerSetProgressing)
+		if exceeded, _ := progressDeadline(lws, time.Now()); exceeded {
+			progressingCondition.Status = metav1.ConditionFalse
+			progressingCondition.Reason = leaderworkerset.ProgressDeadlineExceededReason
+			progressingCondition.Message = fmt.Sprintf("Rolling update has not made progress in %d seconds", *lws.Spec.ProgressDeadlineSeconds)
+			if !progressDeadlineExceededRecorded(lws) {
+				r.Record.Eventf(lws, corev1.EventTypeWarning, leaderworkerset.ProgressDeadlineExceededReason, progressingCondition.Message)
+			}
+		}
+		conditions = append(conditions, progressingCondition)
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetUpgradeInProgress))
-	} else if updatedAndReadyCount == int(*lws.Spec.Replicas) {
+	} else if updatedAndAvailableCount == int(utils.TotalReplicas(lws)) {
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetAvailable))
 	} else {
 		conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetProgressing))
 	}
 
+	replicaFailure := makeCondition(leaderworkerset.LeaderWorkerSetReplicaFailure)
+	if failedCount > 0 {
+		replicaFailure.Message = fmt.Sprintf("%d groups are failed", failedCount)
+	} else {
+		replicaFailure.Status = metav1.ConditionFalse
+		replicaFailure.Reason = "NoGroupsFailed"
+		replicaFailure.Message = "No groups are failed"
+	}
+	conditions = append(conditions, replicaFailure)
+
+	resumed := makeCondition(leaderworkerset.LeaderWorkerSetSuspended)
+	resumed.Status = metav1.ConditionFalse
+	resumed.Reason = "Resumed"
+	resumed.Message = "LeaderWorkerSet is resumed"
+	conditions = append(conditions, resumed)
+
+	// The lws is finished once all the groups are finished in the run-to-completion mode.
+	if lws.Spec.TerminationPolicy == leaderworkerset.LeaderSucceededTerminationPolicy && succeededCount+finishedFailedCount >= *lws.Spec.Replicas {
+		if finishedFailedCount == 0 {
+			conditions = append(conditions, makeCondition(leaderworkerset.LeaderWorkerSetComplete))
+		} else {
+			failed := makeCondition(leaderworkerset.LeaderWorkerSetFailed)
+			failed.Reason = "GroupsFailed"
+			failed.Message = fmt.Sprintf("%d groups are failed by the failure policy", finishedFailedCount)
+			conditions = append(conditions, failed)
+		}
+	}
+
+	// The rolling update completes once the UpgradeInProgress condition is replaced by the Available one.
+	upgradeCondition := meta.FindStatusCondition(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetUpgradeInProgress))
+	upgradeStarted := upgradeCondition != nil && upgradeCondition.Status == metav1.ConditionTrue
+	var upgradeStartTime time.Time
+	if upgradeStarted {
+		upgradeStartTime = upgradeCondition.LastTransitionTime.Time
+	}
 	updateCondition := setConditions(lws, conditions)
+	if upgradeStarted && !meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetUpgradeInProgress)) {
+		metrics.RolloutCompleted(now.Sub(upgradeStartTime))
+	}
 	// if condition changed, record events
 	if updateCondition {
-		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, conditions[0].Message+fmt.Sprintf(", with %d groups ready of total %d groups", readyCount, int(*lws.Spec.Replicas)))
+		r.Record.Eventf(lws, corev1.EventTypeNormal, conditions[0].Reason, conditions[0].Message+fmt.Sprintf(", with %d groups ready of total %d groups", readyCount, int(utils.TotalReplicas(lws))))
 	}
 	return updateStatus || updateCondition, nil
 }
@@ -460,11 +2028,15 @@ func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leade
 	}
 
 	// check if an update is needed
-	updateConditions, err := r.updateConditions(ctx, lws)
+	updateConditions, err := r.updateConditions(ctx, lws, groupsStart(lws, sts))
+	if err != nil {
+		return err
+	}
+	updateTopologyCondition, err := r.updateTopologyKeyMissingCondition(ctx, lws)
 	if err != nil {
 		return err
 	}
-	if updateStatus || updateConditions {
+	if updateStatus || updateConditions || updateTopologyCondition {
 		if err := r.Status().Update(ctx, lws); err != nil {
 			log.Error(err, "Updating LeaderWorkerSet status and/or condition.")
 			return err
@@ -473,42 +2045,75 @@ func (r *LeaderWorkerSetReconciler) updateStatus(ctx context.Context, lws *leade
 	return nil
 }
 
+// updateTopologyKeyMissingCondition sets the TopologyKeyMissing condition once no node has the label keys of the
+// exclusive topology annotations, otherwise the groups stay pending forever silently, and removes it once they exist.
+// It returns true if the conditions are updated.
+func (r *LeaderWorkerSetReconciler) updateTopologyKeyMissingCondition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, error) {
+	var missingKeys []string
+	for _, annotationKey := range []string{leaderworkerset.ExclusiveKeyAnnotationKey, leaderworkerset.SubGroupExclusiveKeyAnnotationKey} {
+		topologyKey, found := lws.Annotations[annotationKey]
+		if !found || slices.Contains(missingKeys, topologyKey) {
+			continue
+		}
+		var nodeList corev1.NodeList
+		if err := r.List(ctx, &nodeList, client.HasLabels{topologyKey}, client.Limit(1)); err != nil {
+			return false, err
+		}
+		if len(nodeList.Items) == 0 {
+			missingKeys = append(missingKeys, topologyKey)
+		}
+	}
+
+	conditionType := string(leaderworkerset.LeaderWorkerSetTopologyKeyMissing)
+	if len(missingKeys) == 0 {
+		return meta.RemoveStatusCondition(&lws.Status.Conditions, conditionType), nil
+	}
+	message := fmt.Sprintf("No node has the exclusive topology label keys %s, the groups can't be scheduled", strings.Join(missingKeys, ", "))
+	if condition := meta.FindStatusCondition(lws.Status.Conditions, conditionType); condition != nil && condition.Message == message {
+		return false, nil
+	}
+	r.Record.Eventf(lws, corev1.EventTypeWarning, conditionType, message)
+	meta.SetStatusCondition(&lws.Status.Conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditionType,
+		Message: message,
+	})
+	return true, nil
+}
+
 // iterateReplicas will iterate the leader pods together with corresponding worker statefulsets
 // to check the replica state, and return two values and an error in the end:
-//   - The first value represents the number of continuous ready replicas ranging from the last index to 0,
+//   - The first value represents the number of continuous ready replicas ranging from the last index to the start,
 //     to help us judge whether we can update the Partition or not.
-//   - The second value represents the unready replicas whose index is smaller than leaderWorkerSet Replicas.
-func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, stsReplicas int32) (int32, int32, error) {
-	podSelector := client.MatchingLabels(map[string]string{
-		leaderworkerset.SetNameLabelKey:     lws.Name,
-		leaderworkerset.WorkerIndexLabelKey: "0",
-	})
+//   - The second value represents the unready replicas whose position from the start is smaller than leaderWorkerSet Replicas.
+func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, start, stsReplicas int32) (int32, int32, error) {
 	var leaderPodList corev1.PodList
-	if err := r.List(ctx, &leaderPodList, podSelector, client.InNamespace(lws.Namespace)); err != nil {
+	if err := r.List(ctx, &leaderPodList, client.MatchingFields{leaderPodKey: lws.Name}, client.InNamespace(lws.Namespace)); err != nil {
 		return 0, 0, err
 	}
 
 	// Get a sorted leader pod list matches with the following sorted statefulsets one by one, which means
 	// the leader pod and the corresponding worker statefulset has the same index.
 	sortedPods := utils.SortByIndex(func(pod corev1.Pod) (int, error) {
-		return strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		index, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+		return index - int(start), err
 	}, leaderPodList.Items, int(stsReplicas))
 
-	stsSelector := client.MatchingLabels(map[string]string{
-		leaderworkerset.SetNameLabelKey: lws.Name,
-	})
 	var stsList appsv1.StatefulSetList
-	if err := r.List(ctx, &stsList, stsSelector, client.InNamespace(lws.Namespace)); err != nil {
+	if err := r.List(ctx, &stsList, client.MatchingFields{statefulSetLwsKey: lws.Name}, client.InNamespace(lws.Namespace)); err != nil {
 		return 0, 0, err
 	}
 
 	sortedSts := utils.SortByIndex(func(sts appsv1.StatefulSet) (int, error) {
-		return strconv.Atoi(sts.Labels[leaderworkerset.GroupIndexLabelKey])
+		index, err := strconv.Atoi(sts.Labels[leaderworkerset.GroupIndexLabelKey])
+		return index - int(start), err
 	}, stsList.Items, int(stsReplicas))
 
 	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	now := time.Now()
 	processReplica := func(index int32) (ready bool) {
-		nominatedName := fmt.Sprintf("%s-%d", lws.Name, index)
+		nominatedName := fmt.Sprintf("%s-%d", lws.Name, start+index)
 		// It can happen that the leader pod or the worker statefulset hasn't created yet
 		// or under rebuilding, which also indicates not ready.
 		if nominatedName != sortedPods[index].Name || nominatedName != sortedSts[index].Name {
@@ -521,7 +2126,8 @@ func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *le
 		}
 
 		stsTemplateHash := sortedSts[index].Labels[leaderworkerset.TemplateRevisionHashKey]
-		return stsTemplateHash == templateHash && statefulsetutils.StatefulsetReady(sortedSts[index])
+		return stsTemplateHash == templateHash && statefulsetutils.StatefulsetReady(sortedSts[index]) &&
+			groupAvailable(lws, sortedSts[index], sortedPods[index], now)
 	}
 
 	var skip bool
@@ -529,12 +2135,21 @@ func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *le
 
 	for index := stsReplicas - 1; index >= 0; index-- {
 		replicaReady := processReplica(index)
+		// An updated group is only considered ready once its post update hook succeeds.
+		if replicaReady && lws.Spec.RolloutStrategy.Type == leaderworkerset.RollingUpdateStrategyType {
+			completed, err := r.rolloutHookCompleted(ctx, lws, hookutils.PostGroupUpdate, start+index)
+			if err != nil {
+				return 0, 0, err
+			}
+			replicaReady = completed
+		}
 		skip = skip || !replicaReady
 
 		if replicaReady && !skip {
 			continuousReadyReplicas++
 		}
-		if !replicaReady && index < *lws.Spec.Replicas {
+		// Replicas below the partition are not expected to be updated.
+		if !replicaReady && index < utils.TotalReplicas(lws) && index >= rollingUpdatePartition(lws) {
 			lwsUnreadyReplicas++
 		}
 	}
@@ -543,13 +2158,25 @@ func (r *LeaderWorkerSetReconciler) iterateReplicas(ctx context.Context, lws *le
 }
 
 // constructLeaderStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
-func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, partition, replicas int32) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
+func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWorkerSet, start, partition, replicas int32) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	var podTemplateSpec corev1.PodTemplateSpec
 	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
 		podTemplateSpec = *lws.Spec.LeaderWorkerTemplate.LeaderTemplate.DeepCopy()
 	} else {
 		podTemplateSpec = *lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
 	}
+	if policy := lws.Spec.LeaderWorkerTemplate.PriorityPolicy; policy != nil && policy.LeaderPriorityClassName != "" {
+		podTemplateSpec.Spec.PriorityClassName = policy.LeaderPriorityClassName
+	}
+	if prefetch := lws.Spec.LeaderWorkerTemplate.ModelPrefetch; prefetch != nil {
+		podutils.AddModelPrefetch(&podTemplateSpec.Spec, prefetch)
+	}
+	podutils.AddGroupSpreadConstraints(&podTemplateSpec.Spec, lws.Name, lws.Spec.LeaderWorkerTemplate.GroupSpreadConstraints)
+	if lws.Spec.DrainSeconds != nil {
+		pod := &corev1.Pod{Spec: podTemplateSpec.Spec}
+		podutils.AddReadinessGate(pod, leaderworkerset.ServingConditionType)
+		podTemplateSpec.Spec = pod.Spec
+	}
 	// construct pod template spec configuration
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTemplateSpec)
 	if err != nil {
@@ -572,6 +2199,41 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
 		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
 	}
+	if lws.Annotations[leaderworkerset.ExclusivePlacementPolicyAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.ExclusivePlacementPolicyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusivePlacementPolicyAnnotationKey]
+	}
+	if lws.Annotations[leaderworkerset.GangSchedulingAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.GangSchedulingAnnotationKey] = lws.Annotations[leaderworkerset.GangSchedulingAnnotationKey]
+	}
+	if lws.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.GroupSchedulingGateAnnotationKey] = lws.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey]
+	}
+	if lws.Annotations[leaderworkerset.GroupReadinessGateAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.GroupReadinessGateAnnotationKey] = lws.Annotations[leaderworkerset.GroupReadinessGateAnnotationKey]
+	}
+	if utils.SubdomainPolicy(lws) == leaderworkerset.SubdomainUniquePerReplica {
+		podAnnotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
+	}
+	if lws.Annotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey] = lws.Annotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey]
+	}
+	if lws.Annotations[leaderworkerset.RestartedAtAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.RestartedAtAnnotationKey] = lws.Annotations[leaderworkerset.RestartedAtAnnotationKey]
+	}
+	if lws.Spec.LeaderWorkerTemplate.EnvVarNaming != nil {
+		envVarNaming, err := json.Marshal(lws.Spec.LeaderWorkerTemplate.EnvVarNaming)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.EnvVarNamingAnnotationKey] = string(envVarNaming)
+	}
+	if len(lws.Spec.LeaderWorkerTemplate.TemplatedEnv) > 0 {
+		templatedEnv, err := json.Marshal(lws.Spec.LeaderWorkerTemplate.TemplatedEnv)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.TemplatedEnvAnnotationKey] = string(templatedEnv)
+	}
 	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
 		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
 		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
@@ -580,6 +2242,22 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 	}
 	podTemplateApplyConfiguration.WithAnnotations(podAnnotations)
 
+	var updateStrategy *appsapplyv1.StatefulSetUpdateStrategyApplyConfiguration
+	switch lws.Spec.RolloutStrategy.Type {
+	// Only the green groups are created with the new template with BlueGreen, the blue ones are never updated.
+	case leaderworkerset.OnDeleteStrategyType, leaderworkerset.BlueGreenStrategyType:
+		updateStrategy = appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.OnDeleteStatefulSetStrategyType)
+	// The groups not updated yet are protected by the partition with Canary.
+	case leaderworkerset.CanaryStrategyType:
+		updateStrategy = appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.RollingUpdateStatefulSetStrategyType).WithRollingUpdate(
+			appsapplyv1.RollingUpdateStatefulSetStrategy().WithPartition(partition),
+		)
+	default:
+		updateStrategy = appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.RollingUpdateStatefulSetStrategyType).WithRollingUpdate(
+			appsapplyv1.RollingUpdateStatefulSetStrategy().WithMaxUnavailable(lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable).WithPartition(partition),
+		)
+	}
+
 	// construct statefulset apply configuration
 	statefulSetConfig := appsapplyv1.StatefulSet(lws.Name, lws.Namespace).
 		WithSpec(appsapplyv1.StatefulSetSpec().
@@ -587,9 +2265,7 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 			WithReplicas(replicas).
 			WithPodManagementPolicy(appsv1.ParallelPodManagement).
 			WithTemplate(&podTemplateApplyConfiguration).
-			WithUpdateStrategy(appsapplyv1.StatefulSetUpdateStrategy().WithType(appsv1.StatefulSetUpdateStrategyType(lws.Spec.RolloutStrategy.Type)).WithRollingUpdate(
-				appsapplyv1.RollingUpdateStatefulSetStrategy().WithMaxUnavailable(lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable).WithPartition(partition),
-			)).
+			WithUpdateStrategy(updateStrategy).
 			WithSelector(metaapplyv1.LabelSelector().
 				WithMatchLabels(map[string]string{
 					leaderworkerset.SetNameLabelKey:     lws.Name,
@@ -600,11 +2276,195 @@ func constructLeaderStatefulSetApplyConfiguration(lws *leaderworkerset.LeaderWor
 			leaderworkerset.TemplateRevisionHashKey: templateHash,
 		}).
 		WithAnnotations(map[string]string{
-			leaderworkerset.ReplicasAnnotationKey: strconv.Itoa(int(*lws.Spec.Replicas)),
+			leaderworkerset.ReplicasAnnotationKey: strconv.Itoa(int(utils.TotalReplicas(lws))),
 		})
+	if start > 0 {
+		statefulSetConfig.Spec.WithOrdinals(appsapplyv1.StatefulSetOrdinals().WithStart(start))
+	}
+	if err := setVolumeClaimTemplates(statefulSetConfig, lws); err != nil {
+		return nil, err
+	}
 	return statefulSetConfig, nil
 }
 
+// setVolumeClaimTemplates passes the volumeClaimTemplates and the retention policy of the lws through
+// to the leader or worker statefulset.
+func setVolumeClaimTemplates(sts *appsapplyv1.StatefulSetApplyConfiguration, lws *leaderworkerset.LeaderWorkerSet) error {
+	for i := range lws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&lws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates[i])
+		if err != nil {
+			return err
+		}
+		var claim coreapplyv1.PersistentVolumeClaimApplyConfiguration
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &claim); err != nil {
+			return err
+		}
+		claim.Status = nil
+		sts.Spec.WithVolumeClaimTemplates(&claim)
+	}
+	if policy := lws.Spec.LeaderWorkerTemplate.PersistentVolumeClaimRetentionPolicy; policy != nil {
+		sts.Spec.WithPersistentVolumeClaimRetentionPolicy(appsapplyv1.StatefulSetPersistentVolumeClaimRetentionPolicy().
+			WithWhenDeleted(policy.WhenDeleted).
+			WithWhenScaled(policy.WhenScaled))
+	}
+	return nil
+}
+
+// groupAvailable returns true if all the pods of the ready group have been ready for at least minReadySeconds.
+func groupAvailable(lws *leaderworkerset.LeaderWorkerSet, sts appsv1.StatefulSet, leaderPod corev1.Pod, now time.Time) bool {
+	if lws.Spec.MinReadySeconds == 0 {
+		return true
+	}
+	return statefulsetutils.StatefulsetAvailable(sts) && podutils.PodAvailable(leaderPod, lws.Spec.MinReadySeconds, now)
+}
+
+// makeReplicaStatus builds the observed state of the group with the given index.
+func makeReplicaStatus(index int32, sts appsv1.StatefulSet, leaderPod corev1.Pod, ready, updated bool) leaderworkerset.ReplicaStatus {
+	var phase leaderworkerset.ReplicaPhase
+	switch {
+	case podutils.PodFailed(leaderPod):
+		phase = leaderworkerset.ReplicaFailed
+	case !updated:
+		phase = leaderworkerset.ReplicaUpdating
+	case ready:
+		phase = leaderworkerset.ReplicaReady
+	default:
+		phase = leaderworkerset.ReplicaPending
+	}
+	return leaderworkerset.ReplicaStatus{
+		Index:        index,
+		Phase:        phase,
+		ReadyWorkers: sts.Status.ReadyReplicas,
+		Revision:     leaderPod.Labels[leaderworkerset.TemplateRevisionHashKey],
+	}
+}
+
+// currentRevision returns the revision of the lowest indexed group not updated yet, which is the last
+// one to update, or the update revision once all the groups are updated. The replicaStatuses are sorted
+// by the group index.
+func currentRevision(lws *leaderworkerset.LeaderWorkerSet, start int32, replicaStatuses []leaderworkerset.ReplicaStatus, updateRevision string) string {
+	for _, status := range replicaStatuses {
+		if status.Index >= start && status.Index < start+utils.TotalReplicas(lws) && status.Revision != "" && status.Revision != updateRevision {
+			return status.Revision
+		}
+	}
+	return updateRevision
+}
+
+type groupEvent struct {
+	index     int32
+	eventType string
+	reason    string
+	message   string
+}
+
+// groupEvents returns the events of the group lifecycle transitions from the former to the new replica statuses,
+// the updating groups are the ones recreated at a new revision but not ready yet, and are updated accordingly.
+func groupEvents(oldStatuses, newStatuses []leaderworkerset.ReplicaStatus, updating sets.Set[int32]) []groupEvent {
+	oldByIndex := make(map[int32]leaderworkerset.ReplicaStatus, len(oldStatuses))
+	for _, status := range oldStatuses {
+		oldByIndex[status.Index] = status
+	}
+	observed := sets.New[int32]()
+	var events []groupEvent
+	for _, status := range newStatuses {
+		observed.Insert(status.Index)
+		old, found := oldByIndex[status.Index]
+		if !found {
+			events = append(events, groupEvent{status.Index, corev1.EventTypeNormal, GroupCreated, fmt.Sprintf("Group %d is created at revision %s", status.Index, status.Revision)})
+		} else if old.Revision != "" && status.Revision != "" && old.Revision != status.Revision {
+			updating.Insert(status.Index)
+			events = append(events, groupEvent{status.Index, corev1.EventTypeNormal, GroupUpdateStarted, fmt.Sprintf("Group %d is updating from revision %s to %s", status.Index, old.Revision, status.Revision)})
+		}
+		if status.Phase == leaderworkerset.ReplicaReady && old.Phase != leaderworkerset.ReplicaReady {
+			if updating.Has(status.Index) {
+				updating.Delete(status.Index)
+				events = append(events, groupEvent{status.Index, corev1.EventTypeNormal, GroupUpdateCompleted, fmt.Sprintf("Group %d is updated to revision %s and ready", status.Index, status.Revision)})
+			} else {
+				events = append(events, groupEvent{status.Index, corev1.EventTypeNormal, GroupReady, fmt.Sprintf("Group %d is ready", status.Index)})
+			}
+		}
+		if status.Phase == leaderworkerset.ReplicaFailed && old.Phase != leaderworkerset.ReplicaFailed {
+			message := fmt.Sprintf("Group %d is failed", status.Index)
+			if status.Reason != "" {
+				message += ": " + status.Reason
+			}
+			events = append(events, groupEvent{status.Index, corev1.EventTypeWarning, GroupFailed, message})
+		}
+	}
+	// The groups scaled down are not updating anymore.
+	for index := range updating {
+		if !observed.Has(index) {
+			updating.Delete(index)
+		}
+	}
+	return events
+}
+
+// carryOverRestarts keeps the restarts and preemptions of the groups recorded by the pod controller, since they
+// can not be observed from the statefulsets, so are the finished groups. All are reset once the group is updated
+// to a new revision.
+func carryOverRestarts(lws *leaderworkerset.LeaderWorkerSet, start int32, replicaStatuses []leaderworkerset.ReplicaStatus) []leaderworkerset.ReplicaStatus {
+	observed := make(map[int32]bool, len(replicaStatuses))
+	for i := range replicaStatuses {
+		observed[replicaStatuses[i].Index] = true
+		oldStatus := findReplicaStatus(lws, replicaStatuses[i].Index)
+		if oldStatus == nil || (oldStatus.Revision != "" && oldStatus.Revision != replicaStatuses[i].Revision) {
+			continue
+		}
+		replicaStatuses[i].Restarts = oldStatus.Restarts
+		replicaStatuses[i].LastRestartTime = oldStatus.LastRestartTime
+		replicaStatuses[i].Preempted = oldStatus.Preempted
+		// Once the group is succeeded, or failed by the failure policy, it stays finished until updated.
+		if groupFinished(*oldStatus) {
+			replicaStatuses[i].Phase = oldStatus.Phase
+			replicaStatuses[i].Reason = oldStatus.Reason
+		}
+	}
+	for _, oldStatus := range lws.Status.ReplicaStatuses {
+		if observed[oldStatus.Index] || oldStatus.Index < start || oldStatus.Index >= start+utils.TotalReplicas(lws) {
+			continue
+		}
+		// The worker statefulsets of the finished groups are deleted in the run-to-completion mode.
+		if groupFinished(oldStatus) {
+			replicaStatuses = append(replicaStatuses, oldStatus)
+			continue
+		}
+		// The group can be under recreation without statefulsets.
+		if oldStatus.Restarts > 0 || oldStatus.Preempted {
+			replicaStatuses = append(replicaStatuses, leaderworkerset.ReplicaStatus{
+				Index:           oldStatus.Index,
+				Phase:           leaderworkerset.ReplicaPending,
+				Revision:        oldStatus.Revision,
+				Restarts:        oldStatus.Restarts,
+				LastRestartTime: oldStatus.LastRestartTime,
+				Preempted:       oldStatus.Preempted,
+			})
+		}
+	}
+	return replicaStatuses
+}
+
+// groupRestartsExhausted returns true if the group has been recreated for the max restarts of the failure policy.
+func groupRestartsExhausted(lws *leaderworkerset.LeaderWorkerSet, replicaStatus leaderworkerset.ReplicaStatus) bool {
+	failurePolicy := lws.Spec.FailurePolicy
+	return failurePolicy != nil && failurePolicy.MaxRestarts != nil && replicaStatus.Restarts >= *failurePolicy.MaxRestarts
+}
+
+// groupFinished returns true if the group is succeeded, or failed by the failure policy.
+func groupFinished(status leaderworkerset.ReplicaStatus) bool {
+	return status.Phase == leaderworkerset.ReplicaSucceeded || (status.Phase == leaderworkerset.ReplicaFailed && status.Reason != "")
+}
+
+func findReplicaStatus(lws *leaderworkerset.LeaderWorkerSet, index int32) *leaderworkerset.ReplicaStatus {
+	for i := range lws.Status.ReplicaStatuses {
+		if lws.Status.ReplicaStatuses[i].Index == index {
+			return &lws.Status.ReplicaStatuses[i]
+		}
+	}
+	return nil
+}
+
 func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) metav1.Condition {
 	var condtype, reason, message string
 	switch conditionType {
@@ -616,6 +2476,22 @@ func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) m
 		condtype = string(leaderworkerset.LeaderWorkerSetUpgradeInProgress)
 		reason = "GroupsAreUpgrading"
 		message = "Rolling Upgrade is in progress"
+	case leaderworkerset.LeaderWorkerSetFailed:
+		condtype = string(leaderworkerset.LeaderWorkerSetFailed)
+		reason = "GroupFailed"
+		message = "A group is failed by the failure policy"
+	case leaderworkerset.LeaderWorkerSetSuspended:
+		condtype = string(leaderworkerset.LeaderWorkerSetSuspended)
+		reason = "Suspended"
+		message = "LeaderWorkerSet is suspended"
+	case leaderworkerset.LeaderWorkerSetComplete:
+		condtype = string(leaderworkerset.LeaderWorkerSetComplete)
+		reason = "AllGroupsSucceeded"
+		message = "All groups are succeeded"
+	case leaderworkerset.LeaderWorkerSetReplicaFailure:
+		condtype = string(leaderworkerset.LeaderWorkerSetReplicaFailure)
+		reason = "GroupsFailed"
+		message = "Some groups are failed"
 	default:
 		condtype = string(leaderworkerset.LeaderWorkerSetProgressing)
 		reason = "GroupsAreProgressing"
@@ -635,7 +2511,10 @@ func makeCondition(conditionType leaderworkerset.LeaderWorkerSetConditionType) m
 func setConditions(lws *leaderworkerset.LeaderWorkerSet, conditions []metav1.Condition) bool {
 	shouldUpdate := false
 	for _, condition := range conditions {
-		shouldUpdate = shouldUpdate || setCondition(lws, condition)
+		// Always set the condition, shouldUpdate must not short-circuit the call.
+		if setCondition(lws, condition) {
+			shouldUpdate = true
+		}
 	}
 
 	return shouldUpdate
@@ -690,6 +2569,232 @@ func exclusiveConditionTypes(condition1 metav1.Condition, condition2 metav1.Cond
 	return false
 }
 
+// progressDeadline returns whether the rolling update has made no progress within the progressDeadlineSeconds,
+// if not exceeded yet, it also returns the remaining time before the deadline.
+func progressDeadline(lws *leaderworkerset.LeaderWorkerSet, now time.Time) (bool, time.Duration) {
+	if lws.Spec.ProgressDeadlineSeconds == nil || lws.Status.LastProgressTime == nil {
+		return false, 0
+	}
+	deadline := lws.Status.LastProgressTime.Add(time.Duration(*lws.Spec.ProgressDeadlineSeconds) * time.Second)
+	if !now.Before(deadline) {
+		return true, 0
+	}
+	return false, deadline.Sub(now)
+}
+
+// progressDeadlineExceededRecorded returns true if the Progressing condition already reports the exceeded deadline.
+func progressDeadlineExceededRecorded(lws *leaderworkerset.LeaderWorkerSet) bool {
+	for _, condition := range lws.Status.Conditions {
+		if condition.Type == string(leaderworkerset.LeaderWorkerSetProgressing) {
+			return condition.Status == metav1.ConditionFalse && condition.Reason == leaderworkerset.ProgressDeadlineExceededReason
+		}
+	}
+	return false
+}
+
+// rollingUpdatePartition returns the user specified partition, groups with index below
+// it will not be updated during the rolling update.
+func rollingUpdatePartition(lws *leaderworkerset.LeaderWorkerSet) int32 {
+	if lws.Spec.RolloutStrategy.Type != leaderworkerset.RollingUpdateStrategyType || lws.Spec.RolloutStrategy.RollingUpdateConfiguration == nil {
+		return 0
+	}
+	return min(lws.Spec.RolloutStrategy.RollingUpdateConfiguration.Partition, utils.TotalReplicas(lws))
+}
+
+// statefulSetStart returns the first ordinal of the statefulset.
+func statefulSetStart(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.Ordinals == nil {
+		return 0
+	}
+	return sts.Spec.Ordinals.Start
+}
+
+// statefulSetPartition returns the partition of the statefulset relative to its start, 0 if the statefulset
+// is not rolling updated.
+func statefulSetPartition(sts *appsv1.StatefulSet) int32 {
+	if sts.Spec.UpdateStrategy.RollingUpdate == nil {
+		return 0
+	}
+	return max(0, ptr.Deref(sts.Spec.UpdateStrategy.RollingUpdate.Partition, 0)-statefulSetStart(sts))
+}
+
+// blueGroups returns the groups [start, end) replaced by the BlueGreen rollout in progress, if any.
+func blueGroups(sts *appsv1.StatefulSet) (int32, int32, bool) {
+	first, last, found := strings.Cut(sts.Annotations[leaderworkerset.BlueGroupsAnnotationKey], "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err := strconv.Atoi(last)
+	if err != nil || end < start {
+		return 0, 0, false
+	}
+	return int32(start), int32(end) + 1, true
+}
+
+// greenGroups returns the groups [start, end) of the leader statefulset replacing the blue ones, they're either
+// below or above the blue groups.
+func greenGroups(sts *appsv1.StatefulSet, blueStart, blueEnd int32) (int32, int32) {
+	start, end := statefulSetStart(sts), statefulSetStart(sts)+*sts.Spec.Replicas
+	if blueStart > start {
+		return start, min(blueStart, end)
+	}
+	return min(max(blueEnd, start), end), end
+}
+
+// groupsStart returns the index of the first group counted in the status, i.e. the first blue group during a
+// BlueGreen rollout, otherwise the start of the leader statefulset.
+func groupsStart(lws *leaderworkerset.LeaderWorkerSet, sts *appsv1.StatefulSet) int32 {
+	if lws.Spec.RolloutStrategy.Type == leaderworkerset.BlueGreenStrategyType {
+		if start, _, found := blueGroups(sts); found {
+			return start
+		}
+	}
+	return statefulSetStart(sts)
+}
+
 func templateUpdated(sts *appsv1.StatefulSet, lws *leaderworkerset.LeaderWorkerSet) bool {
 	return sts.Labels[leaderworkerset.TemplateRevisionHashKey] != utils.LeaderWorkerTemplateHash(lws)
 }
+
+func leaderServiceName(lws *leaderworkerset.LeaderWorkerSet) string {
+	return fmt.Sprintf("%s-leader", lws.Name)
+}
+
+// constructLeaderService returns the Service selecting the leader pods of the ready groups.
+func constructLeaderService(lws *leaderworkerset.LeaderWorkerSet) *corev1.Service {
+	serviceType := lws.Spec.LeaderService.Type
+	if serviceType == "" {
+		serviceType = corev1.ServiceTypeClusterIP
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      leaderServiceName(lws),
+			Namespace: lws.Namespace,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey: lws.Name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Type:  serviceType,
+			Ports: defaultServicePorts(lws.Spec.LeaderService.Ports),
+			Selector: map[string]string{
+				leaderworkerset.SetNameLabelKey:     lws.Name,
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.GroupReadyLabelKey:  "true",
+			},
+		},
+	}
+	setIPFamilies(service, lws)
+	return service
+}
+
+func canaryServiceName(lws *leaderworkerset.LeaderWorkerSet, track string) string {
+	return fmt.Sprintf("%s-%s", lws.Name, track)
+}
+
+// constructCanaryService returns the Service selecting the leader pods of the given revision in the canary rollout.
+func constructCanaryService(lws *leaderworkerset.LeaderWorkerSet, name, templateHash string) *corev1.Service {
+	port := lws.Spec.RolloutStrategy.CanaryConfiguration.Port
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: lws.Namespace,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey: lws.Name,
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{Port: port, TargetPort: intstr.FromInt32(port), Protocol: corev1.ProtocolTCP}},
+			Selector: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.TemplateRevisionHashKey: templateHash,
+			},
+		},
+	}
+}
+
+// canaryReplicas returns the number of groups updated with the given weight, at least one group
+// is updated with a positive weight.
+func canaryReplicas(replicas, weight int32) int32 {
+	return int32(math.Ceil(float64(replicas) * float64(weight) / 100))
+}
+
+// canaryWeight returns the percentage of the traffic shifted to the current revision in the canary rollout,
+// the weight of a step only applies once its groups are ready.
+func canaryWeight(lws *leaderworkerset.LeaderWorkerSet) int32 {
+	status := lws.Status.Canary
+	steps := lws.Spec.RolloutStrategy.CanaryConfiguration.Steps
+	if status.StableRevision == utils.LeaderWorkerTemplateHash(lws) {
+		return 0
+	}
+	step := min(int(status.Step), len(steps))
+	if step < len(steps) && status.StepStartTime != nil {
+		return steps[step].Weight
+	}
+	if step == 0 {
+		return 0
+	}
+	return steps[step-1].Weight
+}
+
+// canaryPauseRemaining returns the remaining time to pause in the canary step started at the given time.
+func canaryPauseRemaining(step leaderworkerset.CanaryStep, startTime *metav1.Time, now time.Time) time.Duration {
+	if startTime == nil {
+		return 0
+	}
+	return max(startTime.Add(time.Duration(step.PauseSeconds)*time.Second).Sub(now), 0)
+}
+
+// constructPodDisruptionBudget returns the PodDisruptionBudget covering the leader pods of the lws,
+// each leader pod stands for its group.
+func constructPodDisruptionBudget(lws *leaderworkerset.LeaderWorkerSet) *policyv1.PodDisruptionBudget {
+	maxUnavailable := lws.Spec.DisruptionBudget.MaxUnavailable
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      lws.Name,
+			Namespace: lws.Namespace,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey: lws.Name,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     lws.Name,
+					leaderworkerset.WorkerIndexLabelKey: "0",
+				},
+			},
+		},
+	}
+}
+
+// setIPFamilies sets the IP families of the service per the network config of the lws,
+// the cluster defaults are used if not specified.
+func setIPFamilies(service *corev1.Service, lws *leaderworkerset.LeaderWorkerSet) {
+	if lws.Spec.NetworkConfig == nil {
+		return
+	}
+	service.Spec.IPFamilyPolicy = lws.Spec.NetworkConfig.IPFamilyPolicy
+	service.Spec.IPFamilies = lws.Spec.NetworkConfig.IPFamilies
+}
+
+// defaultServicePorts defaults the ports the same way as the apiserver does.
+func defaultServicePorts(servicePorts []corev1.ServicePort) []corev1.ServicePort {
+	var ports []corev1.ServicePort
+	for _, port := range servicePorts {
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort == (intstr.IntOrString{}) {
+			port.TargetPort = intstr.FromInt32(port.Port)
+		}
+		ports = append(ports, port)
+	}
+	return ports
+}
@@ -17,20 +17,42 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/schedulerprovider"
 	"sigs.k8s.io/lws/pkg/utils"
+	hookutils "sigs.k8s.io/lws/pkg/utils/hook"
+	kueueutils "sigs.k8s.io/lws/pkg/utils/kueue"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	testutils "sigs.k8s.io/lws/test/testutils"
 )
 
@@ -383,7 +405,7 @@ func TestLeaderStatefulSetApplyConfig(t *testing.T) {
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, *tc.lws.Spec.Replicas)
+			stsApplyConfig, err := constructLeaderStatefulSetApplyConfiguration(tc.lws, 0, 0, *tc.lws.Spec.Replicas)
 			if err != nil {
 				t.Errorf("failed with error: %s", err.Error())
 			}
@@ -440,6 +462,11 @@ func TestExclusiveConditionTypes(t *testing.T) {
 			condition1: metav1.Condition{Type: "UpgradeInProgress"},
 			condition2: metav1.Condition{Type: "Progressing"},
 		},
+		{
+			name:       "First Condition ReplicaFailure, second Available",
+			condition1: metav1.Condition{Type: "ReplicaFailure"},
+			condition2: metav1.Condition{Type: "Available"},
+		},
 	}
 
 	for _, tc := range tests {
@@ -514,3 +541,1988 @@ func TestSetCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestSetConditions(t *testing.T) {
+	lws := testutils.BuildBasicLeaderWorkerSet("test-sample", "default").
+		Conditions([]metav1.Condition{{Type: "Available", Status: "True"}}).
+		Obj()
+	conditions := []metav1.Condition{
+		makeCondition(leaderworkerset.LeaderWorkerSetProgressing),
+		makeCondition(leaderworkerset.LeaderWorkerSetUpgradeInProgress),
+		makeCondition(leaderworkerset.LeaderWorkerSetReplicaFailure),
+	}
+	if !setConditions(lws, conditions) {
+		t.Errorf("Expected conditions to be updated")
+	}
+
+	got := map[string]metav1.ConditionStatus{}
+	for _, condition := range lws.Status.Conditions {
+		got[condition.Type] = condition.Status
+	}
+	want := map[string]metav1.ConditionStatus{
+		"Available":         metav1.ConditionFalse,
+		"Progressing":       metav1.ConditionTrue,
+		"UpgradeInProgress": metav1.ConditionTrue,
+		"ReplicaFailure":    metav1.ConditionTrue,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unexpected conditions (-want,+got):\n%s", diff)
+	}
+}
+
+func TestMakeReplicaStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		leaderPod  corev1.Pod
+		ready      bool
+		updated    bool
+		wantStatus leaderworkerset.ReplicaStatus
+	}{
+		{
+			name: "ready and updated group",
+			leaderPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.TemplateRevisionHashKey: "hash"}},
+			},
+			ready:      true,
+			updated:    true,
+			wantStatus: leaderworkerset.ReplicaStatus{Index: 1, Phase: leaderworkerset.ReplicaReady, ReadyWorkers: 2, Revision: "hash"},
+		},
+		{
+			name: "not ready but updated group",
+			leaderPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.TemplateRevisionHashKey: "hash"}},
+			},
+			updated:    true,
+			wantStatus: leaderworkerset.ReplicaStatus{Index: 1, Phase: leaderworkerset.ReplicaPending, ReadyWorkers: 2, Revision: "hash"},
+		},
+		{
+			name: "ready but outdated group",
+			leaderPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.TemplateRevisionHashKey: "old-hash"}},
+			},
+			ready:      true,
+			wantStatus: leaderworkerset.ReplicaStatus{Index: 1, Phase: leaderworkerset.ReplicaUpdating, ReadyWorkers: 2, Revision: "old-hash"},
+		},
+		{
+			name: "failed leader pod",
+			leaderPod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{leaderworkerset.TemplateRevisionHashKey: "hash"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+			},
+			updated:    true,
+			wantStatus: leaderworkerset.ReplicaStatus{Index: 1, Phase: leaderworkerset.ReplicaFailed, ReadyWorkers: 2, Revision: "hash"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sts := appsv1.StatefulSet{Status: appsv1.StatefulSetStatus{ReadyReplicas: 2}}
+			status := makeReplicaStatus(1, sts, tc.leaderPod, tc.ready, tc.updated)
+			if diff := cmp.Diff(tc.wantStatus, status); diff != "" {
+				t.Errorf("unexpected replica status: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestCurrentRevision(t *testing.T) {
+	tests := []struct {
+		name            string
+		replicaStatuses []leaderworkerset.ReplicaStatus
+		wantRevision    string
+	}{
+		{
+			name:            "all groups updated",
+			replicaStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Revision: "new"}, {Index: 1, Revision: "new"}},
+			wantRevision:    "new",
+		},
+		{
+			name:            "rolling update in progress",
+			replicaStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Revision: "old"}, {Index: 1, Revision: "new"}},
+			wantRevision:    "old",
+		},
+		{
+			name:            "burst group is not counted",
+			replicaStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Revision: "new"}, {Index: 1, Revision: "new"}, {Index: 2, Revision: "old"}},
+			wantRevision:    "new",
+		},
+		{
+			name:            "group without leader pod is skipped",
+			replicaStatuses: []leaderworkerset.ReplicaStatus{{Index: 0}, {Index: 1, Revision: "old"}},
+			wantRevision:    "old",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(2).Obj()
+			if got := currentRevision(lws, 0, tc.replicaStatuses, "new"); got != tc.wantRevision {
+				t.Errorf("Expected revision %s, got %s", tc.wantRevision, got)
+			}
+		})
+	}
+}
+
+func TestGroupEvents(t *testing.T) {
+	tests := []struct {
+		name         string
+		oldStatuses  []leaderworkerset.ReplicaStatus
+		newStatuses  []leaderworkerset.ReplicaStatus
+		updating     []int32
+		wantReasons  []string
+		wantUpdating []int32
+	}{
+		{
+			name:        "group created",
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "old"}},
+			wantReasons: []string{GroupCreated},
+		},
+		{
+			name:        "group ready",
+			oldStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "old"}},
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "old"}},
+			wantReasons: []string{GroupReady},
+		},
+		{
+			name:         "group update started",
+			oldStatuses:  []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaUpdating, Revision: "old"}},
+			newStatuses:  []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "new"}},
+			wantReasons:  []string{GroupUpdateStarted},
+			wantUpdating: []int32{0},
+		},
+		{
+			name:        "group update completed",
+			oldStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "new"}},
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "new"}},
+			updating:    []int32{0},
+			wantReasons: []string{GroupUpdateCompleted},
+		},
+		{
+			name:        "group updated and ready at once",
+			oldStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaUpdating, Revision: "old"}},
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "new"}},
+			wantReasons: []string{GroupUpdateStarted, GroupUpdateCompleted},
+		},
+		{
+			name:        "group failed",
+			oldStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "old"}},
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "old"}},
+			wantReasons: []string{GroupFailed},
+		},
+		{
+			name:        "updating group scaled down",
+			oldStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "new"}, {Index: 1, Phase: leaderworkerset.ReplicaPending, Revision: "new"}},
+			newStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "new"}},
+			updating:    []int32{1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			updating := sets.New(tc.updating...)
+			var reasons []string
+			for _, event := range groupEvents(tc.oldStatuses, tc.newStatuses, updating) {
+				reasons = append(reasons, event.reason)
+			}
+			if diff := cmp.Diff(tc.wantReasons, reasons); diff != "" {
+				t.Errorf("Unexpected event reasons (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantUpdating, sets.List(updating), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("Unexpected updating groups (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRollingUpdatePartition(t *testing.T) {
+	tests := []struct {
+		name          string
+		lws           *leaderworkerset.LeaderWorkerSet
+		wantPartition int32
+	}{
+		{
+			name:          "partition not set",
+			lws:           testutils.BuildLeaderWorkerSet("default").Replica(4).Obj(),
+			wantPartition: 0,
+		},
+		{
+			name:          "partition smaller than replicas",
+			lws:           testutils.BuildLeaderWorkerSet("default").Replica(4).Partition(2).Obj(),
+			wantPartition: 2,
+		},
+		{
+			name:          "partition larger than replicas",
+			lws:           testutils.BuildLeaderWorkerSet("default").Replica(4).Partition(6).Obj(),
+			wantPartition: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rollingUpdatePartition(tc.lws); got != tc.wantPartition {
+				t.Errorf("Expected partition %d, got %d", tc.wantPartition, got)
+			}
+		})
+	}
+}
+
+func TestProgressDeadline(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name             string
+		deadlineSeconds  *int32
+		lastProgressTime *metav1.Time
+		wantExceeded     bool
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:             "progress deadline not set",
+			lastProgressTime: &metav1.Time{Time: now.Add(-time.Hour)},
+		},
+		{
+			name:            "no rolling update in progress",
+			deadlineSeconds: ptr.To[int32](600),
+		},
+		{
+			name:             "progress deadline not exceeded",
+			deadlineSeconds:  ptr.To[int32](600),
+			lastProgressTime: &metav1.Time{Time: now.Add(-time.Minute)},
+			wantRequeueAfter: 9 * time.Minute,
+		},
+		{
+			name:             "progress deadline exceeded",
+			deadlineSeconds:  ptr.To[int32](600),
+			lastProgressTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			wantExceeded:     true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.ProgressDeadlineSeconds = tc.deadlineSeconds
+			lws.Status.LastProgressTime = tc.lastProgressTime
+			exceeded, requeueAfter := progressDeadline(lws, now)
+			if exceeded != tc.wantExceeded {
+				t.Errorf("Expected exceeded %t, got %t", tc.wantExceeded, exceeded)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestTTLAfterFinished(t *testing.T) {
+	now := time.Now()
+	finishedCondition := func(conditionType leaderworkerset.LeaderWorkerSetConditionType, status metav1.ConditionStatus, since time.Duration) metav1.Condition {
+		return metav1.Condition{Type: string(conditionType), Status: status, LastTransitionTime: metav1.NewTime(now.Add(-since))}
+	}
+	tests := []struct {
+		name             string
+		ttlSeconds       *int32
+		conditions       []metav1.Condition
+		wantExpired      bool
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:       "ttl not set",
+			conditions: []metav1.Condition{finishedCondition(leaderworkerset.LeaderWorkerSetComplete, metav1.ConditionTrue, time.Hour)},
+		},
+		{
+			name:       "not finished",
+			ttlSeconds: ptr.To[int32](600),
+			conditions: []metav1.Condition{finishedCondition(leaderworkerset.LeaderWorkerSetComplete, metav1.ConditionFalse, time.Hour)},
+		},
+		{
+			name:             "complete and not expired",
+			ttlSeconds:       ptr.To[int32](600),
+			conditions:       []metav1.Condition{finishedCondition(leaderworkerset.LeaderWorkerSetComplete, metav1.ConditionTrue, time.Minute)},
+			wantRequeueAfter: 9 * time.Minute,
+		},
+		{
+			name:        "failed and expired",
+			ttlSeconds:  ptr.To[int32](600),
+			conditions:  []metav1.Condition{finishedCondition(leaderworkerset.LeaderWorkerSetFailed, metav1.ConditionTrue, time.Hour)},
+			wantExpired: true,
+		},
+		{
+			name:        "expired immediately",
+			ttlSeconds:  ptr.To[int32](0),
+			conditions:  []metav1.Condition{finishedCondition(leaderworkerset.LeaderWorkerSetComplete, metav1.ConditionTrue, 0)},
+			wantExpired: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.TTLSecondsAfterFinished = tc.ttlSeconds
+			lws.Status.Conditions = tc.conditions
+			expired, requeueAfter := ttlAfterFinished(lws, now)
+			if expired != tc.wantExpired {
+				t.Errorf("Expected expired %t, got %t", tc.wantExpired, expired)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestCarryOverRestarts(t *testing.T) {
+	lastRestartTime := metav1.Now()
+	tests := []struct {
+		name                string
+		oldReplicaStatuses  []leaderworkerset.ReplicaStatus
+		replicaStatuses     []leaderworkerset.ReplicaStatus
+		wantReplicaStatuses []leaderworkerset.ReplicaStatus
+	}{
+		{
+			name: "restarts are kept for the same revision",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "v1", Restarts: 1, LastRestartTime: &lastRestartTime},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1", Restarts: 1, LastRestartTime: &lastRestartTime},
+			},
+		},
+		{
+			name: "restarts are reset once updated",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Restarts: 2, LastRestartTime: &lastRestartTime},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "v2"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "v2"},
+			},
+		},
+		{
+			name: "group exceeded the max restarts stays failed",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Restarts: 2, LastRestartTime: &lastRestartTime, Reason: "MaxRestartsExceeded"},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaPending, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Restarts: 2, LastRestartTime: &lastRestartTime, Reason: "MaxRestartsExceeded"},
+			},
+		},
+		{
+			name: "group failed by a failure policy rule stays failed",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Reason: "FailurePolicyRuleMatched"},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Reason: "FailurePolicyRuleMatched"},
+			},
+		},
+		{
+			name: "succeeded group stays succeeded",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaSucceeded, Revision: "v1"},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaSucceeded, Revision: "v1"},
+			},
+		},
+		{
+			name: "finished groups are kept without statefulsets",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaSucceeded, Revision: "v1"},
+				{Index: 1, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Reason: "MaxRestartsExceeded"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaSucceeded, Revision: "v1"},
+				{Index: 1, Phase: leaderworkerset.ReplicaFailed, Revision: "v1", Reason: "MaxRestartsExceeded"},
+			},
+		},
+		{
+			name: "group failed by unready pods is not sticky",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaFailed, Revision: "v1"},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+		},
+		{
+			name: "group under recreation is kept",
+			oldReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+				{Index: 1, Phase: leaderworkerset.ReplicaReady, Revision: "v1", Restarts: 1, LastRestartTime: &lastRestartTime},
+			},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+			},
+			wantReplicaStatuses: []leaderworkerset.ReplicaStatus{
+				{Index: 0, Phase: leaderworkerset.ReplicaReady, Revision: "v1"},
+				{Index: 1, Phase: leaderworkerset.ReplicaPending, Revision: "v1", Restarts: 1, LastRestartTime: &lastRestartTime},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(2).Obj()
+			lws.Spec.FailurePolicy = &leaderworkerset.FailurePolicy{MaxRestarts: ptr.To[int32](2)}
+			lws.Status.ReplicaStatuses = tc.oldReplicaStatuses
+			got := carryOverRestarts(lws, 0, tc.replicaStatuses)
+			if diff := cmp.Diff(tc.wantReplicaStatuses, got); diff != "" {
+				t.Errorf("Unexpected replica statuses (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConstructLeaderService(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.LeaderService = &leaderworkerset.LeaderService{
+		Ports: []corev1.ServicePort{
+			{Name: "http", Port: 8080},
+			{Name: "grpc", Port: 9000, TargetPort: intstr.FromString("grpc"), Protocol: corev1.ProtocolTCP},
+		},
+	}
+	want := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-leader",
+			Namespace: "default",
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeClusterIP,
+			Ports: []corev1.ServicePort{
+				{Name: "http", Port: 8080, TargetPort: intstr.FromInt32(8080), Protocol: corev1.ProtocolTCP},
+				{Name: "grpc", Port: 9000, TargetPort: intstr.FromString("grpc"), Protocol: corev1.ProtocolTCP},
+			},
+			Selector: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+				leaderworkerset.GroupReadyLabelKey:  "true",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, constructLeaderService(lws)); diff != "" {
+		t.Errorf("Unexpected leader service (-want,+got):\n%s", diff)
+	}
+}
+
+func TestConstructPodDisruptionBudget(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.DisruptionBudget = &leaderworkerset.DisruptionBudget{MaxUnavailable: intstr.FromString("25%")}
+	want := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample",
+			Namespace: "default",
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: ptr.To(intstr.FromString("25%")),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, constructPodDisruptionBudget(lws)); diff != "" {
+		t.Errorf("Unexpected pod disruption budget (-want,+got):\n%s", diff)
+	}
+}
+
+func TestConstructHeadlessService(t *testing.T) {
+	selector := map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"}
+	tests := []struct {
+		name          string
+		networkConfig *leaderworkerset.NetworkConfig
+		want          *corev1.Service
+	}{
+		{
+			name: "default headless service",
+			want: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", Labels: selector},
+				Spec: corev1.ServiceSpec{
+					ClusterIP:                "None",
+					Selector:                 selector,
+					PublishNotReadyAddresses: true,
+				},
+			},
+		},
+		{
+			name: "customized headless service",
+			networkConfig: &leaderworkerset.NetworkConfig{
+				HeadlessService: &leaderworkerset.HeadlessService{
+					PublishNotReadyAddresses: ptr.To(false),
+					Ports:                    []corev1.ServicePort{{Name: "nccl", Port: 29500}},
+					Labels:                   map[string]string{"team": "infra"},
+					Annotations:              map[string]string{"example.com/scrape": "true"},
+				},
+			},
+			want: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-sample",
+					Namespace:   "default",
+					Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", "team": "infra"},
+					Annotations: map[string]string{"example.com/scrape": "true"},
+				},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: "None",
+					Selector:  selector,
+					Ports: []corev1.ServicePort{
+						{Name: "nccl", Port: 29500, TargetPort: intstr.FromInt32(29500), Protocol: corev1.ProtocolTCP},
+					},
+				},
+			},
+		},
+		{
+			name: "dual stack headless service",
+			networkConfig: &leaderworkerset.NetworkConfig{
+				IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicyPreferDualStack),
+				IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			},
+			want: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sample", Namespace: "default", Labels: selector},
+				Spec: corev1.ServiceSpec{
+					ClusterIP:                "None",
+					Selector:                 selector,
+					PublishNotReadyAddresses: true,
+					IPFamilyPolicy:           ptr.To(corev1.IPFamilyPolicyPreferDualStack),
+					IPFamilies:               []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.NetworkConfig = tc.networkConfig
+			got := constructHeadlessService(lws, lws.Name, lws.Namespace, selector)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Unexpected headless service (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGroupAvailable(t *testing.T) {
+	now := time.Now()
+	leaderPod := func(readySince time.Time) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+			Type:               corev1.PodReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(readySince),
+		}}}}
+	}
+	workerSts := func(availableReplicas int32) appsv1.StatefulSet {
+		return appsv1.StatefulSet{
+			Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+			Status: appsv1.StatefulSetStatus{AvailableReplicas: availableReplicas},
+		}
+	}
+	tests := []struct {
+		name            string
+		minReadySeconds int32
+		sts             appsv1.StatefulSet
+		leaderPod       corev1.Pod
+		want            bool
+	}{
+		{
+			name:      "ready group without minReadySeconds",
+			sts:       workerSts(0),
+			leaderPod: leaderPod(now),
+			want:      true,
+		},
+		{
+			name:            "workers not available",
+			minReadySeconds: 10,
+			sts:             workerSts(1),
+			leaderPod:       leaderPod(now.Add(-time.Minute)),
+		},
+		{
+			name:            "leader not available",
+			minReadySeconds: 10,
+			sts:             workerSts(2),
+			leaderPod:       leaderPod(now),
+		},
+		{
+			name:            "group available",
+			minReadySeconds: 10,
+			sts:             workerSts(2),
+			leaderPod:       leaderPod(now.Add(-time.Minute)),
+			want:            true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.MinReadySeconds = tc.minReadySeconds
+			if got := groupAvailable(lws, tc.sts, tc.leaderPod, now); got != tc.want {
+				t.Errorf("Expected group available to be %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPriorityPolicy(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.LeaderWorkerTemplate.PriorityPolicy = &leaderworkerset.PriorityPolicy{
+		LeaderPriorityClassName: "high-priority",
+		WorkerPriorityClassName: "low-priority",
+	}
+	leaderSts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to construct the leader statefulset: %v", err)
+	}
+	if diff := cmp.Diff(ptr.To("high-priority"), leaderSts.Spec.Template.Spec.PriorityClassName); diff != "" {
+		t.Errorf("Unexpected leader priority class (-want,+got):\n%s", diff)
+	}
+	leaderPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-sample-0",
+		Namespace: "default",
+		Labels:    map[string]string{leaderworkerset.GroupIndexLabelKey: "0"},
+	}}
+	workerSts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws)
+	if err != nil {
+		t.Fatalf("Failed to construct the worker statefulset: %v", err)
+	}
+	if diff := cmp.Diff(ptr.To("low-priority"), workerSts.Spec.Template.Spec.PriorityClassName); diff != "" {
+		t.Errorf("Unexpected worker priority class (-want,+got):\n%s", diff)
+	}
+}
+
+func TestVolumeClaimTemplates(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{Name: "model"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")},
+			},
+		},
+	}}
+	lws.Spec.LeaderWorkerTemplate.PersistentVolumeClaimRetentionPolicy = &appsv1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenDeleted: appsv1.DeletePersistentVolumeClaimRetentionPolicyType,
+		WhenScaled:  appsv1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	wantClaims := []coreapplyv1.PersistentVolumeClaimApplyConfiguration{{
+		ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{Name: ptr.To("model")},
+		Spec: coreapplyv1.PersistentVolumeClaimSpec().
+			WithAccessModes(corev1.ReadWriteOnce).
+			WithResources(coreapplyv1.VolumeResourceRequirements().
+				WithRequests(corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("100Gi")})),
+	}}
+	wantPolicy := appsapplyv1.StatefulSetPersistentVolumeClaimRetentionPolicy().
+		WithWhenDeleted(appsv1.DeletePersistentVolumeClaimRetentionPolicyType).
+		WithWhenScaled(appsv1.RetainPersistentVolumeClaimRetentionPolicyType)
+
+	leaderSts, err := constructLeaderStatefulSetApplyConfiguration(lws, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to construct the leader statefulset: %v", err)
+	}
+	leaderPod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-sample-0",
+		Namespace: "default",
+		Labels:    map[string]string{leaderworkerset.GroupIndexLabelKey: "0"},
+	}}
+	workerSts, err := constructWorkerStatefulSetApplyConfiguration(leaderPod, *lws)
+	if err != nil {
+		t.Fatalf("Failed to construct the worker statefulset: %v", err)
+	}
+	for _, sts := range []*appsapplyv1.StatefulSetApplyConfiguration{leaderSts, workerSts} {
+		if diff := cmp.Diff(wantClaims, sts.Spec.VolumeClaimTemplates); diff != "" {
+			t.Errorf("Unexpected volumeClaimTemplates of %s (-want,+got):\n%s", *sts.Name, diff)
+		}
+		if diff := cmp.Diff(wantPolicy, sts.Spec.PersistentVolumeClaimRetentionPolicy); diff != "" {
+			t.Errorf("Unexpected retention policy of %s (-want,+got):\n%s", *sts.Name, diff)
+		}
+	}
+}
+
+func TestRestartGroupIfRequested(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                string
+		restartGroup        string
+		wantLeaderAnnotated bool
+	}{
+		{
+			name:                "restart an existing group",
+			restartGroup:        "1",
+			wantLeaderAnnotated: true,
+		},
+		{
+			name:         "group index out of range",
+			restartGroup: "2",
+		},
+		{
+			name:         "invalid group index",
+			restartGroup: "one",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(2).
+				Annotation(map[string]string{leaderworkerset.RestartGroupAnnotationKey: tc.restartGroup}).Obj()
+			leaderPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-sample-1", Namespace: "default"}}
+			r := &LeaderWorkerSetReconciler{
+				Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(lws, leaderPod).Build(),
+				Record: record.NewFakeRecorder(1),
+			}
+			restarted, err := r.restartGroupIfRequested(context.Background(), lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !restarted {
+				t.Errorf("Expected the lws to be updated")
+			}
+
+			var got leaderworkerset.LeaderWorkerSet
+			if err := r.Get(context.Background(), types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &got); err != nil {
+				t.Fatalf("Failed to get the lws: %v", err)
+			}
+			if _, found := got.Annotations[leaderworkerset.RestartGroupAnnotationKey]; found {
+				t.Errorf("Expected the restart group annotation to be removed")
+			}
+			var gotLeader corev1.Pod
+			if err := r.Get(context.Background(), types.NamespacedName{Name: leaderPod.Name, Namespace: leaderPod.Namespace}, &gotLeader); err != nil {
+				t.Fatalf("Failed to get the leader pod: %v", err)
+			}
+			// The leader pod is deleted by the pod controller, within the restart budgets.
+			if _, annotated := gotLeader.Annotations[leaderworkerset.RestartGroupAnnotationKey]; annotated != tc.wantLeaderAnnotated {
+				t.Errorf("Expected leader pod annotated %t, got %t", tc.wantLeaderAnnotated, annotated)
+			}
+		})
+	}
+}
+
+func TestBlueGreenStartAndReplicas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := testutils.BuildLeaderWorkerSet("default").Replica(2).Obj()
+	lws.Spec.RolloutStrategy = leaderworkerset.RolloutStrategy{Type: leaderworkerset.BlueGreenStrategyType}
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	// group returns the ready leader pod and worker statefulset of the group.
+	group := func(groupIndex, hash string) []client.Object {
+		labels := map[string]string{
+			leaderworkerset.SetNameLabelKey:         lws.Name,
+			leaderworkerset.GroupIndexLabelKey:      groupIndex,
+			leaderworkerset.TemplateRevisionHashKey: hash,
+		}
+		leader := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sample-" + groupIndex, Namespace: "default", Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.GroupIndexLabelKey:      groupIndex,
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.TemplateRevisionHashKey: hash,
+			}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		workers := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sample-" + groupIndex, Namespace: "default", Labels: labels},
+			Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+			Status:     appsv1.StatefulSetStatus{Replicas: 1},
+		}
+		return []client.Object{leader, workers}
+	}
+
+	tests := []struct {
+		name            string
+		stsHash         string
+		stsStart        int32
+		stsReplicas     int32
+		blueGroups      string
+		groups          [][]client.Object
+		wantStart       int32
+		wantReplicas    int32
+		wantBlueGroups  string
+		wantLeadersGone []string
+	}{
+		{
+			name:           "template updated, stand up the green groups above the blue ones",
+			stsHash:        "old-hash",
+			stsReplicas:    2,
+			wantStart:      0,
+			wantReplicas:   4,
+			wantBlueGroups: "0-1",
+		},
+		{
+			name:           "template updated, stand up the green groups below the blue ones",
+			stsHash:        "old-hash",
+			stsStart:       2,
+			stsReplicas:    2,
+			wantStart:      0,
+			wantReplicas:   4,
+			wantBlueGroups: "2-3",
+		},
+		{
+			name:         "rollout completed",
+			stsHash:      templateHash,
+			stsStart:     2,
+			stsReplicas:  2,
+			wantStart:    2,
+			wantReplicas: 2,
+		},
+		{
+			name:           "green groups not ready, keep the blue groups",
+			stsHash:        templateHash,
+			stsReplicas:    4,
+			blueGroups:     "0-1",
+			groups:         [][]client.Object{group("0", "old-hash"), group("1", "old-hash"), group("2", templateHash)},
+			wantStart:      0,
+			wantReplicas:   4,
+			wantBlueGroups: "0-1",
+		},
+		{
+			name:           "green groups ready, tear down the blue groups",
+			stsHash:        templateHash,
+			stsReplicas:    4,
+			blueGroups:     "0-1",
+			groups:         [][]client.Object{group("0", "old-hash"), group("1", "old-hash"), group("2", templateHash), group("3", templateHash)},
+			wantStart:      2,
+			wantReplicas:   2,
+			wantBlueGroups: "0-1",
+		},
+		{
+			name:         "blue groups torn down",
+			stsHash:      templateHash,
+			stsStart:     2,
+			stsReplicas:  2,
+			blueGroups:   "0-1",
+			wantStart:    2,
+			wantReplicas: 2,
+		},
+		{
+			name:            "template updated again, recreate the green groups",
+			stsHash:         "newer-hash",
+			stsReplicas:     4,
+			blueGroups:      "2-3",
+			groups:          [][]client.Object{group("0", "outdated-hash"), group("1", templateHash), group("2", "old-hash"), group("3", "old-hash")},
+			wantStart:       0,
+			wantReplicas:    4,
+			wantBlueGroups:  "2-3",
+			wantLeadersGone: []string{"test-sample-0"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      lws.Name,
+					Namespace: lws.Namespace,
+					Labels:    map[string]string{leaderworkerset.TemplateRevisionHashKey: tc.stsHash},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:       ptr.To(tc.stsReplicas),
+					Ordinals:       &appsv1.StatefulSetOrdinals{Start: tc.stsStart},
+					UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+				},
+			}
+			if tc.blueGroups != "" {
+				sts.Annotations = map[string]string{leaderworkerset.BlueGroupsAnnotationKey: tc.blueGroups}
+			}
+			objs := []client.Object{sts}
+			for _, group := range tc.groups {
+				objs = append(objs, group...)
+			}
+			r := &LeaderWorkerSetReconciler{Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+			ctx := context.Background()
+			start, replicas, err := r.blueGreenStartAndReplicas(ctx, lws, sts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if start != tc.wantStart || replicas != tc.wantReplicas {
+				t.Errorf("Expected start %d and replicas %d, got %d and %d", tc.wantStart, tc.wantReplicas, start, replicas)
+			}
+
+			var gotSts appsv1.StatefulSet
+			if err := r.Get(ctx, types.NamespacedName{Name: sts.Name, Namespace: sts.Namespace}, &gotSts); err != nil {
+				t.Fatalf("Failed to get the leader statefulset: %v", err)
+			}
+			if got := gotSts.Annotations[leaderworkerset.BlueGroupsAnnotationKey]; got != tc.wantBlueGroups {
+				t.Errorf("Expected blue groups %q, got %q", tc.wantBlueGroups, got)
+			}
+			var gotLeadersGone []string
+			for _, group := range tc.groups {
+				if err := r.Get(ctx, client.ObjectKeyFromObject(group[0]), &corev1.Pod{}); apierrors.IsNotFound(err) {
+					gotLeadersGone = append(gotLeadersGone, group[0].GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.wantLeadersGone, gotLeadersGone); diff != "" {
+				t.Errorf("Unexpected recreated groups (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScaleDownStart(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := testutils.BuildLeaderWorkerSet("default").Replica(3).Obj()
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	// group returns the leader pod and the worker statefulset of the group with the given ready workers, the group
+	// is ready once all its workers are ready.
+	group := func(groupIndex, hash string, readyWorkers int32, deletionCost string) []client.Object {
+		leader := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sample-" + groupIndex, Namespace: "default", Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.GroupIndexLabelKey:      groupIndex,
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.TemplateRevisionHashKey: hash,
+			}},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			},
+		}
+		if deletionCost != "" {
+			leader.Annotations = map[string]string{corev1.PodDeletionCost: deletionCost}
+		}
+		workers := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-sample-" + groupIndex, Namespace: "default", Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.GroupIndexLabelKey:      groupIndex,
+				leaderworkerset.TemplateRevisionHashKey: hash,
+			}},
+			Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](2)},
+			Status: appsv1.StatefulSetStatus{Replicas: readyWorkers, ReadyReplicas: readyWorkers},
+		}
+		return []client.Object{leader, workers}
+	}
+
+	tests := []struct {
+		name      string
+		stsHash   string
+		stsStart  int32
+		groups    [][]client.Object
+		wantStart int32
+	}{
+		{
+			name:    "all the groups ready, remove the highest group",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 2, ""), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 2, "")},
+			wantStart: 0,
+		},
+		{
+			name:    "lowest group not ready, remove the lowest group",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 1, ""), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 2, "")},
+			wantStart: 1,
+		},
+		{
+			name:    "middle group not ready, groups at both ends are ready",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 2, ""), group("1", templateHash, 0, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 2, "")},
+			wantStart: 0,
+		},
+		{
+			name:    "lower deletion cost of the lowest group",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 2, "-10"), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 2, "10")},
+			wantStart: 1,
+		},
+		{
+			name:    "not ready groups are removed prior to the deletion cost",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 2, "-10"), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 0, "10")},
+			wantStart: 0,
+		},
+		{
+			name:    "fewer ready workers of the lowest group",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", templateHash, 0, ""), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 1, "")},
+			wantStart: 1,
+		},
+		{
+			name:     "groups positioned from the start",
+			stsHash:  templateHash,
+			stsStart: 2,
+			groups: [][]client.Object{group("2", templateHash, 0, ""), group("3", templateHash, 2, ""),
+				group("4", templateHash, 2, ""), group("5", templateHash, 2, "")},
+			wantStart: 3,
+		},
+		{
+			name:    "rollout in progress",
+			stsHash: templateHash,
+			groups: [][]client.Object{group("0", "old-hash", 0, ""), group("1", templateHash, 2, ""),
+				group("2", templateHash, 2, ""), group("3", templateHash, 2, "")},
+			wantStart: 0,
+		},
+		{
+			name:    "template updated",
+			stsHash: "old-hash",
+			groups: [][]client.Object{group("0", "old-hash", 0, ""), group("1", "old-hash", 2, ""),
+				group("2", "old-hash", 2, ""), group("3", "old-hash", 2, "")},
+			wantStart: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      lws.Name,
+					Namespace: lws.Namespace,
+					Labels:    map[string]string{leaderworkerset.TemplateRevisionHashKey: tc.stsHash},
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To[int32](4),
+					Ordinals: &appsv1.StatefulSetOrdinals{Start: tc.stsStart},
+				},
+			}
+			objs := []client.Object{sts}
+			for _, group := range tc.groups {
+				objs = append(objs, group...)
+			}
+			r := &LeaderWorkerSetReconciler{Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+			start, err := r.scaleDownStart(context.Background(), lws, tc.stsStart, 3)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if start != tc.wantStart {
+				t.Errorf("Expected start %d, got %d", tc.wantStart, start)
+			}
+		})
+	}
+}
+
+func TestCanaryPartitionAndReplicas(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	baseLws := testutils.BuildLeaderWorkerSet("default").Replica(4).Obj()
+	baseLws.Spec.RolloutStrategy = leaderworkerset.RolloutStrategy{
+		Type: leaderworkerset.CanaryStrategyType,
+		CanaryConfiguration: &leaderworkerset.CanaryConfiguration{
+			Steps:     []leaderworkerset.CanaryStep{{Weight: 25}, {Weight: 50}},
+			HTTPRoute: "route",
+			Port:      8080,
+		},
+	}
+	templateHash := utils.LeaderWorkerTemplateHash(baseLws)
+	pauseOver := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	tests := []struct {
+		name          string
+		stsHash       string
+		status        *leaderworkerset.CanaryStatus
+		wantPartition int32
+		wantStatus    *leaderworkerset.CanaryStatus
+	}{
+		{
+			name:          "new revision starts the first step",
+			stsHash:       "old-hash",
+			wantPartition: 3,
+			wantStatus:    &leaderworkerset.CanaryStatus{StableRevision: "old-hash"},
+		},
+		{
+			name:          "rollout completed",
+			stsHash:       templateHash,
+			status:        &leaderworkerset.CanaryStatus{StableRevision: templateHash},
+			wantPartition: 0,
+			wantStatus:    &leaderworkerset.CanaryStatus{StableRevision: templateHash},
+		},
+		{
+			name:          "groups of the step not ready",
+			stsHash:       templateHash,
+			status:        &leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 1},
+			wantPartition: 2,
+			wantStatus:    &leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 1},
+		},
+		{
+			name:          "pause over, move to the next step",
+			stsHash:       templateHash,
+			status:        &leaderworkerset.CanaryStatus{StableRevision: "old-hash", StepStartTime: &pauseOver},
+			wantPartition: 2,
+			wantStatus:    &leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 1},
+		},
+		{
+			name:          "all the steps done, update the remaining groups",
+			stsHash:       templateHash,
+			status:        &leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 2},
+			wantPartition: 0,
+			wantStatus:    &leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := baseLws.DeepCopy()
+			lws.Status.Canary = tc.status
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      lws.Name,
+					Namespace: lws.Namespace,
+					Labels:    map[string]string{leaderworkerset.TemplateRevisionHashKey: tc.stsHash},
+				},
+				Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](4)},
+			}
+			r := &LeaderWorkerSetReconciler{
+				Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(lws, sts).WithStatusSubresource(lws).Build(),
+			}
+			partition, replicas, err := r.canaryPartitionAndReplicas(context.Background(), lws, sts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if partition != tc.wantPartition || replicas != 4 {
+				t.Errorf("Expected partition %d and replicas 4, got %d and %d", tc.wantPartition, partition, replicas)
+			}
+			if diff := cmp.Diff(tc.wantStatus, lws.Status.Canary); diff != "" {
+				t.Errorf("Unexpected canary status (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCanaryWeight(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	lws.Spec.RolloutStrategy.CanaryConfiguration = &leaderworkerset.CanaryConfiguration{
+		Steps: []leaderworkerset.CanaryStep{{Weight: 10}, {Weight: 50}},
+	}
+	now := metav1.Now()
+
+	tests := []struct {
+		name       string
+		status     leaderworkerset.CanaryStatus
+		wantWeight int32
+	}{
+		{
+			name:       "no rollout in progress",
+			status:     leaderworkerset.CanaryStatus{StableRevision: utils.LeaderWorkerTemplateHash(lws)},
+			wantWeight: 0,
+		},
+		{
+			name:       "groups of the first step not ready",
+			status:     leaderworkerset.CanaryStatus{StableRevision: "old-hash"},
+			wantWeight: 0,
+		},
+		{
+			name:       "groups of the first step ready",
+			status:     leaderworkerset.CanaryStatus{StableRevision: "old-hash", StepStartTime: &now},
+			wantWeight: 10,
+		},
+		{
+			name:       "groups of the second step not ready",
+			status:     leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 1},
+			wantWeight: 10,
+		},
+		{
+			name:       "all the steps done",
+			status:     leaderworkerset.CanaryStatus{StableRevision: "old-hash", Step: 2},
+			wantWeight: 50,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws.Status.Canary = &tc.status
+			if got := canaryWeight(lws); got != tc.wantWeight {
+				t.Errorf("Expected weight %d, got %d", tc.wantWeight, got)
+			}
+		})
+	}
+}
+
+func TestPreGroupUpdateHooksPartition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := testutils.BuildLeaderWorkerSet("default").Replica(4).Obj()
+	lws.Spec.RolloutStrategy.Hooks = &leaderworkerset.RolloutHooks{
+		PreGroupUpdate: &leaderworkerset.RolloutHook{Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "drain", Image: "drain"}}},
+		}},
+	}
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	hookJob := func(groupIndex int32, conditionType batchv1.JobConditionType) *batchv1.Job {
+		job := hookutils.NewJob(lws, hookutils.PreGroupUpdate, lws.Spec.RolloutStrategy.Hooks.PreGroupUpdate, groupIndex, templateHash)
+		job.Status.Conditions = []batchv1.JobCondition{{Type: conditionType, Status: corev1.ConditionTrue}}
+		return job
+	}
+
+	tests := []struct {
+		name          string
+		jobs          []client.Object
+		wantPartition int32
+		wantJobs      []string
+	}{
+		{
+			name:          "hooks not run yet",
+			wantPartition: 4,
+			wantJobs:      []string{"test-sample-2-pre-" + templateHash[:10], "test-sample-3-pre-" + templateHash[:10]},
+		},
+		{
+			name:          "hook of the highest group succeeded",
+			jobs:          []client.Object{hookJob(3, batchv1.JobComplete)},
+			wantPartition: 3,
+			wantJobs:      []string{"test-sample-2-pre-" + templateHash[:10], "test-sample-3-pre-" + templateHash[:10]},
+		},
+		{
+			name:          "hook of the highest group failed",
+			jobs:          []client.Object{hookJob(3, batchv1.JobFailed), hookJob(2, batchv1.JobComplete)},
+			wantPartition: 4,
+			wantJobs:      []string{"test-sample-2-pre-" + templateHash[:10], "test-sample-3-pre-" + templateHash[:10]},
+		},
+		{
+			name:          "all the hooks succeeded",
+			jobs:          []client.Object{hookJob(3, batchv1.JobComplete), hookJob(2, batchv1.JobComplete)},
+			wantPartition: 2,
+			wantJobs:      []string{"test-sample-2-pre-" + templateHash[:10], "test-sample-3-pre-" + templateHash[:10]},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeClientBuilder().WithScheme(scheme).WithObjects(append(tc.jobs, lws.DeepCopy())...).Build()
+			r := &LeaderWorkerSetReconciler{Client: c, Scheme: scheme, Record: record.NewFakeRecorder(10)}
+			partition, err := r.preGroupUpdateHooksPartition(context.Background(), lws, 0, 4, 2)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if partition != tc.wantPartition {
+				t.Errorf("Expected partition %d, got %d", tc.wantPartition, partition)
+			}
+			var jobs batchv1.JobList
+			if err := c.List(context.Background(), &jobs); err != nil {
+				t.Fatal(err)
+			}
+			var gotJobs []string
+			for _, job := range jobs.Items {
+				gotJobs = append(gotJobs, job.Name)
+			}
+			if diff := cmp.Diff(tc.wantJobs, gotJobs); diff != "" {
+				t.Errorf("Unexpected hook jobs (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServingGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		lws         *leaderworkerset.LeaderWorkerSet
+		start       int
+		readyGroups map[int]bool
+		want        map[int]bool
+	}{
+		{
+			name:        "no warm groups",
+			lws:         testutils.BuildLeaderWorkerSet("default").Replica(3).Obj(),
+			readyGroups: map[int]bool{0: true, 1: false, 2: true},
+			want:        map[int]bool{0: true, 2: true},
+		},
+		{
+			name:        "warm groups are excluded when all the groups are ready",
+			lws:         testutils.BuildLeaderWorkerSet("default").Replica(2).WarmReplicas(2).Obj(),
+			readyGroups: map[int]bool{0: true, 1: true, 2: true, 3: true},
+			want:        map[int]bool{0: true, 1: true},
+		},
+		{
+			name:        "ready warm groups are selected in place of the unready groups",
+			lws:         testutils.BuildLeaderWorkerSet("default").Replica(3).WarmReplicas(2).Obj(),
+			readyGroups: map[int]bool{0: false, 1: true, 2: false, 3: false, 4: true},
+			want:        map[int]bool{1: true, 4: true},
+		},
+		{
+			name:        "bursted groups are selected",
+			lws:         testutils.BuildLeaderWorkerSet("default").Replica(2).WarmReplicas(1).Obj(),
+			readyGroups: map[int]bool{0: true, 1: true, 2: true, 3: true},
+			want:        map[int]bool{0: true, 1: true, 3: true},
+		},
+		{
+			name:        "groups positioned from the start",
+			lws:         testutils.BuildLeaderWorkerSet("default").Replica(2).WarmReplicas(1).Obj(),
+			start:       3,
+			readyGroups: map[int]bool{3: false, 4: true, 5: true},
+			want:        map[int]bool{4: true, 5: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, servingGroups(tc.lws, tc.start, tc.readyGroups)); diff != "" {
+				t.Errorf("Unexpected serving groups (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUpdateTopologyKeyMissingCondition(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"cloud.google.com/gke-nodepool": "pool-a"}}}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		conditions  []metav1.Condition
+		wantUpdated bool
+		wantMissing bool
+	}{
+		{
+			name: "no exclusive placement",
+		},
+		{
+			name:        "topology key exists",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+		},
+		{
+			name:        "topology key missing",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-tpu-slice"},
+			wantUpdated: true,
+			wantMissing: true,
+		},
+		{
+			name: "subgroup topology key missing",
+			annotations: map[string]string{
+				leaderworkerset.ExclusiveKeyAnnotationKey:         "cloud.google.com/gke-nodepool",
+				leaderworkerset.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-tpu-slice",
+			},
+			wantUpdated: true,
+			wantMissing: true,
+		},
+		{
+			name:        "topology key added to the nodes",
+			annotations: map[string]string{leaderworkerset.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+			conditions: []metav1.Condition{{
+				Type:   string(leaderworkerset.LeaderWorkerSetTopologyKeyMissing),
+				Status: metav1.ConditionTrue,
+			}},
+			wantUpdated: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Annotation(tc.annotations).Obj()
+			lws.Status.Conditions = tc.conditions
+			r := &LeaderWorkerSetReconciler{
+				Client: newFakeClientBuilder().WithObjects(node).Build(),
+				Record: record.NewFakeRecorder(1),
+			}
+
+			updated, err := r.updateTopologyKeyMissingCondition(context.Background(), lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if updated != tc.wantUpdated {
+				t.Errorf("Expected updated %t, got %t", tc.wantUpdated, updated)
+			}
+			if missing := meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetTopologyKeyMissing)); missing != tc.wantMissing {
+				t.Errorf("Expected topology key missing %t, got %t", tc.wantMissing, missing)
+			}
+			// The condition is not updated again once set.
+			if updated, _ := r.updateTopologyKeyMissingCondition(context.Background(), lws); updated {
+				t.Error("Expected the conditions not updated again")
+			}
+		})
+	}
+}
+
+// newFakeClientBuilder returns a fake client builder with the field indexes of the controllers registered.
+func newFakeClientBuilder() *fake.ClientBuilder {
+	return fake.NewClientBuilder().
+		WithIndex(&corev1.Pod{}, podGroupKey, indexPodGroup).
+		WithIndex(&corev1.Pod{}, leaderPodKey, indexLeaderPod).
+		WithIndex(&appsv1.StatefulSet{}, statefulSetLwsKey, indexStatefulSetLws)
+}
+
+func TestIndexes(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		wantGroup   []string
+		wantLeader  []string
+		wantLws     []string
+	}{
+		{
+			name: "leader pod",
+			labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			},
+			wantGroup:  []string{"test-sample/1"},
+			wantLeader: []string{"test-sample"},
+			wantLws:    []string{"test-sample"},
+		},
+		{
+			name: "worker pod",
+			labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "2",
+			},
+			wantGroup: []string{"test-sample/1"},
+			wantLws:   []string{"test-sample"},
+		},
+		{
+			name: "debug clone of the leader pod",
+			labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			},
+			annotations: map[string]string{leaderworkerset.SkipInjectionAnnotationKey: "true"},
+			wantLws:     []string{"test-sample"},
+		},
+		{
+			name:    "leader statefulset",
+			labels:  map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+			wantLws: []string{"test-sample"},
+		},
+		{
+			name:   "not managed by lws",
+			labels: map[string]string{"app": "test"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tc.labels, Annotations: tc.annotations}}
+			if diff := cmp.Diff(tc.wantGroup, indexPodGroup(obj)); diff != "" {
+				t.Errorf("Unexpected group index (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantLeader, indexLeaderPod(obj)); diff != "" {
+				t.Errorf("Unexpected leader index (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantLws, indexStatefulSetLws(obj)); diff != "" {
+				t.Errorf("Unexpected lws index (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	lws := testutils.BuildLeaderWorkerSet("default").Replica(1).Obj()
+	lws.UID = "lws-uid"
+	lws.Annotations = map[string]string{leaderworkerset.GangSchedulingAnnotationKey: string(schedulerprovider.Volcano)}
+	lws.Finalizers = []string{leaderworkerset.CleanupFinalizer}
+	lws.DeletionTimestamp = ptr.To(metav1.Now())
+	sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+		Name: lws.Name, Namespace: lws.Namespace,
+		Labels: map[string]string{leaderworkerset.SetNameLabelKey: lws.Name},
+	}}
+	leader := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "test-sample-0", Namespace: lws.Namespace, UID: "pod-uid",
+		Labels: map[string]string{leaderworkerset.SetNameLabelKey: lws.Name, leaderworkerset.WorkerIndexLabelKey: "0"},
+	}}
+	controller := true
+	lwsOwner := metav1.OwnerReference{APIVersion: apiGVStr, Kind: "LeaderWorkerSet", Name: lws.Name, UID: lws.UID, Controller: &controller}
+	service := func(name, ownerName string, uid types.UID, labeled bool) *corev1.Service {
+		owner := metav1.OwnerReference{APIVersion: "v1", Kind: "Pod", Name: ownerName, UID: uid, Controller: &controller}
+		if ownerName == lws.Name {
+			owner = lwsOwner
+		}
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: lws.Namespace,
+			OwnerReferences: []metav1.OwnerReference{owner},
+		}}
+		if labeled {
+			svc.Labels = map[string]string{leaderworkerset.SetNameLabelKey: lws.Name}
+		}
+		return svc
+	}
+	lwsService := service("test-sample", lws.Name, lws.UID, true)
+	groupService := service("test-sample-0", "test-sample-0", "pod-uid", true)
+	// Controlled by a pod named after the lws, but not by one of its leader pods.
+	foreignService := service("test-sample-1", "test-sample-1", "foreign-uid", true)
+	unlabeledService := service("other", lws.Name, lws.UID, false)
+	pdb := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{
+		Name: lws.Name, Namespace: lws.Namespace, OwnerReferences: []metav1.OwnerReference{lwsOwner},
+	}}
+	podGroup := &unstructured.Unstructured{}
+	podGroup.SetGroupVersionKind(schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"})
+	podGroup.SetName("test-sample-0")
+	podGroup.SetNamespace(lws.Namespace)
+	podGroup.SetLabels(map[string]string{leaderworkerset.SetNameLabelKey: lws.Name})
+	podGroup.SetOwnerReferences([]metav1.OwnerReference{lwsOwner})
+
+	r := &LeaderWorkerSetReconciler{
+		Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(lws, sts, leader, lwsService, groupService, foreignService, unlabeledService, pdb, podGroup).Build(),
+		Record: record.NewFakeRecorder(1),
+	}
+	ctx := context.Background()
+
+	// The finalizer is kept until the statefulsets are gone.
+	result, err := r.finalize(ctx, lws)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.RequeueAfter != cleanupRecheckInterval {
+		t.Errorf("Expected a requeue after %v, got %v", cleanupRecheckInterval, result.RequeueAfter)
+	}
+	for _, svc := range []*corev1.Service{lwsService, groupService} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(svc), &corev1.Service{}); !apierrors.IsNotFound(err) {
+			t.Errorf("Expected the service %s to be deleted, got %v", svc.Name, err)
+		}
+	}
+	for _, svc := range []*corev1.Service{foreignService, unlabeledService} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(svc), &corev1.Service{}); err != nil {
+			t.Errorf("Expected the service %s to be kept, got %v", svc.Name, err)
+		}
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(pdb), &policyv1.PodDisruptionBudget{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the pod disruption budget to be deleted, got %v", err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(podGroup), podGroup.DeepCopy()); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the pod group to be deleted, got %v", err)
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{}); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the statefulset to be deleted, got %v", err)
+	}
+
+	var got leaderworkerset.LeaderWorkerSet
+	if err := r.Get(ctx, client.ObjectKeyFromObject(lws), &got); err != nil {
+		t.Fatalf("Failed to get the lws: %v", err)
+	}
+	result, err = r.finalize(ctx, &got)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("Expected no requeue, got %v", result.RequeueAfter)
+	}
+	// Removing the last finalizer completes the deletion.
+	if err := r.Get(ctx, client.ObjectKeyFromObject(lws), &got); !apierrors.IsNotFound(err) {
+		t.Errorf("Expected the lws to be deleted, got %v", err)
+	}
+}
+
+func TestTearDownWorkers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	pod := func(groupIndex, workerIndex string, annotations map[string]string) *corev1.Pod {
+		name := "test-sample-" + groupIndex
+		if workerIndex != "0" {
+			name += "-" + workerIndex
+		}
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: name, Namespace: "default", Annotations: annotations,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  groupIndex,
+				leaderworkerset.WorkerIndexLabelKey: workerIndex,
+			},
+		}}
+	}
+	teardown := map[string]string{leaderworkerset.TeardownAnnotationKey: "true"}
+	tests := []struct {
+		name                 string
+		order                leaderworkerset.TerminationOrderType
+		start                int32
+		replicas             int32
+		objs                 []client.Object
+		wantStart            int32
+		wantReplicas         int32
+		wantAnnotated        []string
+		wantWorkerStsDeleted []string
+	}{
+		{
+			name:         "leader first",
+			replicas:     1,
+			objs:         []client.Object{pod("0", "0", nil), pod("1", "0", nil), pod("1", "1", nil)},
+			wantReplicas: 1,
+		},
+		{
+			name:                 "workers first with workers left",
+			order:                leaderworkerset.WorkersFirstTerminationOrder,
+			replicas:             1,
+			objs:                 []client.Object{pod("0", "0", nil), pod("0", "1", nil), pod("1", "0", nil), pod("1", "1", nil)},
+			wantReplicas:         2,
+			wantAnnotated:        []string{"test-sample-1"},
+			wantWorkerStsDeleted: []string{"test-sample-1"},
+		},
+		{
+			name:                 "workers first with workers gone",
+			order:                leaderworkerset.WorkersFirstTerminationOrder,
+			replicas:             1,
+			objs:                 []client.Object{pod("0", "0", nil), pod("0", "1", nil), pod("1", "0", teardown)},
+			wantReplicas:         1,
+			wantAnnotated:        []string{"test-sample-1"},
+			wantWorkerStsDeleted: []string{"test-sample-1"},
+		},
+		{
+			name:                 "workers first with workers left below the start",
+			order:                leaderworkerset.WorkersFirstTerminationOrder,
+			start:                1,
+			replicas:             1,
+			objs:                 []client.Object{pod("0", "0", nil), pod("0", "1", nil), pod("1", "0", nil)},
+			wantStart:            0,
+			wantReplicas:         2,
+			wantAnnotated:        []string{"test-sample-0"},
+			wantWorkerStsDeleted: []string{"test-sample-0"},
+		},
+		{
+			name:         "scaled up again",
+			order:        leaderworkerset.WorkersFirstTerminationOrder,
+			replicas:     2,
+			objs:         []client.Object{pod("0", "0", nil), pod("1", "0", teardown)},
+			wantReplicas: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(int(tc.replicas)).Obj()
+			lws.Spec.TerminationOrder = tc.order
+			objs := append([]client.Object{lws}, tc.objs...)
+			for _, name := range []string{"test-sample-0", "test-sample-1"} {
+				objs = append(objs, &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+			}
+			r := &LeaderWorkerSetReconciler{
+				Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+				Record: record.NewFakeRecorder(1),
+			}
+			ctx := context.Background()
+			start, replicas, err := r.tearDownWorkers(ctx, lws, tc.start, tc.replicas)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if start != tc.wantStart || replicas != tc.wantReplicas {
+				t.Errorf("Expected start %d and replicas %d, got %d and %d", tc.wantStart, tc.wantReplicas, start, replicas)
+			}
+
+			var annotated, deleted []string
+			for _, name := range []string{"test-sample-0", "test-sample-1"} {
+				var leader corev1.Pod
+				if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &leader); client.IgnoreNotFound(err) != nil {
+					t.Fatalf("Failed to get the leader pod: %v", err)
+				}
+				if leader.Annotations[leaderworkerset.TeardownAnnotationKey] == "true" {
+					annotated = append(annotated, name)
+				}
+				if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &appsv1.StatefulSet{}); apierrors.IsNotFound(err) {
+					deleted = append(deleted, name)
+				}
+			}
+			if diff := cmp.Diff(tc.wantAnnotated, annotated); diff != "" {
+				t.Errorf("Unexpected annotated leader pods (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantWorkerStsDeleted, deleted); diff != "" {
+				t.Errorf("Unexpected deleted worker statefulsets (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDrainGroups(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	current := utils.LeaderWorkerTemplateHash(testutils.BuildLeaderWorkerSet("default").Obj())
+	leader := func(groupIndex, templateHash string, serving *corev1.PodCondition) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name: "test-sample-" + groupIndex, Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         "test-sample",
+				leaderworkerset.GroupIndexLabelKey:      groupIndex,
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.TemplateRevisionHashKey: templateHash,
+			},
+		}}
+		if serving != nil {
+			pod.Status.Conditions = []corev1.PodCondition{*serving}
+		}
+		return pod
+	}
+	draining := func(since time.Duration) *corev1.PodCondition {
+		return &corev1.PodCondition{Type: leaderworkerset.ServingConditionType, Status: corev1.ConditionFalse, Reason: "Draining",
+			LastTransitionTime: metav1.NewTime(time.Now().Add(-since))}
+	}
+	tests := []struct {
+		name          string
+		drainSeconds  *int32
+		strategy      leaderworkerset.RolloutStrategyType
+		start         int32
+		partition     int32
+		replicas      int32
+		leaders       []client.Object
+		wantStart     int32
+		wantPartition int32
+		wantReplicas  int32
+		wantDraining  []string
+		wantDrainWait bool
+	}{
+		{
+			name:         "no drain",
+			replicas:     1,
+			leaders:      []client.Object{leader("0", current, nil), leader("1", current, nil)},
+			wantReplicas: 1,
+		},
+		{
+			name:          "scale down",
+			drainSeconds:  ptr.To[int32](60),
+			replicas:      1,
+			leaders:       []client.Object{leader("0", current, nil), leader("1", current, nil)},
+			wantReplicas:  2,
+			wantDraining:  []string{"test-sample-1"},
+			wantDrainWait: true,
+		},
+		{
+			name:         "scale down drained",
+			drainSeconds: ptr.To[int32](60),
+			replicas:     1,
+			leaders:      []client.Object{leader("0", current, nil), leader("1", current, draining(2*time.Minute))},
+			wantReplicas: 1,
+			wantDraining: []string{"test-sample-1"},
+		},
+		{
+			name:          "rolling update",
+			drainSeconds:  ptr.To[int32](60),
+			partition:     1,
+			replicas:      2,
+			leaders:       []client.Object{leader("0", "old", nil), leader("1", "old", nil)},
+			wantPartition: 2,
+			wantReplicas:  2,
+			wantDraining:  []string{"test-sample-1"},
+			wantDrainWait: true,
+		},
+		{
+			name:         "blue groups torn down",
+			drainSeconds: ptr.To[int32](60),
+			strategy:     leaderworkerset.BlueGreenStrategyType,
+			start:        2,
+			partition:    2,
+			replicas:     2,
+			leaders: []client.Object{leader("0", "old", nil), leader("1", "old", nil),
+				leader("2", current, nil), leader("3", current, nil)},
+			wantStart:     0,
+			wantPartition: 2,
+			wantReplicas:  4,
+			wantDraining:  []string{"test-sample-0", "test-sample-1"},
+			wantDrainWait: true,
+		},
+		{
+			name:         "on delete",
+			drainSeconds: ptr.To[int32](60),
+			strategy:     leaderworkerset.OnDeleteStrategyType,
+			replicas:     2,
+			leaders:      []client.Object{leader("0", "old", nil), leader("1", "old", nil)},
+			wantReplicas: 2,
+		},
+		{
+			name:         "scaled up again",
+			drainSeconds: ptr.To[int32](60),
+			replicas:     2,
+			leaders:      []client.Object{leader("0", current, nil), leader("1", current, draining(time.Second))},
+			wantReplicas: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(int(tc.replicas)).Obj()
+			lws.Spec.DrainSeconds = tc.drainSeconds
+			if tc.strategy != "" {
+				lws.Spec.RolloutStrategy.Type = tc.strategy
+			}
+			r := &LeaderWorkerSetReconciler{
+				Client: newFakeClientBuilder().WithScheme(scheme).WithObjects(append([]client.Object{lws}, tc.leaders...)...).Build(),
+				Record: record.NewFakeRecorder(1),
+			}
+			ctx := context.Background()
+			start, partition, replicas, drainAfter, err := r.drainGroups(ctx, lws, tc.start, tc.partition, tc.replicas)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if start != tc.wantStart || partition != tc.wantPartition || replicas != tc.wantReplicas {
+				t.Errorf("Expected start %d, partition %d and replicas %d, got %d, %d and %d", tc.wantStart, tc.wantPartition, tc.wantReplicas, start, partition, replicas)
+			}
+			if drainWait := drainAfter > 0; drainWait != tc.wantDrainWait {
+				t.Errorf("Expected waiting for the drain %t, got %v", tc.wantDrainWait, drainAfter)
+			}
+
+			var pods corev1.PodList
+			if err := r.List(ctx, &pods); err != nil {
+				t.Fatalf("Failed to list the pods: %v", err)
+			}
+			var gotDraining []string
+			for _, pod := range pods.Items {
+				if podutils.Draining(pod) {
+					gotDraining = append(gotDraining, pod.Name)
+				}
+			}
+			if diff := cmp.Diff(tc.wantDraining, gotDraining); diff != "" {
+				t.Errorf("Unexpected draining leader pods (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileWorkload(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name                 string
+		admitted             bool
+		replicas             int32
+		resizeRejected       bool
+		wantAdmitted         bool
+		wantAdmittedReplicas *int32
+		wantWorkloadMatches  bool
+	}{
+		{
+			name:                "pending",
+			replicas:            2,
+			wantWorkloadMatches: true,
+		},
+		{
+			name:                "admitted",
+			admitted:            true,
+			replicas:            2,
+			wantAdmitted:        true,
+			wantWorkloadMatches: true,
+		},
+		{
+			name:                 "scaled after admission",
+			admitted:             true,
+			replicas:             3,
+			wantAdmitted:         true,
+			wantAdmittedReplicas: ptr.To[int32](2),
+			wantWorkloadMatches:  true,
+		},
+		{
+			name:                 "resize rejected",
+			admitted:             true,
+			replicas:             3,
+			resizeRejected:       true,
+			wantAdmitted:         true,
+			wantAdmittedReplicas: ptr.To[int32](2),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(2).Obj()
+			lws.Labels = map[string]string{kueueutils.QueueNameLabelKey: "queue"}
+			workload, err := kueueutils.NewWorkload(lws)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.admitted {
+				if err := unstructured.SetNestedSlice(workload.Object, []interface{}{
+					map[string]interface{}{"type": kueueutils.WorkloadAdmittedCondition, "status": "True"},
+				}, "status", "conditions"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			lws.Spec.Replicas = ptr.To(tc.replicas)
+			builder := newFakeClientBuilder().WithScheme(scheme).WithObjects(lws, workload).WithStatusSubresource(lws)
+			if tc.resizeRejected {
+				builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+					Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+						if _, ok := obj.(*unstructured.Unstructured); ok {
+							return apierrors.NewInvalid(kueueutils.WorkloadGVK.GroupKind(), obj.GetName(), nil)
+						}
+						return c.Update(ctx, obj, opts...)
+					},
+				})
+			}
+			r := &LeaderWorkerSetReconciler{
+				Client: builder.Build(),
+				Record: record.NewFakeRecorder(1),
+			}
+			ctx := context.Background()
+			admitted, admittedReplicas, err := r.reconcileWorkload(ctx, lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if admitted != tc.wantAdmitted {
+				t.Errorf("Expected admitted %t, got %t", tc.wantAdmitted, admitted)
+			}
+			if diff := cmp.Diff(tc.wantAdmittedReplicas, admittedReplicas); diff != "" {
+				t.Errorf("Unexpected admitted replicas (-want +got):\n%s", diff)
+			}
+
+			var got leaderworkerset.LeaderWorkerSet
+			if err := r.Get(ctx, client.ObjectKeyFromObject(lws), &got); err != nil {
+				t.Fatalf("Failed to get the lws: %v", err)
+			}
+			if got.Spec.Suspend != nil {
+				t.Errorf("Expected the suspend of the lws untouched, got %t", *got.Spec.Suspend)
+			}
+			if condition := meta.IsStatusConditionTrue(got.Status.Conditions, string(leaderworkerset.LeaderWorkerSetAdmitted)); condition != tc.wantAdmitted {
+				t.Errorf("Expected the Admitted condition %t, got %t", tc.wantAdmitted, condition)
+			}
+			// The Workload is resized in place unless Kueue rejects it.
+			gotWorkload := &unstructured.Unstructured{}
+			gotWorkload.SetGroupVersionKind(kueueutils.WorkloadGVK)
+			if err := r.Get(ctx, client.ObjectKeyFromObject(workload), gotWorkload); err != nil {
+				t.Fatalf("Failed to get the workload: %v", err)
+			}
+			if matches := kueueutils.WorkloadMatches(gotWorkload, &got); matches != tc.wantWorkloadMatches {
+				t.Errorf("Expected the workload matching the lws %t, got %t", tc.wantWorkloadMatches, matches)
+			}
+		})
+	}
+}
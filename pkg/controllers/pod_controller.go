@@ -18,12 +18,20 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,17 +39,31 @@ import (
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	k8spodutils "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/schedulerprovider"
+	"sigs.k8s.io/lws/pkg/tracing"
+	"sigs.k8s.io/lws/pkg/utils"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	"sigs.k8s.io/lws/pkg/utils/expectations"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	provisioningutils "sigs.k8s.io/lws/pkg/utils/provisioning"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
@@ -49,16 +71,48 @@ import (
 type PodReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	Record record.EventRecorder
+	// MaxConcurrentGroupRestarts is the maximum number of groups across all the lws which can be
+	// recreated at the same time, unlimited if not positive. It is updated when the configuration is reloaded.
+	MaxConcurrentGroupRestarts atomic.Int32
+	// PodWebhookDisabled labels the pods and stamps the worker statefulsets with what the pod webhook injects
+	// otherwise, for the clusters which can't run the pod webhook.
+	PodWebhookDisabled bool
+	// RateLimiter is the rate limiter of the workqueue, the default one of controller-runtime if nil.
+	RateLimiter ratelimiter.RateLimiter
+	// leaderDeletions tracks the leader pods deleted to recreate their groups until the cache observes the deletions.
+	leaderDeletions expectations.Store
+	// apiReader reads the exclusive domain claims from the API server, the cache may not observe the claims yet.
+	apiReader client.Reader
+	// restartMu serializes the checks of the restart budgets with the deletions of the leader pods, and claimMu
+	// the checks of the exclusive domains with the claims, since the pods are reconciled concurrently.
+	restartMu sync.Mutex
+	claimMu   sync.Mutex
 }
 
-func NewPodReconciler(client client.Client, schema *runtime.Scheme) *PodReconciler {
-	return &PodReconciler{Client: client, Scheme: schema}
+// groupRestartRetryInterval is the interval to retry recreating a group once the restart budget is exhausted.
+const groupRestartRetryInterval = 5 * time.Second
+
+func NewPodReconciler(client client.Client, apiReader client.Reader, schema *runtime.Scheme, record record.EventRecorder) *PodReconciler {
+	return &PodReconciler{Client: client, apiReader: apiReader, Scheme: schema, Record: record}
 }
 
 //+kubebuilder:rbac:groups=core,resources=pods,verbs=create;delete;get;list;patch;update;watch
+//+kubebuilder:rbac:groups=core,resources=pods/status,verbs=get;patch;update
 //+kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=scheduling.x-k8s.io,resources=podgroups,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups=core,resources=podtemplates,verbs=get;create
+//+kubebuilder:rbac:groups=autoscaling.x-k8s.io,resources=provisioningrequests,verbs=get;list;watch;create
+//+kubebuilder:rbac:groups=scheduling.volcano.sh,resources=podgroups,verbs=get;list;watch;create;delete
 
-func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	defer func() {
+		if err != nil {
+			metrics.ReconcileFailed("pod")
+		}
+	}()
+	ctx, span := tracing.Start(ctx, "Reconcile Pod", tracing.ObjectAttributes("Pod", req.Namespace, req.Name)...)
+	defer func() { tracing.End(span, err) }()
 	var pod corev1.Pod
 	if err := r.Get(ctx, types.NamespacedName{Name: req.Name, Namespace: req.Namespace}, &pod); err != nil {
 		return ctrl.Result{}, client.IgnoreNotFound(err)
@@ -71,6 +125,16 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	if lwsName == "" {
 		return ctrl.Result{}, errors.New("leaderworkerset.sigs.k8s.io/name label is unexpected missing")
 	}
+	// The pods opting out of the injection, e.g. the debug clones, are not part of any group.
+	if podutils.SkipInjection(pod) {
+		return ctrl.Result{}, nil
+	}
+	// The pods are labeled here as well when the pod webhook misses them, e.g. with the Ignore failure
+	// policy or outside of its namespace selector. Labeling the pod triggers another reconciliation.
+	labeled, err := r.labelPod(ctx, &pod)
+	if err != nil || labeled {
+		return ctrl.Result{}, err
+	}
 	if _, exist := pod.Labels[leaderworkerset.WorkerIndexLabelKey]; !exist {
 		return ctrl.Result{}, errors.New("leaderworkerset.sigs.k8s.io/worker-index label is unexpected missing")
 	}
@@ -80,7 +144,34 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		// If lws not found, it's mostly because deleted, ignore the error as Pods will be GCed finally.
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
-	leaderDeleted, err := r.handleRestartPolicy(ctx, pod, leaderWorkerSet)
+	// The group is skipped until the cache observes the deletion of its leader pod, otherwise the lagging
+	// cache would make it recreated twice, or the worker statefulset applied for the deleted leader pod.
+	pending, err := r.leaderDeletionPending(ctx, pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if pending {
+		log.V(2).Info("skip the reconciliation since the deletion of the leader pod is not observed yet")
+		return ctrl.Result{}, nil
+	}
+	// The completed group is neither restarted nor recreated anymore.
+	completed, err := r.handleGroupCompletion(ctx, pod, leaderWorkerSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if completed {
+		log.V(2).Info("skip the reconciliation since the group is succeeded")
+		return ctrl.Result{}, nil
+	}
+	leaderDeleted, err := r.handlePreemption(ctx, pod, leaderWorkerSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if leaderDeleted {
+		log.V(2).Info("recreating the preempted group")
+		return ctrl.Result{}, nil
+	}
+	leaderDeleted, requeueAfter, err := r.handleRestartPolicy(ctx, pod, leaderWorkerSet)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -88,6 +179,10 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.V(2).Info("restarting the group")
 		return ctrl.Result{}, nil
 	}
+	if requeueAfter > 0 {
+		log.V(2).Info("delay restarting the group for backoff", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
 
 	// worker pods' reconciliation is only done to handle restart policy
 	if !podutils.LeaderPod(pod) {
@@ -101,17 +196,84 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		log.V(2).Info("skip creating the worker sts since the leader pod is being deleted")
 		return ctrl.Result{}, nil
 	}
+	leaderDeleted, requeueAfter, err = r.handleRestartRequest(ctx, pod, leaderWorkerSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if leaderDeleted {
+		log.V(2).Info("restarting the group as requested")
+		return ctrl.Result{}, nil
+	}
+	if requeueAfter > 0 {
+		log.V(2).Info("delay restarting the group for the restart budgets", "requeueAfter", requeueAfter)
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	if ptr.Deref(leaderWorkerSet.Spec.Suspend, false) {
+		log.V(2).Info("skip creating the worker sts since the leaderworkerset is suspended")
+		return ctrl.Result{}, nil
+	}
+	if groupTearingDown(&leaderWorkerSet, pod) {
+		log.V(2).Info("skip creating the worker sts since the group is being deleted")
+		return ctrl.Result{}, nil
+	}
+
+	// The leader pod is not ready until its serving condition is set.
+	if err := r.setServingCondition(ctx, &leaderWorkerSet, &pod); err != nil {
+		log.Error(err, "Setting the serving condition of the leader pod")
+		return ctrl.Result{}, err
+	}
+
+	// healthCheckAfter is the time to check the leader pod again if it's not ready yet.
+	leaderDeleted, healthCheckAfter, err := r.handleUnhealthyLeader(ctx, pod, leaderWorkerSet)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if leaderDeleted {
+		log.V(2).Info("restarting the group since the leader pod is unhealthy")
+		return ctrl.Result{}, nil
+	}
+
+	// Create the PodGroup before the worker pods, the pods of the group are not scheduled until then.
+	if providerType, found := leaderWorkerSet.Annotations[leaderworkerset.GangSchedulingAnnotationKey]; found {
+		provider, err := schedulerprovider.NewSchedulerProvider(schedulerprovider.ProviderType(providerType))
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := provider.CreatePodGroupIfNotExists(ctx, r.Client, r.Scheme, &leaderWorkerSet, &pod); err != nil {
+			log.Error(err, "Creating pod group")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if utils.SubdomainPolicy(&leaderWorkerSet) == leaderworkerset.SubdomainUniquePerReplica && !headlessServiceDisabled(&leaderWorkerSet) {
+		if err := reconcileHeadlessService(ctx, r.Client, r.Scheme, &leaderWorkerSet, &pod, pod.Name, map[string]string{
+			leaderworkerset.SetNameLabelKey:    leaderWorkerSet.Name,
+			leaderworkerset.GroupIndexLabelKey: pod.Labels[leaderworkerset.GroupIndexLabelKey],
+		}); err != nil {
+			log.Error(err, "Creating headless service for the group")
+			return ctrl.Result{}, err
+		}
+	}
 
 	// logic for handling leader pod
 	if leaderWorkerSet.Spec.StartupPolicy == leaderworkerset.LeaderReadyStartupPolicy && !k8spodutils.IsPodReady(&pod) {
 		log.V(2).Info("defer the creation of the worker statefulset because leader pod is not ready.")
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: healthCheckAfter}, nil
 	}
 
-	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, leaderWorkerSet)
+	groupLeaderWorkerSet, err := r.leaderWorkerSetAtLeaderRevision(ctx, leaderWorkerSet, pod)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	statefulSet, err := constructWorkerStatefulSetApplyConfiguration(pod, groupLeaderWorkerSet)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if r.PodWebhookDisabled {
+		if err := stampPodTemplate(statefulSet, leaderworkerset.WorkerIndexLabelKey); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// if exclusive placement is enabled but leader pod is not scheduled, don't create the worker sts
 	if topologyKey, found := leaderWorkerSet.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
@@ -120,6 +282,13 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			log.V(2).Info(fmt.Sprintf("Pod %q is not scheduled yet", pod.Name))
 			return ctrl.Result{}, nil
 		}
+		// The exclusive affinities are injected by the pod webhook, fall back to the NodeSelector policy without it.
+		if r.PodWebhookDisabled || leaderWorkerSet.Annotations[leaderworkerset.ExclusivePlacementPolicyAnnotationKey] == string(leaderworkerset.ExclusivePlacementNodeSelector) {
+			claimed, err := r.claimExclusiveDomain(ctx, &pod, topologyKey)
+			if err != nil || !claimed {
+				return ctrl.Result{}, err
+			}
+		}
 		if err := r.setNodeSelectorForWorkerPods(ctx, &pod, statefulSet, topologyKey); err != nil {
 			log.Error(err, "setting node selector for worker pods")
 			return ctrl.Result{}, err
@@ -131,59 +300,566 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		return ctrl.Result{}, nil
 	}
 
-	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(statefulSet)
+	// TODO b/316776287 add E2E test for SSA
+	applyCtx, span := tracing.Start(ctx, "Apply worker StatefulSet", tracing.ObjectAttributes("StatefulSet", pod.Namespace, pod.Name)...)
+	err = applyStatefulSet(applyCtx, r.Client, statefulSet)
+	tracing.End(span, err)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
-	patch := &unstructured.Unstructured{
-		Object: obj,
+	if pod.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey] == "true" {
+		if err := r.ungateGroupIfCompleted(ctx, &leaderWorkerSet, &pod); err != nil {
+			log.Error(err, "Removing scheduling gates of the group")
+			return ctrl.Result{}, err
+		}
 	}
-	// Use server side apply and add fieldmanagaer to the lws owned fields
-	// If there are conflicts in the fields owned by the lws controller, lws will obtain the ownership and force override
-	// these fields to the ones desired by the lws controller. These fields are specified in the StatefulSetApplyConfiguration
-	// TODO b/316776287 add E2E test for SSA
-	err = r.Patch(ctx, patch, client.Apply, &client.PatchOptions{
-		FieldManager: fieldManager,
-		Force:        ptr.To[bool](true),
-	})
-	if err != nil {
-		return ctrl.Result{}, err
+	if pod.Annotations[leaderworkerset.GroupReadinessGateAnnotationKey] == "true" {
+		if err := r.setWorkersReadyCondition(ctx, &leaderWorkerSet, &pod); err != nil {
+			log.Error(err, "Setting the workers ready condition of the leader pod")
+			return ctrl.Result{}, err
+		}
 	}
 	log.V(2).Info("Worker Reconcile completed.")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: healthCheckAfter}, nil
 }
 
-func (r *PodReconciler) handleRestartPolicy(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, error) {
-	if leaderWorkerSet.Spec.LeaderWorkerTemplate.RestartPolicy != leaderworkerset.RecreateGroupOnPodRestart {
+// ungateGroupIfCompleted removes the scheduling gates of all the pods in the group once all of them
+// are created, so that partial groups never consume the nodes.
+func (r *PodReconciler) ungateGroupIfCompleted(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(leaderPod.Namespace),
+		client.MatchingFields{podGroupKey: groupIndexValue(lws.Name, leaderPod.Labels[leaderworkerset.GroupIndexLabelKey])},
+		client.MatchingLabels{leaderworkerset.GroupUniqueHashLabelKey: leaderPod.Labels[leaderworkerset.GroupUniqueHashLabelKey]}); err != nil {
+		return err
+	}
+	if len(podList.Items) < int(*lws.Spec.LeaderWorkerTemplate.Size) {
+		ctrl.LoggerFrom(ctx).V(2).Info("Waiting for all the pods of the group to be created", "created", len(podList.Items))
+		return nil
+	}
+	className := lws.Annotations[leaderworkerset.ProvisioningClassNameAnnotationKey]
+	if className != "" {
+		provisioned, err := r.ensureProvisioningRequest(ctx, lws, leaderPod)
+		if err != nil || !provisioned {
+			return err
+		}
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !podutils.RemoveSchedulingGate(pod, leaderworkerset.GroupSchedulingGateName) {
+			continue
+		}
+		if className != "" {
+			pod.Annotations[provisioningutils.ConsumeAnnotationKey] = provisioningutils.RequestName(leaderPod)
+			pod.Annotations[provisioningutils.ClassNameAnnotationKey] = className
+		}
+		if err := r.Update(ctx, pod); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setWorkersReadyCondition sets the readiness gate condition of the leader pod to true only when all
+// the workers of the group are ready, the worker statefulset is owned by the leader pod so the leader
+// pod is reconciled again once the readiness of the workers changes.
+func (r *PodReconciler) setWorkersReadyCondition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.InNamespace(leaderPod.Namespace),
+		client.MatchingFields{podGroupKey: groupIndexValue(lws.Name, leaderPod.Labels[leaderworkerset.GroupIndexLabelKey])},
+		client.MatchingLabels{leaderworkerset.GroupUniqueHashLabelKey: leaderPod.Labels[leaderworkerset.GroupUniqueHashLabelKey]}); err != nil {
+		return err
+	}
+	readyWorkers := 0
+	for _, pod := range podList.Items {
+		if !podutils.LeaderPod(pod) && pod.DeletionTimestamp == nil && podutils.PodRunningAndReady(pod) {
+			readyWorkers++
+		}
+	}
+	status, reason := corev1.ConditionFalse, "WorkersNotReady"
+	if readyWorkers >= int(*lws.Spec.LeaderWorkerTemplate.Size)-1 {
+		status, reason = corev1.ConditionTrue, "AllWorkersReady"
+	}
+	if !podutils.SetPodCondition(leaderPod, leaderworkerset.WorkersReadyConditionType, status, reason) {
+		return nil
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Setting the workers ready condition of the leader pod", "status", status, "readyWorkers", readyWorkers)
+	return r.Status().Update(ctx, leaderPod)
+}
+
+// setServingCondition sets the serving readiness gate condition of the leader pod to true once created, the lws
+// controller sets it to false when draining the group before the deletion.
+func (r *PodReconciler) setServingCondition(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	if lws.Spec.DrainSeconds == nil || podutils.GetPodCondition(*leaderPod, leaderworkerset.ServingConditionType) != nil {
+		return nil
+	}
+	podutils.SetPodCondition(leaderPod, leaderworkerset.ServingConditionType, corev1.ConditionTrue, "Serving")
+	return r.Status().Update(ctx, leaderPod)
+}
+
+// ensureProvisioningRequest creates the ProvisioningRequest of the group together with the referenced pod
+// templates if not exists, and returns whether the capacity of the group is provisioned.
+func (r *PodReconciler) ensureProvisioningRequest(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+	request := &unstructured.Unstructured{}
+	request.SetGroupVersionKind(provisioningutils.ProvisioningRequestGVK)
+	err := r.Get(ctx, types.NamespacedName{Name: provisioningutils.RequestName(leaderPod), Namespace: leaderPod.Namespace}, request)
+	if err == nil {
+		if provisioningutils.Failed(request) {
+			log.V(2).Info("Provisioning request of the group is failed, the group will not be scheduled", "provisioningRequest", request.GetName())
+			return false, nil
+		}
+		return provisioningutils.Provisioned(request), nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	// Both the pod templates and the request are garbage collected together with the leader pod.
+	templates := provisioningutils.NewPodTemplates(lws, leaderPod)
+	for i := range templates {
+		if err := ctrl.SetControllerReference(leaderPod, &templates[i], r.Scheme); err != nil {
+			return false, err
+		}
+		if err := r.Create(ctx, &templates[i]); client.IgnoreAlreadyExists(err) != nil {
+			return false, err
+		}
+	}
+	request, err = provisioningutils.NewProvisioningRequest(lws, leaderPod, templates)
+	if err != nil {
+		return false, err
+	}
+	if err := ctrl.SetControllerReference(leaderPod, request, r.Scheme); err != nil {
+		return false, err
+	}
+	log.V(2).Info("Creating provisioning request of the group", "provisioningRequest", request.GetName())
+	return false, client.IgnoreAlreadyExists(r.Create(ctx, request))
+}
+
+func (r *PodReconciler) handleRestartPolicy(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	restartPolicy := leaderWorkerSet.Spec.LeaderWorkerTemplate.RestartPolicy
+	// Evicted or preempted pods recreate the whole group regardless of the restart policy if opted in.
+	disrupted := groupDisrupted(&leaderWorkerSet, pod)
+	if !disrupted && restartPolicy != leaderworkerset.RecreateGroupOnPodRestart && restartPolicy != leaderworkerset.RecreateWorkerOnPodRestart {
+		return false, 0, nil
+	}
+	// the leader pod will be deleted if the worker pod is deleted, failed or any containes were restarted
+	if !disrupted && !podutils.ContainerRestarted(pod) && !podutils.PodDeleted(pod) && !podutils.PodFailed(pod) {
+		return false, 0, nil
+	}
+	// Only the worker pod is recreated, the worker statefulset creates it again to rejoin the group.
+	if restartPolicy == leaderworkerset.RecreateWorkerOnPodRestart && !podutils.LeaderPod(pod) && !disrupted {
+		if podutils.PodDeleted(pod) {
+			return false, 0, nil
+		}
+		ctrl.LoggerFrom(ctx).V(2).Info("Recreating the worker pod")
+		return false, 0, client.IgnoreNotFound(r.Delete(ctx, &pod))
+	}
+	leader, err := r.getLeaderPod(ctx, pod)
+	if err != nil {
+		return false, 0, err
+	}
+	// if the leader pod is being deleted, we don't need to send deletion requests
+	if leader.DeletionTimestamp != nil {
+		return true, 0, nil
+	}
+	// The deleted workers of the group being deleted are not restarted.
+	if groupTearingDown(&leaderWorkerSet, leader) {
+		return false, 0, nil
+	}
+	reason, cause := restartCause(&leaderWorkerSet, pod)
+	return r.restartGroup(ctx, &leaderWorkerSet, leader, pod, reason, cause)
+}
+
+// restartCause returns the reason why the pod recreates its group for the metrics, and the cause for the event.
+func restartCause(lws *leaderworkerset.LeaderWorkerSet, pod corev1.Pod) (string, string) {
+	switch {
+	case lws.Annotations[leaderworkerset.GroupEvictionAnnotationKey] == "true" && podutils.PodEvicted(pod):
+		return "Evicted", fmt.Sprintf("pod %s is evicted", pod.Name)
+	case lws.Annotations[leaderworkerset.GroupPreemptionAnnotationKey] == "true" && podutils.PodPreemptedByScheduler(pod):
+		return "PreemptedByScheduler", fmt.Sprintf("pod %s is preempted by the scheduler", pod.Name)
+	case podutils.PodDeleted(pod):
+		return "PodDeleted", fmt.Sprintf("pod %s is deleted", pod.Name)
+	case podutils.PodFailed(pod):
+		return "PodFailed", fmt.Sprintf("pod %s is failed", pod.Name)
+	}
+	return "ContainerRestarted", fmt.Sprintf("a container of pod %s restarted", pod.Name)
+}
+
+// groupTearingDown returns true if the group of the leader pod is being deleted, either with the lws, or by the lws
+// controller with the WorkersFirst termination order, whose workers are deleted before the leader pod.
+func groupTearingDown(lws *leaderworkerset.LeaderWorkerSet, leader corev1.Pod) bool {
+	return lws.DeletionTimestamp != nil || leader.Annotations[leaderworkerset.TeardownAnnotationKey] == "true"
+}
+
+// groupDisrupted returns true if the pod is evicted, or preempted by the scheduler, and the lws opts in to
+// recreate the whole group on such disruptions.
+func groupDisrupted(lws *leaderworkerset.LeaderWorkerSet, pod corev1.Pod) bool {
+	return (lws.Annotations[leaderworkerset.GroupEvictionAnnotationKey] == "true" && podutils.PodEvicted(pod)) ||
+		(lws.Annotations[leaderworkerset.GroupPreemptionAnnotationKey] == "true" && podutils.PodPreemptedByScheduler(pod))
+}
+
+// handlePreemption recreates the whole group once any of its pods is about to be preempted per the preemption
+// policy. The group is marked as preempted before, so that its pods are recreated with the fallback node selector.
+func (r *PodReconciler) handlePreemption(ctx context.Context, pod corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, error) {
+	policy := leaderWorkerSet.Spec.LeaderWorkerTemplate.PreemptionPolicy
+	if policy == nil {
 		return false, nil
 	}
-	// the leader pod will be deleted if the worker pod is deleted or any containes were restarted
-	if !podutils.ContainerRestarted(pod) && !podutils.PodDeleted(pod) {
+	preempted, err := r.podPreempted(ctx, pod, policy)
+	if err != nil || !preempted {
+		return false, err
+	}
+	leader, err := r.getLeaderPod(ctx, pod)
+	if err != nil {
+		return false, err
+	}
+	if leader.DeletionTimestamp != nil {
+		return true, nil
+	}
+	status, err := replicaStatusOf(&leaderWorkerSet, leader)
+	if err != nil {
+		return false, err
+	}
+	if !status.Preempted {
+		status.Preempted = true
+		if err := r.Status().Update(ctx, &leaderWorkerSet); err != nil {
+			return false, err
+		}
+	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Recreating the preempted group", "node", pod.Spec.NodeName)
+	if err := r.recreateGroup(ctx, &leaderWorkerSet, &leader, "Preempted", fmt.Sprintf("pod %s is preempted from node %s", pod.Name, pod.Spec.NodeName)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// podPreempted returns true if the pod is being terminated since its node is going away, or the node
+// is tainted with any of the taints of the preemption policy.
+func (r *PodReconciler) podPreempted(ctx context.Context, pod corev1.Pod, policy *leaderworkerset.PreemptionPolicy) (bool, error) {
+	if podutils.PodPreempted(pod) {
+		return true, nil
+	}
+	if len(policy.NodeTaintKeys) == 0 || pod.Spec.NodeName == "" {
 		return false, nil
 	}
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, &node); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	for _, taint := range node.Spec.Taints {
+		if slices.Contains(policy.NodeTaintKeys, taint.Key) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getLeaderPod returns the leader pod of the group the pod belongs to.
+func (r *PodReconciler) getLeaderPod(ctx context.Context, pod corev1.Pod) (corev1.Pod, error) {
+	if podutils.LeaderPod(pod) {
+		return pod, nil
+	}
 	var leader corev1.Pod
-	if !podutils.LeaderPod(pod) {
-		leaderPodName, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
-		if ordinal == -1 {
-			return false, fmt.Errorf("parsing pod name for pod %s", pod.Name)
+	leaderPodName, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	if ordinal == -1 {
+		return leader, fmt.Errorf("parsing pod name for pod %s", pod.Name)
+	}
+	err := r.Get(ctx, types.NamespacedName{Name: leaderPodName, Namespace: pod.Namespace}, &leader)
+	return leader, err
+}
+
+// leaderWorkerSetAtLeaderRevision returns the lws with the templates of the revision the leader pod is created from
+// under the OnDelete and BlueGreen rollout strategies, so the workers are not updated until the group is recreated.
+// The current templates are used if the revision is already truncated.
+func (r *PodReconciler) leaderWorkerSetAtLeaderRevision(ctx context.Context, lws leaderworkerset.LeaderWorkerSet, leaderPod corev1.Pod) (leaderworkerset.LeaderWorkerSet, error) {
+	leaderHash := leaderPod.Labels[leaderworkerset.TemplateRevisionHashKey]
+	if lws.Spec.RolloutStrategy.Type == leaderworkerset.RollingUpdateStrategyType || leaderHash == "" || leaderHash == utils.LeaderWorkerTemplateHash(&lws) {
+		return lws, nil
+	}
+	var revisionList appsv1.ControllerRevisionList
+	if err := r.List(ctx, &revisionList, client.InNamespace(lws.Namespace), client.MatchingLabels{
+		leaderworkerset.SetNameLabelKey:         lws.Name,
+		leaderworkerset.TemplateRevisionHashKey: leaderHash,
+	}); err != nil {
+		return lws, err
+	}
+	if len(revisionList.Items) == 0 {
+		ctrl.LoggerFrom(ctx).V(2).Info("Revision of the leader pod not found, using the current templates", "templateHash", leaderHash)
+		return lws, nil
+	}
+	groupLws := lws.DeepCopy()
+	if err := revisionutils.ApplyRevision(groupLws, &revisionList.Items[0]); err != nil {
+		return lws, err
+	}
+	return *groupLws, nil
+}
+
+// handleGroupCompletion marks the group as succeeded once the leader container exits with 0 with the LeaderSucceeded
+// termination policy, and deletes the worker statefulset of the succeeded group. It returns true if the group is succeeded.
+func (r *PodReconciler) handleGroupCompletion(ctx context.Context, pod corev1.Pod, lws leaderworkerset.LeaderWorkerSet) (bool, error) {
+	if lws.Spec.TerminationPolicy != leaderworkerset.LeaderSucceededTerminationPolicy {
+		return false, nil
+	}
+	groupIndex, err := strconv.Atoi(pod.Labels[leaderworkerset.GroupIndexLabelKey])
+	if err != nil {
+		return false, err
+	}
+	status := findReplicaStatus(&lws, int32(groupIndex))
+	if status == nil || status.Phase != leaderworkerset.ReplicaSucceeded {
+		// The leader container is the first container of the leader pod.
+		if !podutils.LeaderPod(pod) || len(pod.Spec.Containers) == 0 || !podutils.ContainerSucceeded(pod, pod.Spec.Containers[0].Name) {
+			return false, nil
 		}
-		if err := r.Get(ctx, types.NamespacedName{Name: leaderPodName, Namespace: pod.Namespace}, &leader); err != nil {
+		status, err = replicaStatusOf(&lws, pod)
+		if err != nil {
+			return false, err
+		}
+		status.Phase = leaderworkerset.ReplicaSucceeded
+		status.Reason = ""
+		ctrl.LoggerFrom(ctx).V(2).Info("Group is succeeded", "groupIndex", groupIndex)
+		if err := r.Status().Update(ctx, &lws); err != nil {
 			return false, err
 		}
-	} else {
-		leader = pod
 	}
-	// if the leader pod is being deleted, we don't need to send deletion requests
-	if leader.DeletionTimestamp != nil {
+	if !podutils.LeaderPod(pod) {
+		return true, nil
+	}
+	var sts appsv1.StatefulSet
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &sts); err != nil {
+		return true, client.IgnoreNotFound(err)
+	}
+	if sts.DeletionTimestamp != nil {
 		return true, nil
 	}
+	ctrl.LoggerFrom(ctx).V(2).Info("Deleting the worker statefulset of the succeeded group")
+	return true, client.IgnoreNotFound(r.Delete(ctx, &sts, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+}
+
+// handleRestartRequest recreates the group once its restart is requested by the restart group annotation,
+// which the lws controller moves from the lws to the leader pod. The requested restart is bound by the restart
+// budgets, but not by the failure policy since the group doesn't fail.
+func (r *PodReconciler) handleRestartRequest(ctx context.Context, leader corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	if _, found := leader.Annotations[leaderworkerset.RestartGroupAnnotationKey]; !found {
+		return false, 0, nil
+	}
+	r.restartMu.Lock()
+	defer r.restartMu.Unlock()
+	allowed, requeueAfter, err := r.groupRestartAllowed(ctx, &leaderWorkerSet)
+	if err != nil || !allowed {
+		return false, requeueAfter, err
+	}
+	if err := r.recreateGroup(ctx, &leaderWorkerSet, &leader, "Requested", "a restart is requested"); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
+
+// handleUnhealthyLeader recreates the group once the leader pod is persistently unhealthy per the leader health
+// policy. If the leader pod is not ready yet, it returns the time to check the leader pod again as well.
+func (r *PodReconciler) handleUnhealthyLeader(ctx context.Context, leader corev1.Pod, leaderWorkerSet leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	policy := leaderWorkerSet.Spec.LeaderWorkerTemplate.LeaderHealthPolicy
+	// The draining leader pod is not ready on purpose.
+	if policy == nil || podutils.Draining(leader) {
+		return false, 0, nil
+	}
+	unhealthy, requeueAfter := leaderUnhealthy(leader, policy, time.Now())
+	if !unhealthy {
+		return false, requeueAfter, nil
+	}
+	return r.restartGroup(ctx, &leaderWorkerSet, leader, leader, "LeaderUnhealthy", fmt.Sprintf("leader pod %s is unhealthy", leader.Name))
+}
+
+// leaderUnhealthy returns true if the leader pod exceeds any threshold of the leader health policy, otherwise
+// it returns the remaining time before the leader pod exceeds the not ready threshold.
+func leaderUnhealthy(leader corev1.Pod, policy *leaderworkerset.LeaderHealthPolicy, now time.Time) (bool, time.Duration) {
+	if policy.RestartThreshold != nil && podutils.ContainerRestarts(leader) >= *policy.RestartThreshold {
+		return true, 0
+	}
+	if policy.NotReadySeconds == nil {
+		return false, 0
+	}
+	notReadySince, notReady := podutils.NotReadySince(leader)
+	if !notReady {
+		return false, 0
+	}
+	deadline := notReadySince.Add(time.Duration(*policy.NotReadySeconds) * time.Second)
+	if !now.Before(deadline) {
+		return true, 0
+	}
+	return false, deadline.Sub(now)
+}
+
+// deleteLeaderPod deletes the leader pod in the foreground, which recreates the whole group.
+func (r *PodReconciler) deleteLeaderPod(ctx context.Context, leader *corev1.Pod) error {
 	deletionOpt := metav1.DeletePropagationForeground
-	if err := r.Delete(ctx, &leader, &client.DeleteOptions{
+	if err := r.Delete(ctx, leader, &client.DeleteOptions{
 		PropagationPolicy: &deletionOpt,
 	}); err != nil {
+		return err
+	}
+	r.leaderDeletions.ExpectDeletion(client.ObjectKeyFromObject(leader), leader.UID)
+	return nil
+}
+
+// leaderDeletionPending returns true if the leader pod of the group of the pod is deleted by the controller,
+// but the cache doesn't observe the deletion yet. The deletion event of the leader pod triggers the reconciliation.
+func (r *PodReconciler) leaderDeletionPending(ctx context.Context, pod corev1.Pod) (bool, error) {
+	leader, err := r.getLeaderPod(ctx, pod)
+	if apierrors.IsNotFound(err) {
+		leaderName, _ := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+		r.leaderDeletions.DeletionObserved(types.NamespacedName{Namespace: pod.Namespace, Name: leaderName})
+		return false, nil
+	}
+	if err != nil {
 		return false, err
 	}
-	return true, nil
+	key := client.ObjectKeyFromObject(&leader)
+	if leader.DeletionTimestamp != nil {
+		r.leaderDeletions.DeletionObserved(key)
+		return false, nil
+	}
+	return r.leaderDeletions.DeletionPending(key, leader.UID), nil
+}
+
+// recreateGroup deletes the leader pod to recreate the whole group, and records the cause in an event.
+func (r *PodReconciler) recreateGroup(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leader *corev1.Pod, reason, cause string) error {
+	if err := r.deleteLeaderPod(ctx, leader); err != nil {
+		return err
+	}
+	metrics.GroupRestarted(reason)
+	r.Record.Eventf(lws, corev1.EventTypeNormal, GroupRecreated, "Group %s is recreated since %s", leader.Labels[leaderworkerset.GroupIndexLabelKey], cause)
+	return nil
+}
+
+// restartGroup recreates the group of the leader pod if the failure policy and the restart budgets allow it.
+// The budgets are checked and the leader pod is deleted under restartMu, so that the groups recreated by the
+// concurrent reconciles are accounted for. It returns true if the group is recreated.
+func (r *PodReconciler) restartGroup(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leader corev1.Pod, pod corev1.Pod, reason, cause string) (bool, time.Duration, error) {
+	r.restartMu.Lock()
+	defer r.restartMu.Unlock()
+	recreate, requeueAfter, err := r.recordGroupRestart(ctx, lws, leader, pod)
+	if err != nil || !recreate {
+		return false, requeueAfter, err
+	}
+	if err := r.recreateGroup(ctx, lws, &leader, reason, cause); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
+
+// recordGroupRestart checks the failure policy before recreating the group and records the restart
+// in the lws status. It returns false if the group should not be recreated, either because the group
+// is failed by the failure policy, or because it is still in restart backoff, in which case the remaining
+// backoff is returned as well.
+func (r *PodReconciler) recordGroupRestart(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, leader corev1.Pod, pod corev1.Pod) (bool, time.Duration, error) {
+	failurePolicy := lws.Spec.FailurePolicy
+	if failurePolicy == nil {
+		return r.groupRestartAllowed(ctx, lws)
+	}
+	if meta.IsStatusConditionTrue(lws.Status.Conditions, string(leaderworkerset.LeaderWorkerSetFailed)) {
+		return false, 0, nil
+	}
+	status, err := replicaStatusOf(lws, leader)
+	if err != nil {
+		return false, 0, err
+	}
+	if status.Phase == leaderworkerset.ReplicaFailed && status.Reason != "" {
+		return false, 0, nil
+	}
+
+	if rule := podutils.MatchFailurePolicyRule(failurePolicy.Rules, pod); rule != nil {
+		if rule.Action == leaderworkerset.RestartGroupAction {
+			return r.groupRestartAllowed(ctx, lws)
+		}
+		return false, 0, r.failGroup(ctx, lws, status, rule.Action, "FailurePolicyRuleMatched")
+	}
+	if groupRestartsExhausted(lws, *status) {
+		return false, 0, r.failGroup(ctx, lws, status, failurePolicy.Action, "MaxRestartsExceeded")
+	}
+	if failurePolicy.RestartBackoffSeconds != nil && status.LastRestartTime != nil {
+		backoffEnd := status.LastRestartTime.Add(time.Duration(*failurePolicy.RestartBackoffSeconds) * time.Second)
+		if now := time.Now(); now.Before(backoffEnd) {
+			return false, backoffEnd.Sub(now), nil
+		}
+	}
+	if allowed, requeueAfter, err := r.groupRestartAllowed(ctx, lws); err != nil || !allowed {
+		return false, requeueAfter, err
+	}
+
+	status.Restarts++
+	status.LastRestartTime = ptr.To(metav1.Now())
+	if err := r.Status().Update(ctx, lws); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
+
+// replicaStatusOf returns the status of the group led by the leader pod, it's added to the lws status if missing.
+func replicaStatusOf(lws *leaderworkerset.LeaderWorkerSet, leader corev1.Pod) (*leaderworkerset.ReplicaStatus, error) {
+	groupIndex, err := strconv.Atoi(leader.Labels[leaderworkerset.GroupIndexLabelKey])
+	if err != nil {
+		return nil, err
+	}
+	if status := findReplicaStatus(lws, int32(groupIndex)); status != nil {
+		return status, nil
+	}
+	lws.Status.ReplicaStatuses = append(lws.Status.ReplicaStatuses, leaderworkerset.ReplicaStatus{
+		Index:    int32(groupIndex),
+		Phase:    leaderworkerset.ReplicaPending,
+		Revision: leader.Labels[leaderworkerset.TemplateRevisionHashKey],
+	})
+	return &lws.Status.ReplicaStatuses[len(lws.Status.ReplicaStatuses)-1], nil
+}
+
+// groupRestartAllowed checks whether one more group can be recreated within the restart budgets of both the
+// controller and the lws, otherwise it returns the time to retry. Groups whose leader pods are being deleted
+// are counted as being recreated, including the deletions the cache doesn't observe yet.
+func (r *PodReconciler) groupRestartAllowed(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet) (bool, time.Duration, error) {
+	var lwsBudget int32
+	if lws.Spec.FailurePolicy != nil {
+		lwsBudget = ptr.Deref(lws.Spec.FailurePolicy.MaxConcurrentRestarts, 0)
+	}
+	maxRestarts := int(r.MaxConcurrentGroupRestarts.Load())
+	if maxRestarts <= 0 && lwsBudget == 0 {
+		return true, 0, nil
+	}
+	var leaderPods corev1.PodList
+	if err := r.List(ctx, &leaderPods, client.HasLabels{leaderworkerset.SetNameLabelKey}, client.MatchingLabels{leaderworkerset.WorkerIndexLabelKey: "0"}); err != nil {
+		return false, 0, err
+	}
+	var restarting, lwsRestarting int
+	for _, pod := range leaderPods.Items {
+		if pod.DeletionTimestamp == nil && !r.leaderDeletions.DeletionPending(client.ObjectKeyFromObject(&pod), pod.UID) {
+			continue
+		}
+		restarting++
+		if pod.Namespace == lws.Namespace && pod.Labels[leaderworkerset.SetNameLabelKey] == lws.Name {
+			lwsRestarting++
+		}
+	}
+	if (maxRestarts > 0 && restarting >= maxRestarts) || (lwsBudget > 0 && lwsRestarting >= int(lwsBudget)) {
+		ctrl.LoggerFrom(ctx).V(2).Info("Group restart budget is exhausted", "restartingGroups", restarting, "lwsRestartingGroups", lwsRestarting)
+		return false, groupRestartRetryInterval, nil
+	}
+	return true, 0, nil
+}
+
+// failGroup marks the group as failed with the given reason, the whole lws is marked as failed as well
+// if the action is FailLeaderWorkerSet.
+func (r *PodReconciler) failGroup(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, status *leaderworkerset.ReplicaStatus, action leaderworkerset.FailurePolicyAction, reason string) error {
+	status.Phase = leaderworkerset.ReplicaFailed
+	status.Reason = reason
+	if action == leaderworkerset.FailLeaderWorkerSetAction {
+		condition := makeCondition(leaderworkerset.LeaderWorkerSetFailed)
+		condition.Reason = reason
+		condition.Message = fmt.Sprintf("Group %d is failed by the failure policy", status.Index)
+		meta.SetStatusCondition(&lws.Status.Conditions, condition)
+	}
+	if err := r.Status().Update(ctx, lws); err != nil {
+		return err
+	}
+	r.Record.Eventf(lws, corev1.EventTypeWarning, GroupFailed, "Group %d is failed: %s", status.Index, reason)
+	return nil
 }
 
 func (r *PodReconciler) setNodeSelectorForWorkerPods(ctx context.Context, pod *corev1.Pod, sts *appsapplyv1.StatefulSetApplyConfiguration, topologyKey string) error {
@@ -204,15 +880,47 @@ func (r *PodReconciler) setNodeSelectorForWorkerPods(ctx context.Context, pod *c
 	return nil
 }
 
+// claimExclusiveDomain labels the leader pod with the topology domain it's scheduled to, which is the only
+// guarantee of exclusivity for the NodeSelector exclusive placement policy. The leader pod is deleted to be
+// rescheduled if the domain is already claimed by another group. It returns true once the domain is claimed.
+// The claims are read from the API server under claimMu, so that two groups never claim the same domain.
+func (r *PodReconciler) claimExclusiveDomain(ctx context.Context, leader *corev1.Pod, topologyKey string) (bool, error) {
+	log := ctrl.LoggerFrom(ctx)
+	topologyValue, err := r.topologyValueFromPod(ctx, leader, topologyKey)
+	if err != nil || topologyValue == "" {
+		return false, err
+	}
+	domain := utils.Sha1Hash(topologyKey + "=" + topologyValue)
+	if leader.Labels[leaderworkerset.ExclusiveDomainLabelKey] == domain {
+		return true, nil
+	}
+
+	r.claimMu.Lock()
+	defer r.claimMu.Unlock()
+	var podList corev1.PodList
+	if err := r.apiReader.List(ctx, &podList, client.InNamespace(leader.Namespace), client.MatchingLabels{
+		leaderworkerset.ExclusiveDomainLabelKey: domain,
+	}); err != nil {
+		return false, err
+	}
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp == nil && pod.Labels[leaderworkerset.GroupUniqueHashLabelKey] != leader.Labels[leaderworkerset.GroupUniqueHashLabelKey] {
+			log.V(2).Info(fmt.Sprintf("Topology %s=%s is claimed by pod %q, rescheduling the leader pod", topologyKey, topologyValue, pod.Name))
+			return false, client.IgnoreNotFound(r.deleteLeaderPod(ctx, leader))
+		}
+	}
+	leader.Labels[leaderworkerset.ExclusiveDomainLabelKey] = domain
+	return true, r.Update(ctx, leader)
+}
+
 func (r *PodReconciler) topologyValueFromPod(ctx context.Context, pod *corev1.Pod, topologyKey string) (string, error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	nodeName := pod.Spec.NodeName
-	ns := pod.Namespace
 
 	// Get node the leader pod is running on.
 	var node corev1.Node
-	if err := r.Get(ctx, types.NamespacedName{Name: nodeName, Namespace: ns}, &node); err != nil {
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
 		// We'll ignore not-found errors, since there is nothing we can do here.
 		// A node may not exist temporarily due to a maintenance event or other scenarios.
 		log.Error(err, fmt.Sprintf("getting node %s", nodeName))
@@ -227,6 +935,79 @@ func (r *PodReconciler) topologyValueFromPod(ctx context.Context, pod *corev1.Po
 	return topology, nil
 }
 
+// labelPod adds the labels the pod webhook adds otherwise, the group index and the group unique key of the
+// leader pods and the worker index of the worker pods. It returns true if the pod is labeled.
+func (r *PodReconciler) labelPod(ctx context.Context, pod *corev1.Pod) (bool, error) {
+	_, ordinal := statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+	if ordinal == -1 {
+		return false, fmt.Errorf("parsing pod ordinal for pod %s", pod.Name)
+	}
+	labels := map[string]string{}
+	if podutils.LeaderPod(*pod) {
+		labels[leaderworkerset.GroupIndexLabelKey] = fmt.Sprint(ordinal)
+		// The same as the group unique key generated by the pod webhook.
+		labels[leaderworkerset.GroupUniqueHashLabelKey] = utils.Sha1Hash(fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+	} else {
+		labels[leaderworkerset.WorkerIndexLabelKey] = fmt.Sprint(ordinal)
+	}
+	labeled := false
+	for key, value := range labels {
+		if _, found := pod.Labels[key]; !found {
+			pod.Labels[key] = value
+			labeled = true
+		}
+	}
+	if !labeled {
+		return false, nil
+	}
+	return true, r.Update(ctx, pod)
+}
+
+// stampPodTemplate stamps the pod template of the statefulset with what the pod webhook injects into the pods
+// otherwise: the env vars, the group scheduling gate and the group readiness gate. The pods are told apart by
+// the ordinal of the statefulset, recorded in the label of indexLabelKey.
+func stampPodTemplate(sts *appsapplyv1.StatefulSetApplyConfiguration, indexLabelKey string) error {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sts.Spec.Template)
+	if err != nil {
+		return err
+	}
+	var template corev1.PodTemplateSpec
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &template); err != nil {
+		return err
+	}
+
+	pod := &corev1.Pod{ObjectMeta: template.ObjectMeta, Spec: template.Spec}
+	if template.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey] == "true" {
+		podutils.AddSchedulingGate(pod, leaderworkerset.GroupSchedulingGateName)
+	}
+	if podutils.LeaderPod(*pod) && template.Annotations[leaderworkerset.GroupReadinessGateAnnotationKey] == "true" {
+		podutils.AddReadinessGate(pod, leaderworkerset.WorkersReadyConditionType)
+	}
+	template.Spec = pod.Spec
+
+	if err := podutils.StampEnvVars(&template, func(ordinal int) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: *template.ObjectMeta.DeepCopy(), Spec: *template.Spec.DeepCopy()}
+		pod.Name = fmt.Sprintf("%s-%d", ptr.Deref(sts.Name, ""), ordinal)
+		pod.Namespace = ptr.Deref(sts.Namespace, "")
+		pod.Labels[indexLabelKey] = fmt.Sprint(ordinal)
+		pod.Spec.Subdomain = ptr.Deref(sts.Spec.ServiceName, "")
+		return pod
+	}); err != nil {
+		return err
+	}
+
+	obj, err = runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+	if err != nil {
+		return err
+	}
+	var templateApplyConfiguration coreapplyv1.PodTemplateSpecApplyConfiguration
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, &templateApplyConfiguration); err != nil {
+		return err
+	}
+	sts.Spec.WithTemplate(&templateApplyConfiguration)
+	return nil
+}
+
 // setControllerReferenceWithStatefulSet set controller reference for the StatefulSet
 func setControllerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1.StatefulSetApplyConfiguration, scheme *runtime.Scheme) error {
 	// Validate the owner.
@@ -248,9 +1029,70 @@ func setControllerReferenceWithStatefulSet(owner metav1.Object, sts *appsapplyv1
 	return nil
 }
 
+// renderedStatefulSetHash returns the hash of the rendered StatefulSet apply configuration.
+func renderedStatefulSetHash(sts *appsapplyv1.StatefulSetApplyConfiguration) (string, error) {
+	data, err := json.Marshal(sts)
+	if err != nil {
+		return "", err
+	}
+	return utils.Sha1Hash(string(data)), nil
+}
+
+// applyStatefulSet applies the StatefulSet with server side apply, unless the StatefulSet in the cache was applied
+// from the same rendered configuration already. The hash of the rendered configuration is compared instead of the
+// live object, whose fields defaulted by the api server would otherwise look like a change on every reconcile.
+func applyStatefulSet(ctx context.Context, c client.Client, sts *appsapplyv1.StatefulSetApplyConfiguration) error {
+	hash, err := renderedStatefulSetHash(sts)
+	if err != nil {
+		return err
+	}
+	var current appsv1.StatefulSet
+	err = c.Get(ctx, types.NamespacedName{Namespace: *sts.Namespace, Name: *sts.Name}, &current)
+	if err == nil && current.Annotations[leaderworkerset.RenderedHashAnnotationKey] == hash {
+		return nil
+	}
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+	sts.WithAnnotations(map[string]string{leaderworkerset.RenderedHashAnnotationKey: hash})
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(sts)
+	if err != nil {
+		return err
+	}
+	patch := &unstructured.Unstructured{
+		Object: obj,
+	}
+	// Use server side apply and add fieldmanager to the lws owned fields
+	// If there are conflicts in the fields owned by the lws controller, lws will obtain the ownership and force override
+	// these fields to the ones desired by the lws controller. These fields are specified in the StatefulSetApplyConfiguration
+	return c.Patch(ctx, patch, client.Apply, &client.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        ptr.To[bool](true),
+	})
+}
+
 // constructWorkerStatefulSetApplyConfiguration constructs the applied configuration for the leader StatefulSet
 func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws leaderworkerset.LeaderWorkerSet) (*appsapplyv1.StatefulSetApplyConfiguration, error) {
 	podTemplateSpec := *lws.Spec.LeaderWorkerTemplate.WorkerTemplate.DeepCopy()
+	leaderTemplate := lws.Spec.LeaderWorkerTemplate.LeaderTemplate
+	propagatePlacement := lws.Spec.LeaderWorkerTemplate.PropagatePlacement && leaderTemplate != nil
+	if propagatePlacement {
+		utils.PropagatePlacement(&podTemplateSpec.Spec, &leaderTemplate.Spec)
+	}
+	if policy := lws.Spec.LeaderWorkerTemplate.PriorityPolicy; policy != nil && policy.WorkerPriorityClassName != "" {
+		podTemplateSpec.Spec.PriorityClassName = policy.WorkerPriorityClassName
+	}
+	var groupOverride *leaderworkerset.GroupOverride
+	if groupIndex, err := strconv.Atoi(leaderPod.Labels[leaderworkerset.GroupIndexLabelKey]); err == nil {
+		groupOverride = utils.GroupOverrideOf(&lws, groupIndex)
+	}
+	if groupOverride != nil {
+		utils.ApplyGroupOverride(&podTemplateSpec.Spec, groupOverride)
+	}
+	if prefetch := lws.Spec.LeaderWorkerTemplate.ModelPrefetch; prefetch != nil {
+		podutils.AddModelPrefetch(&podTemplateSpec.Spec, prefetch)
+	}
 	// construct pod template spec configuration
 	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&podTemplateSpec)
 	if err != nil {
@@ -274,12 +1116,67 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 	}
 
 	podTemplateApplyConfiguration.WithLabels(labelMap)
+	// The workers share the subdomain with the leader.
+	serviceName := lws.Name
+	if utils.SubdomainPolicy(&lws) == leaderworkerset.SubdomainUniquePerReplica {
+		serviceName = leaderPod.Name
+	}
 	podAnnotations := make(map[string]string)
 	podAnnotations[leaderworkerset.SizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.Size))
 	podAnnotations[leaderworkerset.LeaderPodNameAnnotationKey] = leaderPod.Name
 	if lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] != "" {
 		podAnnotations[leaderworkerset.ExclusiveKeyAnnotationKey] = lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
 	}
+	if lws.Annotations[leaderworkerset.GangSchedulingAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.GangSchedulingAnnotationKey] = lws.Annotations[leaderworkerset.GangSchedulingAnnotationKey]
+	}
+	if lws.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.GroupSchedulingGateAnnotationKey] = lws.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey]
+	}
+	if utils.SubdomainPolicy(&lws) == leaderworkerset.SubdomainUniquePerReplica {
+		podAnnotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
+	}
+	if lws.Annotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey] != "" {
+		podAnnotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey] = lws.Annotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey]
+	}
+	if lws.Spec.LeaderWorkerTemplate.EnvVarNaming != nil {
+		envVarNaming, err := json.Marshal(lws.Spec.LeaderWorkerTemplate.EnvVarNaming)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.EnvVarNamingAnnotationKey] = string(envVarNaming)
+	}
+	if len(lws.Spec.LeaderWorkerTemplate.TemplatedEnv) > 0 {
+		templatedEnv, err := json.Marshal(lws.Spec.LeaderWorkerTemplate.TemplatedEnv)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.TemplatedEnvAnnotationKey] = string(templatedEnv)
+	}
+	if len(lws.Spec.LeaderWorkerTemplate.WorkerTemplates) > 0 {
+		workerTemplates := make([]leaderworkerset.NamedWorkerTemplate, 0, len(lws.Spec.LeaderWorkerTemplate.WorkerTemplates))
+		for _, template := range lws.Spec.LeaderWorkerTemplate.WorkerTemplates {
+			template := *template.DeepCopy()
+			if propagatePlacement {
+				utils.PropagatePlacement(&template.Template.Spec, &leaderTemplate.Spec)
+			}
+			if policy := lws.Spec.LeaderWorkerTemplate.PriorityPolicy; policy != nil && policy.WorkerPriorityClassName != "" {
+				template.Template.Spec.PriorityClassName = policy.WorkerPriorityClassName
+			}
+			if groupOverride != nil {
+				utils.ApplyGroupOverride(&template.Template.Spec, groupOverride)
+			}
+			if prefetch := lws.Spec.LeaderWorkerTemplate.ModelPrefetch; prefetch != nil {
+				podutils.AddModelPrefetch(&template.Template.Spec, prefetch)
+			}
+			workerTemplates = append(workerTemplates, template)
+		}
+		raw, err := json.Marshal(workerTemplates)
+		if err != nil {
+			return nil, err
+		}
+		podAnnotations[leaderworkerset.WorkerTemplatesAnnotationKey] = string(raw)
+	}
 	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
 		podAnnotations[leaderworkerset.SubGroupSizeAnnotationKey] = strconv.Itoa(int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize))
 		if lws.Annotations[leaderworkerset.SubGroupExclusiveKeyAnnotationKey] != "" {
@@ -291,7 +1188,7 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 	// construct statefulset apply configuration
 	statefulSetConfig := appsapplyv1.StatefulSet(leaderPod.Name, leaderPod.Namespace).
 		WithSpec(appsapplyv1.StatefulSetSpec().
-			WithServiceName(lws.Name).
+			WithServiceName(serviceName).
 			WithReplicas(*lws.Spec.LeaderWorkerTemplate.Size - 1).
 			WithPodManagementPolicy(appsv1.ParallelPodManagement).
 			WithTemplate(&podTemplateApplyConfiguration).
@@ -299,21 +1196,67 @@ func constructWorkerStatefulSetApplyConfiguration(leaderPod corev1.Pod, lws lead
 			WithSelector(metaapplyv1.LabelSelector().
 				WithMatchLabels(selectorMap))).
 		WithLabels(labelMap)
+	// The availability of the workers is tracked by the statefulset controller.
+	if lws.Spec.MinReadySeconds > 0 {
+		statefulSetConfig.Spec.WithMinReadySeconds(lws.Spec.MinReadySeconds)
+	}
+	if err := setVolumeClaimTemplates(statefulSetConfig, &lws); err != nil {
+		return nil, err
+	}
 	return statefulSetConfig, nil
 }
 
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&corev1.Pod{}).
-		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
-			if pod, ok := object.(*corev1.Pod); ok {
-				_, exist := pod.Labels[leaderworkerset.SetNameLabelKey]
-				return exist
-			}
-			if statefulSet, ok := object.(*appsv1.StatefulSet); ok {
-				_, exist := statefulSet.Labels[leaderworkerset.SetNameLabelKey]
-				return exist
-			}
-			return false
-		})).Owns(&appsv1.StatefulSet{}).Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr)
+	// Cluster autoscaler is an optional dependency, only watch the provisioning requests if installed.
+	gvk := provisioningutils.ProvisioningRequestGVK
+	if _, err := mgr.GetRESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err == nil {
+		request := &unstructured.Unstructured{}
+		request.SetGroupVersionKind(gvk)
+		b = b.Owns(request)
+	}
+	// The predicates are set per watch, a global event filter would drop the events of the provisioning requests.
+	return b.
+		WithOptions(controller.Options{RateLimiter: r.RateLimiter}).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, exist := object.GetLabels()[leaderworkerset.SetNameLabelKey]
+			return exist && !podutils.SkipInjection(*object.(*corev1.Pod))
+		}))).
+		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, exist := object.GetLabels()[leaderworkerset.SetNameLabelKey]
+			return exist
+		}))).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.podsOnNode), builder.WithPredicates(nodeTaintsChanged)).
+		Complete(r)
+}
+
+// nodeTaintsChanged filters the node events, only the taint changes may signal the preemption of the node.
+var nodeTaintsChanged = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return len(e.Object.(*corev1.Node).Spec.Taints) > 0
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, newNode := e.ObjectOld.(*corev1.Node), e.ObjectNew.(*corev1.Node)
+		return len(newNode.Spec.Taints) > 0 && !equality.Semantic.DeepEqual(oldNode.Spec.Taints, newNode.Spec.Taints)
+	},
+	DeleteFunc:  func(event.DeleteEvent) bool { return false },
+	GenericFunc: func(event.GenericEvent) bool { return false },
+}
+
+// podsOnNode enqueues the lws pods on the node, so that the groups are recreated once the node is tainted
+// with the taints of the preemption policy.
+func (r *PodReconciler) podsOnNode(ctx context.Context, node client.Object) []reconcile.Request {
+	var podList corev1.PodList
+	if err := r.List(ctx, &podList, client.MatchingFields{podNodeNameKey: node.GetName()}, client.HasLabels{leaderworkerset.SetNameLabelKey}); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "Listing pods on the node", "node", node.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if podutils.SkipInjection(pod) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}})
+	}
+	return requests
 }
@@ -17,17 +17,30 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	appsapplyv1 "k8s.io/client-go/applyconfigurations/apps/v1"
 	coreapplyv1 "k8s.io/client-go/applyconfigurations/core/v1"
 	metaapplyv1 "k8s.io/client-go/applyconfigurations/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	revisionutils "sigs.k8s.io/lws/pkg/utils/revision"
 	testutils "sigs.k8s.io/lws/test/testutils"
 )
 
@@ -110,6 +123,80 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "1 replica, size 1, unique subdomain per replica",
+			pod: &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						"leaderworkerset.sigs.k8s.io/worker-index": "0",
+						"leaderworkerset.sigs.k8s.io/name":         "test-sample",
+						"leaderworkerset.sigs.k8s.io/group-index":  "1",
+						"leaderworkerset.sigs.k8s.io/group-key":    "test-key",
+					},
+				},
+			},
+			lws: testutils.BuildBasicLeaderWorkerSet("test-sample", "default").
+				Replica(1).
+				WorkerTemplateSpec(testutils.MakeWorkerPodSpec()).
+				SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica).
+				Size(1).Obj(),
+			wantStatefulSetConfig: &appsapplyv1.StatefulSetApplyConfiguration{
+				TypeMetaApplyConfiguration: metaapplyv1.TypeMetaApplyConfiguration{
+					Kind:       ptr.To[string]("StatefulSet"),
+					APIVersion: ptr.To[string]("apps/v1"),
+				},
+				ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+					Name:      ptr.To[string]("test-sample-1"),
+					Namespace: ptr.To[string]("default"),
+					Labels: map[string]string{
+						"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+						"leaderworkerset.sigs.k8s.io/group-index":            "1",
+						"leaderworkerset.sigs.k8s.io/group-key":              "test-key",
+						"leaderworkerset.sigs.k8s.io/template-revision-hash": "",
+					},
+				},
+				Spec: &appsapplyv1.StatefulSetSpecApplyConfiguration{
+					Replicas: ptr.To[int32](0),
+					Selector: &metaapplyv1.LabelSelectorApplyConfiguration{
+						MatchLabels: map[string]string{
+							"leaderworkerset.sigs.k8s.io/name":        "test-sample",
+							"leaderworkerset.sigs.k8s.io/group-index": "1",
+							"leaderworkerset.sigs.k8s.io/group-key":   "test-key",
+						},
+					},
+					Template: &coreapplyv1.PodTemplateSpecApplyConfiguration{
+						ObjectMetaApplyConfiguration: &metaapplyv1.ObjectMetaApplyConfiguration{
+							Labels: map[string]string{
+								"leaderworkerset.sigs.k8s.io/name":                   "test-sample",
+								"leaderworkerset.sigs.k8s.io/group-index":            "1",
+								"leaderworkerset.sigs.k8s.io/group-key":              "test-key",
+								"leaderworkerset.sigs.k8s.io/template-revision-hash": "",
+							},
+							Annotations: map[string]string{
+								"leaderworkerset.sigs.k8s.io/size":             "1",
+								"leaderworkerset.sigs.k8s.io/leader-name":      "test-sample-1",
+								"leaderworkerset.sigs.k8s.io/subdomain-policy": "UniquePerReplica",
+							},
+						},
+						Spec: &coreapplyv1.PodSpecApplyConfiguration{
+							Containers: []coreapplyv1.ContainerApplyConfiguration{
+								{
+									Name:      ptr.To[string]("leader"),
+									Image:     ptr.To[string]("nginx:1.14.2"),
+									Ports:     []coreapplyv1.ContainerPortApplyConfiguration{{ContainerPort: ptr.To[int32](8080), Protocol: ptr.To[corev1.Protocol](corev1.ProtocolTCP)}},
+									Resources: &coreapplyv1.ResourceRequirementsApplyConfiguration{},
+								},
+							},
+						},
+					},
+					Ordinals:            &appsapplyv1.StatefulSetOrdinalsApplyConfiguration{Start: ptr.To[int32](1)},
+					ServiceName:         ptr.To[string]("test-sample-1"),
+					PodManagementPolicy: ptr.To[appsv1.PodManagementPolicyType](appsv1.ParallelPodManagement),
+				},
+			},
+		},
 		{
 			name: "1 replica, size 2, exclusive placement enabled",
 			pod: &corev1.Pod{
@@ -277,3 +364,928 @@ func TestConstructWorkerStatefulSetApplyConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestLeaderUnhealthy(t *testing.T) {
+	now := time.Now()
+	leaderPod := func(restarts int32, started bool, notReadySince time.Time) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{
+				Type:               corev1.PodReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: v1.NewTime(notReadySince),
+			}},
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:         "leader",
+				RestartCount: restarts,
+				Started:      ptr.To(started),
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{StartedAt: v1.NewTime(notReadySince.Add(-time.Minute))},
+				},
+			}},
+		}}
+	}
+	policy := &leaderworkerset.LeaderHealthPolicy{
+		RestartThreshold: ptr.To[int32](3),
+		NotReadySeconds:  ptr.To[int32](60),
+	}
+	tests := []struct {
+		name             string
+		pod              corev1.Pod
+		wantUnhealthy    bool
+		wantRequeueAfter time.Duration
+	}{
+		{
+			name:          "restarts exceed the threshold",
+			pod:           leaderPod(3, true, now),
+			wantUnhealthy: true,
+		},
+		{
+			name:             "not ready within the threshold",
+			pod:              leaderPod(1, true, now.Add(-20*time.Second)),
+			wantRequeueAfter: 40 * time.Second,
+		},
+		{
+			name:          "not ready longer than the threshold",
+			pod:           leaderPod(1, true, now.Add(-2*time.Minute)),
+			wantUnhealthy: true,
+		},
+		{
+			name: "containers not started yet",
+			pod:  leaderPod(0, false, now.Add(-2*time.Minute)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			unhealthy, requeueAfter := leaderUnhealthy(tc.pod, policy, now)
+			if unhealthy != tc.wantUnhealthy {
+				t.Errorf("Expected unhealthy to be %t, got %t", tc.wantUnhealthy, unhealthy)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestGroupRestartAllowed(t *testing.T) {
+	terminatingLeader := func(name, lwsName string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			Labels:            map[string]string{leaderworkerset.SetNameLabelKey: lwsName, leaderworkerset.WorkerIndexLabelKey: "0"},
+			DeletionTimestamp: ptr.To(v1.Now()),
+			Finalizers:        []string{"test"},
+		}}
+	}
+	tests := []struct {
+		name                       string
+		maxConcurrentGroupRestarts int32
+		maxConcurrentRestarts      *int32
+		deletionPending            bool
+		wantAllowed                bool
+	}{
+		{
+			name:        "unlimited",
+			wantAllowed: true,
+		},
+		{
+			name:                       "within the controller budget",
+			maxConcurrentGroupRestarts: 3,
+			wantAllowed:                true,
+		},
+		{
+			name:                       "controller budget exhausted",
+			maxConcurrentGroupRestarts: 2,
+		},
+		{
+			name:                  "lws budget exhausted",
+			maxConcurrentRestarts: ptr.To[int32](1),
+		},
+		{
+			name:                       "within both budgets",
+			maxConcurrentGroupRestarts: 3,
+			maxConcurrentRestarts:      ptr.To[int32](2),
+			wantAllowed:                true,
+		},
+		{
+			name:                       "lws budget exhausted by a deletion not observed yet",
+			maxConcurrentGroupRestarts: 4,
+			maxConcurrentRestarts:      ptr.To[int32](2),
+			deletionPending:            true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.FailurePolicy = &leaderworkerset.FailurePolicy{MaxConcurrentRestarts: tc.maxConcurrentRestarts}
+			leader := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-1",
+				Namespace: "default",
+				UID:       "uid-1",
+				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lws.Name, leaderworkerset.WorkerIndexLabelKey: "0"},
+			}}
+			r := &PodReconciler{
+				Client: newFakeClientBuilder().WithObjects(terminatingLeader("test-sample-0", lws.Name), terminatingLeader("other-0", "other"), leader).Build(),
+			}
+			if tc.deletionPending {
+				r.leaderDeletions.ExpectDeletion(client.ObjectKeyFromObject(leader), leader.UID)
+			}
+			r.MaxConcurrentGroupRestarts.Store(tc.maxConcurrentGroupRestarts)
+			allowed, requeueAfter, err := r.groupRestartAllowed(context.Background(), lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if allowed != tc.wantAllowed {
+				t.Errorf("Expected allowed to be %t, got %t", tc.wantAllowed, allowed)
+			}
+			if !allowed && requeueAfter != groupRestartRetryInterval {
+				t.Errorf("Expected requeue after %v, got %v", groupRestartRetryInterval, requeueAfter)
+			}
+		})
+	}
+}
+
+func TestHandleRestartRequest(t *testing.T) {
+	tests := []struct {
+		name                       string
+		requested                  bool
+		maxConcurrentGroupRestarts int32
+		wantDeleted                bool
+		wantRequeueAfter           time.Duration
+	}{
+		{
+			name: "restart not requested",
+		},
+		{
+			name:        "restart requested",
+			requested:   true,
+			wantDeleted: true,
+		},
+		{
+			name:                       "restart budget exhausted",
+			requested:                  true,
+			maxConcurrentGroupRestarts: 1,
+			wantRequeueAfter:           groupRestartRetryInterval,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			leader := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-1",
+				Namespace: "default",
+				UID:       "uid-1",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     lws.Name,
+					leaderworkerset.WorkerIndexLabelKey: "0",
+					leaderworkerset.GroupIndexLabelKey:  "1",
+				},
+			}}
+			if tc.requested {
+				leader.Annotations = map[string]string{leaderworkerset.RestartGroupAnnotationKey: "1"}
+			}
+			terminating := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+				Name:              "test-sample-0",
+				Namespace:         "default",
+				Labels:            map[string]string{leaderworkerset.SetNameLabelKey: lws.Name, leaderworkerset.WorkerIndexLabelKey: "0"},
+				DeletionTimestamp: ptr.To(v1.Now()),
+				Finalizers:        []string{"test"},
+			}}
+			recorder := record.NewFakeRecorder(1)
+			r := &PodReconciler{
+				Client: newFakeClientBuilder().WithObjects(leader, terminating).Build(),
+				Record: recorder,
+			}
+			r.MaxConcurrentGroupRestarts.Store(tc.maxConcurrentGroupRestarts)
+			deleted, requeueAfter, err := r.handleRestartRequest(context.Background(), *leader, *lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if deleted != tc.wantDeleted {
+				t.Errorf("Expected deleted %t, got %t", tc.wantDeleted, deleted)
+			}
+			if requeueAfter != tc.wantRequeueAfter {
+				t.Errorf("Expected requeue after %v, got %v", tc.wantRequeueAfter, requeueAfter)
+			}
+			err = r.Get(context.Background(), client.ObjectKeyFromObject(leader), &corev1.Pod{})
+			if gone := apierrors.IsNotFound(err); gone != tc.wantDeleted {
+				t.Errorf("Expected the leader pod deleted %t, got %t", tc.wantDeleted, gone)
+			}
+			if pending := r.leaderDeletions.DeletionPending(client.ObjectKeyFromObject(leader), leader.UID); pending != tc.wantDeleted {
+				t.Errorf("Expected the leader deletion expected %t, got %t", tc.wantDeleted, pending)
+			}
+			if tc.wantDeleted {
+				if event := <-recorder.Events; !strings.Contains(event, GroupRecreated) {
+					t.Errorf("Expected a %s event, got %q", GroupRecreated, event)
+				}
+			}
+		})
+	}
+}
+
+func TestPodPreempted(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: "node-1"},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{{Key: "cloud.google.com/impending-node-termination", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	tests := []struct {
+		name          string
+		nodeName      string
+		conditions    []corev1.PodCondition
+		nodeTaintKeys []string
+		wantPreempted bool
+	}{
+		{
+			name:     "pod on a healthy node",
+			nodeName: "node-1",
+		},
+		{
+			name:     "pod terminated by graceful node shutdown",
+			nodeName: "node-1",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: corev1.PodReasonTerminationByKubelet},
+			},
+			wantPreempted: true,
+		},
+		{
+			name:          "pod on a node tainted for preemption",
+			nodeName:      "node-1",
+			nodeTaintKeys: []string{"cloud.google.com/impending-node-termination"},
+			wantPreempted: true,
+		},
+		{
+			name:          "pod on a missing node",
+			nodeName:      "node-2",
+			nodeTaintKeys: []string{"cloud.google.com/impending-node-termination"},
+		},
+		{
+			name:          "pod not scheduled yet",
+			nodeTaintKeys: []string{"cloud.google.com/impending-node-termination"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &PodReconciler{Client: newFakeClientBuilder().WithObjects(node).Build()}
+			pod := corev1.Pod{
+				Spec:   corev1.PodSpec{NodeName: tc.nodeName},
+				Status: corev1.PodStatus{Conditions: tc.conditions},
+			}
+			preempted, err := r.podPreempted(context.Background(), pod, &leaderworkerset.PreemptionPolicy{NodeTaintKeys: tc.nodeTaintKeys})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if preempted != tc.wantPreempted {
+				t.Errorf("Expected preempted %t, got %t", tc.wantPreempted, preempted)
+			}
+		})
+	}
+}
+
+func TestGroupDisrupted(t *testing.T) {
+	disruptedPod := func(reason string) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: reason},
+		}}}
+	}
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		pod           corev1.Pod
+		wantDisrupted bool
+	}{
+		{
+			name:        "evicted pod without opting in",
+			pod:         disruptedPod("EvictionByEvictionAPI"),
+			annotations: map[string]string{leaderworkerset.GroupPreemptionAnnotationKey: "true"},
+		},
+		{
+			name:          "evicted pod",
+			pod:           disruptedPod("EvictionByEvictionAPI"),
+			annotations:   map[string]string{leaderworkerset.GroupEvictionAnnotationKey: "true"},
+			wantDisrupted: true,
+		},
+		{
+			name:        "preempted pod without opting in",
+			pod:         disruptedPod(corev1.PodReasonPreemptionByScheduler),
+			annotations: map[string]string{leaderworkerset.GroupEvictionAnnotationKey: "true"},
+		},
+		{
+			name:          "preempted pod",
+			pod:           disruptedPod(corev1.PodReasonPreemptionByScheduler),
+			annotations:   map[string]string{leaderworkerset.GroupPreemptionAnnotationKey: "true"},
+			wantDisrupted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Annotation(tc.annotations).Obj()
+			if disrupted := groupDisrupted(lws, tc.pod); disrupted != tc.wantDisrupted {
+				t.Errorf("Expected disrupted %t, got %t", tc.wantDisrupted, disrupted)
+			}
+		})
+	}
+}
+
+func TestGroupTearingDown(t *testing.T) {
+	tests := []struct {
+		name            string
+		lwsDeleting     bool
+		annotations     map[string]string
+		wantTearingDown bool
+	}{
+		{
+			name: "running group",
+		},
+		{
+			name:            "leaderworkerset being deleted",
+			lwsDeleting:     true,
+			wantTearingDown: true,
+		},
+		{
+			name:            "leader pod annotated",
+			annotations:     map[string]string{leaderworkerset.TeardownAnnotationKey: "true"},
+			wantTearingDown: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			if tc.lwsDeleting {
+				lws.DeletionTimestamp = ptr.To(v1.Now())
+			}
+			leader := corev1.Pod{ObjectMeta: v1.ObjectMeta{Annotations: tc.annotations}}
+			if tearingDown := groupTearingDown(lws, leader); tearingDown != tc.wantTearingDown {
+				t.Errorf("Expected tearing down %t, got %t", tc.wantTearingDown, tearingDown)
+			}
+		})
+	}
+}
+
+func TestRestartCause(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		pod         corev1.Pod
+		wantReason  string
+		wantCause   string
+	}{
+		{
+			name:        "evicted pod",
+			annotations: map[string]string{leaderworkerset.GroupEvictionAnnotationKey: "true"},
+			pod: corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Conditions: []corev1.PodCondition{
+				{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: "EvictionByEvictionAPI"},
+			}}},
+			wantReason: "Evicted",
+			wantCause:  "pod test-sample-0-1 is evicted",
+		},
+		{
+			name:       "failed pod",
+			pod:        corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+			wantReason: "PodFailed",
+			wantCause:  "pod test-sample-0-1 is failed",
+		},
+		{
+			name:       "restarted container",
+			pod:        corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			wantReason: "ContainerRestarted",
+			wantCause:  "a container of pod test-sample-0-1 restarted",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Annotation(tc.annotations).Obj()
+			tc.pod.Name = "test-sample-0-1"
+			reason, cause := restartCause(lws, tc.pod)
+			if reason != tc.wantReason || cause != tc.wantCause {
+				t.Errorf("Expected reason %s and cause %q, got %s and %q", tc.wantReason, tc.wantCause, reason, cause)
+			}
+		})
+	}
+}
+
+func TestLeaderWorkerSetAtLeaderRevision(t *testing.T) {
+	former := testutils.BuildLeaderWorkerSet("default").Obj()
+	former.Spec.RolloutStrategy = leaderworkerset.RolloutStrategy{Type: leaderworkerset.OnDeleteStrategyType}
+	formerHash := utils.LeaderWorkerTemplateHash(former)
+	revision, err := revisionutils.NewRevision(former, 1)
+	if err != nil {
+		t.Fatalf("Failed to create revision: %v", err)
+	}
+	current := former.DeepCopy()
+	current.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginx:1.16.1"
+
+	tests := []struct {
+		name         string
+		strategyType leaderworkerset.RolloutStrategyType
+		revisions    []client.Object
+		wantHash     string
+	}{
+		{
+			name:         "OnDelete builds the workers from the leader revision",
+			strategyType: leaderworkerset.OnDeleteStrategyType,
+			revisions:    []client.Object{revision},
+			wantHash:     formerHash,
+		},
+		{
+			name:         "OnDelete falls back to the current templates once the revision is truncated",
+			strategyType: leaderworkerset.OnDeleteStrategyType,
+			wantHash:     utils.LeaderWorkerTemplateHash(current),
+		},
+		{
+			name:         "RollingUpdate always builds the workers from the current templates",
+			strategyType: leaderworkerset.RollingUpdateStrategyType,
+			revisions:    []client.Object{revision},
+			wantHash:     utils.LeaderWorkerTemplateHash(current),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := current.DeepCopy()
+			lws.Spec.RolloutStrategy.Type = tc.strategyType
+			leaderPod := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-0",
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.TemplateRevisionHashKey: formerHash},
+			}}
+			r := &PodReconciler{Client: newFakeClientBuilder().WithObjects(tc.revisions...).Build()}
+			got, err := r.leaderWorkerSetAtLeaderRevision(context.Background(), *lws, leaderPod)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantHash, utils.LeaderWorkerTemplateHash(&got)); diff != "" {
+				t.Errorf("Unexpected template hash (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeaderDeletionPending(t *testing.T) {
+	leaderKey := types.NamespacedName{Namespace: "default", Name: "test-sample-0"}
+	leader := func(uid types.UID, terminating bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+			Name:      leaderKey.Name,
+			Namespace: leaderKey.Namespace,
+			UID:       uid,
+			Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "0"},
+		}}
+		if terminating {
+			pod.DeletionTimestamp = ptr.To(v1.Now())
+			pod.Finalizers = []string{"test"}
+		}
+		return pod
+	}
+	worker := corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:      "test-sample-0-1",
+		Namespace: "default",
+		Labels:    map[string]string{leaderworkerset.SetNameLabelKey: "test-sample", leaderworkerset.WorkerIndexLabelKey: "1"},
+	}}
+
+	tests := []struct {
+		name        string
+		expect      bool
+		cached      *corev1.Pod
+		wantPending bool
+	}{
+		{
+			name:   "leader not deleted",
+			cached: leader("uid-1", false),
+		},
+		{
+			name:        "cache lagging behind the deletion",
+			expect:      true,
+			cached:      leader("uid-1", false),
+			wantPending: true,
+		},
+		{
+			name:   "leader being deleted",
+			expect: true,
+			cached: leader("uid-1", true),
+		},
+		{
+			name:   "leader recreated",
+			expect: true,
+			cached: leader("uid-2", false),
+		},
+		{
+			name:   "leader gone",
+			expect: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := newFakeClientBuilder()
+			if tc.cached != nil {
+				builder = builder.WithObjects(tc.cached)
+			}
+			r := &PodReconciler{Client: builder.Build()}
+			if tc.expect {
+				r.leaderDeletions.ExpectDeletion(leaderKey, "uid-1")
+			}
+			pending, err := r.leaderDeletionPending(context.Background(), worker)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pending != tc.wantPending {
+				t.Errorf("Expected pending %t, got %t", tc.wantPending, pending)
+			}
+			if got := r.leaderDeletions.DeletionPending(leaderKey, "uid-1"); got != tc.wantPending {
+				t.Errorf("Expected the expectation kept %t, got %t", tc.wantPending, got)
+			}
+		})
+	}
+}
+
+func TestHandleGroupCompletion(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	leaderPod := func(exitCode *int32) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-0",
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  "0",
+					leaderworkerset.WorkerIndexLabelKey: "0",
+				},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "leader"}, {Name: "sidecar"}}},
+		}
+		if exitCode != nil {
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+				{Name: "leader", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: *exitCode}}},
+			}
+		}
+		return pod
+	}
+	tests := []struct {
+		name              string
+		terminationPolicy leaderworkerset.TerminationPolicyType
+		pod               corev1.Pod
+		replicaStatuses   []leaderworkerset.ReplicaStatus
+		wantCompleted     bool
+		wantPhase         leaderworkerset.ReplicaPhase
+		wantStsDeleted    bool
+	}{
+		{
+			name:      "long-running groups",
+			pod:       leaderPod(ptr.To[int32](0)),
+			wantPhase: leaderworkerset.ReplicaReady,
+		},
+		{
+			name:              "leader container running",
+			terminationPolicy: leaderworkerset.LeaderSucceededTerminationPolicy,
+			pod:               leaderPod(nil),
+			wantPhase:         leaderworkerset.ReplicaReady,
+		},
+		{
+			name:              "leader container failed",
+			terminationPolicy: leaderworkerset.LeaderSucceededTerminationPolicy,
+			pod:               leaderPod(ptr.To[int32](1)),
+			wantPhase:         leaderworkerset.ReplicaReady,
+		},
+		{
+			name:              "leader container succeeded",
+			terminationPolicy: leaderworkerset.LeaderSucceededTerminationPolicy,
+			pod:               leaderPod(ptr.To[int32](0)),
+			wantCompleted:     true,
+			wantPhase:         leaderworkerset.ReplicaSucceeded,
+			wantStsDeleted:    true,
+		},
+		{
+			name:              "succeeded group is not restarted on worker failures",
+			terminationPolicy: leaderworkerset.LeaderSucceededTerminationPolicy,
+			pod: corev1.Pod{ObjectMeta: v1.ObjectMeta{
+				Name:      "test-sample-0-1",
+				Namespace: "default",
+				Labels: map[string]string{
+					leaderworkerset.SetNameLabelKey:     "test-sample",
+					leaderworkerset.GroupIndexLabelKey:  "0",
+					leaderworkerset.WorkerIndexLabelKey: "1",
+				},
+			}},
+			replicaStatuses: []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaSucceeded}},
+			wantCompleted:   true,
+			wantPhase:       leaderworkerset.ReplicaSucceeded,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.TerminationPolicy = tc.terminationPolicy
+			lws.Status.ReplicaStatuses = tc.replicaStatuses
+			if lws.Status.ReplicaStatuses == nil {
+				lws.Status.ReplicaStatuses = []leaderworkerset.ReplicaStatus{{Index: 0, Phase: leaderworkerset.ReplicaReady}}
+			}
+			sts := &appsv1.StatefulSet{ObjectMeta: v1.ObjectMeta{Name: "test-sample-0", Namespace: "default"}}
+			c := newFakeClientBuilder().WithScheme(scheme).WithObjects(lws, sts).WithStatusSubresource(lws).Build()
+			r := &PodReconciler{Client: c, Scheme: scheme}
+
+			completed, err := r.handleGroupCompletion(context.Background(), tc.pod, *lws)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if completed != tc.wantCompleted {
+				t.Errorf("Expected completed %t, got %t", tc.wantCompleted, completed)
+			}
+			var got leaderworkerset.LeaderWorkerSet
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(lws), &got); err != nil {
+				t.Fatal(err)
+			}
+			if phase := got.Status.ReplicaStatuses[0].Phase; phase != tc.wantPhase {
+				t.Errorf("Expected phase %s, got %s", tc.wantPhase, phase)
+			}
+			err = c.Get(context.Background(), client.ObjectKeyFromObject(sts), &appsv1.StatefulSet{})
+			if deleted := apierrors.IsNotFound(err); deleted != tc.wantStsDeleted {
+				t.Errorf("Expected worker statefulset deleted %t, got %t", tc.wantStsDeleted, deleted)
+			}
+		})
+	}
+}
+
+func TestClaimExclusiveDomain(t *testing.T) {
+	const topologyKey = "cloud.google.com/gke-nodepool"
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	domain := utils.Sha1Hash(topologyKey + "=pool-a")
+	leaderPod := func(name, groupKey, domain string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{leaderworkerset.GroupUniqueHashLabelKey: groupKey},
+			},
+			Spec: corev1.PodSpec{NodeName: "node-a"},
+		}
+		if domain != "" {
+			pod.Labels[leaderworkerset.ExclusiveDomainLabelKey] = domain
+		}
+		return pod
+	}
+	tests := []struct {
+		name          string
+		existingPod   *corev1.Pod
+		wantClaimed   bool
+		wantDomain    string
+		wantPodExists bool
+	}{
+		{
+			name:          "domain not claimed",
+			wantClaimed:   true,
+			wantDomain:    domain,
+			wantPodExists: true,
+		},
+		{
+			name:          "domain claimed by another group",
+			existingPod:   leaderPod("test-sample-1", "group-1", domain),
+			wantPodExists: false,
+		},
+		{
+			name:          "domain claimed by another topology value",
+			existingPod:   leaderPod("test-sample-1", "group-1", utils.Sha1Hash(topologyKey+"=pool-b")),
+			wantClaimed:   true,
+			wantDomain:    domain,
+			wantPodExists: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-a", Labels: map[string]string{topologyKey: "pool-a"}}}
+			pod := leaderPod("test-sample-0", "group-0", "")
+			builder := newFakeClientBuilder().WithScheme(scheme).WithObjects(node, pod)
+			if tc.existingPod != nil {
+				builder.WithObjects(tc.existingPod)
+			}
+			c := builder.Build()
+			r := &PodReconciler{Client: c, apiReader: c, Scheme: scheme}
+
+			claimed, err := r.claimExclusiveDomain(context.Background(), pod, topologyKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if claimed != tc.wantClaimed {
+				t.Errorf("Expected claimed %t, got %t", tc.wantClaimed, claimed)
+			}
+			var got corev1.Pod
+			err = c.Get(context.Background(), client.ObjectKeyFromObject(pod), &got)
+			if exists := err == nil; exists != tc.wantPodExists {
+				t.Fatalf("Expected pod exists %t, got %t", tc.wantPodExists, exists)
+			}
+			if tc.wantPodExists && got.Labels[leaderworkerset.ExclusiveDomainLabelKey] != tc.wantDomain {
+				t.Errorf("Expected domain %q, got %q", tc.wantDomain, got.Labels[leaderworkerset.ExclusiveDomainLabelKey])
+			}
+		})
+	}
+}
+
+func TestLabelPod(t *testing.T) {
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		wantLabeled bool
+		wantLabels  map[string]string
+	}{
+		{
+			name: "leader pod",
+			pod: &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "test-sample-2", Namespace: "default", Labels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			}}},
+			wantLabeled: true,
+			wantLabels: map[string]string{
+				leaderworkerset.WorkerIndexLabelKey:     "0",
+				leaderworkerset.GroupIndexLabelKey:      "2",
+				leaderworkerset.GroupUniqueHashLabelKey: utils.Sha1Hash("default/test-sample-2"),
+			},
+		},
+		{
+			name: "worker pod",
+			pod: &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "test-sample-2-3", Namespace: "default", Labels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey: "2",
+			}}},
+			wantLabeled: true,
+			wantLabels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:  "2",
+				leaderworkerset.WorkerIndexLabelKey: "3",
+			},
+		},
+		{
+			name: "worker pod labeled",
+			pod: &corev1.Pod{ObjectMeta: v1.ObjectMeta{Name: "test-sample-2-3", Namespace: "default", Labels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:  "2",
+				leaderworkerset.WorkerIndexLabelKey: "3",
+			}}},
+			wantLabels: map[string]string{
+				leaderworkerset.GroupIndexLabelKey:  "2",
+				leaderworkerset.WorkerIndexLabelKey: "3",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newFakeClientBuilder().WithObjects(tc.pod).Build()
+			r := &PodReconciler{Client: c}
+
+			labeled, err := r.labelPod(context.Background(), tc.pod)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if labeled != tc.wantLabeled {
+				t.Errorf("Expected labeled %t, got %t", tc.wantLabeled, labeled)
+			}
+			var got corev1.Pod
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(tc.pod), &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantLabels, got.Labels); diff != "" {
+				t.Errorf("unexpected labels: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestReconcileSkipInjection(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:        "test-sample-2-debug",
+		Namespace:   "default",
+		Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		Annotations: map[string]string{leaderworkerset.SkipInjectionAnnotationKey: "true"},
+	}}
+	c := newFakeClientBuilder().WithObjects(pod).Build()
+	r := &PodReconciler{Client: c, PodWebhookDisabled: true}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got corev1.Pod
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), &got); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pod.Labels, got.Labels); diff != "" {
+		t.Errorf("unexpected labels: (-want, +got) %s", diff)
+	}
+}
+
+func TestReconcileLabelsPodMissedByWebhook(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: v1.ObjectMeta{
+		Name:      "test-sample-2-3",
+		Namespace: "default",
+		Labels: map[string]string{
+			leaderworkerset.SetNameLabelKey:    "test-sample",
+			leaderworkerset.GroupIndexLabelKey: "2",
+		},
+	}}
+	c := newFakeClientBuilder().WithObjects(pod).Build()
+	r := &PodReconciler{Client: c}
+
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var got corev1.Pod
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Labels[leaderworkerset.WorkerIndexLabelKey] != "3" {
+		t.Errorf("Expected worker index 3, got %q", got.Labels[leaderworkerset.WorkerIndexLabelKey])
+	}
+}
+
+func TestApplyStatefulSet(t *testing.T) {
+	desired := func(replicas int32) *appsapplyv1.StatefulSetApplyConfiguration {
+		return appsapplyv1.StatefulSet("test-sample-0", "default").
+			WithSpec(appsapplyv1.StatefulSetSpec().WithReplicas(replicas))
+	}
+	hash, err := renderedStatefulSetHash(desired(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing := func(hash string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: v1.ObjectMeta{
+				Name:        "test-sample-0",
+				Namespace:   "default",
+				Annotations: map[string]string{leaderworkerset.RenderedHashAnnotationKey: hash},
+			},
+			// The defaulted fields of the live object don't matter.
+			Spec: appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3), RevisionHistoryLimit: ptr.To[int32](10)},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		existing  *appsv1.StatefulSet
+		desired   *appsapplyv1.StatefulSetApplyConfiguration
+		wantPatch bool
+	}{
+		{
+			name:      "statefulset not found",
+			desired:   desired(3),
+			wantPatch: true,
+		},
+		{
+			name:     "same rendered configuration",
+			existing: existing(hash),
+			desired:  desired(3),
+		},
+		{
+			name:      "rendered configuration changed",
+			existing:  existing(hash),
+			desired:   desired(4),
+			wantPatch: true,
+		},
+		{
+			name:      "hash annotation missing",
+			existing:  existing(""),
+			desired:   desired(3),
+			wantPatch: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := newFakeClientBuilder()
+			if tc.existing != nil {
+				builder = builder.WithObjects(tc.existing)
+			}
+			var patched map[string]string
+			c := builder.WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					patched = obj.GetAnnotations()
+					return nil
+				},
+			}).Build()
+
+			wantHash, err := renderedStatefulSetHash(tc.desired)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := applyStatefulSet(context.Background(), c, tc.desired); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotPatch := patched != nil; gotPatch != tc.wantPatch {
+				t.Fatalf("want patch %v, got %v", tc.wantPatch, gotPatch)
+			}
+			if got := patched[leaderworkerset.RenderedHashAnnotationKey]; tc.wantPatch && got != wantHash {
+				t.Errorf("unexpected hash annotation %q, want %q", got, wantHash)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package debug serves the pprof profiles and the expvar variables of the manager, to profile
+// the hot spots of the reconciles in production. It is off by default.
+package debug
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Server serves /debug/pprof/ and /debug/vars on a localhost-bound address, it runs on all the
+// replicas of the manager, not only on the leader.
+type Server struct {
+	bindAddress string
+}
+
+// NewServer returns the debug server, the address must be bound to a loopback interface so that
+// the profiles are only reachable through a port-forward.
+func NewServer(bindAddress string) (*Server, error) {
+	host, _, err := net.SplitHostPort(bindAddress)
+	if err != nil {
+		return nil, err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("debug bind address %q is not bound to localhost", bindAddress)
+	}
+	return &Server{bindAddress: bindAddress}, nil
+}
+
+func handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// Start serves the debug endpoints until the context is done.
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.bindAddress)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			ctrl.Log.WithName("debug").Error(err, "Shutting down the debug server")
+		}
+	}()
+	ctrl.Log.WithName("debug").Info("Serving the debug endpoints", "address", listener.Addr().String())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface, the replicas waiting for
+// the leadership are profiled as well.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewServer(t *testing.T) {
+	tests := []struct {
+		bindAddress string
+		wantErr     bool
+	}{
+		{bindAddress: "127.0.0.1:6060"},
+		{bindAddress: "localhost:6060"},
+		{bindAddress: "[::1]:6060"},
+		{bindAddress: ":6060", wantErr: true},
+		{bindAddress: "0.0.0.0:6060", wantErr: true},
+		{bindAddress: "10.0.0.1:6060", wantErr: true},
+		{bindAddress: "6060", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.bindAddress, func(t *testing.T) {
+			if _, err := NewServer(tc.bindAddress); (err != nil) != tc.wantErr {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	for _, path := range []string{"/debug/pprof/", "/debug/vars"} {
+		t.Run(path, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+			if recorder.Code != http.StatusOK {
+				t.Errorf("Unexpected status code %d", recorder.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package features holds the feature gates of the alpha and beta features of lws, they are
+// set from the featureGates of the manager configuration and the --feature-gates flag.
+package features
+
+import (
+	"maps"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/component-base/featuregate"
+)
+
+const (
+	// Enables spec.leaderWorkerTemplate.subGroupPolicy to split the groups into subgroups.
+	SubGroups featuregate.Feature = "SubGroups"
+
+	// Enables spec.failurePolicy to bound and tune the restarts of the groups.
+	FailurePolicy featuregate.Feature = "FailurePolicy"
+
+	// Enables webhook.pod.disable of the manager configuration, to label the pods and stamp the
+	// StatefulSet pod templates in the controllers instead of the pod webhook.
+	ControllerSideInjection featuregate.Feature = "ControllerSideInjection"
+)
+
+// defaultFeatureGates consists of all known lws feature keys. To add a new feature,
+// define a key for it above and add it here.
+var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
+	SubGroups:               {Default: true, PreRelease: featuregate.Beta},
+	FailurePolicy:           {Default: false, PreRelease: featuregate.Alpha},
+	ControllerSideInjection: {Default: false, PreRelease: featuregate.Alpha},
+}
+
+var gate = featuregate.NewFeatureGate()
+
+func init() {
+	utilruntime.Must(gate.Add(defaultFeatureGates))
+}
+
+// Set sets the feature gates from a comma separated list of feature=bool pairs,
+// e.g. SubGroups=true,FailurePolicy=false, it fails on the unknown features.
+func Set(value string) error {
+	return gate.Set(value)
+}
+
+// SetFromMap sets the feature gates, it fails on the unknown features.
+func SetFromMap(m map[string]bool) error {
+	return gate.SetFromMap(m)
+}
+
+// Enabled returns whether the feature is enabled.
+func Enabled(f featuregate.Feature) bool {
+	return gate.Enabled(f)
+}
+
+// Specs returns the specs of all the known features.
+func Specs() map[featuregate.Feature]featuregate.FeatureSpec {
+	return maps.Clone(defaultFeatureGates)
+}
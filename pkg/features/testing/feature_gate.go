@@ -0,0 +1,41 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing holds the helpers to set the lws feature gates in the tests, it is kept
+// apart from the features package so the binaries don't link the testing package.
+package testing
+
+import (
+	"testing"
+
+	"k8s.io/component-base/featuregate"
+
+	"sigs.k8s.io/lws/pkg/features"
+)
+
+// SetFeatureGateDuringTest sets the feature gate for the duration of the test.
+func SetFeatureGateDuringTest(tb testing.TB, f featuregate.Feature, value bool) {
+	tb.Helper()
+	original := features.Enabled(f)
+	if err := features.SetFromMap(map[string]bool{string(f): value}); err != nil {
+		tb.Fatalf("Unable to set the feature gate %s: %v", f, err)
+	}
+	tb.Cleanup(func() {
+		if err := features.SetFromMap(map[string]bool{string(f): original}); err != nil {
+			tb.Errorf("Unable to restore the feature gate %s: %v", f, err)
+		}
+	})
+}
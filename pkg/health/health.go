@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health provides the checks of the dependencies of the controller manager served on
+// /healthz and /readyz, so that a rollout of the manager stops at a replica which can't serve.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// checkTimeout bounds the checks calling the api server or waiting for the cache, within the
+// default timeout of the probes.
+const checkTimeout = time.Second
+
+// WebhookServerStarted fails until the certs of the webhooks are ready and the webhook server
+// serves them, so that the replica doesn't receive admission requests it can't answer.
+func WebhookServerStarted(certsReady <-chan struct{}, started healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-certsReady:
+			return started(req)
+		default:
+			return errors.New("the certs of the webhooks are not ready")
+		}
+	}
+}
+
+// WebhookServerAlive only checks the webhook server once the certs are ready, the webhook
+// server doesn't start before then, so that the replica isn't restarted while waiting for them.
+func WebhookServerAlive(certsReady <-chan struct{}, started healthz.Checker) healthz.Checker {
+	return func(req *http.Request) error {
+		select {
+		case <-certsReady:
+			return started(req)
+		default:
+			return nil
+		}
+	}
+}
+
+// cacheSyncer is implemented by the cache of the manager.
+type cacheSyncer interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// CacheSynced fails until the informers of the cache are synced.
+func CacheSynced(c cacheSyncer) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		if !c.WaitForCacheSync(ctx) {
+			return errors.New("the informers are not synced")
+		}
+		return nil
+	}
+}
+
+// CRDsInstalled fails if the LeaderWorkerSet CRD isn't served by the api server.
+func CRDsInstalled(reader client.Reader) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), checkTimeout)
+		defer cancel()
+		if err := reader.List(ctx, &leaderworkerset.LeaderWorkerSetList{}, client.Limit(1)); err != nil {
+			return fmt.Errorf("listing the LeaderWorkerSets: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestWebhookServerChecks(t *testing.T) {
+	notStarted := func(*http.Request) error { return errors.New("not started") }
+	tests := []struct {
+		name       string
+		certsReady bool
+		started    healthz.Checker
+		wantReady  bool
+		wantAlive  bool
+	}{
+		{
+			name:      "certs not ready",
+			started:   notStarted,
+			wantAlive: true,
+		},
+		{
+			name:       "certs ready, webhook server not started",
+			certsReady: true,
+			started:    notStarted,
+		},
+		{
+			name:       "webhook server started",
+			certsReady: true,
+			started:    healthz.Ping,
+			wantReady:  true,
+			wantAlive:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			certsReady := make(chan struct{})
+			if tc.certsReady {
+				close(certsReady)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			if err := WebhookServerStarted(certsReady, tc.started)(req); (err == nil) != tc.wantReady {
+				t.Errorf("Unexpected readiness error: %v", err)
+			}
+			if err := WebhookServerAlive(certsReady, tc.started)(req); (err == nil) != tc.wantAlive {
+				t.Errorf("Unexpected liveness error: %v", err)
+			}
+		})
+	}
+}
+
+type fakeCache bool
+
+func (f fakeCache) WaitForCacheSync(context.Context) bool { return bool(f) }
+
+func TestCacheSynced(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	if err := CacheSynced(fakeCache(true))(req); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := CacheSynced(fakeCache(false))(req); err == nil {
+		t.Error("Expected an error for the informers not synced")
+	}
+}
+
+func TestCRDsInstalled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	if err := CRDsInstalled(fake.NewClientBuilder().WithScheme(scheme).Build())(req); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	// The kind isn't known without the CRD.
+	if err := CRDsInstalled(fake.NewClientBuilder().WithScheme(runtime.NewScheme()).Build())(req); err == nil {
+		t.Error("Expected an error for the CRD not installed")
+	}
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics defines the Prometheus metrics of the lws controllers and webhooks, served on the
+// controller-runtime metrics endpoint once registered.
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+)
+
+const subsystem = "lws"
+
+var (
+	groupRestarts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "group_restarts_total",
+		Help:      "The number of groups recreated by the controller, by the reason of the restart.",
+	}, []string{"reason"})
+
+	rolloutDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Subsystem: subsystem,
+		Name:      "rollout_duration_seconds",
+		Help:      "The time from the start to the end of the rolling updates.",
+		Buckets:   prometheus.ExponentialBuckets(10, 2, 12),
+	})
+
+	// groupReadyDuration is the cold start latency of the groups, labeled by the lws so that alerts can be
+	// scoped to the services, the buckets go up to more than 2 hours for the large models.
+	groupReadyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: subsystem,
+		Name:      "group_ready_duration_seconds",
+		Help:      "The time from the creation or the recreation of a group, i.e. of its leader pod, until all the pods of the group are ready, by the lws.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"namespace", "name"})
+
+	webhookMutationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: subsystem,
+		Name:      "webhook_mutation_duration_seconds",
+		Help:      "The latency of the mutating webhooks, by the webhook.",
+		Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 12),
+	}, []string{"webhook"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: subsystem,
+		Name:      "reconcile_errors_total",
+		Help:      "The number of reconciles returning an error, by the controller.",
+	}, []string{"controller"})
+
+	// The per object gauges follow the kube-state-metrics conventions, so that the dashboards of the
+	// workloads apply to the lws as well.
+	specReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "spec_replicas",
+		Help:      "The desired number of groups of the lws.",
+	}, []string{"namespace", "name"})
+
+	specSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "spec_size",
+		Help:      "The number of pods of each group of the lws.",
+	}, []string{"namespace", "name"})
+
+	statusReadyReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "status_ready_replicas",
+		Help:      "The number of ready groups of the lws.",
+	}, []string{"namespace", "name"})
+
+	statusUpdatedReplicas = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "status_updated_replicas",
+		Help:      "The number of groups of the lws updated to the latest template.",
+	}, []string{"namespace", "name"})
+
+	statusCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "status_condition",
+		Help:      "The conditions of the lws, 1 for the current status of each condition and 0 for the others.",
+	}, []string{"namespace", "name", "condition", "status"})
+
+	featureEnabled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: subsystem,
+		Name:      "feature_enabled",
+		Help:      "Whether the feature gate is enabled, 1 if enabled and 0 otherwise, by the feature and its stage.",
+	}, []string{"name", "stage"})
+
+	objectGauges = []*prometheus.GaugeVec{specReplicas, specSize, statusReadyReplicas, statusUpdatedReplicas, statusCondition}
+)
+
+// Register registers the metrics on the controller-runtime metrics registry.
+func Register() {
+	metrics.Registry.MustRegister(
+		groupRestarts,
+		rolloutDuration,
+		groupReadyDuration,
+		webhookMutationDuration,
+		reconcileErrors,
+		featureEnabled,
+	)
+	for _, gauge := range objectGauges {
+		metrics.Registry.MustRegister(gauge)
+	}
+}
+
+// GroupRestarted counts a group recreated for the reason.
+func GroupRestarted(reason string) {
+	groupRestarts.WithLabelValues(reason).Inc()
+}
+
+// RolloutCompleted records the duration of a rolling update.
+func RolloutCompleted(duration time.Duration) {
+	rolloutDuration.Observe(duration.Seconds())
+}
+
+// GroupReady records the time a group of the lws takes to be ready.
+func GroupReady(namespace, name string, duration time.Duration) {
+	groupReadyDuration.WithLabelValues(namespace, name).Observe(duration.Seconds())
+}
+
+// LeaderWorkerSetObserved records the spec and the status of the lws in the per object gauges.
+func LeaderWorkerSetObserved(lws *leaderworkerset.LeaderWorkerSet) {
+	labels := prometheus.Labels{"namespace": lws.Namespace, "name": lws.Name}
+	specReplicas.With(labels).Set(float64(ptr.Deref(lws.Spec.Replicas, 0)))
+	specSize.With(labels).Set(float64(ptr.Deref(lws.Spec.LeaderWorkerTemplate.Size, 0)))
+	statusReadyReplicas.With(labels).Set(float64(lws.Status.ReadyReplicas))
+	statusUpdatedReplicas.With(labels).Set(float64(lws.Status.UpdatedReplicas))
+
+	// The removed conditions are dropped as well.
+	statusCondition.DeletePartialMatch(labels)
+	for _, condition := range lws.Status.Conditions {
+		for _, status := range []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown} {
+			value := 0.0
+			if condition.Status == status {
+				value = 1
+			}
+			statusCondition.WithLabelValues(lws.Namespace, lws.Name, condition.Type, strings.ToLower(string(status))).Set(value)
+		}
+	}
+}
+
+// LeaderWorkerSetDeleted removes the series of the deleted lws.
+func LeaderWorkerSetDeleted(namespace, name string) {
+	groupReadyDuration.DeleteLabelValues(namespace, name)
+	for _, gauge := range objectGauges {
+		gauge.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	}
+}
+
+// WebhookMutated records the latency of the mutating webhook started at the given time.
+func WebhookMutated(webhook string, start time.Time) {
+	webhookMutationDuration.WithLabelValues(webhook).Observe(time.Since(start).Seconds())
+}
+
+// FeatureGatesObserved records the state of all the known feature gates.
+func FeatureGatesObserved() {
+	for feature, spec := range features.Specs() {
+		value := 0.0
+		if features.Enabled(feature) {
+			value = 1
+		}
+		featureEnabled.WithLabelValues(string(feature), string(spec.PreRelease)).Set(value)
+	}
+}
+
+// ReconcileFailed counts a reconcile of the controller returning an error.
+func ReconcileFailed(controller string) {
+	reconcileErrors.WithLabelValues(controller).Inc()
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	featuregatetesting "sigs.k8s.io/lws/pkg/features/testing"
+)
+
+func TestCounters(t *testing.T) {
+	testCases := []struct {
+		name  string
+		count func()
+		value func() float64
+		want  float64
+	}{
+		{
+			name:  "group restarts by reason",
+			count: func() { GroupRestarted("PodFailed"); GroupRestarted("PodFailed"); GroupRestarted("Evicted") },
+			value: func() float64 { return testutil.ToFloat64(groupRestarts.WithLabelValues("PodFailed")) },
+			want:  2,
+		},
+		{
+			name:  "reconcile errors by controller",
+			count: func() { ReconcileFailed("pod") },
+			value: func() float64 { return testutil.ToFloat64(reconcileErrors.WithLabelValues("pod")) },
+			want:  1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.count()
+			if got := tc.value(); got != tc.want {
+				t.Errorf("Expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestHistograms(t *testing.T) {
+	RolloutCompleted(time.Minute)
+	GroupReady("default", "lws", 30*time.Second)
+	WebhookMutated("pod", time.Now())
+	if got := testutil.CollectAndCount(rolloutDuration); got != 1 {
+		t.Errorf("Expected 1 rollout duration series, got %d", got)
+	}
+	if got := testutil.CollectAndCount(groupReadyDuration); got != 1 {
+		t.Errorf("Expected 1 group ready duration series, got %d", got)
+	}
+	LeaderWorkerSetDeleted("default", "lws")
+	if got := testutil.CollectAndCount(groupReadyDuration); got != 0 {
+		t.Errorf("Expected the group ready duration series removed, got %d", got)
+	}
+	if got := testutil.CollectAndCount(webhookMutationDuration, "lws_webhook_mutation_duration_seconds"); got != 1 {
+		t.Errorf("Expected 1 webhook mutation duration series, got %d", got)
+	}
+}
+
+func TestLeaderWorkerSetObserved(t *testing.T) {
+	lws := &leaderworkerset.LeaderWorkerSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "lws"},
+		Spec: leaderworkerset.LeaderWorkerSetSpec{
+			Replicas:             ptr.To[int32](3),
+			LeaderWorkerTemplate: leaderworkerset.LeaderWorkerTemplate{Size: ptr.To[int32](4)},
+		},
+		Status: leaderworkerset.LeaderWorkerSetStatus{
+			ReadyReplicas:   2,
+			UpdatedReplicas: 1,
+			Conditions:      []metav1.Condition{{Type: "Available", Status: metav1.ConditionFalse}},
+		},
+	}
+	LeaderWorkerSetObserved(lws)
+
+	testCases := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{name: "spec replicas", value: testutil.ToFloat64(specReplicas.WithLabelValues("default", "lws")), want: 3},
+		{name: "spec size", value: testutil.ToFloat64(specSize.WithLabelValues("default", "lws")), want: 4},
+		{name: "ready replicas", value: testutil.ToFloat64(statusReadyReplicas.WithLabelValues("default", "lws")), want: 2},
+		{name: "updated replicas", value: testutil.ToFloat64(statusUpdatedReplicas.WithLabelValues("default", "lws")), want: 1},
+		{name: "current condition status", value: testutil.ToFloat64(statusCondition.WithLabelValues("default", "lws", "Available", "false")), want: 1},
+		{name: "other condition status", value: testutil.ToFloat64(statusCondition.WithLabelValues("default", "lws", "Available", "true")), want: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.value != tc.want {
+				t.Errorf("Expected %v, got %v", tc.want, tc.value)
+			}
+		})
+	}
+
+	LeaderWorkerSetDeleted("default", "lws")
+	if got := testutil.CollectAndCount(statusCondition); got != 0 {
+		t.Errorf("Expected the condition series removed, got %d", got)
+	}
+}
+
+func TestFeatureGatesObserved(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.FailurePolicy, true)
+	FeatureGatesObserved()
+	if got := testutil.ToFloat64(featureEnabled.WithLabelValues(string(features.FailurePolicy), "ALPHA")); got != 1 {
+		t.Errorf("Expected the FailurePolicy feature enabled, got %v", got)
+	}
+	if got := testutil.ToFloat64(featureEnabled.WithLabelValues(string(features.ControllerSideInjection), "ALPHA")); got != 0 {
+		t.Errorf("Expected the ControllerSideInjection feature disabled, got %v", got)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulerprovider
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// ProviderType is the scheduler provider used for gang scheduling, set via
+// the leaderworkerset.sigs.k8s.io/gang-scheduling annotation.
+type ProviderType string
+
+const (
+	// SchedulerPlugins creates the PodGroups of the coscheduling plugin from
+	// kubernetes-sigs/scheduler-plugins.
+	SchedulerPlugins ProviderType = "scheduler-plugins"
+
+	// Volcano creates the PodGroups of the volcano scheduler, pods are scheduled
+	// by volcano.
+	Volcano ProviderType = "volcano"
+)
+
+// SchedulerProvider manages the PodGroup of each group, so that all the pods
+// of a group are scheduled in an all-or-nothing way.
+type SchedulerProvider interface {
+	// CreatePodGroupIfNotExists creates the PodGroup of the group the leader pod belongs to.
+	CreatePodGroupIfNotExists(ctx context.Context, c client.Client, scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error
+	// InjectPodGroupMetadata stamps the PodGroup metadata on the pod.
+	InjectPodGroupMetadata(pod *corev1.Pod)
+	// DeletePodGroups deletes the PodGroups of all the groups of the lws.
+	DeletePodGroups(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet) error
+}
+
+// Providers are the supported scheduler providers.
+var Providers = []ProviderType{SchedulerPlugins, Volcano}
+
+// NewSchedulerProvider returns the scheduler provider of the given type.
+func NewSchedulerProvider(providerType ProviderType) (SchedulerProvider, error) {
+	switch providerType {
+	case SchedulerPlugins:
+		return &schedulerPluginsProvider{}, nil
+	case Volcano:
+		return &volcanoProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported gang scheduling provider %q", providerType)
+	}
+}
+
+// PodGroupName returns the name of the PodGroup of the group, the pod must
+// have the set name and group index labels.
+func PodGroupName(pod *corev1.Pod) string {
+	return fmt.Sprintf("%s-%s", pod.Labels[leaderworkerset.SetNameLabelKey], pod.Labels[leaderworkerset.GroupIndexLabelKey])
+}
+
+// createPodGroupIfNotExists creates the PodGroup of the given kind for the group, the PodGroup is
+// kept across group restarts and garbage collected together with the lws.
+func createPodGroupIfNotExists(ctx context.Context, c client.Client, scheme *runtime.Scheme, gvk schema.GroupVersionKind, spec map[string]interface{}, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	podGroup := &unstructured.Unstructured{}
+	podGroup.SetGroupVersionKind(gvk)
+	err := c.Get(ctx, types.NamespacedName{Name: PodGroupName(leaderPod), Namespace: leaderPod.Namespace}, podGroup)
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	podGroup.SetName(PodGroupName(leaderPod))
+	podGroup.SetNamespace(leaderPod.Namespace)
+	podGroup.SetLabels(map[string]string{
+		leaderworkerset.SetNameLabelKey:    lws.Name,
+		leaderworkerset.GroupIndexLabelKey: leaderPod.Labels[leaderworkerset.GroupIndexLabelKey],
+	})
+	if err := unstructured.SetNestedMap(podGroup.Object, spec, "spec"); err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(lws, podGroup, scheme); err != nil {
+		return err
+	}
+	return client.IgnoreAlreadyExists(c.Create(ctx, podGroup))
+}
+
+// deletePodGroups deletes the PodGroups of the given kind controlled by the lws, there is nothing to
+// delete if the PodGroup CRD isn't installed.
+func deletePodGroups(ctx context.Context, c client.Client, gvk schema.GroupVersionKind, lws *leaderworkerset.LeaderWorkerSet) error {
+	podGroups := &unstructured.UnstructuredList{}
+	podGroups.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, podGroups, client.InNamespace(lws.Namespace), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+		return err
+	}
+	for i := range podGroups.Items {
+		if !metav1.IsControlledBy(&podGroups.Items[i], lws) {
+			continue
+		}
+		if err := c.Delete(ctx, &podGroups.Items[i]); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
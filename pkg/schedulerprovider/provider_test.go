@@ -0,0 +1,126 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulerprovider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/testutils"
+)
+
+func TestInjectPodGroupMetadata(t *testing.T) {
+	tests := []struct {
+		name              string
+		providerType      ProviderType
+		wantLabels        map[string]string
+		wantAnnotations   map[string]string
+		wantSchedulerName string
+		wantErr           bool
+	}{
+		{
+			name:         "scheduler-plugins",
+			providerType: SchedulerPlugins,
+			wantLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "1",
+				SchedulerPluginsPodGroupLabelKey:   "test-sample-1",
+			},
+		},
+		{
+			name:         "volcano",
+			providerType: Volcano,
+			wantLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "1",
+			},
+			wantAnnotations:   map[string]string{VolcanoPodGroupAnnotationKey: "test-sample-1"},
+			wantSchedulerName: VolcanoSchedulerName,
+		},
+		{
+			name:         "unsupported provider",
+			providerType: "unknown",
+			wantErr:      true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := NewSchedulerProvider(tc.providerType)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.wantErr {
+				return
+			}
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:    "test-sample",
+				leaderworkerset.GroupIndexLabelKey: "1",
+			}}}
+			provider.InjectPodGroupMetadata(pod)
+			if diff := cmp.Diff(tc.wantLabels, pod.Labels); diff != "" {
+				t.Errorf("Unexpected pod labels (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, pod.Annotations); diff != "" {
+				t.Errorf("Unexpected pod annotations (-want,+got):\n%s", diff)
+			}
+			if pod.Spec.SchedulerName != tc.wantSchedulerName {
+				t.Errorf("Expected scheduler name %q, got %q", tc.wantSchedulerName, pod.Spec.SchedulerName)
+			}
+		})
+	}
+}
+
+func TestSchedulerPluginsCreatePodGroupIfNotExists(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	lws := testutils.BuildLeaderWorkerSet("default").Size(4).Obj()
+	leaderPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-sample-0",
+		Namespace: "default",
+		Labels: map[string]string{
+			leaderworkerset.SetNameLabelKey:    lws.Name,
+			leaderworkerset.GroupIndexLabelKey: "0",
+		},
+	}}
+
+	provider := &schedulerPluginsProvider{}
+	for i := 0; i < 2; i++ {
+		if err := provider.CreatePodGroupIfNotExists(context.Background(), c, scheme, lws, leaderPod); err != nil {
+			t.Fatalf("Failed to create pod group: %v", err)
+		}
+	}
+	podGroup := &unstructured.Unstructured{}
+	podGroup.SetGroupVersionKind(schedulerPluginsPodGroupGVK)
+	if err := c.Get(context.Background(), types.NamespacedName{Name: lws.Name + "-0", Namespace: "default"}, podGroup); err != nil {
+		t.Fatalf("Failed to get pod group: %v", err)
+	}
+	if minMember, _, _ := unstructured.NestedInt64(podGroup.Object, "spec", "minMember"); minMember != 4 {
+		t.Errorf("Expected minMember 4, got %d", minMember)
+	}
+}
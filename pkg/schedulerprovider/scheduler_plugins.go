@@ -0,0 +1,58 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulerprovider
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	// SchedulerPluginsPodGroupLabelKey is the label the coscheduling plugin uses to
+	// find the PodGroup of the pod.
+	SchedulerPluginsPodGroupLabelKey = "scheduling.x-k8s.io/pod-group"
+)
+
+// schedulerPluginsPodGroupGVK is the GroupVersionKind of the scheduler-plugins PodGroup, it is managed
+// as an unstructured object so scheduler-plugins is not a hard dependency.
+var schedulerPluginsPodGroupGVK = schema.GroupVersionKind{Group: "scheduling.x-k8s.io", Version: "v1alpha1", Kind: "PodGroup"}
+
+type schedulerPluginsProvider struct{}
+
+func (p *schedulerPluginsProvider) CreatePodGroupIfNotExists(ctx context.Context, c client.Client, scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	spec := map[string]interface{}{
+		"minMember": int64(*lws.Spec.LeaderWorkerTemplate.Size),
+	}
+	return createPodGroupIfNotExists(ctx, c, scheme, schedulerPluginsPodGroupGVK, spec, lws, leaderPod)
+}
+
+func (p *schedulerPluginsProvider) InjectPodGroupMetadata(pod *corev1.Pod) {
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[SchedulerPluginsPodGroupLabelKey] = PodGroupName(pod)
+}
+
+func (p *schedulerPluginsProvider) DeletePodGroups(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet) error {
+	return deletePodGroups(ctx, c, schedulerPluginsPodGroupGVK, lws)
+}
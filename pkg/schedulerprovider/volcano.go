@@ -0,0 +1,68 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulerprovider
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	// VolcanoSchedulerName is the scheduler name of volcano.
+	VolcanoSchedulerName = "volcano"
+
+	// VolcanoPodGroupAnnotationKey is the annotation volcano uses to find the PodGroup of the pod.
+	VolcanoPodGroupAnnotationKey = "scheduling.k8s.io/group-name"
+
+	// VolcanoQueueNameAnnotationKey is the annotation of the lws to specify the volcano queue
+	// the PodGroups are submitted to, the default queue is used if not set.
+	VolcanoQueueNameAnnotationKey = "scheduling.volcano.sh/queue-name"
+)
+
+// volcanoPodGroupGVK is the GroupVersionKind of the volcano PodGroup, it is managed as an
+// unstructured object so volcano is not a hard dependency.
+var volcanoPodGroupGVK = schema.GroupVersionKind{Group: "scheduling.volcano.sh", Version: "v1beta1", Kind: "PodGroup"}
+
+type volcanoProvider struct{}
+
+func (p *volcanoProvider) CreatePodGroupIfNotExists(ctx context.Context, c client.Client, scheme *runtime.Scheme, lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) error {
+	spec := map[string]interface{}{
+		"minMember": int64(*lws.Spec.LeaderWorkerTemplate.Size),
+	}
+	if queue := lws.Annotations[VolcanoQueueNameAnnotationKey]; queue != "" {
+		spec["queue"] = queue
+	}
+	return createPodGroupIfNotExists(ctx, c, scheme, volcanoPodGroupGVK, spec, lws, leaderPod)
+}
+
+func (p *volcanoProvider) InjectPodGroupMetadata(pod *corev1.Pod) {
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[VolcanoPodGroupAnnotationKey] = PodGroupName(pod)
+	pod.Spec.SchedulerName = VolcanoSchedulerName
+}
+
+func (p *volcanoProvider) DeletePodGroups(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet) error {
+	return deletePodGroups(ctx, c, volcanoPodGroupGVK, lws)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package testing
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/client-go/clientset/versioned/fake"
+)
+
+// NewScheme returns a scheme with the built-in types and LeaderWorkerSet registered.
+func NewScheme() *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(leaderworkerset.AddToScheme(scheme))
+	return scheme
+}
+
+// NewFakeClient returns a controller-runtime fake client serving the objects, the status
+// of LeaderWorkerSet is a subresource as in the cluster.
+func NewFakeClient(objs ...client.Object) client.Client {
+	return fakeclient.NewClientBuilder().
+		WithScheme(NewScheme()).
+		WithObjects(objs...).
+		WithStatusSubresource(&leaderworkerset.LeaderWorkerSet{}).
+		Build()
+}
+
+// NewFakeClientset returns a fake typed clientset serving the LeaderWorkerSet objects, for the
+// controllers built on the generated clients.
+func NewFakeClientset(objs ...runtime.Object) *fake.Clientset {
+	return fake.NewSimpleClientset(objs...)
+}
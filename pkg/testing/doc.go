@@ -0,0 +1,19 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testing provides the helpers to test against LeaderWorkerSet out of this repository:
+// the builders of the LeaderWorkerSet objects and the groups, the fake clients, and the envtest
+// helpers to install the CRD and to wait for the groups to be ready.
+package testing
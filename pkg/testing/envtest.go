@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package testing
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+// CRDDirectoryPath returns the directory of the LeaderWorkerSet CRD, which is shipped with the module.
+func CRDDirectoryPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "config", "crd", "bases")
+}
+
+// NewEnvironment returns an envtest environment installing the LeaderWorkerSet CRD once started.
+func NewEnvironment() *envtest.Environment {
+	return &envtest.Environment{
+		CRDDirectoryPaths:     []string{CRDDirectoryPath()},
+		ErrorIfCRDPathMissing: true,
+	}
+}
+
+// GroupReady returns true if the leader pod of the group is running and ready, and the worker
+// statefulset of the group is ready, the same as the lws controller.
+func GroupReady(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet, groupIndex int) (bool, error) {
+	name := GroupName(lws, groupIndex)
+	var leaderPod corev1.Pod
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: lws.Namespace}, &leaderPod); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	var sts appsv1.StatefulSet
+	if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: lws.Namespace}, &sts); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if leaderPod.Status.Phase != corev1.PodRunning || !podReady(leaderPod) {
+		return false, nil
+	}
+	return sts.Spec.Replicas != nil && *sts.Spec.Replicas == sts.Status.Replicas &&
+		sts.Status.CurrentRevision == sts.Status.UpdateRevision, nil
+}
+
+// WaitForGroupReady polls until the group is ready or the timeout is reached.
+func WaitForGroupReady(ctx context.Context, c client.Client, lws *leaderworkerset.LeaderWorkerSet, groupIndex int, timeout time.Duration) error {
+	return wait.PollUntilContextTimeout(ctx, 250*time.Millisecond, timeout, true, func(ctx context.Context) (bool, error) {
+		return GroupReady(ctx, c, lws, groupIndex)
+	})
+}
+
+func podReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package testing
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// maxCRDSize leaves room below the 1.5MiB request size limit of etcd, which the CRDs are stored in as JSON.
+const maxCRDSize = 1 << 20
+
+func TestCRDSize(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(CRDDirectoryPath(), "*.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("No CRD found in %s", CRDDirectoryPath())
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		crd, err := yaml.ToJSON(data)
+		if err != nil {
+			t.Fatalf("Failed to convert %s to JSON: %v", file, err)
+		}
+		if len(crd) > maxCRDSize {
+			t.Errorf("Expected the CRD %s within %d bytes, got %d", filepath.Base(file), maxCRDSize, len(crd))
+		}
+	}
+}
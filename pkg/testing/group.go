@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package testing
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+)
+
+// GroupName returns the name of the group, which is the name of the leader pod and the worker statefulset.
+func GroupName(lws *leaderworkerset.LeaderWorkerSet, groupIndex int) string {
+	return fmt.Sprintf("%s-%d", lws.Name, groupIndex)
+}
+
+// MakeGroupPods returns the leader pod followed by the worker pods of the group, labeled and
+// annotated as by the controllers and the webhooks, without the injected env vars.
+func MakeGroupPods(lws *leaderworkerset.LeaderWorkerSet, groupIndex int) []corev1.Pod {
+	size := 1
+	if lws.Spec.LeaderWorkerTemplate.Size != nil {
+		size = int(*lws.Spec.LeaderWorkerTemplate.Size)
+	}
+	leaderName := GroupName(lws, groupIndex)
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	groupKey := utils.Sha1Hash(fmt.Sprintf("%s/%s", lws.Namespace, leaderName))
+
+	pods := make([]corev1.Pod, 0, size)
+	for workerIndex := 0; workerIndex < size; workerIndex++ {
+		name := leaderName
+		template := lws.Spec.LeaderWorkerTemplate.WorkerTemplate
+		annotations := map[string]string{
+			leaderworkerset.SizeAnnotationKey: strconv.Itoa(size),
+		}
+		if workerIndex == 0 {
+			if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+				template = *lws.Spec.LeaderWorkerTemplate.LeaderTemplate
+			}
+		} else {
+			name = fmt.Sprintf("%s-%d", leaderName, workerIndex)
+			annotations[leaderworkerset.LeaderPodNameAnnotationKey] = leaderName
+		}
+		labels := map[string]string{
+			leaderworkerset.SetNameLabelKey:         lws.Name,
+			leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(groupIndex),
+			leaderworkerset.WorkerIndexLabelKey:     strconv.Itoa(workerIndex),
+			leaderworkerset.GroupUniqueHashLabelKey: groupKey,
+			leaderworkerset.TemplateRevisionHashKey: templateHash,
+		}
+		for key, value := range template.Labels {
+			labels[key] = value
+		}
+		for key, value := range template.Annotations {
+			annotations[key] = value
+		}
+		pods = append(pods, corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   lws.Namespace,
+				Labels:      labels,
+				Annotations: annotations,
+			},
+			Spec: *template.Spec.DeepCopy(),
+		})
+	}
+	return pods
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package testing
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestMakeGroupPods(t *testing.T) {
+	lws := BuildLeaderWorkerSet("default").Size(3).Obj()
+	pods := MakeGroupPods(lws, 1)
+
+	var names, workerIndexes []string
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+		workerIndexes = append(workerIndexes, pod.Labels[leaderworkerset.WorkerIndexLabelKey])
+		if pod.Labels[leaderworkerset.GroupUniqueHashLabelKey] != pods[0].Labels[leaderworkerset.GroupUniqueHashLabelKey] {
+			t.Errorf("Unexpected group key of pod %s", pod.Name)
+		}
+	}
+	if diff := cmp.Diff([]string{"test-sample-1", "test-sample-1-1", "test-sample-1-2"}, names); diff != "" {
+		t.Errorf("Unexpected pod names (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"0", "1", "2"}, workerIndexes); diff != "" {
+		t.Errorf("Unexpected worker indexes (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec, pods[0].Spec); diff != "" {
+		t.Errorf("Unexpected leader pod spec (-want,+got):\n%s", diff)
+	}
+	if got := pods[1].Annotations[leaderworkerset.LeaderPodNameAnnotationKey]; got != "test-sample-1" {
+		t.Errorf("Unexpected leader name annotation %q", got)
+	}
+}
+
+func TestGroupReady(t *testing.T) {
+	lws := BuildLeaderWorkerSet("default").Obj()
+	readyCondition := corev1.PodCondition{Type: corev1.PodReady, Status: corev1.ConditionTrue}
+	tests := []struct {
+		name      string
+		objs      []client.Object
+		wantReady bool
+	}{
+		{
+			name: "group not created",
+		},
+		{
+			name: "leader pod and worker statefulset ready",
+			objs: []client.Object{
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+					Status:     corev1.PodStatus{Phase: corev1.PodRunning, Conditions: []corev1.PodCondition{readyCondition}},
+				},
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+					Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+					Status:     appsv1.StatefulSetStatus{Replicas: 1},
+				},
+			},
+			wantReady: true,
+		},
+		{
+			name: "leader pod not ready",
+			objs: []client.Object{
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+					Status:     corev1.PodStatus{Phase: corev1.PodPending},
+				},
+				&appsv1.StatefulSet{
+					ObjectMeta: metav1.ObjectMeta{Name: "test-sample-0", Namespace: "default"},
+					Spec:       appsv1.StatefulSetSpec{Replicas: ptr.To[int32](1)},
+					Status:     appsv1.StatefulSetStatus{Replicas: 1},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewFakeClient(tc.objs...)
+			ready, err := GroupReady(context.Background(), c, lws, 0)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ready != tc.wantReady {
+				t.Errorf("Unexpected ready, want %t, got %t", tc.wantReady, ready)
+			}
+			err = WaitForGroupReady(context.Background(), c, lws, 0, 500*time.Millisecond)
+			if gotReady := err == nil; gotReady != tc.wantReady {
+				t.Errorf("Unexpected error waiting for the group: %v", err)
+			}
+		})
+	}
+}
+
+func TestCRDDirectoryPath(t *testing.T) {
+	if _, err := os.Stat(CRDDirectoryPath()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	testCases := []struct {
+		name       string
+		err        error
+		wantStatus codes.Code
+	}{
+		{
+			name:       "succeeded",
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "failed",
+			err:        errors.New("conflict"),
+			wantStatus: codes.Error,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, parent := Start(context.Background(), "Reconcile LeaderWorkerSet", ObjectAttributes("LeaderWorkerSet", "default", "lws")...)
+			_, child := Start(ctx, "Apply leader StatefulSet")
+			End(child, tc.err)
+			End(parent, nil)
+
+			spans := recorder.Ended()
+			got, gotParent := spans[len(spans)-2], spans[len(spans)-1]
+			if got.Parent().SpanID() != gotParent.SpanContext().SpanID() {
+				t.Errorf("Expected the span to be a child of the reconcile span")
+			}
+			if got.Status().Code != tc.wantStatus {
+				t.Errorf("Expected status %v, got %v", tc.wantStatus, got.Status().Code)
+			}
+			if len(gotParent.Attributes()) != 3 {
+				t.Errorf("Expected the object attributes, got %v", gotParent.Attributes())
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
+)
+
+// GroupMeta describes the group the pod belongs to.
+type GroupMeta struct {
+	// Size is the number of pods in the group, including the leader.
+	Size int
+}
+
+// Provider injects the accelerator specific environment variables to the pods,
+// e.g. the TPU worker hostnames.
+type Provider interface {
+	// Name is the unique name of the provider.
+	Name() string
+	// Detect returns true if the pod requests the accelerators of the provider.
+	Detect(podSpec corev1.PodSpec) bool
+	// Inject adds the accelerator specific environment variables to the pod.
+	Inject(pod *corev1.Pod, groupMeta GroupMeta) error
+}
+
+var providers []Provider
+
+// Register adds the provider to the registry, it panics if the name is registered already.
+func Register(provider Provider) {
+	for _, p := range providers {
+		if p.Name() == provider.Name() {
+			panic(fmt.Sprintf("accelerator provider %q is registered already", provider.Name()))
+		}
+	}
+	providers = append(providers, provider)
+}
+
+// InjectVariables injects the environment variables of all the providers detected in the pod.
+func InjectVariables(pod *corev1.Pod, groupMeta GroupMeta) error {
+	for _, provider := range providers {
+		if !provider.Detect(pod.Spec) {
+			continue
+		}
+		if err := provider.Inject(pod, groupMeta); err != nil {
+			return fmt.Errorf("injecting %s variables: %w", provider.Name(), err)
+		}
+	}
+	return nil
+}
+
+// podRequestsResource returns true if any container of the pod requests the resource.
+func podRequestsResource(podSpec corev1.PodSpec, resourceName corev1.ResourceName) bool {
+	return len(containersRequestingResource(&podSpec, resourceName)) > 0
+}
+
+// containersRequestingResource returns the containers and init containers requesting the resource.
+func containersRequestingResource(podSpec *corev1.PodSpec, resourceName corev1.ResourceName) []*corev1.Container {
+	var containers []*corev1.Container
+	requests := func(c corev1.Container) bool {
+		limit, request := c.Resources.Limits[resourceName], c.Resources.Requests[resourceName]
+		return !limit.IsZero() || !request.IsZero()
+	}
+	for i := range podSpec.Containers {
+		if requests(podSpec.Containers[i]) {
+			containers = append(containers, &podSpec.Containers[i])
+		}
+	}
+	for i := range podSpec.InitContainers {
+		if requests(podSpec.InitContainers[i]) {
+			containers = append(containers, &podSpec.InitContainers[i])
+		}
+	}
+	return containers
+}
+
+// leaderAddress returns the DNS address of the leader pod of the group.
+func leaderAddress(pod *corev1.Pod) (string, error) {
+	leaderName := pod.Name
+	if pod.Labels[leaderworkerset.WorkerIndexLabelKey] != "0" {
+		leaderName, _ = statefulsetutils.GetParentNameAndOrdinal(pod.Name)
+		if leaderName == "" {
+			return "", fmt.Errorf("parsing parent name from pod %s", pod.Name)
+		}
+	}
+	return fmt.Sprintf("%s.%s", leaderName, pod.Spec.Subdomain), nil
+}
+
+// addEnvVarsIfNotExist adds the env vars to the container, the ones set by users already are kept.
+func addEnvVarsIfNotExist(container *corev1.Container, envVars ...corev1.EnvVar) {
+	for _, envVar := range envVars {
+		exists := false
+		for _, env := range container.Env {
+			if env.Name == envVar.Name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			container.Env = append(container.Env, envVar)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestInjectVariables(t *testing.T) {
+	tests := []struct {
+		name        string
+		podSpec     corev1.PodSpec
+		wantEnvVars []string
+	}{
+		{
+			name:        "pod requests TPUs",
+			podSpec:     MakeLeaderPodSpecWithTPUResource(),
+			wantEnvVars: []string{TpuWorkerHostNames, TpuWorkerId},
+		},
+		{
+			name:    "pod requests no accelerators",
+			podSpec: MakeLeaderPodSpec(),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   "test-sample-1",
+					Labels: map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+				},
+				Spec: tc.podSpec,
+			}
+			if err := InjectVariables(pod, GroupMeta{Size: 2}); err != nil {
+				t.Fatalf("Failed to inject variables: %v", err)
+			}
+			var gotEnvVars []string
+			for _, env := range pod.Spec.Containers[0].Env {
+				gotEnvVars = append(gotEnvVars, env.Name)
+			}
+			if diff := cmp.Diff(tc.wantEnvVars, gotEnvVars); diff != "" {
+				t.Errorf("Unexpected env vars (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterDuplicateProvider(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected registering a duplicate provider to panic")
+		}
+	}()
+	Register(&tpuProvider{})
+}
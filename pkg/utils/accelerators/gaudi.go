@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	GaudiResourceName corev1.ResourceName = corev1.ResourceName("habana.ai/gaudi")
+
+	// HcclCommId is the address of the HCCL coordinator, which is the leader.
+	HcclCommId string = "HCCL_COMM_ID"
+
+	// DefaultHcclCommPort is the default port of the HCCL coordinator.
+	DefaultHcclCommPort int = 5555
+)
+
+func init() {
+	Register(&gaudiProvider{})
+}
+
+// gaudiProvider injects the HCCL variables for multi-node Intel Gaudi setups.
+type gaudiProvider struct{}
+
+func (p *gaudiProvider) Name() string {
+	return "gaudi"
+}
+
+func (p *gaudiProvider) Detect(podSpec corev1.PodSpec) bool {
+	return podRequestsResource(podSpec, GaudiResourceName)
+}
+
+func (p *gaudiProvider) Inject(pod *corev1.Pod, groupMeta GroupMeta) error {
+	leader, err := leaderAddress(pod)
+	if err != nil {
+		return err
+	}
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return fmt.Errorf("worker index label is missing for pod %s", pod.Name)
+	}
+	for _, container := range containersRequestingResource(&pod.Spec, GaudiResourceName) {
+		addEnvVarsIfNotExist(container,
+			corev1.EnvVar{Name: HcclCommId, Value: fmt.Sprintf("%s:%d", leader, DefaultHcclCommPort)},
+			corev1.EnvVar{Name: WorldSize, Value: fmt.Sprint(groupMeta.Size)},
+			corev1.EnvVar{Name: NodeRank, Value: workerIndex},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestGaudiProviderInject(t *testing.T) {
+	tests := []struct {
+		name        string
+		podName     string
+		workerIndex string
+		wantEnv     []corev1.EnvVar
+	}{
+		{
+			name:        "leader pod",
+			podName:     "test-sample-1",
+			workerIndex: "0",
+			wantEnv: []corev1.EnvVar{
+				{Name: HcclCommId, Value: "test-sample-1.default:5555"},
+				{Name: WorldSize, Value: "4"},
+				{Name: NodeRank, Value: "0"},
+			},
+		},
+		{
+			name:        "worker pod",
+			podName:     "test-sample-1-3",
+			workerIndex: "3",
+			wantEnv: []corev1.EnvVar{
+				{Name: HcclCommId, Value: "test-sample-1.default:5555"},
+				{Name: WorldSize, Value: "4"},
+				{Name: NodeRank, Value: "3"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   tc.podName,
+					Labels: map[string]string{leaderworkerset.WorkerIndexLabelKey: tc.workerIndex},
+				},
+				Spec: corev1.PodSpec{
+					Subdomain: "default",
+					Containers: []corev1.Container{{
+						Name: "worker",
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{GaudiResourceName: resource.MustParse("8")},
+						},
+					}},
+				},
+			}
+			provider := &gaudiProvider{}
+			if !provider.Detect(pod.Spec) {
+				t.Fatalf("Expected the pod to request gaudi devices")
+			}
+			if err := provider.Inject(pod, GroupMeta{Size: 4}); err != nil {
+				t.Fatalf("Failed to inject variables: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantEnv, pod.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("Unexpected env vars (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	GpuResourceName corev1.ResourceName = corev1.ResourceName("nvidia.com/gpu")
+
+	// torch distributed style variables, the leader acts as the master.
+	MasterAddr string = "MASTER_ADDR"
+	MasterPort string = "MASTER_PORT"
+	WorldSize  string = "WORLD_SIZE"
+	Rank       string = "RANK"
+	NodeRank   string = "NODE_RANK"
+
+	// DefaultMasterPort is the default port of torch distributed.
+	DefaultMasterPort string = "29500"
+)
+
+func init() {
+	Register(&gpuProvider{})
+}
+
+// gpuProvider injects the torch distributed variables for multi-node GPU setups, e.g. vLLM or DeepSpeed.
+type gpuProvider struct{}
+
+func (p *gpuProvider) Name() string {
+	return "gpu"
+}
+
+func (p *gpuProvider) Detect(podSpec corev1.PodSpec) bool {
+	return podRequestsResource(podSpec, GpuResourceName)
+}
+
+func (p *gpuProvider) Inject(pod *corev1.Pod, groupMeta GroupMeta) error {
+	masterAddr, err := leaderAddress(pod)
+	if err != nil {
+		return err
+	}
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return fmt.Errorf("worker index label is missing for pod %s", pod.Name)
+	}
+	for _, container := range containersRequestingResource(&pod.Spec, GpuResourceName) {
+		addEnvVarsIfNotExist(container,
+			corev1.EnvVar{Name: MasterAddr, Value: masterAddr},
+			corev1.EnvVar{Name: MasterPort, Value: DefaultMasterPort},
+			corev1.EnvVar{Name: WorldSize, Value: fmt.Sprint(groupMeta.Size)},
+			corev1.EnvVar{Name: Rank, Value: workerIndex},
+			corev1.EnvVar{Name: NodeRank, Value: workerIndex},
+		)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestGPUProviderInject(t *testing.T) {
+	makePod := func(name, workerIndex string, env ...corev1.EnvVar) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: v1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{leaderworkerset.WorkerIndexLabelKey: workerIndex},
+			},
+			Spec: corev1.PodSpec{
+				Subdomain: "default",
+				Containers: []corev1.Container{{
+					Name: "worker",
+					Env:  env,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{GpuResourceName: resource.MustParse("8")},
+					},
+				}},
+			},
+		}
+	}
+	tests := []struct {
+		name    string
+		pod     *corev1.Pod
+		wantEnv []corev1.EnvVar
+	}{
+		{
+			name: "leader pod",
+			pod:  makePod("test-sample-1", "0"),
+			wantEnv: []corev1.EnvVar{
+				{Name: MasterAddr, Value: "test-sample-1.default"},
+				{Name: MasterPort, Value: DefaultMasterPort},
+				{Name: WorldSize, Value: "4"},
+				{Name: Rank, Value: "0"},
+				{Name: NodeRank, Value: "0"},
+			},
+		},
+		{
+			name: "worker pod keeps the user defined port",
+			pod:  makePod("test-sample-1-2", "2", corev1.EnvVar{Name: MasterPort, Value: "1234"}),
+			wantEnv: []corev1.EnvVar{
+				{Name: MasterPort, Value: "1234"},
+				{Name: MasterAddr, Value: "test-sample-1.default"},
+				{Name: WorldSize, Value: "4"},
+				{Name: Rank, Value: "2"},
+				{Name: NodeRank, Value: "2"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &gpuProvider{}
+			if !provider.Detect(tc.pod.Spec) {
+				t.Fatalf("Expected the pod to request GPUs")
+			}
+			if err := provider.Inject(tc.pod, GroupMeta{Size: 4}); err != nil {
+				t.Fatalf("Failed to inject variables: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantEnv, tc.pod.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("Unexpected env vars (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	NeuronResourceName       corev1.ResourceName = corev1.ResourceName("aws.amazon.com/neuron")
+	NeuronCoreResourceName   corev1.ResourceName = corev1.ResourceName("aws.amazon.com/neuroncore")
+	NeuronDeviceResourceName corev1.ResourceName = corev1.ResourceName("aws.amazon.com/neurondevice")
+
+	// NeuronRtRootCommId is the address of the root communicator, which is the leader.
+	NeuronRtRootCommId string = "NEURON_RT_ROOT_COMM_ID"
+	NeuronRankId       string = "NEURON_RANK_ID"
+
+	// DefaultNeuronRootCommPort is the default port of the Neuron root communicator.
+	DefaultNeuronRootCommPort int = 62182
+)
+
+var neuronResourceNames = []corev1.ResourceName{NeuronResourceName, NeuronCoreResourceName, NeuronDeviceResourceName}
+
+func init() {
+	Register(&neuronProvider{})
+}
+
+// neuronProvider injects the Neuron distributed variables for AWS Trainium and Inferentia.
+type neuronProvider struct{}
+
+func (p *neuronProvider) Name() string {
+	return "neuron"
+}
+
+func (p *neuronProvider) Detect(podSpec corev1.PodSpec) bool {
+	return len(containersRequestingNeurons(&podSpec)) > 0
+}
+
+func (p *neuronProvider) Inject(pod *corev1.Pod, groupMeta GroupMeta) error {
+	leader, err := leaderAddress(pod)
+	if err != nil {
+		return err
+	}
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return fmt.Errorf("worker index label is missing for pod %s", pod.Name)
+	}
+	for _, container := range containersRequestingNeurons(&pod.Spec) {
+		addEnvVarsIfNotExist(container,
+			corev1.EnvVar{Name: NeuronRtRootCommId, Value: fmt.Sprintf("%s:%d", leader, DefaultNeuronRootCommPort)},
+			corev1.EnvVar{Name: NeuronRankId, Value: workerIndex},
+			corev1.EnvVar{Name: NodeRank, Value: workerIndex},
+			corev1.EnvVar{Name: WorldSize, Value: fmt.Sprint(groupMeta.Size)},
+		)
+	}
+	return nil
+}
+
+// containersRequestingNeurons returns the containers requesting any of the Neuron resources.
+func containersRequestingNeurons(podSpec *corev1.PodSpec) []*corev1.Container {
+	var containers []*corev1.Container
+	seen := map[*corev1.Container]bool{}
+	for _, resourceName := range neuronResourceNames {
+		for _, container := range containersRequestingResource(podSpec, resourceName) {
+			if !seen[container] {
+				seen[container] = true
+				containers = append(containers, container)
+			}
+		}
+	}
+	return containers
+}
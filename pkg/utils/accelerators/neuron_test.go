@@ -0,0 +1,92 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accelerator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+func TestNeuronProviderInject(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceName corev1.ResourceName
+		podName      string
+		workerIndex  string
+		wantEnv      []corev1.EnvVar
+	}{
+		{
+			name:         "leader pod requests neuron devices",
+			resourceName: NeuronResourceName,
+			podName:      "test-sample-1",
+			workerIndex:  "0",
+			wantEnv: []corev1.EnvVar{
+				{Name: NeuronRtRootCommId, Value: "test-sample-1.default:62182"},
+				{Name: NeuronRankId, Value: "0"},
+				{Name: NodeRank, Value: "0"},
+				{Name: WorldSize, Value: "2"},
+			},
+		},
+		{
+			name:         "worker pod requests neuron cores",
+			resourceName: NeuronCoreResourceName,
+			podName:      "test-sample-1-1",
+			workerIndex:  "1",
+			wantEnv: []corev1.EnvVar{
+				{Name: NeuronRtRootCommId, Value: "test-sample-1.default:62182"},
+				{Name: NeuronRankId, Value: "1"},
+				{Name: NodeRank, Value: "1"},
+				{Name: WorldSize, Value: "2"},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: v1.ObjectMeta{
+					Name:   tc.podName,
+					Labels: map[string]string{leaderworkerset.WorkerIndexLabelKey: tc.workerIndex},
+				},
+				Spec: corev1.PodSpec{
+					Subdomain: "default",
+					Containers: []corev1.Container{{
+						Name: "worker",
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{tc.resourceName: resource.MustParse("1")},
+						},
+					}},
+				},
+			}
+			provider := &neuronProvider{}
+			if !provider.Detect(pod.Spec) {
+				t.Fatalf("Expected the pod to request neurons")
+			}
+			if err := provider.Inject(pod, GroupMeta{Size: 2}); err != nil {
+				t.Fatalf("Failed to inject variables: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantEnv, pod.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("Unexpected env vars (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
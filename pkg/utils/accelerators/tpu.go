@@ -33,8 +33,35 @@ const (
 	TpuWorkerHostNames              string              = "TPU_WORKER_HOSTNAMES"
 	TpuWorkerId                     string              = "TPU_WORKER_ID"
 	LeaderRequestsTPUsAnnotationKey string              = "leaderworkerset.sigs.k8s.io/leader-requests-tpus"
+
+	// TpuTopologyNodeSelectorKey is the node selector declaring the topology of the TPU slice, e.g. 4x4x8.
+	TpuTopologyNodeSelectorKey string = "cloud.google.com/gke-tpu-topology"
+
+	// Multislice variables, each subgroup is a TPU slice when the group spans multiple slices.
+	MegascaleNumSlices          string = "MEGASCALE_NUM_SLICES"
+	MegascaleSliceId            string = "MEGASCALE_SLICE_ID"
+	MegascaleCoordinatorAddress string = "MEGASCALE_COORDINATOR_ADDRESS"
 )
 
+func init() {
+	Register(&tpuProvider{})
+}
+
+// tpuProvider injects the TPU worker hostnames and ids, as well as the multislice variables.
+type tpuProvider struct{}
+
+func (p *tpuProvider) Name() string {
+	return "tpu"
+}
+
+func (p *tpuProvider) Detect(podSpec corev1.PodSpec) bool {
+	return PodRequestsTPUs(podSpec)
+}
+
+func (p *tpuProvider) Inject(pod *corev1.Pod, groupMeta GroupMeta) error {
+	return AddTPUVariables(pod, groupMeta.Size)
+}
+
 // PodRequestsTPUs returns true if the pod requesting TPUs
 func PodRequestsTPUs(podTs corev1.PodSpec) bool {
 	return containersRequestTPUs(podTs.Containers...) || containersRequestTPUs(podTs.InitContainers...)
@@ -155,10 +182,41 @@ func addTPUVariablesSubGroup(pod *corev1.Pod, size int) error {
 			Value: fmt.Sprint(tpuWorkerId),
 		},
 	)
+	container.Env = append(container.Env, megascaleVariables(pod, leaderName, size, subGroupSize, subGroupIndex)...)
 	return nil
 
 }
 
+// megascaleVariables returns the multislice variables when the TPU hosts of the group span multiple
+// subgroups, the first TPU host of subgroup 0 acts as the coordinator.
+func megascaleVariables(pod *corev1.Pod, leaderName string, size, subGroupSize, subGroupIndex int) []corev1.EnvVar {
+	leaderRequestsTPUs := pod.Annotations[LeaderRequestsTPUsAnnotationKey] == "true" || pod.Labels[leaderworkerset.WorkerIndexLabelKey] == "0"
+	tpuHosts := size
+	coordinator := fmt.Sprintf("%s.%s", leaderName, pod.Spec.Subdomain)
+	if !leaderRequestsTPUs {
+		tpuHosts = size - 1
+		coordinator = fmt.Sprintf("%s-1.%s", leaderName, pod.Spec.Subdomain)
+	}
+	numSlices := (tpuHosts + subGroupSize - 1) / subGroupSize
+	if numSlices <= 1 {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{
+			Name:  MegascaleNumSlices,
+			Value: fmt.Sprint(numSlices),
+		},
+		{
+			Name:  MegascaleSliceId,
+			Value: fmt.Sprint(subGroupIndex),
+		},
+		{
+			Name:  MegascaleCoordinatorAddress,
+			Value: coordinator,
+		},
+	}
+}
+
 // AddTPUVariables adds TPU related environment variables to containers
 func AddTPUVariables(pod *corev1.Pod, size int) error {
 	_, foundSubGroupSize := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
@@ -215,6 +273,47 @@ func AddTPUVariables(pod *corev1.Pod, size int) error {
 	return nil
 }
 
+// TPUHostsPerSlice returns the number of TPU hosts of each slice of the group, each subgroup is a
+// slice when subGroupSize is set, the leader is not a TPU host if it doesn't request TPUs.
+func TPUHostsPerSlice(size, subGroupSize int, leaderRequestsTPUs bool) int {
+	if subGroupSize > 0 {
+		return subGroupSize
+	}
+	if !leaderRequestsTPUs {
+		return size - 1
+	}
+	return size
+}
+
+// ValidateTPUTopology returns an error if the number of TPU hosts doesn't match the topology declared
+// in the node selector, the chips per host are derived from the TPUs requested by the pod.
+func ValidateTPUTopology(podSpec corev1.PodSpec, hosts int) error {
+	topology, found := podSpec.NodeSelector[TpuTopologyNodeSelectorKey]
+	if !found {
+		return nil
+	}
+	container := getContainerRequestingTPUs(&podSpec)
+	if container == nil {
+		return nil
+	}
+	chips := 1
+	for _, dim := range strings.Split(topology, "x") {
+		n, err := strconv.Atoi(dim)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid TPU topology %q", topology)
+		}
+		chips *= n
+	}
+	chipsPerHost := int(numTPUsRequested(*container))
+	if chips%chipsPerHost != 0 {
+		return fmt.Errorf("TPU topology %s is not divisible by %d chips per host", topology, chipsPerHost)
+	}
+	if want := chips / chipsPerHost; want != hosts {
+		return fmt.Errorf("TPU topology %s with %d chips per host requires %d hosts, got %d", topology, chipsPerHost, want, hosts)
+	}
+	return nil
+}
+
 // AddTPUAnnotations adds TPU specific annotations.
 func AddTPUAnnotations(leaderPod corev1.Pod, annotations map[string]string) {
 	if PodRequestsTPUs(leaderPod.Spec) {
@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"k8s.io/apimachinery/pkg/api/resource"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 
@@ -107,6 +108,7 @@ func TestAddTPUVariablesSubGroup(t *testing.T) {
 		size                       int
 		expectedTpuWorkerHostNames string
 		expectedTpuWorkerId        string
+		expectedMegascaleEnv       []corev1.EnvVar
 	}{
 		{
 			name: "Leader requests TPU resources",
@@ -149,6 +151,11 @@ func TestAddTPUVariablesSubGroup(t *testing.T) {
 			size:                       8,
 			expectedTpuWorkerId:        "3",
 			expectedTpuWorkerHostNames: "test-sample-1-4.default,test-sample-1-5.default,test-sample-1-6.default,test-sample-1-7.default",
+			expectedMegascaleEnv: []corev1.EnvVar{
+				{Name: MegascaleNumSlices, Value: "2"},
+				{Name: MegascaleSliceId, Value: "1"},
+				{Name: MegascaleCoordinatorAddress, Value: "test-sample-1.default"},
+			},
 		},
 		{
 			name: "Leader does not request TPU resources, worker with subgroup index > 0",
@@ -169,6 +176,11 @@ func TestAddTPUVariablesSubGroup(t *testing.T) {
 			size:                       9,
 			expectedTpuWorkerId:        "0",
 			expectedTpuWorkerHostNames: "test-sample-1-5.default,test-sample-1-6.default,test-sample-1-7.default,test-sample-1-8.default",
+			expectedMegascaleEnv: []corev1.EnvVar{
+				{Name: MegascaleNumSlices, Value: "2"},
+				{Name: MegascaleSliceId, Value: "1"},
+				{Name: MegascaleCoordinatorAddress, Value: "test-sample-1-1.default"},
+			},
 		},
 	}
 	for _, tc := range tests {
@@ -183,6 +195,9 @@ func TestAddTPUVariablesSubGroup(t *testing.T) {
 			if diff := cmp.Diff(tc.pod.Spec.Containers[0].Env[1].Value, tc.expectedTpuWorkerId); diff != "" {
 				t.Errorf("unexpected add TPU worker ID operation: %s", diff)
 			}
+			if diff := cmp.Diff(tc.expectedMegascaleEnv, tc.pod.Spec.Containers[0].Env[2:], cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("unexpected add megascale variables operation: %s", diff)
+			}
 		})
 	}
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expectations
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ExpectationsTimeout is the time after which the unobserved expectations are dropped, in case the
+// cache misses the events, so that the objects are never blocked forever.
+const ExpectationsTimeout = 5 * time.Minute
+
+type expectation struct {
+	uid       types.UID
+	timestamp time.Time
+}
+
+// Store tracks the objects deleted by the controller until its cache observes the deletions, in the fashion
+// of the ReplicaSet expectations. The controller skips the objects with pending expectations, since the
+// lagging cache would otherwise make it delete the same objects again, or act on the deleted ones.
+// The zero value is ready to use.
+type Store struct {
+	mu           sync.Mutex
+	expectations map[types.NamespacedName]expectation
+	// now is replaced in the tests.
+	now func() time.Time
+}
+
+// ExpectDeletion records the deletion of the object of the key and the uid.
+func (s *Store) ExpectDeletion(key types.NamespacedName, uid types.UID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.expectations == nil {
+		s.expectations = make(map[types.NamespacedName]expectation)
+	}
+	s.expectations[key] = expectation{uid: uid, timestamp: s.clock()}
+}
+
+// DeletionPending returns true if the object of the key and the uid is deleted by the controller, but the
+// deletion is not observed yet. The expectation is dropped once the cache holds another object of the key,
+// the caller reports the other observations, i.e. the object missing or being deleted, with DeletionObserved.
+func (s *Store) DeletionPending(key types.NamespacedName, uid types.UID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, found := s.expectations[key]
+	if !found {
+		return false
+	}
+	if e.uid != uid || s.clock().Sub(e.timestamp) > ExpectationsTimeout {
+		delete(s.expectations, key)
+		return false
+	}
+	return true
+}
+
+// DeletionObserved drops the expectation of the key.
+func (s *Store) DeletionObserved(key types.NamespacedName) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.expectations, key)
+}
+
+func (s *Store) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
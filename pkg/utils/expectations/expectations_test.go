@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package expectations
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestStore(t *testing.T) {
+	key := types.NamespacedName{Namespace: "default", Name: "test-sample-0"}
+	now := time.Now()
+	testCases := []struct {
+		name        string
+		expect      bool
+		observed    bool
+		uid         types.UID
+		elapsed     time.Duration
+		wantPending bool
+	}{
+		{
+			name: "nothing deleted",
+			uid:  "uid-1",
+		},
+		{
+			name:        "deletion not observed",
+			expect:      true,
+			uid:         "uid-1",
+			wantPending: true,
+		},
+		{
+			name:     "deletion observed",
+			expect:   true,
+			observed: true,
+			uid:      "uid-1",
+		},
+		{
+			name:   "object recreated",
+			expect: true,
+			uid:    "uid-2",
+		},
+		{
+			name:    "expectation timed out",
+			expect:  true,
+			uid:     "uid-1",
+			elapsed: ExpectationsTimeout + time.Second,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := now
+			s := &Store{now: func() time.Time { return clock }}
+			if tc.expect {
+				s.ExpectDeletion(key, "uid-1")
+			}
+			if tc.observed {
+				s.DeletionObserved(key)
+			}
+			clock = clock.Add(tc.elapsed)
+			if got := s.DeletionPending(key, tc.uid); got != tc.wantPending {
+				t.Errorf("Expected pending %t, got %t", tc.wantPending, got)
+			}
+			if got := s.DeletionPending(key, "uid-1"); got != tc.wantPending {
+				t.Errorf("Expected the expectation kept %t, got %t", tc.wantPending, got)
+			}
+		})
+	}
+}
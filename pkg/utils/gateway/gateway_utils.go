@@ -0,0 +1,110 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HTTPRouteGVK is the GroupVersionKind of the Gateway API HTTPRoute, the HTTPRoute is managed as
+// an unstructured object so Gateway API is not a hard dependency.
+var HTTPRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+
+// BackendRef is a Service the HTTPRoute sends the given weight of the traffic to.
+type BackendRef struct {
+	Name   string
+	Port   int32
+	Weight int32
+}
+
+// SetBackendRefs sets the backendRefs of all the rules of the HTTPRoute to the given Services,
+// and returns true if the HTTPRoute is changed.
+func SetBackendRefs(route *unstructured.Unstructured, backendRefs []BackendRef) (bool, error) {
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil {
+		return false, err
+	}
+	var refs []interface{}
+	for _, ref := range backendRefs {
+		refs = append(refs, map[string]interface{}{
+			"kind":   "Service",
+			"name":   ref.Name,
+			"port":   int64(ref.Port),
+			"weight": int64(ref.Weight),
+		})
+	}
+	// A route without rules matches all the requests once a rule is added.
+	if len(rules) == 0 {
+		rules = []interface{}{map[string]interface{}{}}
+	}
+	changed := false
+	for i := range rules {
+		rule, ok := rules[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if equality.Semantic.DeepEqual(rule["backendRefs"], refs) {
+			continue
+		}
+		rule["backendRefs"] = refs
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+}
+
+// RemoveBackendRefs removes the backendRefs to the given Services from all the rules of the HTTPRoute,
+// and returns true if the HTTPRoute is changed.
+func RemoveBackendRefs(route *unstructured.Unstructured, names ...string) (bool, error) {
+	rules, _, err := unstructured.NestedSlice(route.Object, "spec", "rules")
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for i := range rules {
+		rule, ok := rules[i].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		refs, _ := rule["backendRefs"].([]interface{})
+		kept := make([]interface{}, 0, len(refs))
+		for _, ref := range refs {
+			if r, ok := ref.(map[string]interface{}); ok {
+				name, _ := r["name"].(string)
+				if (r["kind"] == nil || r["kind"] == "Service") && slices.Contains(names, name) {
+					continue
+				}
+			}
+			kept = append(kept, ref)
+		}
+		if len(kept) == len(refs) {
+			continue
+		}
+		rule["backendRefs"] = kept
+		changed = true
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, unstructured.SetNestedSlice(route.Object, rules, "spec", "rules")
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSetBackendRefs(t *testing.T) {
+	backendRefs := []BackendRef{
+		{Name: "lws-stable", Port: 8080, Weight: 80},
+		{Name: "lws-canary", Port: 8080, Weight: 20},
+	}
+	wantRefs := []interface{}{
+		map[string]interface{}{"kind": "Service", "name": "lws-stable", "port": int64(8080), "weight": int64(80)},
+		map[string]interface{}{"kind": "Service", "name": "lws-canary", "port": int64(8080), "weight": int64(20)},
+	}
+
+	tests := []struct {
+		name        string
+		rules       []interface{}
+		wantChanged bool
+		wantRules   int
+	}{
+		{
+			name:        "route without rules",
+			wantChanged: true,
+			wantRules:   1,
+		},
+		{
+			name: "all the rules are updated",
+			rules: []interface{}{
+				map[string]interface{}{"matches": []interface{}{map[string]interface{}{"path": map[string]interface{}{"value": "/v1"}}}},
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "other"}}},
+			},
+			wantChanged: true,
+			wantRules:   2,
+		},
+		{
+			name:      "backendRefs up to date",
+			rules:     []interface{}{map[string]interface{}{"backendRefs": wantRefs}},
+			wantRules: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+			route.SetGroupVersionKind(HTTPRouteGVK)
+			if tc.rules != nil {
+				if err := unstructured.SetNestedSlice(route.Object, tc.rules, "spec", "rules"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			changed, err := SetBackendRefs(route, backendRefs)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.wantChanged {
+				t.Errorf("Expected changed %t, got %t", tc.wantChanged, changed)
+			}
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			if len(rules) != tc.wantRules {
+				t.Fatalf("Expected %d rules, got %d", tc.wantRules, len(rules))
+			}
+			for _, r := range rules {
+				if diff := cmp.Diff(wantRefs, r.(map[string]interface{})["backendRefs"]); diff != "" {
+					t.Errorf("Unexpected backendRefs (-want,+got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRemoveBackendRefs(t *testing.T) {
+	tests := []struct {
+		name        string
+		rules       []interface{}
+		wantChanged bool
+		wantRules   []interface{}
+	}{
+		{
+			name: "route without rules",
+		},
+		{
+			name: "backendRefs to the services removed",
+			rules: []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{
+					map[string]interface{}{"kind": "Service", "name": "lws-stable"},
+					map[string]interface{}{"name": "lws-canary"},
+					map[string]interface{}{"name": "other"},
+				}},
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "other"}}},
+			},
+			wantChanged: true,
+			wantRules: []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "other"}}},
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"name": "other"}}},
+			},
+		},
+		{
+			name: "backendRefs of another kind kept",
+			rules: []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"kind": "ServiceImport", "name": "lws-stable"}}},
+			},
+			wantRules: []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{map[string]interface{}{"kind": "ServiceImport", "name": "lws-stable"}}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			route := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+			if tc.rules != nil {
+				route.Object["spec"].(map[string]interface{})["rules"] = tc.rules
+			}
+			changed, err := RemoveBackendRefs(route, "lws-stable", "lws-canary")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if changed != tc.wantChanged {
+				t.Errorf("Expected changed %t, got %t", tc.wantChanged, changed)
+			}
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			if diff := cmp.Diff(tc.wantRules, rules); diff != "" {
+				t.Errorf("Unexpected rules (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+)
+
+const (
+	// PreGroupUpdate is the name of the hook run before an old group is torn down.
+	PreGroupUpdate = "preGroupUpdate"
+	// PostGroupUpdate is the name of the hook run once an updated group is ready.
+	PostGroupUpdate = "postGroupUpdate"
+)
+
+// Hook returns the rollout hook of the given name, nil if not set.
+func Hook(lws *leaderworkerset.LeaderWorkerSet, name string) *leaderworkerset.RolloutHook {
+	hooks := lws.Spec.RolloutStrategy.Hooks
+	if hooks == nil {
+		return nil
+	}
+	if name == PreGroupUpdate {
+		return hooks.PreGroupUpdate
+	}
+	return hooks.PostGroupUpdate
+}
+
+// JobName returns the name of the Job of the hook for the group, the Jobs are run once per
+// group and template revision. The name is a label value of the Job pods, so the lws name is
+// truncated if too long, and hashed along the template hash to keep the names unique.
+func JobName(lws *leaderworkerset.LeaderWorkerSet, name string, groupIndex int32, templateHash string) string {
+	suffix := "pre"
+	if name == PostGroupUpdate {
+		suffix = "post"
+	}
+	jobName := fmt.Sprintf("%s-%d-%s-%s", lws.Name, groupIndex, suffix, templateHash[:10])
+	if len(jobName) <= validation.DNS1123LabelMaxLength {
+		return jobName
+	}
+	tail := fmt.Sprintf("-%d-%s-%s", groupIndex, suffix, utils.Sha1Hash(lws.Name + "/" + templateHash)[:10])
+	// The truncated name must still end with an alphanumeric character.
+	return strings.TrimRight(lws.Name[:validation.DNS1123LabelMaxLength-len(tail)], "-.") + tail
+}
+
+// NewJob returns the Job of the hook for the group, the group index and the leader address
+// are injected to all the containers, the variables defined in the containers take precedence.
+func NewJob(lws *leaderworkerset.LeaderWorkerSet, name string, hook *leaderworkerset.RolloutHook, groupIndex int32, templateHash string) *batchv1.Job {
+	template := *hook.Template.DeepCopy()
+	if template.Spec.RestartPolicy == "" {
+		template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	leaderName := fmt.Sprintf("%s-%d", lws.Name, groupIndex)
+	serviceName := lws.Name
+	if utils.SubdomainPolicy(lws) == leaderworkerset.SubdomainUniquePerReplica {
+		serviceName = leaderName
+	}
+	envVars := []corev1.EnvVar{
+		{Name: leaderworkerset.LwsGroupIndex, Value: strconv.Itoa(int(groupIndex))},
+		{Name: leaderworkerset.LwsLeaderAddress, Value: fmt.Sprintf("%s.%s.%s", leaderName, serviceName, lws.Namespace)},
+	}
+	for _, containers := range [][]corev1.Container{template.Spec.InitContainers, template.Spec.Containers} {
+		for i := range containers {
+			containers[i].Env = append(append([]corev1.EnvVar{}, envVars...), containers[i].Env...)
+		}
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      JobName(lws, name, groupIndex, templateHash),
+			Namespace: lws.Namespace,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.GroupIndexLabelKey:      strconv.Itoa(int(groupIndex)),
+				leaderworkerset.TemplateRevisionHashKey: templateHash,
+				leaderworkerset.RolloutHookLabelKey:     name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			Template:              template,
+			BackoffLimit:          hook.BackoffLimit,
+			ActiveDeadlineSeconds: hook.ActiveDeadlineSeconds,
+		},
+	}
+}
+
+// JobFinished returns true and the condition type if the Job is complete or failed.
+func JobFinished(job *batchv1.Job) (bool, batchv1.JobConditionType) {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true, c.Type
+		}
+	}
+	return false, ""
+}
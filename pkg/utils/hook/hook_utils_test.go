@@ -0,0 +1,143 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hook
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/test/testutils"
+)
+
+func TestNewJob(t *testing.T) {
+	tests := []struct {
+		name            string
+		subdomainPolicy leaderworkerset.SubdomainPolicy
+		restartPolicy   corev1.RestartPolicy
+		wantEnv         []corev1.EnvVar
+		wantRestart     corev1.RestartPolicy
+	}{
+		{
+			name:            "shared subdomain",
+			subdomainPolicy: leaderworkerset.SubdomainShared,
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsGroupIndex, Value: "2"},
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-2.test-sample.default"},
+				{Name: "MODEL", Value: "llama"},
+			},
+			wantRestart: corev1.RestartPolicyNever,
+		},
+		{
+			name:            "subdomain per replica",
+			subdomainPolicy: leaderworkerset.SubdomainUniquePerReplica,
+			restartPolicy:   corev1.RestartPolicyOnFailure,
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsGroupIndex, Value: "2"},
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-2.test-sample-2.default"},
+				{Name: "MODEL", Value: "llama"},
+			},
+			wantRestart: corev1.RestartPolicyOnFailure,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.NetworkConfig = &leaderworkerset.NetworkConfig{SubdomainPolicy: tc.subdomainPolicy}
+			hook := &leaderworkerset.RolloutHook{Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					RestartPolicy: tc.restartPolicy,
+					Containers:    []corev1.Container{{Name: "warmup", Env: []corev1.EnvVar{{Name: "MODEL", Value: "llama"}}}},
+				},
+			}}
+			job := NewJob(lws, PostGroupUpdate, hook, 2, "0123456789abcdef")
+			if job.Name != "test-sample-2-post-0123456789" {
+				t.Errorf("Unexpected job name %s", job.Name)
+			}
+			if job.Labels[leaderworkerset.RolloutHookLabelKey] != PostGroupUpdate {
+				t.Errorf("Expected the job to be labeled with the hook name, got %v", job.Labels)
+			}
+			if diff := cmp.Diff(tc.wantEnv, job.Spec.Template.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("Unexpected env (-want,+got):\n%s", diff)
+			}
+			if job.Spec.Template.Spec.RestartPolicy != tc.wantRestart {
+				t.Errorf("Expected restart policy %s, got %s", tc.wantRestart, job.Spec.Template.Spec.RestartPolicy)
+			}
+			if len(hook.Template.Spec.Containers[0].Env) != 1 {
+				t.Errorf("Expected the hook template not to be mutated")
+			}
+		})
+	}
+}
+
+func TestJobName(t *testing.T) {
+	const templateHash = "0123456789abcdef"
+	longName := strings.Repeat("a", 60)
+	tests := []struct {
+		name       string
+		lwsName    string
+		hook       string
+		groupIndex int32
+		want       string
+	}{
+		{
+			name:       "pre group update",
+			lwsName:    "test-sample",
+			hook:       PreGroupUpdate,
+			groupIndex: 2,
+			want:       "test-sample-2-pre-0123456789",
+		},
+		{
+			name:       "post group update",
+			lwsName:    "test-sample",
+			hook:       PostGroupUpdate,
+			groupIndex: 2,
+			want:       "test-sample-2-post-0123456789",
+		},
+		{
+			name:       "long lws name truncated",
+			lwsName:    longName,
+			hook:       PostGroupUpdate,
+			groupIndex: 100,
+			want:       longName[:43] + "-100-post-" + utils.Sha1Hash(longName + "/" + templateHash)[:10],
+		},
+		{
+			name:       "truncated lws name ending with a separator",
+			lwsName:    strings.Repeat("a", 41) + ".-" + strings.Repeat("b", 17),
+			hook:       PostGroupUpdate,
+			groupIndex: 100,
+			want:       strings.Repeat("a", 41) + "-100-post-" + utils.Sha1Hash(strings.Repeat("a", 41) + ".-" + strings.Repeat("b", 17) + "/" + templateHash)[:10],
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Name = tc.lwsName
+			got := JobName(lws, tc.hook, tc.groupIndex, templateHash)
+			if got != tc.want {
+				t.Errorf("Expected job name %q, got %q", tc.want, got)
+			}
+			if len(got) > 63 {
+				t.Errorf("Expected job name of at most 63 characters, got %d", len(got))
+			}
+		})
+	}
+}
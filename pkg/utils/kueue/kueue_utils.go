@@ -0,0 +1,194 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kueue
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+)
+
+const (
+	// QueueNameLabelKey is the label key of the lws to specify the Kueue LocalQueue
+	// the lws is submitted to, groups are only created once the Workload is admitted.
+	QueueNameLabelKey = "kueue.x-k8s.io/queue-name"
+
+	// WorkloadAdmittedCondition is the condition type of the Workload once admitted.
+	WorkloadAdmittedCondition = "Admitted"
+
+	leaderPodSetName = "leader"
+	workerPodSetName = "workers"
+)
+
+// WorkloadGVK is the GroupVersionKind of the Kueue Workload, the Workload is managed as
+// an unstructured object so Kueue is not a hard dependency.
+var WorkloadGVK = schema.GroupVersionKind{Group: "kueue.x-k8s.io", Version: "v1beta1", Kind: "Workload"}
+
+// QueueName returns the Kueue LocalQueue name of the lws, empty if not queued.
+func QueueName(lws *leaderworkerset.LeaderWorkerSet) string {
+	return lws.Labels[QueueNameLabelKey]
+}
+
+// WorkloadName returns the name of the Workload of the lws.
+func WorkloadName(lws *leaderworkerset.LeaderWorkerSet) string {
+	return fmt.Sprintf("leaderworkerset-%s", lws.Name)
+}
+
+// NewWorkload returns a Workload covering the aggregate resources of all the groups of the lws,
+// the leaders and the workers are counted as separate podSets.
+func NewWorkload(lws *leaderworkerset.LeaderWorkerSet) (*unstructured.Unstructured, error) {
+	podSets, err := podSets(lws)
+	if err != nil {
+		return nil, err
+	}
+	workload := &unstructured.Unstructured{}
+	workload.SetGroupVersionKind(WorkloadGVK)
+	workload.SetName(WorkloadName(lws))
+	workload.SetNamespace(lws.Namespace)
+	workload.SetLabels(map[string]string{leaderworkerset.SetNameLabelKey: lws.Name})
+	if err := unstructured.SetNestedField(workload.Object, QueueName(lws), "spec", "queueName"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedSlice(workload.Object, podSets, "spec", "podSets"); err != nil {
+		return nil, err
+	}
+	return workload, nil
+}
+
+// UpdateWorkload sets the podSets of the Workload to the ones of the lws, e.g. once the lws is scaled.
+func UpdateWorkload(workload *unstructured.Unstructured, lws *leaderworkerset.LeaderWorkerSet) error {
+	podSets, err := podSets(lws)
+	if err != nil {
+		return err
+	}
+	return unstructured.SetNestedSlice(workload.Object, podSets, "spec", "podSets")
+}
+
+// WorkloadAdmitted returns true if the Workload is admitted by Kueue.
+func WorkloadAdmitted(workload *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(workload.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if condition["type"] == WorkloadAdmittedCondition && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
+
+// AdmittedReplicas returns the number of groups admitted with the Workload, i.e. the count of the leader
+// podSet in the admission, or in the spec of the Workload if the admission doesn't record the counts.
+func AdmittedReplicas(workload *unstructured.Unstructured) int32 {
+	assignments, _, _ := unstructured.NestedSlice(workload.Object, "status", "admission", "podSetAssignments")
+	if count, found := leaderPodSetCount(assignments); found {
+		return count
+	}
+	podSets, _, _ := unstructured.NestedSlice(workload.Object, "spec", "podSets")
+	count, _ := leaderPodSetCount(podSets)
+	return count
+}
+
+func leaderPodSetCount(podSets []interface{}) (int32, bool) {
+	for _, p := range podSets {
+		podSet, ok := p.(map[string]interface{})
+		if !ok || podSet["name"] != leaderPodSetName {
+			continue
+		}
+		count, found, _ := unstructured.NestedInt64(podSet, "count")
+		return int32(count), found
+	}
+	return 0, false
+}
+
+// WorkloadMatches returns true if the podSet counts of the Workload match the lws, the Workload
+// must be updated otherwise.
+func WorkloadMatches(workload *unstructured.Unstructured, lws *leaderworkerset.LeaderWorkerSet) bool {
+	got, _, _ := unstructured.NestedSlice(workload.Object, "spec", "podSets")
+	want := podSetCounts(lws)
+	if len(got) != len(want) {
+		return false
+	}
+	for i, p := range got {
+		podSet, ok := p.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		count, _, _ := unstructured.NestedInt64(podSet, "count")
+		if podSet["name"] != want[i].name || count != int64(want[i].count) {
+			return false
+		}
+	}
+	return true
+}
+
+type podSetCount struct {
+	name     string
+	count    int32
+	template corev1.PodTemplateSpec
+}
+
+// podSetCounts returns the podSets of the lws, the workers of every named worker template are counted
+// as a podSet of the template name, the remaining workers as the workers podSet.
+func podSetCounts(lws *leaderworkerset.LeaderWorkerSet) []podSetCount {
+	replicas := utils.TotalReplicas(lws)
+	leaderTemplate := lws.Spec.LeaderWorkerTemplate.WorkerTemplate
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		leaderTemplate = *lws.Spec.LeaderWorkerTemplate.LeaderTemplate
+	}
+	counts := []podSetCount{{name: leaderPodSetName, count: replicas, template: leaderTemplate}}
+	workers := *lws.Spec.LeaderWorkerTemplate.Size - 1
+	for _, t := range lws.Spec.LeaderWorkerTemplate.WorkerTemplates {
+		counts = append(counts, podSetCount{name: t.Name, count: replicas * t.Count, template: t.Template})
+		workers -= t.Count
+	}
+	if workers > 0 {
+		counts = append(counts, podSetCount{name: workerPodSetName, count: replicas * workers, template: lws.Spec.LeaderWorkerTemplate.WorkerTemplate})
+	}
+	return counts
+}
+
+func podSets(lws *leaderworkerset.LeaderWorkerSet) ([]interface{}, error) {
+	var podSets []interface{}
+	for _, c := range podSetCounts(lws) {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&corev1.PodTemplateSpec{Spec: c.template.Spec})
+		if err != nil {
+			return nil, err
+		}
+		podSets = append(podSets, map[string]interface{}{
+			"name":     c.name,
+			"count":    int64(c.count),
+			"template": obj,
+		})
+	}
+	return podSets, nil
+}
+
+// WorkloadCRDInstalled returns true if the Workload CRD is served in the cluster.
+func WorkloadCRDInstalled(mapper meta.RESTMapper) bool {
+	_, err := mapper.RESTMapping(WorkloadGVK.GroupKind(), WorkloadGVK.Version)
+	return err == nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kueue
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/testutils"
+)
+
+func TestNewWorkload(t *testing.T) {
+	tests := []struct {
+		name            string
+		size            int
+		workerTemplates []leaderworkerset.NamedWorkerTemplate
+		wantCounts      map[string]int64
+	}{
+		{
+			name:       "leader and workers",
+			size:       4,
+			wantCounts: map[string]int64{leaderPodSetName: 2, workerPodSetName: 6},
+		},
+		{
+			name:            "named worker templates",
+			size:            4,
+			workerTemplates: []leaderworkerset.NamedWorkerTemplate{{Name: "prefill", Count: 1}},
+			wantCounts:      map[string]int64{leaderPodSetName: 2, "prefill": 2, workerPodSetName: 4},
+		},
+		{
+			name:            "named worker templates covering all the workers",
+			size:            3,
+			workerTemplates: []leaderworkerset.NamedWorkerTemplate{{Name: "prefill", Count: 1}, {Name: "decode", Count: 1}},
+			wantCounts:      map[string]int64{leaderPodSetName: 2, "prefill": 2, "decode": 2},
+		},
+		{
+			name:       "leader only",
+			size:       1,
+			wantCounts: map[string]int64{leaderPodSetName: 2},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Replica(2).Size(tc.size).Obj()
+			lws.Labels = map[string]string{QueueNameLabelKey: "queue"}
+			lws.Spec.LeaderWorkerTemplate.WorkerTemplates = tc.workerTemplates
+			workload, err := NewWorkload(lws)
+			if err != nil {
+				t.Fatalf("Failed to build workload: %v", err)
+			}
+			if queueName, _, _ := unstructured.NestedString(workload.Object, "spec", "queueName"); queueName != "queue" {
+				t.Errorf("Expected queue name queue, got %s", queueName)
+			}
+			podSets, _, _ := unstructured.NestedSlice(workload.Object, "spec", "podSets")
+			gotCounts := map[string]int64{}
+			for _, p := range podSets {
+				podSet := p.(map[string]interface{})
+				gotCounts[podSet["name"].(string)] = podSet["count"].(int64)
+			}
+			if diff := cmp.Diff(tc.wantCounts, gotCounts); diff != "" {
+				t.Errorf("Unexpected podSet counts (-want,+got):\n%s", diff)
+			}
+			if !WorkloadMatches(workload, lws) {
+				t.Errorf("Expected workload to match the lws")
+			}
+			lws.Spec.Replicas = ptr.To[int32](3)
+			if WorkloadMatches(workload, lws) {
+				t.Errorf("Expected workload not to match the scaled lws")
+			}
+			if err := UpdateWorkload(workload, lws); err != nil {
+				t.Fatalf("Failed to update workload: %v", err)
+			}
+			if !WorkloadMatches(workload, lws) {
+				t.Errorf("Expected the updated workload to match the scaled lws")
+			}
+		})
+	}
+}
+
+func TestWorkloadAdmitted(t *testing.T) {
+	tests := []struct {
+		name         string
+		conditions   []interface{}
+		wantAdmitted bool
+	}{
+		{
+			name: "admitted",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "QuotaReserved", "status": "True"},
+				map[string]interface{}{"type": WorkloadAdmittedCondition, "status": "True"},
+			},
+			wantAdmitted: true,
+		},
+		{
+			name: "evicted",
+			conditions: []interface{}{
+				map[string]interface{}{"type": WorkloadAdmittedCondition, "status": "False"},
+			},
+		},
+		{
+			name: "pending",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workload := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tc.conditions != nil {
+				if err := unstructured.SetNestedSlice(workload.Object, tc.conditions, "status", "conditions"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := WorkloadAdmitted(workload); got != tc.wantAdmitted {
+				t.Errorf("Expected admitted %t, got %t", tc.wantAdmitted, got)
+			}
+		})
+	}
+}
+
+func TestAdmittedReplicas(t *testing.T) {
+	tests := []struct {
+		name         string
+		podSets      []interface{}
+		assignments  []interface{}
+		wantReplicas int32
+	}{
+		{
+			name: "counts of the spec",
+			podSets: []interface{}{
+				map[string]interface{}{"name": leaderPodSetName, "count": int64(2)},
+				map[string]interface{}{"name": workerPodSetName, "count": int64(6)},
+			},
+			wantReplicas: 2,
+		},
+		{
+			name: "counts of the admission",
+			podSets: []interface{}{
+				map[string]interface{}{"name": leaderPodSetName, "count": int64(3)},
+				map[string]interface{}{"name": workerPodSetName, "count": int64(9)},
+			},
+			assignments: []interface{}{
+				map[string]interface{}{"name": workerPodSetName, "count": int64(6)},
+				map[string]interface{}{"name": leaderPodSetName, "count": int64(2)},
+			},
+			wantReplicas: 2,
+		},
+		{
+			name: "admission without counts",
+			podSets: []interface{}{
+				map[string]interface{}{"name": leaderPodSetName, "count": int64(2)},
+			},
+			assignments: []interface{}{
+				map[string]interface{}{"name": leaderPodSetName},
+			},
+			wantReplicas: 2,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			workload := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if err := unstructured.SetNestedSlice(workload.Object, tc.podSets, "spec", "podSets"); err != nil {
+				t.Fatal(err)
+			}
+			if tc.assignments != nil {
+				if err := unstructured.SetNestedSlice(workload.Object, tc.assignments, "status", "admission", "podSetAssignments"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := AdmittedReplicas(workload); got != tc.wantReplicas {
+				t.Errorf("Expected admitted replicas %d, got %d", tc.wantReplicas, got)
+			}
+		})
+	}
+}
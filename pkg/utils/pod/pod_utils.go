@@ -17,11 +17,29 @@ limitations under the License.
 package pod
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+)
+
+const (
+	// evictionByEvictionAPIReason is the reason of the DisruptionTarget condition set by the eviction API.
+	evictionByEvictionAPIReason = "EvictionByEvictionAPI"
+	// deletionByTaintManagerReason is the reason of the DisruptionTarget condition set by the taint manager
+	// on NoExecute taints.
+	deletionByTaintManagerReason = "DeletionByTaintManager"
 )
 
 // ContainerRestarted return true when there is any container in the pod that gets restarted
@@ -43,21 +61,210 @@ func ContainerRestarted(pod corev1.Pod) bool {
 	return false
 }
 
+// ContainerRestarts returns the total number of restarts of the containers in the pod.
+func ContainerRestarts(pod corev1.Pod) int32 {
+	var restarts int32
+	for _, stat := range pod.Status.ContainerStatuses {
+		restarts += stat.RestartCount
+	}
+	return restarts
+}
+
+// ContainerSucceeded checks if the container of the given name exited with 0, either in its current state or
+// in its last termination state once restarted.
+func ContainerSucceeded(pod corev1.Pod, name string) bool {
+	for _, stat := range pod.Status.ContainerStatuses {
+		if stat.Name != name {
+			continue
+		}
+		for _, terminated := range []*corev1.ContainerStateTerminated{stat.State.Terminated, stat.LastTerminationState.Terminated} {
+			if terminated != nil && terminated.ExitCode == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NotReadySince returns the time since when the pod is not ready once all its containers are started,
+// it returns false if the pod is ready or any of its containers is not started yet.
+func NotReadySince(pod corev1.Pod) (time.Time, bool) {
+	condition := getPodReadyCondition(pod.Status)
+	if condition == nil || condition.Status == corev1.ConditionTrue || len(pod.Status.ContainerStatuses) == 0 {
+		return time.Time{}, false
+	}
+	since := condition.LastTransitionTime.Time
+	for _, stat := range pod.Status.ContainerStatuses {
+		if stat.Started == nil || !*stat.Started || stat.State.Running == nil {
+			return time.Time{}, false
+		}
+		if stat.State.Running.StartedAt.After(since) {
+			since = stat.State.Running.StartedAt.Time
+		}
+	}
+	return since, true
+}
+
 // PodDeleted checks if the worker pod has been deleted
 func PodDeleted(pod corev1.Pod) bool {
 	return pod.DeletionTimestamp != nil
 }
 
+// PodFailed checks if the pod has reached the failed phase, e.g. evicted or
+// all containers terminated with failures under restartPolicy Never.
+func PodFailed(pod corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodFailed
+}
+
+// PodEvicted checks if the pod is being terminated by the eviction API, e.g. by a node drain.
+func PodEvicted(pod corev1.Pod) bool {
+	_, condition := getPodCondition(&pod.Status, corev1.DisruptionTarget)
+	return condition != nil && condition.Status == corev1.ConditionTrue && condition.Reason == evictionByEvictionAPIReason
+}
+
+// PodPreemptedByScheduler checks if the pod is being terminated by the scheduler for higher priority pods.
+func PodPreemptedByScheduler(pod corev1.Pod) bool {
+	_, condition := getPodCondition(&pod.Status, corev1.DisruptionTarget)
+	return condition != nil && condition.Status == corev1.ConditionTrue && condition.Reason == corev1.PodReasonPreemptionByScheduler
+}
+
+// PodPreempted checks if the pod is being terminated since its node is going away, either by the graceful
+// node shutdown of the kubelet or by the taint manager.
+func PodPreempted(pod corev1.Pod) bool {
+	_, condition := getPodCondition(&pod.Status, corev1.DisruptionTarget)
+	return condition != nil && condition.Status == corev1.ConditionTrue &&
+		(condition.Reason == corev1.PodReasonTerminationByKubelet || condition.Reason == deletionByTaintManagerReason)
+}
+
+// MatchFailurePolicyRule returns the first rule matching the exit codes of the terminated containers
+// or the condition reasons of the pod, nil if no rule matches.
+func MatchFailurePolicyRule(rules []leaderworkerset.FailurePolicyRule, pod corev1.Pod) *leaderworkerset.FailurePolicyRule {
+	for i := range rules {
+		if failurePolicyRuleMatches(rules[i], pod) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+func failurePolicyRuleMatches(rule leaderworkerset.FailurePolicyRule, pod corev1.Pod) bool {
+	for _, reason := range rule.OnPodConditionReasons {
+		if pod.Status.Reason == reason {
+			return true
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Reason == reason {
+				return true
+			}
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, exitCode := range rule.OnExitCodes {
+		for _, status := range statuses {
+			for _, terminated := range []*corev1.ContainerStateTerminated{status.State.Terminated, status.LastTerminationState.Terminated} {
+				if terminated != nil && terminated.ExitCode == exitCode {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// AddSchedulingGate adds the scheduling gate to the pod if not exists.
+func AddSchedulingGate(pod *corev1.Pod, name string) {
+	for _, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == name {
+			return
+		}
+	}
+	pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates, corev1.PodSchedulingGate{Name: name})
+}
+
+// RemoveSchedulingGate removes the scheduling gate from the pod, and returns true if the gate exists.
+func RemoveSchedulingGate(pod *corev1.Pod, name string) bool {
+	for i, gate := range pod.Spec.SchedulingGates {
+		if gate.Name == name {
+			pod.Spec.SchedulingGates = append(pod.Spec.SchedulingGates[:i], pod.Spec.SchedulingGates[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AddReadinessGate adds the readiness gate of the condition type to the pod if not exists.
+func AddReadinessGate(pod *corev1.Pod, conditionType corev1.PodConditionType) {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == conditionType {
+			return
+		}
+	}
+	pod.Spec.ReadinessGates = append(pod.Spec.ReadinessGates, corev1.PodReadinessGate{ConditionType: conditionType})
+}
+
+// SetPodCondition sets the status of the pod condition, and returns true if the condition is changed.
+// The transition time is only updated when the status changes.
+func SetPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType, status corev1.ConditionStatus, reason string) bool {
+	_, condition := getPodCondition(&pod.Status, conditionType)
+	if condition == nil {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type:               conditionType,
+			Status:             status,
+			Reason:             reason,
+			LastTransitionTime: metav1.Now(),
+		})
+		return true
+	}
+	if condition.Status == status && condition.Reason == reason {
+		return false
+	}
+	if condition.Status != status {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Status = status
+	condition.Reason = reason
+	return true
+}
+
+// GetPodCondition returns the condition of the pod of the given type, or nil if not found.
+func GetPodCondition(pod corev1.Pod, conditionType corev1.PodConditionType) *corev1.PodCondition {
+	_, condition := getPodCondition(&pod.Status, conditionType)
+	return condition
+}
+
+// Draining returns true if the leader pod is drained before its group is deleted, i.e. the condition
+// of its serving readiness gate is false.
+func Draining(pod corev1.Pod) bool {
+	condition := GetPodCondition(pod, leaderworkerset.ServingConditionType)
+	return condition != nil && condition.Status == corev1.ConditionFalse
+}
+
 // LeaderPod check is the pod is a leader pod
 func LeaderPod(pod corev1.Pod) bool {
 	return pod.Labels[leaderworkerset.WorkerIndexLabelKey] == "0"
 }
 
+// SkipInjection returns true if the pod opts out of the lws management with the skip injection annotation,
+// e.g. the debug clones carrying the set label.
+func SkipInjection(pod corev1.Pod) bool {
+	return pod.Annotations[leaderworkerset.SkipInjectionAnnotationKey] == "true"
+}
+
 // PodRunningAndReady checks if the pod condition is running and marked as ready.
 func PodRunningAndReady(pod corev1.Pod) bool {
 	return pod.Status.Phase == corev1.PodRunning && podReady(pod)
 }
 
+// PodAvailable checks if the pod has been ready for at least minReadySeconds.
+func PodAvailable(pod corev1.Pod, minReadySeconds int32, now time.Time) bool {
+	condition := getPodReadyCondition(pod.Status)
+	if condition == nil || condition.Status != corev1.ConditionTrue {
+		return false
+	}
+	minReadySecondsDuration := time.Duration(minReadySeconds) * time.Second
+	return minReadySeconds == 0 || !condition.LastTransitionTime.IsZero() && condition.LastTransitionTime.Add(minReadySecondsDuration).Before(now)
+}
+
 func podReady(pod corev1.Pod) bool {
 	return podReadyConditionTrue(pod.Status)
 }
@@ -100,31 +307,328 @@ func addEnvVarIfNotExists(c *corev1.Container, e corev1.EnvVar) {
 	c.Env = append([]corev1.EnvVar{e}, c.Env...)
 }
 
-// AddLWSVariables adds LWS_LEADER_ADDRESS environment variable to every container.
-func AddLWSVariables(pod *corev1.Pod) error {
+// AddModelPrefetch injects the init container downloading the model artifacts into a shared volume,
+// which is mounted to all the containers. The init container runs before the other init containers.
+func AddModelPrefetch(spec *corev1.PodSpec, prefetch *leaderworkerset.ModelPrefetch) {
+	mountPath := prefetch.MountPath
+	if mountPath == "" {
+		mountPath = leaderworkerset.DefaultModelPath
+	}
+	volumeMount := corev1.VolumeMount{Name: leaderworkerset.ModelPrefetchVolumeName, MountPath: mountPath}
+	modelPathEnvVar := corev1.EnvVar{Name: leaderworkerset.LwsModelPath, Value: mountPath}
+	for _, c := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range c {
+			c[i].VolumeMounts = append(c[i].VolumeMounts, volumeMount)
+			addEnvVarIfNotExists(&c[i], modelPathEnvVar)
+		}
+	}
+
+	spec.InitContainers = append([]corev1.Container{{
+		Name:  leaderworkerset.ModelPrefetchContainerName,
+		Image: prefetch.Image,
+		Env: []corev1.EnvVar{
+			{Name: leaderworkerset.LwsModelURI, Value: prefetch.URI},
+			modelPathEnvVar,
+			{
+				Name: leaderworkerset.LwsGroupIndex,
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.labels['%s']", leaderworkerset.GroupIndexLabelKey),
+				}},
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{volumeMount},
+	}}, spec.InitContainers...)
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         leaderworkerset.ModelPrefetchVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: prefetch.SizeLimit}},
+	})
+}
+
+// AddGroupSpreadConstraints injects the topologySpreadConstraints selecting the leader pods of the lws into the
+// leader pod spec, the existing constraints of the same topology key are kept as they are.
+func AddGroupSpreadConstraints(spec *corev1.PodSpec, lwsName string, constraints []leaderworkerset.GroupSpreadConstraint) {
+	existing := sets.New[string]()
+	for _, c := range spec.TopologySpreadConstraints {
+		existing.Insert(c.TopologyKey)
+	}
+	for _, c := range constraints {
+		if existing.Has(c.TopologyKey) {
+			continue
+		}
+		maxSkew := c.MaxSkew
+		if maxSkew == 0 {
+			maxSkew = 1
+		}
+		whenUnsatisfiable := c.WhenUnsatisfiable
+		if whenUnsatisfiable == "" {
+			whenUnsatisfiable = corev1.ScheduleAnyway
+		}
+		spec.TopologySpreadConstraints = append(spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           maxSkew,
+			TopologyKey:       c.TopologyKey,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     lwsName,
+				leaderworkerset.WorkerIndexLabelKey: "0",
+			}},
+		})
+	}
+}
+
+// EnvTemplateData is the group metadata the templated env vars are expanded with.
+type EnvTemplateData struct {
+	GroupIndex    string
+	WorkerIndex   string
+	Size          string
+	LeaderAddress string
+}
+
+// envTemplateData returns the group metadata of the pod from its labels and annotations.
+func envTemplateData(pod *corev1.Pod) (*EnvTemplateData, error) {
 	lwsName, found := pod.Labels[leaderworkerset.SetNameLabelKey]
 	if !found {
-		return fmt.Errorf("Failure constructing environment variables, no name label found for pod %v", pod.Name)
+		return nil, fmt.Errorf("Failure constructing environment variables, no name label found for pod %v", pod.Name)
 	}
 
 	groupIndex, found := pod.Labels[leaderworkerset.GroupIndexLabelKey]
 	if !found {
-		return fmt.Errorf("Failure constructing environment variables, no group index label found for pod %v", pod.Name)
+		return nil, fmt.Errorf("Failure constructing environment variables, no group index label found for pod %v", pod.Name)
+	}
+
+	workerIndex, found := pod.Labels[leaderworkerset.WorkerIndexLabelKey]
+	if !found {
+		return nil, fmt.Errorf("Failure constructing environment variables, no worker index label found for pod %v", pod.Name)
+	}
+
+	size, found := pod.Annotations[leaderworkerset.SizeAnnotationKey]
+	if !found {
+		return nil, fmt.Errorf("Failure constructing environment variables, no size annotation found for pod %v", pod.Name)
+	}
+
+	// The headless service name is assumed to be the same as the LWS name, or the leader pod name
+	// with the UniquePerReplica subdomain policy.
+	// See function [reconcileHeadlessService](sigs.k8s.io/lws/pkg/controllers/leaderworkerset_controller.go).
+	leaderName := fmt.Sprintf("%s-%s", lwsName, groupIndex)
+	serviceName := lwsName
+	if pod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] == string(leaderworkerset.SubdomainUniquePerReplica) {
+		serviceName = leaderName
+	}
+
+	return &EnvTemplateData{
+		GroupIndex:    groupIndex,
+		WorkerIndex:   workerIndex,
+		Size:          size,
+		LeaderAddress: fmt.Sprintf("%s.%s.%s", leaderName, serviceName, pod.ObjectMeta.Namespace),
+	}, nil
+}
+
+// AddLWSVariables adds LWS_LEADER_ADDRESS, LWS_GROUP_SIZE and LWS_WORKER_INDEX environment variables to every container,
+// as well as LWS_WORKER_TEMPLATE and LWS_WORKER_TEMPLATE_INDEX for the workers created from the named worker templates.
+func AddLWSVariables(pod *corev1.Pod) error {
+	data, err := envTemplateData(pod)
+	if err != nil {
+		return err
 	}
 
-	// The headless service name is assumed to be the same as the LWS name.
-	// See function [createHeadlessServiceIfNotExists](sigs.k8s.io/lws/pkg/controllers/leaderworkerset_controller.go).
 	leaderAddressEnvVar := corev1.EnvVar{
 		Name:  leaderworkerset.LwsLeaderAddress,
-		Value: fmt.Sprintf("%s-%s.%s.%s", lwsName, groupIndex, lwsName, pod.ObjectMeta.Namespace),
+		Value: data.LeaderAddress,
+	}
+	groupSizeEnvVar := corev1.EnvVar{
+		Name:  leaderworkerset.LwsGroupSize,
+		Value: data.Size,
+	}
+	workerIndexEnvVar := corev1.EnvVar{
+		Name:  leaderworkerset.LwsWorkerIndex,
+		Value: data.WorkerIndex,
 	}
 
-	for i := range pod.Spec.Containers {
-		addEnvVarIfNotExists(&pod.Spec.Containers[i], leaderAddressEnvVar)
+	envVars := []corev1.EnvVar{workerIndexEnvVar, groupSizeEnvVar, leaderAddressEnvVar}
+	if templateName, found := pod.Labels[leaderworkerset.WorkerTemplateLabelKey]; found {
+		envVars = append([]corev1.EnvVar{
+			{Name: leaderworkerset.LwsWorkerTemplateIndex, Value: pod.Labels[leaderworkerset.WorkerTemplateIndexLabelKey]},
+			{Name: leaderworkerset.LwsWorkerTemplate, Value: templateName},
+		}, envVars...)
+	}
+
+	// Env vars are prepended, so add the leader address last to keep it as the first one.
+	for _, envVar := range envVars {
+		for i := range pod.Spec.Containers {
+			addEnvVarIfNotExists(&pod.Spec.Containers[i], envVar)
+		}
+		for i := range pod.Spec.InitContainers {
+			addEnvVarIfNotExists(&pod.Spec.InitContainers[i], envVar)
+		}
+	}
+
+	return nil
+}
+
+// ExpandEnvTemplate expands the value of a templated env var with the group metadata.
+func ExpandEnvTemplate(value string, data EnvTemplateData) (string, error) {
+	tmpl, err := template.New("env").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// AddTemplatedEnvVars expands the templated env vars with the group metadata of the pod and adds them to every container.
+func AddTemplatedEnvVars(pod *corev1.Pod, envs []leaderworkerset.TemplatedEnvVar) error {
+	data, err := envTemplateData(pod)
+	if err != nil {
+		return err
 	}
+	// Env vars are prepended, so add them in reverse order to keep the declared order.
+	for i := len(envs) - 1; i >= 0; i-- {
+		value, err := ExpandEnvTemplate(envs[i].Value, *data)
+		if err != nil {
+			return fmt.Errorf("expanding env var %s: %w", envs[i].Name, err)
+		}
+		for _, container := range containers(pod) {
+			addEnvVarIfNotExists(container, corev1.EnvVar{Name: envs[i].Name, Value: value})
+		}
+	}
+	return nil
+}
+
+// EnvVarNames returns the env var names of each container and init container of the pod, keyed by the container name.
+func EnvVarNames(pod *corev1.Pod) map[string]sets.Set[string] {
+	names := make(map[string]sets.Set[string])
+	for _, container := range containers(pod) {
+		names[container.Name] = sets.New[string]()
+		for _, env := range container.Env {
+			names[container.Name].Insert(env.Name)
+		}
+	}
+	return names
+}
+
+// RenameInjectedEnvVars renames the env vars which are not part of the existing names, i.e. the ones
+// injected since the names were recorded, they are either renamed explicitly or prefixed.
+func RenameInjectedEnvVars(pod *corev1.Pod, existing map[string]sets.Set[string], naming leaderworkerset.EnvVarNaming) {
+	for _, container := range containers(pod) {
+		for i, env := range container.Env {
+			if existing[container.Name].Has(env.Name) {
+				continue
+			}
+			if name, found := naming.Renames[env.Name]; found {
+				container.Env[i].Name = name
+			} else {
+				container.Env[i].Name = naming.Prefix + env.Name
+			}
+		}
+	}
+}
+
+func containers(pod *corev1.Pod) []*corev1.Container {
+	var containers []*corev1.Container
 	for i := range pod.Spec.InitContainers {
-		addEnvVarIfNotExists(&pod.Spec.InitContainers[i], leaderAddressEnvVar)
+		containers = append(containers, &pod.Spec.InitContainers[i])
+	}
+	for i := range pod.Spec.Containers {
+		containers = append(containers, &pod.Spec.Containers[i])
+	}
+	return containers
+}
+
+// InjectEnvVars injects the accelerator, LWS and templated env vars, the accelerator and LWS env vars
+// are renamed afterwards if the lws customizes the naming.
+func InjectEnvVars(pod *corev1.Pod, size int) error {
+	existing := EnvVarNames(pod)
+	// injecting accelerator env vars if needed
+	if pod.Annotations[leaderworkerset.DisableAcceleratorEnvAnnotationKey] != "true" {
+		if err := acceleratorutils.InjectVariables(pod, acceleratorutils.GroupMeta{Size: size}); err != nil {
+			return err
+		}
+	}
+
+	if err := AddLWSVariables(pod); err != nil {
+		return err
+	}
+
+	if value, found := pod.Annotations[leaderworkerset.EnvVarNamingAnnotationKey]; found {
+		var naming leaderworkerset.EnvVarNaming
+		if err := json.Unmarshal([]byte(value), &naming); err != nil {
+			return err
+		}
+		RenameInjectedEnvVars(pod, existing, naming)
+	}
+
+	// User-defined env vars are not subject to the naming.
+	if value, found := pod.Annotations[leaderworkerset.TemplatedEnvAnnotationKey]; found {
+		var envs []leaderworkerset.TemplatedEnvVar
+		if err := json.Unmarshal([]byte(value), &envs); err != nil {
+			return err
+		}
+		if err := AddTemplatedEnvVars(pod, envs); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// StampEnvVars injects the env vars into the pod template for the pods created without the pod webhook. The env vars
+// are injected into two pods created from the template by newPod with the ordinals 1 and 2: the ones of the same value
+// are added as is, the ones valued the ordinals are read from the pod index label set by the StatefulSet controller,
+// and the ones containing the pod names are expanded from the LWS_POD_NAME env var. The other ones vary in a way the
+// template can't express and are left out.
+func StampEnvVars(template *corev1.PodTemplateSpec, newPod func(ordinal int) *corev1.Pod) error {
+	size, err := strconv.Atoi(template.Annotations[leaderworkerset.SizeAnnotationKey])
+	if err != nil {
+		return err
+	}
+	first, second := newPod(1), newPod(2)
+	for _, pod := range []*corev1.Pod{first, second} {
+		if err := InjectEnvVars(pod, size); err != nil {
+			return err
+		}
+	}
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = stampedEnvVars(first.Spec.Containers[i].Env, second.Spec.Containers[i].Env, first.Name, second.Name)
+	}
+	for i := range template.Spec.InitContainers {
+		template.Spec.InitContainers[i].Env = stampedEnvVars(first.Spec.InitContainers[i].Env, second.Spec.InitContainers[i].Env, first.Name, second.Name)
+	}
 	return nil
 }
+
+// stampedEnvVars returns the env vars of the first pod which can be expressed in the pod template.
+func stampedEnvVars(first, second []corev1.EnvVar, firstName, secondName string) []corev1.EnvVar {
+	podNameRef := fmt.Sprintf("$(%s)", leaderworkerset.LwsPodName)
+	var envVars []corev1.EnvVar
+	podNameUsed := false
+	for _, envVar := range first {
+		var other *corev1.EnvVar
+		for i := range second {
+			if second[i].Name == envVar.Name {
+				other = &second[i]
+			}
+		}
+		switch {
+		case other == nil:
+			continue
+		case equality.Semantic.DeepEqual(envVar, *other):
+			envVars = append(envVars, envVar)
+		case envVar.Value == "1" && other.Value == "2":
+			envVars = append(envVars, corev1.EnvVar{Name: envVar.Name, ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.labels['%s']", appsv1.PodIndexLabel)},
+			}})
+		case strings.Contains(envVar.Value, firstName) &&
+			strings.ReplaceAll(envVar.Value, firstName, podNameRef) == strings.ReplaceAll(other.Value, secondName, podNameRef):
+			envVars = append(envVars, corev1.EnvVar{Name: envVar.Name, Value: strings.ReplaceAll(envVar.Value, firstName, podNameRef)})
+			podNameUsed = true
+		}
+	}
+	// Env vars can only refer to the ones defined before them.
+	if podNameUsed {
+		envVars = append([]corev1.EnvVar{{Name: leaderworkerset.LwsPodName, ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+		}}}, envVars...)
+	}
+	return envVars
+}
@@ -17,10 +17,16 @@ limitations under the License.
 package pod
 
 import (
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 	"sigs.k8s.io/lws/test/testutils"
 )
 
@@ -99,41 +105,301 @@ func TestContainerRestarted(t *testing.T) {
 	}
 }
 
+func TestContainerSucceeded(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          corev1.ContainerStatus
+		expectSucceeded bool
+	}{
+		{
+			name:   "container running",
+			status: corev1.ContainerStatus{Name: "leader", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		},
+		{
+			name:            "container exited with 0",
+			status:          corev1.ContainerStatus{Name: "leader", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+			expectSucceeded: true,
+		},
+		{
+			name: "container restarted after exiting with 0",
+			status: corev1.ContainerStatus{
+				Name:                 "leader",
+				State:                corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+				RestartCount:         1,
+			},
+			expectSucceeded: true,
+		},
+		{
+			name:   "container exited with 1",
+			status: corev1.ContainerStatus{Name: "leader", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}},
+		},
+		{
+			name:   "other container exited with 0",
+			status: corev1.ContainerStatus{Name: "sidecar", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{tc.status}}}
+			if succeeded := ContainerSucceeded(pod, "leader"); succeeded != tc.expectSucceeded {
+				t.Errorf("Expected value %t, got %t", tc.expectSucceeded, succeeded)
+			}
+		})
+	}
+}
+
+func TestPodFailed(t *testing.T) {
+	tests := []struct {
+		name         string
+		pod          corev1.Pod
+		expectFailed bool
+	}{
+		{
+			name: "Pod in failed phase",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodFailed,
+				},
+			},
+			expectFailed: true,
+		},
+		{
+			name: "Pod in running phase",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			},
+		},
+		{
+			name: "Pod in succeeded phase",
+			pod: corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodSucceeded,
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if failed := PodFailed(tc.pod); failed != tc.expectFailed {
+				t.Errorf("Expected value %t, got %t", tc.expectFailed, failed)
+			}
+		})
+	}
+}
+
+func TestPodEvicted(t *testing.T) {
+	tests := []struct {
+		name          string
+		conditions    []corev1.PodCondition
+		expectEvicted bool
+	}{
+		{
+			name: "Pod evicted by the eviction API",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: "EvictionByEvictionAPI"},
+			},
+			expectEvicted: true,
+		},
+		{
+			name: "Pod preempted by the scheduler",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.DisruptionTarget, Status: corev1.ConditionTrue, Reason: corev1.PodReasonPreemptionByScheduler},
+			},
+		},
+		{
+			name: "Pod without disruption condition",
+			conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := corev1.Pod{Status: corev1.PodStatus{Conditions: tc.conditions}}
+			if evicted := PodEvicted(pod); evicted != tc.expectEvicted {
+				t.Errorf("Expected value %t, got %t", tc.expectEvicted, evicted)
+			}
+		})
+	}
+}
+
+func TestMatchFailurePolicyRule(t *testing.T) {
+	rules := []leaderworkerset.FailurePolicyRule{
+		{Action: leaderworkerset.RestartGroupAction, OnExitCodes: []int32{137}, OnPodConditionReasons: []string{"DisruptionTarget"}},
+		{Action: leaderworkerset.FailLeaderWorkerSetAction, OnExitCodes: []int32{1}},
+	}
+	terminated := func(exitCode int32) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}}
+	}
+	tests := []struct {
+		name       string
+		pod        corev1.Pod
+		wantAction *leaderworkerset.FailurePolicyAction
+	}{
+		{
+			name: "container terminated with a matched exit code",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{State: terminated(1)}},
+			}},
+			wantAction: ptr.To(leaderworkerset.FailLeaderWorkerSetAction),
+		},
+		{
+			name: "restarted container with a matched last exit code",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{LastTerminationState: terminated(137)}},
+			}},
+			wantAction: ptr.To(leaderworkerset.RestartGroupAction),
+		},
+		{
+			name: "pod condition with a matched reason",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.DisruptionTarget, Reason: "DisruptionTarget"}},
+			}},
+			wantAction: ptr.To(leaderworkerset.RestartGroupAction),
+		},
+		{
+			name: "first matched rule wins",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{State: terminated(1)}, {State: terminated(137)}},
+			}},
+			wantAction: ptr.To(leaderworkerset.RestartGroupAction),
+		},
+		{
+			name: "no rule matches",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Reason:            "Evicted",
+				ContainerStatuses: []corev1.ContainerStatus{{State: terminated(2)}},
+			}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotAction *leaderworkerset.FailurePolicyAction
+			if rule := MatchFailurePolicyRule(rules, tc.pod); rule != nil {
+				gotAction = &rule.Action
+			}
+			if diff := cmp.Diff(tc.wantAction, gotAction); diff != "" {
+				t.Errorf("Unexpected matched action (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchedulingGate(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{SchedulingGates: []corev1.PodSchedulingGate{{Name: "other"}}}}
+	AddSchedulingGate(&pod, leaderworkerset.GroupSchedulingGateName)
+	AddSchedulingGate(&pod, leaderworkerset.GroupSchedulingGateName)
+	wantGates := []corev1.PodSchedulingGate{{Name: "other"}, {Name: leaderworkerset.GroupSchedulingGateName}}
+	if diff := cmp.Diff(wantGates, pod.Spec.SchedulingGates); diff != "" {
+		t.Errorf("Unexpected scheduling gates after adding (-want,+got):\n%s", diff)
+	}
+
+	if !RemoveSchedulingGate(&pod, leaderworkerset.GroupSchedulingGateName) {
+		t.Errorf("Expected the scheduling gate to be removed")
+	}
+	if RemoveSchedulingGate(&pod, leaderworkerset.GroupSchedulingGateName) {
+		t.Errorf("Expected no scheduling gate to be removed")
+	}
+	wantGates = []corev1.PodSchedulingGate{{Name: "other"}}
+	if diff := cmp.Diff(wantGates, pod.Spec.SchedulingGates); diff != "" {
+		t.Errorf("Unexpected scheduling gates after removing (-want,+got):\n%s", diff)
+	}
+}
+
+func TestReadinessGate(t *testing.T) {
+	pod := corev1.Pod{}
+	AddReadinessGate(&pod, leaderworkerset.WorkersReadyConditionType)
+	AddReadinessGate(&pod, leaderworkerset.WorkersReadyConditionType)
+	wantGates := []corev1.PodReadinessGate{{ConditionType: leaderworkerset.WorkersReadyConditionType}}
+	if diff := cmp.Diff(wantGates, pod.Spec.ReadinessGates); diff != "" {
+		t.Errorf("Unexpected readiness gates (-want,+got):\n%s", diff)
+	}
+
+	if !SetPodCondition(&pod, leaderworkerset.WorkersReadyConditionType, corev1.ConditionFalse, "WorkersNotReady") {
+		t.Errorf("Expected the condition to be added")
+	}
+	if SetPodCondition(&pod, leaderworkerset.WorkersReadyConditionType, corev1.ConditionFalse, "WorkersNotReady") {
+		t.Errorf("Expected the condition to be unchanged")
+	}
+	if !SetPodCondition(&pod, leaderworkerset.WorkersReadyConditionType, corev1.ConditionTrue, "AllWorkersReady") {
+		t.Errorf("Expected the condition to be updated")
+	}
+	if len(pod.Status.Conditions) != 1 || pod.Status.Conditions[0].Status != corev1.ConditionTrue {
+		t.Errorf("Unexpected pod conditions: %v", pod.Status.Conditions)
+	}
+
+	if Draining(pod) {
+		t.Errorf("Expected the pod without the serving condition not draining")
+	}
+	SetPodCondition(&pod, leaderworkerset.ServingConditionType, corev1.ConditionFalse, "Draining")
+	if !Draining(pod) {
+		t.Errorf("Expected the pod draining")
+	}
+}
+
 func TestAddLWSVariables(t *testing.T) {
 	tests := []struct {
 		name                     string
 		pod                      *corev1.Pod
 		expectedLwsLeaderAddress string
+		expectedGroupSize        string
+		expectedWorkerIndex      string
 	}{
 		{
 			name:                     "Leader pod",
-			pod:                      testutils.MakePodWithLabels("test-sample", "0", "", "default"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "0", "0", "default", 2),
 			expectedLwsLeaderAddress: "test-sample-0.test-sample.default",
+			expectedGroupSize:        "2",
+			expectedWorkerIndex:      "0",
 		},
 		{
 			name:                     "Worker pod",
-			pod:                      testutils.MakePodWithLabels("test-sample", "0", "1", "default"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "0", "1", "default", 2),
 			expectedLwsLeaderAddress: "test-sample-0.test-sample.default",
+			expectedGroupSize:        "2",
+			expectedWorkerIndex:      "1",
 		},
 		{
 			name:                     "Leader pod, group 1",
-			pod:                      testutils.MakePodWithLabels("test-sample", "1", "", "default"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "1", "0", "default", 2),
 			expectedLwsLeaderAddress: "test-sample-1.test-sample.default",
+			expectedGroupSize:        "2",
+			expectedWorkerIndex:      "0",
 		},
 		{
 			name:                     "Worker pod, group 1",
-			pod:                      testutils.MakePodWithLabels("test-sample", "1", "3", "default"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "1", "3", "default", 4),
 			expectedLwsLeaderAddress: "test-sample-1.test-sample.default",
+			expectedGroupSize:        "4",
+			expectedWorkerIndex:      "3",
 		},
 		{
 			name:                     "Leader pod, group 1, non-default namespace",
-			pod:                      testutils.MakePodWithLabels("test-sample", "1", "3", "lws"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "1", "0", "lws", 4),
 			expectedLwsLeaderAddress: "test-sample-1.test-sample.lws",
+			expectedGroupSize:        "4",
+			expectedWorkerIndex:      "0",
 		},
 		{
 			name:                     "Worker pod, group 1, non-default namespace",
-			pod:                      testutils.MakePodWithLabels("test-sample", "1", "3", "lws"),
+			pod:                      testutils.MakePodWithLabels("test-sample", "1", "3", "lws", 4),
 			expectedLwsLeaderAddress: "test-sample-1.test-sample.lws",
+			expectedGroupSize:        "4",
+			expectedWorkerIndex:      "3",
+		},
+		{
+			name: "Worker pod, group 1, unique subdomain per replica",
+			pod: func() *corev1.Pod {
+				pod := testutils.MakePodWithLabels("test-sample", "1", "3", "default", 4)
+				pod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] = string(leaderworkerset.SubdomainUniquePerReplica)
+				return pod
+			}(),
+			expectedLwsLeaderAddress: "test-sample-1.test-sample-1.default",
+			expectedGroupSize:        "4",
+			expectedWorkerIndex:      "3",
 		},
 	}
 
@@ -149,15 +415,269 @@ func TestAddLWSVariables(t *testing.T) {
 			}
 
 			for _, container := range containers {
-				if len(container.Env) == 0 {
-					t.Errorf("Failed to add LWS Variables to container %+v", container)
+				if len(container.Env) < 3 {
+					t.Fatalf("Failed to add LWS Variables to container %+v", container)
 				}
 
-				envVar := container.Env[0]
-				if diff := cmp.Diff(envVar.Value, tc.expectedLwsLeaderAddress); diff != "" {
-					t.Errorf("Unexpected lws leader address %s", diff)
+				wantEnv := []corev1.EnvVar{
+					{Name: leaderworkerset.LwsLeaderAddress, Value: tc.expectedLwsLeaderAddress},
+					{Name: leaderworkerset.LwsGroupSize, Value: tc.expectedGroupSize},
+					{Name: leaderworkerset.LwsWorkerIndex, Value: tc.expectedWorkerIndex},
+				}
+				if diff := cmp.Diff(wantEnv, container.Env[:3]); diff != "" {
+					t.Errorf("Unexpected lws env vars (-want,+got):\n%s", diff)
 				}
 			}
 		})
 	}
 }
+
+func TestRenameInjectedEnvVars(t *testing.T) {
+	pod := corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init"}},
+		Containers:     []corev1.Container{{Name: "main", Env: []corev1.EnvVar{{Name: "USER_DEFINED", Value: "foo"}}}},
+	}}
+	existing := EnvVarNames(&pod)
+	pod.Spec.InitContainers[0].Env = append(pod.Spec.InitContainers[0].Env, corev1.EnvVar{Name: leaderworkerset.LwsWorkerIndex, Value: "0"})
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-0.test-sample.default"},
+		corev1.EnvVar{Name: leaderworkerset.LwsWorkerIndex, Value: "0"},
+	)
+
+	RenameInjectedEnvVars(&pod, existing, leaderworkerset.EnvVarNaming{
+		Prefix:  "MY_",
+		Renames: map[string]string{leaderworkerset.LwsLeaderAddress: "RAY_HEAD_ADDRESS"},
+	})
+	wantInitEnv := []corev1.EnvVar{{Name: "MY_LWS_WORKER_INDEX", Value: "0"}}
+	if diff := cmp.Diff(wantInitEnv, pod.Spec.InitContainers[0].Env); diff != "" {
+		t.Errorf("Unexpected init container env vars (-want,+got):\n%s", diff)
+	}
+	wantEnv := []corev1.EnvVar{
+		{Name: "USER_DEFINED", Value: "foo"},
+		{Name: "RAY_HEAD_ADDRESS", Value: "test-sample-0.test-sample.default"},
+		{Name: "MY_LWS_WORKER_INDEX", Value: "0"},
+	}
+	if diff := cmp.Diff(wantEnv, pod.Spec.Containers[0].Env); diff != "" {
+		t.Errorf("Unexpected container env vars (-want,+got):\n%s", diff)
+	}
+}
+
+func TestAddTemplatedEnvVars(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-sample-1-2",
+			Namespace: "default",
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:     "test-sample",
+				leaderworkerset.GroupIndexLabelKey:  "1",
+				leaderworkerset.WorkerIndexLabelKey: "2",
+			},
+			Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "4"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Env: []corev1.EnvVar{{Name: "RAY_PORT", Value: "6380"}}}},
+		},
+	}
+	envs := []leaderworkerset.TemplatedEnvVar{
+		{Name: "RAY_HEAD_ADDRESS", Value: "{{.LeaderAddress}}:6379"},
+		{Name: "NODE_ID", Value: "{{.GroupIndex}}-{{.WorkerIndex}}-of-{{.Size}}"},
+		{Name: "RAY_PORT", Value: "6379"},
+	}
+	if err := AddTemplatedEnvVars(&pod, envs); err != nil {
+		t.Fatalf("Failed to add templated env vars: %v", err)
+	}
+	wantEnv := []corev1.EnvVar{
+		{Name: "RAY_HEAD_ADDRESS", Value: "test-sample-1.test-sample.default:6379"},
+		{Name: "NODE_ID", Value: "1-2-of-4"},
+		{Name: "RAY_PORT", Value: "6380"},
+	}
+	if diff := cmp.Diff(wantEnv, pod.Spec.Containers[0].Env); diff != "" {
+		t.Errorf("Unexpected env vars (-want,+got):\n%s", diff)
+	}
+
+	if err := AddTemplatedEnvVars(&pod, []leaderworkerset.TemplatedEnvVar{{Name: "FOO", Value: "{{.Unknown}}"}}); err == nil {
+		t.Errorf("Expected an error expanding an unknown field")
+	}
+}
+
+func TestPodAvailable(t *testing.T) {
+	now := time.Now()
+	readyPod := func(readySince time.Time) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{
+			Type:               corev1.PodReady,
+			Status:             corev1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(readySince),
+		}}}}
+	}
+	tests := []struct {
+		name            string
+		pod             corev1.Pod
+		minReadySeconds int32
+		want            bool
+	}{
+		{
+			name: "pod not ready",
+			pod:  corev1.Pod{},
+		},
+		{
+			name: "ready pod without minReadySeconds",
+			pod:  readyPod(now),
+			want: true,
+		},
+		{
+			name:            "pod ready for less than minReadySeconds",
+			pod:             readyPod(now.Add(-5 * time.Second)),
+			minReadySeconds: 10,
+		},
+		{
+			name:            "pod ready for more than minReadySeconds",
+			pod:             readyPod(now.Add(-15 * time.Second)),
+			minReadySeconds: 10,
+			want:            true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := PodAvailable(tc.pod, tc.minReadySeconds, now); got != tc.want {
+				t.Errorf("Expected pod available to be %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAddModelPrefetch(t *testing.T) {
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "init"}},
+		Containers:     []corev1.Container{{Name: "server", Env: []corev1.EnvVar{{Name: "PORT", Value: "8080"}}}},
+	}
+	AddModelPrefetch(&spec, &leaderworkerset.ModelPrefetch{URI: "s3://bucket/llama", Image: "downloader"})
+
+	volumeMount := corev1.VolumeMount{Name: leaderworkerset.ModelPrefetchVolumeName, MountPath: leaderworkerset.DefaultModelPath}
+	modelPathEnvVar := corev1.EnvVar{Name: leaderworkerset.LwsModelPath, Value: leaderworkerset.DefaultModelPath}
+	wantSpec := corev1.PodSpec{
+		InitContainers: []corev1.Container{
+			{
+				Name:  leaderworkerset.ModelPrefetchContainerName,
+				Image: "downloader",
+				Env: []corev1.EnvVar{
+					{Name: leaderworkerset.LwsModelURI, Value: "s3://bucket/llama"},
+					modelPathEnvVar,
+					{
+						Name: leaderworkerset.LwsGroupIndex,
+						ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: "metadata.labels['leaderworkerset.sigs.k8s.io/group-index']",
+						}},
+					},
+				},
+				VolumeMounts: []corev1.VolumeMount{volumeMount},
+			},
+			{Name: "init", Env: []corev1.EnvVar{modelPathEnvVar}, VolumeMounts: []corev1.VolumeMount{volumeMount}},
+		},
+		Containers: []corev1.Container{
+			{Name: "server", Env: []corev1.EnvVar{modelPathEnvVar, {Name: "PORT", Value: "8080"}}, VolumeMounts: []corev1.VolumeMount{volumeMount}},
+		},
+		Volumes: []corev1.Volume{{
+			Name:         leaderworkerset.ModelPrefetchVolumeName,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}},
+	}
+	if diff := cmp.Diff(wantSpec, spec); diff != "" {
+		t.Errorf("Unexpected pod spec (-want,+got):\n%s", diff)
+	}
+}
+
+func TestAddGroupSpreadConstraints(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{
+		leaderworkerset.SetNameLabelKey:     "test-sample",
+		leaderworkerset.WorkerIndexLabelKey: "0",
+	}}
+	userConstraint := corev1.TopologySpreadConstraint{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule}
+	spec := corev1.PodSpec{TopologySpreadConstraints: []corev1.TopologySpreadConstraint{userConstraint}}
+	AddGroupSpreadConstraints(&spec, "test-sample", []leaderworkerset.GroupSpreadConstraint{
+		{TopologyKey: "topology.kubernetes.io/zone"},
+		{TopologyKey: "cloud.google.com/gke-nodepool"},
+		{TopologyKey: "example.com/superpod", MaxSkew: 3, WhenUnsatisfiable: corev1.DoNotSchedule},
+	})
+	want := []corev1.TopologySpreadConstraint{
+		userConstraint,
+		{MaxSkew: 1, TopologyKey: "cloud.google.com/gke-nodepool", WhenUnsatisfiable: corev1.ScheduleAnyway, LabelSelector: selector},
+		{MaxSkew: 3, TopologyKey: "example.com/superpod", WhenUnsatisfiable: corev1.DoNotSchedule, LabelSelector: selector},
+	}
+	if diff := cmp.Diff(want, spec.TopologySpreadConstraints); diff != "" {
+		t.Errorf("Unexpected topology spread constraints (-want,+got):\n%s", diff)
+	}
+}
+
+func TestStampEnvVars(t *testing.T) {
+	podIndexEnvVar := func(name string) corev1.EnvVar {
+		return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.labels['apps.kubernetes.io/pod-index']"},
+		}}
+	}
+	podNameEnvVar := corev1.EnvVar{Name: leaderworkerset.LwsPodName, ValueFrom: &corev1.EnvVarSource{
+		FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+	}}
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		indexLabelKey string
+		podName       func(ordinal int) string
+		wantEnv       []corev1.EnvVar
+	}{
+		{
+			name:          "leader pods",
+			labels:        map[string]string{leaderworkerset.WorkerIndexLabelKey: "0"},
+			indexLabelKey: leaderworkerset.GroupIndexLabelKey,
+			podName:       func(ordinal int) string { return fmt.Sprintf("test-sample-%d", ordinal) },
+			wantEnv: []corev1.EnvVar{
+				podNameEnvVar,
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "$(LWS_POD_NAME).test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "4"},
+				{Name: leaderworkerset.LwsWorkerIndex, Value: "0"},
+				{Name: "USER", Value: "set"},
+			},
+		},
+		{
+			name:          "worker pods",
+			labels:        map[string]string{leaderworkerset.GroupIndexLabelKey: "1"},
+			indexLabelKey: leaderworkerset.WorkerIndexLabelKey,
+			podName:       func(ordinal int) string { return fmt.Sprintf("test-sample-1-%d", ordinal) },
+			wantEnv: []corev1.EnvVar{
+				{Name: leaderworkerset.LwsLeaderAddress, Value: "test-sample-1.test-sample.default"},
+				{Name: leaderworkerset.LwsGroupSize, Value: "4"},
+				podIndexEnvVar(leaderworkerset.LwsWorkerIndex),
+				{Name: "USER", Value: "set"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			template := &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+					Annotations: map[string]string{leaderworkerset.SizeAnnotationKey: "4"},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Env: []corev1.EnvVar{{Name: "USER", Value: "set"}}}}},
+			}
+			for key, value := range tc.labels {
+				template.Labels[key] = value
+			}
+			err := StampEnvVars(template, func(ordinal int) *corev1.Pod {
+				pod := &corev1.Pod{ObjectMeta: *template.ObjectMeta.DeepCopy(), Spec: *template.Spec.DeepCopy()}
+				pod.Name = tc.podName(ordinal)
+				pod.Namespace = "default"
+				pod.Labels[tc.indexLabelKey] = fmt.Sprint(ordinal)
+				pod.Spec.Subdomain = "test-sample"
+				return pod
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantEnv, template.Spec.Containers[0].Env); diff != "" {
+				t.Errorf("unexpected env vars: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+)
+
+const (
+	// CheckCapacityClass only checks whether the capacity is available in the cluster.
+	CheckCapacityClass = "check-capacity.autoscaling.x-k8s.io"
+	// AtomicScaleUpClass provisions all the nodes of the request atomically.
+	AtomicScaleUpClass = "best-effort-atomic-scale-up.autoscaling.x-k8s.io"
+
+	// ConsumeAnnotationKey and ClassNameAnnotationKey bind the pods to the provisioned capacity.
+	ConsumeAnnotationKey   = "autoscaling.x-k8s.io/consume-provisioning-request"
+	ClassNameAnnotationKey = "autoscaling.x-k8s.io/provisioning-class-name"
+
+	provisionedCondition = "Provisioned"
+	failedCondition      = "Failed"
+)
+
+// ProvisioningRequestGVK is the GroupVersionKind of the cluster autoscaler ProvisioningRequest, it is
+// managed as an unstructured object so cluster autoscaler is not a hard dependency.
+var ProvisioningRequestGVK = schema.GroupVersionKind{Group: "autoscaling.x-k8s.io", Version: "v1beta1", Kind: "ProvisioningRequest"}
+
+// SupportedClasses are the provisioning classes can be used by the lws.
+var SupportedClasses = []string{CheckCapacityClass, AtomicScaleUpClass}
+
+// RequestName returns the name of the ProvisioningRequest of the group, a new request is made
+// each time the group is recreated.
+func RequestName(leaderPod *corev1.Pod) string {
+	uid := string(leaderPod.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-%s", leaderPod.Name, uid)
+}
+
+// NewPodTemplates returns the pod templates referenced by the ProvisioningRequest, the leader
+// template is only returned when it differs from the worker template.
+func NewPodTemplates(lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod) []corev1.PodTemplate {
+	makeTemplate := func(suffix string, template corev1.PodTemplateSpec) corev1.PodTemplate {
+		return corev1.PodTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("%s-%s", RequestName(leaderPod), suffix),
+				Namespace: leaderPod.Namespace,
+				Labels:    map[string]string{leaderworkerset.SetNameLabelKey: lws.Name},
+			},
+			Template: corev1.PodTemplateSpec{Spec: template.Spec},
+		}
+	}
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate == nil {
+		return []corev1.PodTemplate{makeTemplate("worker", lws.Spec.LeaderWorkerTemplate.WorkerTemplate)}
+	}
+	templates := []corev1.PodTemplate{makeTemplate("leader", *lws.Spec.LeaderWorkerTemplate.LeaderTemplate)}
+	if *lws.Spec.LeaderWorkerTemplate.Size > 1 {
+		templates = append(templates, makeTemplate("worker", lws.Spec.LeaderWorkerTemplate.WorkerTemplate))
+	}
+	return templates
+}
+
+// NewProvisioningRequest returns the ProvisioningRequest describing all the pods of the group.
+func NewProvisioningRequest(lws *leaderworkerset.LeaderWorkerSet, leaderPod *corev1.Pod, templates []corev1.PodTemplate) (*unstructured.Unstructured, error) {
+	size := int64(*lws.Spec.LeaderWorkerTemplate.Size)
+	var podSets []interface{}
+	for i, template := range templates {
+		count := size
+		if len(templates) > 1 {
+			// The leader template comes first.
+			count = 1
+			if i > 0 {
+				count = size - 1
+			}
+		}
+		podSets = append(podSets, map[string]interface{}{
+			"count":          count,
+			"podTemplateRef": map[string]interface{}{"name": template.Name},
+		})
+	}
+
+	request := &unstructured.Unstructured{}
+	request.SetGroupVersionKind(ProvisioningRequestGVK)
+	request.SetName(RequestName(leaderPod))
+	request.SetNamespace(leaderPod.Namespace)
+	request.SetLabels(map[string]string{
+		leaderworkerset.SetNameLabelKey:    lws.Name,
+		leaderworkerset.GroupIndexLabelKey: leaderPod.Labels[leaderworkerset.GroupIndexLabelKey],
+	})
+	if err := unstructured.SetNestedField(request.Object, lws.Annotations[leaderworkerset.ProvisioningClassNameAnnotationKey], "spec", "provisioningClassName"); err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedSlice(request.Object, podSets, "spec", "podSets"); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Provisioned returns true if the capacity of the ProvisioningRequest is provisioned.
+func Provisioned(request *unstructured.Unstructured) bool {
+	return conditionTrue(request, provisionedCondition)
+}
+
+// Failed returns true if the ProvisioningRequest is failed.
+func Failed(request *unstructured.Unstructured) bool {
+	return conditionTrue(request, failedCondition)
+}
+
+func conditionTrue(request *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(request.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/testutils"
+)
+
+func TestNewProvisioningRequest(t *testing.T) {
+	leaderPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "test-sample-1",
+		Namespace: "default",
+		UID:       "0123456789abcdef",
+		Labels:    map[string]string{leaderworkerset.GroupIndexLabelKey: "1"},
+	}}
+	tests := []struct {
+		name                  string
+		withoutLeaderTemplate bool
+		wantPodSets           []interface{}
+	}{
+		{
+			name: "leader template is set",
+			wantPodSets: []interface{}{
+				map[string]interface{}{"count": int64(1), "podTemplateRef": map[string]interface{}{"name": "test-sample-1-01234567-leader"}},
+				map[string]interface{}{"count": int64(3), "podTemplateRef": map[string]interface{}{"name": "test-sample-1-01234567-worker"}},
+			},
+		},
+		{
+			name:                  "worker template only",
+			withoutLeaderTemplate: true,
+			wantPodSets: []interface{}{
+				map[string]interface{}{"count": int64(4), "podTemplateRef": map[string]interface{}{"name": "test-sample-1-01234567-worker"}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Size(4).
+				Annotation(map[string]string{leaderworkerset.ProvisioningClassNameAnnotationKey: CheckCapacityClass}).Obj()
+			if tc.withoutLeaderTemplate {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate = nil
+			}
+			request, err := NewProvisioningRequest(lws, leaderPod, NewPodTemplates(lws, leaderPod))
+			if err != nil {
+				t.Fatalf("Failed to build provisioning request: %v", err)
+			}
+			if className, _, _ := unstructured.NestedString(request.Object, "spec", "provisioningClassName"); className != CheckCapacityClass {
+				t.Errorf("Expected provisioning class %s, got %s", CheckCapacityClass, className)
+			}
+			podSets, _, _ := unstructured.NestedSlice(request.Object, "spec", "podSets")
+			if diff := cmp.Diff(tc.wantPodSets, podSets); diff != "" {
+				t.Errorf("Unexpected podSets (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProvisioned(t *testing.T) {
+	tests := []struct {
+		name            string
+		conditions      []interface{}
+		wantProvisioned bool
+		wantFailed      bool
+	}{
+		{
+			name: "provisioned",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Accepted", "status": "True"},
+				map[string]interface{}{"type": "Provisioned", "status": "True"},
+			},
+			wantProvisioned: true,
+		},
+		{
+			name: "failed",
+			conditions: []interface{}{
+				map[string]interface{}{"type": "Failed", "status": "True"},
+			},
+			wantFailed: true,
+		},
+		{
+			name: "pending",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			request := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			if tc.conditions != nil {
+				if err := unstructured.SetNestedSlice(request.Object, tc.conditions, "status", "conditions"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := Provisioned(request); got != tc.wantProvisioned {
+				t.Errorf("Expected provisioned %t, got %t", tc.wantProvisioned, got)
+			}
+			if got := Failed(request); got != tc.wantFailed {
+				t.Errorf("Expected failed %t, got %t", tc.wantFailed, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+)
+
+// NewRevision returns a ControllerRevision recording the leader and worker templates of the lws,
+// the name of the revision is derived from the template hash.
+func NewRevision(lws *leaderworkerset.LeaderWorkerSet, revision int64) (*appsv1.ControllerRevision, error) {
+	templates := leaderworkerset.LeaderWorkerTemplate{
+		LeaderTemplate:         lws.Spec.LeaderWorkerTemplate.LeaderTemplate,
+		WorkerTemplate:         lws.Spec.LeaderWorkerTemplate.WorkerTemplate,
+		WorkerTemplates:        lws.Spec.LeaderWorkerTemplate.WorkerTemplates,
+		GroupOverrides:         lws.Spec.LeaderWorkerTemplate.GroupOverrides,
+		ModelPrefetch:          lws.Spec.LeaderWorkerTemplate.ModelPrefetch,
+		GroupSpreadConstraints: lws.Spec.LeaderWorkerTemplate.GroupSpreadConstraints,
+		PropagatePlacement:     lws.Spec.LeaderWorkerTemplate.PropagatePlacement,
+	}
+	raw, err := json.Marshal(templates)
+	if err != nil {
+		return nil, err
+	}
+
+	templateHash := utils.LeaderWorkerTemplateHash(lws)
+	return &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", lws.Name, templateHash[:10]),
+			Namespace: lws.Namespace,
+			Labels: map[string]string{
+				leaderworkerset.SetNameLabelKey:         lws.Name,
+				leaderworkerset.TemplateRevisionHashKey: templateHash,
+			},
+		},
+		Data:     runtime.RawExtension{Raw: raw},
+		Revision: revision,
+	}, nil
+}
+
+// ApplyRevision restores the leader and worker templates recorded in the revision to the lws.
+func ApplyRevision(lws *leaderworkerset.LeaderWorkerSet, revision *appsv1.ControllerRevision) error {
+	var templates leaderworkerset.LeaderWorkerTemplate
+	if err := json.Unmarshal(revision.Data.Raw, &templates); err != nil {
+		return err
+	}
+	lws.Spec.LeaderWorkerTemplate.LeaderTemplate = templates.LeaderTemplate
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate = templates.WorkerTemplate
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplates = templates.WorkerTemplates
+	lws.Spec.LeaderWorkerTemplate.GroupOverrides = templates.GroupOverrides
+	lws.Spec.LeaderWorkerTemplate.ModelPrefetch = templates.ModelPrefetch
+	lws.Spec.LeaderWorkerTemplate.GroupSpreadConstraints = templates.GroupSpreadConstraints
+	lws.Spec.LeaderWorkerTemplate.PropagatePlacement = templates.PropagatePlacement
+	return nil
+}
+
+// SortRevisions sorts the revisions in ascending order of the revision number.
+func SortRevisions(revisions []appsv1.ControllerRevision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+}
+
+// MaxRevision returns the max revision number of the given revisions, 0 if there are no revisions.
+func MaxRevision(revisions []appsv1.ControllerRevision) int64 {
+	var maxRevision int64
+	for _, revision := range revisions {
+		if revision.Revision > maxRevision {
+			maxRevision = revision.Revision
+		}
+	}
+	return maxRevision
+}
+
+// RevisionsToTruncate returns the oldest revisions exceeding the history limit, the revisions
+// of the given live template hashes, e.g. the current one, are never truncated.
+func RevisionsToTruncate(revisions []appsv1.ControllerRevision, liveHashes sets.Set[string], limit int32) []appsv1.ControllerRevision {
+	var history []appsv1.ControllerRevision
+	for _, revision := range revisions {
+		if !liveHashes.Has(revision.Labels[leaderworkerset.TemplateRevisionHashKey]) {
+			history = append(history, revision)
+		}
+	}
+	if len(history) <= int(limit) {
+		return nil
+	}
+	SortRevisions(history)
+	return history[:len(history)-int(limit)]
+}
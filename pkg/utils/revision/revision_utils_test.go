@@ -0,0 +1,114 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	"sigs.k8s.io/lws/test/testutils"
+)
+
+func TestApplyRevision(t *testing.T) {
+	lws := testutils.BuildLeaderWorkerSet("default").Obj()
+	revision, err := NewRevision(lws, 1)
+	if err != nil {
+		t.Fatalf("Failed to create revision: %v", err)
+	}
+	wantHash := utils.LeaderWorkerTemplateHash(lws)
+	if revision.Labels[leaderworkerset.TemplateRevisionHashKey] != wantHash {
+		t.Errorf("Expected revision hash %s, got %s", wantHash, revision.Labels[leaderworkerset.TemplateRevisionHashKey])
+	}
+
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Containers[0].Image = "nginx:1.16.1"
+	if utils.LeaderWorkerTemplateHash(lws) == wantHash {
+		t.Fatalf("Expected template hash to change after updating the worker template")
+	}
+	if err := ApplyRevision(lws, revision); err != nil {
+		t.Fatalf("Failed to apply revision: %v", err)
+	}
+	if diff := cmp.Diff(wantHash, utils.LeaderWorkerTemplateHash(lws)); diff != "" {
+		t.Errorf("Unexpected template hash after rollback (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRevisionsToTruncate(t *testing.T) {
+	makeRevision := func(name string, revision int64) appsv1.ControllerRevision {
+		return appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{leaderworkerset.TemplateRevisionHashKey: name},
+			},
+			Revision: revision,
+		}
+	}
+	revisions := []appsv1.ControllerRevision{
+		makeRevision("c", 3),
+		makeRevision("a", 1),
+		makeRevision("d", 4),
+		makeRevision("b", 2),
+	}
+
+	tests := []struct {
+		name       string
+		liveHashes sets.Set[string]
+		limit      int32
+		wantNames  []string
+	}{
+		{
+			name:       "history within the limit",
+			liveHashes: sets.New("d"),
+			limit:      3,
+		},
+		{
+			name:       "truncate the oldest revisions",
+			liveHashes: sets.New("d"),
+			limit:      1,
+			wantNames:  []string{"a", "b"},
+		},
+		{
+			name:       "current revision is never truncated",
+			liveHashes: sets.New("a"),
+			limit:      0,
+			wantNames:  []string{"b", "c", "d"},
+		},
+		{
+			name:       "revisions still used by the groups are never truncated",
+			liveHashes: sets.New("d", "b"),
+			limit:      0,
+			wantNames:  []string{"a", "c"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotNames []string
+			for _, revision := range RevisionsToTruncate(revisions, tc.liveHashes, tc.limit) {
+				gotNames = append(gotNames, revision.Name)
+			}
+			if diff := cmp.Diff(tc.wantNames, gotNames); diff != "" {
+				t.Errorf("Unexpected truncated revisions (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
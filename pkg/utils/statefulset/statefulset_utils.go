@@ -44,6 +44,11 @@ func GetParentNameAndOrdinal(name string) (string, int) {
 	return parent, ordinal
 }
 
+// StatefulsetAvailable checks whether all the replicas of a sts have been ready for at least minReadySeconds.
+func StatefulsetAvailable(sts appsv1.StatefulSet) bool {
+	return sts.Status.AvailableReplicas >= *sts.Spec.Replicas
+}
+
 // StatefulsetReady checks whether a sts is ready.
 func StatefulsetReady(sts appsv1.StatefulSet) bool {
 	return *sts.Spec.Replicas == sts.Status.Replicas &&
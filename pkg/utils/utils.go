@@ -19,6 +19,9 @@ package utils
 import (
 	"crypto/sha1"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -40,9 +43,117 @@ func NonZeroValue(value int32) int32 {
 	return value
 }
 
+// LeaderWorkerTemplateHash returns the hash of the leader and worker templates, the restartedAt annotation
+// is part of it once set, so that restarts are rolled out as template updates.
 func LeaderWorkerTemplateHash(lws *leaderworkerset.LeaderWorkerSet) string {
-	return Sha1Hash(lws.Spec.LeaderWorkerTemplate.LeaderTemplate.String() +
-		lws.Spec.LeaderWorkerTemplate.WorkerTemplate.String())
+	templates := lws.Spec.LeaderWorkerTemplate.LeaderTemplate.String() +
+		lws.Spec.LeaderWorkerTemplate.WorkerTemplate.String()
+	for _, template := range lws.Spec.LeaderWorkerTemplate.WorkerTemplates {
+		templates += fmt.Sprintf("%s/%d/%s", template.Name, template.Count, template.Template.String())
+	}
+	if len(lws.Spec.LeaderWorkerTemplate.GroupOverrides) > 0 {
+		// The overrides only consist of plain values which always marshal.
+		raw, _ := json.Marshal(lws.Spec.LeaderWorkerTemplate.GroupOverrides)
+		templates += string(raw)
+	}
+	if prefetch := lws.Spec.LeaderWorkerTemplate.ModelPrefetch; prefetch != nil {
+		raw, _ := json.Marshal(prefetch)
+		templates += string(raw)
+	}
+	if constraints := lws.Spec.LeaderWorkerTemplate.GroupSpreadConstraints; len(constraints) > 0 {
+		raw, _ := json.Marshal(constraints)
+		templates += string(raw)
+	}
+	if lws.Spec.LeaderWorkerTemplate.PropagatePlacement {
+		templates += "/propagatePlacement"
+	}
+	if restartedAt := lws.Annotations[leaderworkerset.RestartedAtAnnotationKey]; restartedAt != "" {
+		templates += restartedAt
+	}
+	return Sha1Hash(templates)
+}
+
+// GroupOverrideOf returns the first group override covering the group index, nil if none.
+func GroupOverrideOf(lws *leaderworkerset.LeaderWorkerSet, groupIndex int) *leaderworkerset.GroupOverride {
+	for i, override := range lws.Spec.LeaderWorkerTemplate.GroupOverrides {
+		if int(override.StartIndex) <= groupIndex && groupIndex <= int(override.EndIndex) {
+			return &lws.Spec.LeaderWorkerTemplate.GroupOverrides[i]
+		}
+	}
+	return nil
+}
+
+// ApplyGroupOverride applies the group override to the pod spec.
+func ApplyGroupOverride(spec *corev1.PodSpec, override *leaderworkerset.GroupOverride) {
+	if len(override.NodeSelector) > 0 && spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	for key, value := range override.NodeSelector {
+		spec.NodeSelector[key] = value
+	}
+	for _, containerOverride := range override.Containers {
+		for i := range spec.Containers {
+			container := &spec.Containers[i]
+			if container.Name != containerOverride.Name {
+				continue
+			}
+			if containerOverride.Image != "" {
+				container.Image = containerOverride.Image
+			}
+			if containerOverride.Resources != nil {
+				container.Resources = *containerOverride.Resources.DeepCopy()
+			}
+			for _, envVar := range containerOverride.Env {
+				container.Env = setEnvVar(container.Env, *envVar.DeepCopy())
+			}
+		}
+	}
+}
+
+// PropagatePlacement copies the nodeSelector, tolerations and affinity of the leader pod spec to the worker pod spec,
+// the nodeSelector entries and the affinity of the worker pod spec take precedence, the tolerations are merged.
+func PropagatePlacement(spec *corev1.PodSpec, leaderSpec *corev1.PodSpec) {
+	for key, value := range leaderSpec.NodeSelector {
+		if _, found := spec.NodeSelector[key]; found {
+			continue
+		}
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = map[string]string{}
+		}
+		spec.NodeSelector[key] = value
+	}
+	for _, toleration := range leaderSpec.Tolerations {
+		if !slices.ContainsFunc(spec.Tolerations, func(t corev1.Toleration) bool { return t.MatchToleration(&toleration) }) {
+			spec.Tolerations = append(spec.Tolerations, toleration)
+		}
+	}
+	if spec.Affinity == nil && leaderSpec.Affinity != nil {
+		spec.Affinity = leaderSpec.Affinity.DeepCopy()
+	}
+}
+
+// setEnvVar replaces the environment variable of the same name, or appends it.
+func setEnvVar(envVars []corev1.EnvVar, envVar corev1.EnvVar) []corev1.EnvVar {
+	for i := range envVars {
+		if envVars[i].Name == envVar.Name {
+			envVars[i] = envVar
+			return envVars
+		}
+	}
+	return append(envVars, envVar)
+}
+
+// SubdomainPolicy returns the subdomain policy of the lws, defaults to Shared.
+func SubdomainPolicy(lws *leaderworkerset.LeaderWorkerSet) leaderworkerset.SubdomainPolicy {
+	if lws.Spec.NetworkConfig == nil || lws.Spec.NetworkConfig.SubdomainPolicy == "" {
+		return leaderworkerset.SubdomainShared
+	}
+	return lws.Spec.NetworkConfig.SubdomainPolicy
+}
+
+// TotalReplicas returns the number of groups of the lws, including the warm ones.
+func TotalReplicas(lws *leaderworkerset.LeaderWorkerSet) int32 {
+	return *lws.Spec.Replicas + lws.Spec.WarmReplicas
 }
 
 // SortByIndex returns an ascending list, the length of the list is always specified by the parameter.
@@ -57,7 +168,7 @@ func SortByIndex[T appsv1.StatefulSet | corev1.Pod | int](indexFunc func(T) (int
 			continue
 		}
 
-		if index >= length {
+		if index < 0 || index >= length {
 			continue
 		}
 		result[index] = item
@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+
+	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
 )
 
 func Test_SortByIndex(t *testing.T) {
@@ -63,3 +66,151 @@ func Test_SortByIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestLeaderWorkerTemplateHash(t *testing.T) {
+	lws := &leaderworkerset.LeaderWorkerSet{}
+	lws.Spec.LeaderWorkerTemplate.WorkerTemplate = corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "worker", Image: "nginx:1.14.2"}}},
+	}
+	hash := LeaderWorkerTemplateHash(lws)
+
+	lws.Annotations = map[string]string{leaderworkerset.RestartedAtAnnotationKey: "2024-01-01T00:00:00Z"}
+	restartedHash := LeaderWorkerTemplateHash(lws)
+	if restartedHash == hash {
+		t.Errorf("Expected the hash to change once restarted")
+	}
+
+	lws.Annotations[leaderworkerset.RestartedAtAnnotationKey] = "2024-01-02T00:00:00Z"
+	if LeaderWorkerTemplateHash(lws) == restartedHash {
+		t.Errorf("Expected the hash to change once restarted again")
+	}
+}
+
+func TestApplyGroupOverride(t *testing.T) {
+	lws := &leaderworkerset.LeaderWorkerSet{}
+	lws.Spec.LeaderWorkerTemplate.GroupOverrides = []leaderworkerset.GroupOverride{
+		{
+			StartIndex:   0,
+			EndIndex:     3,
+			NodeSelector: map[string]string{"pool": "h100"},
+			Containers: []leaderworkerset.ContainerOverride{{
+				Name:  "worker",
+				Image: "vllm:h100",
+				Env:   []corev1.EnvVar{{Name: "TP", Value: "8"}, {Name: "ARCH", Value: "hopper"}},
+			}},
+		},
+		{
+			StartIndex:   4,
+			EndIndex:     7,
+			NodeSelector: map[string]string{"pool": "a100"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		groupIndex int
+		wantSpec   corev1.PodSpec
+	}{
+		{
+			name:       "first override",
+			groupIndex: 3,
+			wantSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a", "pool": "h100"},
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "vllm:h100", Env: []corev1.EnvVar{{Name: "TP", Value: "8"}, {Name: "ARCH", Value: "hopper"}}},
+					{Name: "sidecar", Image: "sidecar"},
+				},
+			},
+		},
+		{
+			name:       "second override",
+			groupIndex: 4,
+			wantSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a", "pool": "a100"},
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "vllm", Env: []corev1.EnvVar{{Name: "TP", Value: "4"}}},
+					{Name: "sidecar", Image: "sidecar"},
+				},
+			},
+		},
+		{
+			name:       "group not overridden",
+			groupIndex: 8,
+			wantSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "vllm", Env: []corev1.EnvVar{{Name: "TP", Value: "4"}}},
+					{Name: "sidecar", Image: "sidecar"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a"},
+				Containers: []corev1.Container{
+					{Name: "worker", Image: "vllm", Env: []corev1.EnvVar{{Name: "TP", Value: "4"}}},
+					{Name: "sidecar", Image: "sidecar"},
+				},
+			}
+			if override := GroupOverrideOf(lws, tc.groupIndex); override != nil {
+				ApplyGroupOverride(&spec, override)
+			}
+			if diff := cmp.Diff(tc.wantSpec, spec); diff != "" {
+				t.Errorf("unexpected spec: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
+
+func TestPropagatePlacement(t *testing.T) {
+	gpuToleration := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spotToleration := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	leaderAffinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"h100"}}},
+		}}},
+	}}
+	workerAffinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+	leaderSpec := corev1.PodSpec{
+		NodeSelector: map[string]string{"zone": "a", "pool": "h100"},
+		Tolerations:  []corev1.Toleration{gpuToleration, spotToleration},
+		Affinity:     leaderAffinity,
+	}
+
+	tests := []struct {
+		name     string
+		spec     corev1.PodSpec
+		wantSpec corev1.PodSpec
+	}{
+		{
+			name:     "worker without placement",
+			spec:     corev1.PodSpec{},
+			wantSpec: leaderSpec,
+		},
+		{
+			name: "worker placement takes precedence",
+			spec: corev1.PodSpec{
+				NodeSelector: map[string]string{"pool": "a100"},
+				Tolerations:  []corev1.Toleration{gpuToleration},
+				Affinity:     workerAffinity,
+			},
+			wantSpec: corev1.PodSpec{
+				NodeSelector: map[string]string{"zone": "a", "pool": "a100"},
+				Tolerations:  []corev1.Toleration{gpuToleration, spotToleration},
+				Affinity:     workerAffinity,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			PropagatePlacement(&tc.spec, &leaderSpec)
+			if diff := cmp.Diff(tc.wantSpec, tc.spec); diff != "" {
+				t.Errorf("unexpected spec: (-want, +got) %s", diff)
+			}
+		})
+	}
+}
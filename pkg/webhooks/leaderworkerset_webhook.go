@@ -20,19 +20,36 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/url"
+	"path"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/schedulerprovider"
+	"sigs.k8s.io/lws/pkg/tracing"
+	"sigs.k8s.io/lws/pkg/utils"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	podutils "sigs.k8s.io/lws/pkg/utils/pod"
+	provisioningutils "sigs.k8s.io/lws/pkg/utils/provisioning"
 )
 
 type LeaderWorkerSetWebhook struct{}
@@ -51,12 +68,23 @@ func SetupLeaderWorkerSetWebhook(mgr ctrl.Manager) error {
 var _ webhook.CustomDefaulter = &LeaderWorkerSetWebhook{}
 
 // Default implements webhook.Defaulter so a webhook will be registered for the type
-func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object) error {
+func (r *LeaderWorkerSetWebhook) Default(ctx context.Context, obj runtime.Object) (err error) {
+	defer metrics.WebhookMutated("leaderworkerset", time.Now())
+	_, span := tracing.Start(ctx, "Admit LeaderWorkerSet")
+	defer func() { tracing.End(span, err) }()
 	lws := obj.(*v1.LeaderWorkerSet)
 	if lws.Spec.LeaderWorkerTemplate.RestartPolicy == "" {
 		lws.Spec.LeaderWorkerTemplate.RestartPolicy = v1.DefaultRestartPolicy
 	}
 
+	if lws.Spec.LeaderWorkerTemplate.ModelPrefetch != nil && lws.Spec.LeaderWorkerTemplate.ModelPrefetch.MountPath == "" {
+		lws.Spec.LeaderWorkerTemplate.ModelPrefetch.MountPath = v1.DefaultModelPath
+	}
+
+	if lws.Spec.FailurePolicy != nil && lws.Spec.FailurePolicy.Action == "" {
+		lws.Spec.FailurePolicy.Action = v1.FailGroupAction
+	}
+
 	if lws.Spec.RolloutStrategy.Type == "" {
 		lws.Spec.RolloutStrategy.Type = v1.RollingUpdateStrategyType
 	}
@@ -77,6 +105,7 @@ var _ webhook.CustomValidator = &LeaderWorkerSetWebhook{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *LeaderWorkerSetWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	warnings, allErrs := r.generalValidate(obj)
+	allErrs = append(allErrs, validateFeatureGates(obj.(*v1.LeaderWorkerSet), nil)...)
 	return warnings, allErrs.ToAggregate()
 }
 
@@ -87,17 +116,57 @@ func (r *LeaderWorkerSetWebhook) ValidateUpdate(ctx context.Context, oldObj, new
 
 	oldLws := oldObj.(*v1.LeaderWorkerSet)
 	newLws := newObj.(*v1.LeaderWorkerSet)
-	allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.Size, *oldLws.Spec.LeaderWorkerTemplate.Size, field.NewPath("spec", "leaderWorkerTemplate", "size"))...)
-	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, *oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, field.NewPath("spec", "leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"))...)
+	allErrs = append(allErrs, validateFeatureGates(newLws, oldLws)...)
+	if !newLws.Spec.AllowDisruption {
+		allErrs = append(allErrs, validateDisruptiveUpdate(newLws, oldLws)...)
+	}
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(utils.SubdomainPolicy(newLws), utils.SubdomainPolicy(oldLws), specPath.Child("networkConfig", "subdomainPolicy"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newLws.Spec.TerminationPolicy, oldLws.Spec.TerminationPolicy, specPath.Child("terminationPolicy"))...)
+	// The serving readiness gate of the leader pods can't be added or removed once created.
+	if (newLws.Spec.DrainSeconds == nil) != (oldLws.Spec.DrainSeconds == nil) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("drainSeconds"), "drainSeconds can't be set or unset after creation"))
+	}
+	// The volumeClaimTemplates of the statefulsets are immutable.
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newLws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates, oldLws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates, specPath.Child("leaderWorkerTemplate", "volumeClaimTemplates"))...)
+	return warnings, allErrs.ToAggregate()
+}
+
+// validateFeatureGates rejects the fields of the disabled features, unless the old lws already sets them
+// so that the existing lws can still be updated after a feature is disabled. oldLws is nil on creation.
+func validateFeatureGates(newLws, oldLws *v1.LeaderWorkerSet) field.ErrorList {
+	var allErrs field.ErrorList
+	specPath := field.NewPath("spec")
+	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && !features.Enabled(features.SubGroups) &&
+		(oldLws == nil || oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("leaderWorkerTemplate", "subGroupPolicy"),
+			fmt.Sprintf("requires the %s feature gate", features.SubGroups)))
+	}
+	if newLws.Spec.FailurePolicy != nil && !features.Enabled(features.FailurePolicy) &&
+		(oldLws == nil || oldLws.Spec.FailurePolicy == nil) {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("failurePolicy"),
+			fmt.Sprintf("requires the %s feature gate", features.FailurePolicy)))
+	}
+	return allErrs
+}
+
+// validateDisruptiveUpdate rejects the updates which recreate all the groups, i.e. of the size,
+// the subGroupSize and the exclusive-topology annotation, unless spec.allowDisruption is set.
+func validateDisruptiveUpdate(newLws, oldLws *v1.LeaderWorkerSet) field.ErrorList {
+	var allErrs field.ErrorList
+	templatePath := field.NewPath("spec", "leaderWorkerTemplate")
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.Size, *oldLws.Spec.LeaderWorkerTemplate.Size, templatePath.Child("size"))...)
+	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil &&
+		newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize != nil {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(*newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, *oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, templatePath.Child("SubGroupPolicy", "subGroupSize"))...)
 	}
 	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot enable subGroupSize after the lws is already created"))
+		allErrs = append(allErrs, field.Invalid(templatePath.Child("SubGroupPolicy", "subGroupSize"), newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot enable subGroupSize after the lws is already created unless spec.allowDisruption is set"))
 	}
 	if newLws.Spec.LeaderWorkerTemplate.SubGroupPolicy == nil && oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot remove subGroupSize after enabled"))
+		allErrs = append(allErrs, field.Invalid(templatePath.Child("SubGroupPolicy", "subGroupSize"), oldLws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize, "cannot remove subGroupSize after enabled unless spec.allowDisruption is set"))
 	}
-	return warnings, allErrs.ToAggregate()
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newLws.Annotations[v1.ExclusiveKeyAnnotationKey], oldLws.Annotations[v1.ExclusiveKeyAnnotationKey], field.NewPath("metadata", "annotations").Key(v1.ExclusiveKeyAnnotationKey))...)
+	return allErrs
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -115,38 +184,41 @@ func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) (admission.
 	if lws.Spec.Replicas != nil && *lws.Spec.Replicas < 0 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, "replicas must be equal or greater than 0"))
 	}
+	if lws.Spec.WarmReplicas < 0 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("warmReplicas"), lws.Spec.WarmReplicas, "warmReplicas must be equal or greater than 0"))
+	}
+	if lws.Spec.WarmReplicas > 0 && lws.Spec.TerminationPolicy == v1.LeaderSucceededTerminationPolicy {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("warmReplicas"), lws.Spec.WarmReplicas, "warmReplicas is not supported with the LeaderSucceeded terminationPolicy"))
+	}
+	if lws.Spec.TTLSecondsAfterFinished != nil {
+		if *lws.Spec.TTLSecondsAfterFinished < 0 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), lws.Spec.TTLSecondsAfterFinished, "ttlSecondsAfterFinished must be equal or greater than 0"))
+		}
+		if lws.Spec.TerminationPolicy != v1.LeaderSucceededTerminationPolicy {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("ttlSecondsAfterFinished"), lws.Spec.TTLSecondsAfterFinished, "ttlSecondsAfterFinished is only supported with the LeaderSucceeded terminationPolicy"))
+		}
+	}
 	if *lws.Spec.LeaderWorkerTemplate.Size < 1 {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "size"), lws.Spec.LeaderWorkerTemplate.Size, "size must be equal or greater than 1"))
 	}
-	if int64(*lws.Spec.Replicas)*int64(*lws.Spec.LeaderWorkerTemplate.Size) > math.MaxInt32 {
-		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, fmt.Sprintf("the product of replicas and worker replicas must not exceed %d", math.MaxInt32)))
+	if (int64(*lws.Spec.Replicas)+int64(lws.Spec.WarmReplicas))*int64(*lws.Spec.LeaderWorkerTemplate.Size) > math.MaxInt32 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("replicas"), lws.Spec.Replicas, fmt.Sprintf("the product of replicas, including the warm ones, and worker replicas must not exceed %d", math.MaxInt32)))
 	}
 
-	maxUnavailable := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable
-	maxUnavailablePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxUnavailable")
 	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxUnavailable, maxUnavailablePath)...)
-		// This is aligned with Statefulset.
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxUnavailable, maxUnavailablePath)...)
+		allErrs = append(allErrs, validateRollingUpdateConfiguration(specPath, lws)...)
 	}
-
-	maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
-	maxSurgePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxSurge")
-	if lws.Spec.RolloutStrategy.RollingUpdateConfiguration != nil {
-		allErrs = append(allErrs, validatePositiveIntOrPercent(maxSurge, maxSurgePath)...)
-		allErrs = append(allErrs, isNotMoreThan100Percent(maxSurge, maxSurgePath)...)
+	if lws.Spec.RolloutStrategy.Type == v1.CanaryStrategyType {
+		allErrs = append(allErrs, validateCanaryConfiguration(specPath.Child("rolloutStrategy", "canaryConfiguration"), lws.Spec.RolloutStrategy.CanaryConfiguration)...)
 	}
-	maxUnavailableValue, err := intstr.GetValueFromIntOrPercent(&maxUnavailable, int(*lws.Spec.Replicas), false)
-	if err != nil {
-		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "invalid value"))
+	if lws.Spec.RolloutStrategy.Hooks != nil {
+		allErrs = append(allErrs, validateRolloutHooks(specPath.Child("rolloutStrategy", "hooks"), lws.Spec.RolloutStrategy)...)
 	}
-	maxSurgeValue, err := intstr.GetValueFromIntOrPercent(&maxSurge, int(*lws.Spec.Replicas), true)
-	if err != nil {
-		allErrs = append(allErrs, field.Invalid(maxSurgePath, maxSurge, "invalid value"))
+	if lws.Spec.FailurePolicy != nil {
+		allErrs = append(allErrs, validateFailurePolicy(specPath.Child("failurePolicy"), lws.Spec.FailurePolicy)...)
 	}
-	if maxUnavailableValue == 0 && maxSurgeValue == 0 {
-		// Both MaxSurge and MaxUnavailable cannot be zero.
-		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "must not be 0 when `maxSurge` is 0"))
+	if lws.Spec.RevisionHistoryLimit != nil {
+		allErrs = append(allErrs, validateNonnegativeField(int64(*lws.Spec.RevisionHistoryLimit), specPath.Child("revisionHistoryLimit"))...)
 	}
 
 	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil {
@@ -156,8 +228,122 @@ func (r *LeaderWorkerSetWebhook) generalValidate(obj runtime.Object) (admission.
 			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey], "cannot have subgroup-exclusive-topology without subGroupSize set"))
 		}
 	}
+	allErrs = append(allErrs, validateExclusivePlacement(metadataPath, lws)...)
+	if lws.Spec.LeaderWorkerTemplate.EnvVarNaming != nil {
+		allErrs = append(allErrs, validateEnvVarNaming(specPath.Child("leaderWorkerTemplate", "envVarNaming"), lws.Spec.LeaderWorkerTemplate.EnvVarNaming)...)
+	}
+	if policy := lws.Spec.LeaderWorkerTemplate.PreemptionPolicy; policy != nil {
+		allErrs = append(allErrs, metav1validation.ValidateLabels(policy.FallbackNodeSelector, specPath.Child("leaderWorkerTemplate", "preemptionPolicy", "fallbackNodeSelector"))...)
+	}
+	if len(lws.Spec.LeaderWorkerTemplate.WorkerTemplates) > 0 {
+		allErrs = append(allErrs, validateWorkerTemplates(specPath.Child("leaderWorkerTemplate", "workerTemplates"), lws.Spec.LeaderWorkerTemplate.WorkerTemplates, *lws.Spec.LeaderWorkerTemplate.Size)...)
+	}
+	for i, override := range lws.Spec.LeaderWorkerTemplate.GroupOverrides {
+		if override.EndIndex < override.StartIndex {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("leaderWorkerTemplate", "groupOverrides").Index(i).Child("endIndex"), override.EndIndex, "must be greater than or equal to startIndex"))
+		}
+	}
+	if prefetch := lws.Spec.LeaderWorkerTemplate.ModelPrefetch; prefetch != nil {
+		allErrs = append(allErrs, validateModelPrefetch(specPath.Child("leaderWorkerTemplate", "modelPrefetch"), prefetch)...)
+	}
+	for i, constraint := range lws.Spec.LeaderWorkerTemplate.GroupSpreadConstraints {
+		allErrs = append(allErrs, metav1validation.ValidateLabelName(constraint.TopologyKey, specPath.Child("leaderWorkerTemplate", "groupSpreadConstraints").Index(i).Child("topologyKey"))...)
+	}
+	if policy := lws.Spec.LeaderWorkerTemplate.PriorityPolicy; policy != nil {
+		allErrs = append(allErrs, validatePriorityPolicy(specPath.Child("leaderWorkerTemplate", "priorityPolicy"), policy)...)
+	}
+	if lws.Spec.LeaderService != nil {
+		allErrs = append(allErrs, validateLeaderService(specPath.Child("leaderService"), lws.Spec.LeaderService)...)
+	}
+	if lws.Spec.DisruptionBudget != nil {
+		maxUnavailablePath := specPath.Child("disruptionBudget", "maxUnavailable")
+		allErrs = append(allErrs, validatePositiveIntOrPercent(lws.Spec.DisruptionBudget.MaxUnavailable, maxUnavailablePath)...)
+		allErrs = append(allErrs, isNotMoreThan100Percent(lws.Spec.DisruptionBudget.MaxUnavailable, maxUnavailablePath)...)
+	}
+	if lws.Spec.NetworkConfig != nil {
+		allErrs = append(allErrs, validateIPFamilies(specPath.Child("networkConfig"), lws.Spec.NetworkConfig)...)
+	}
+	allErrs = append(allErrs, validateTemplatedEnv(specPath.Child("leaderWorkerTemplate", "templatedEnv"), lws.Spec.LeaderWorkerTemplate.TemplatedEnv)...)
+	allErrs = append(allErrs, validateTPUTopology(specPath.Child("leaderWorkerTemplate"), lws)...)
+	if lws.Annotations[v1.GroupSchedulingGateAnnotationKey] == "true" {
+		// Worker pods are not created until the leader pod is ready or scheduled, which would never happen with the gate.
+		if lws.Spec.StartupPolicy == v1.LeaderReadyStartupPolicy {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.GroupSchedulingGateAnnotationKey), lws.Annotations[v1.GroupSchedulingGateAnnotationKey], "cannot be used together with the LeaderReady startup policy"))
+		}
+		if _, found := lws.Annotations[v1.ExclusiveKeyAnnotationKey]; found {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.GroupSchedulingGateAnnotationKey), lws.Annotations[v1.GroupSchedulingGateAnnotationKey], "cannot be used together with exclusive-topology"))
+		}
+	}
+	// The leader pod is not ready until the workers are ready, which are not created until then with LeaderReady.
+	if lws.Annotations[v1.GroupReadinessGateAnnotationKey] == "true" && lws.Spec.StartupPolicy == v1.LeaderReadyStartupPolicy {
+		allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.GroupReadinessGateAnnotationKey), lws.Annotations[v1.GroupReadinessGateAnnotationKey], "cannot be used together with the LeaderReady startup policy"))
+	}
+	if className, found := lws.Annotations[v1.ProvisioningClassNameAnnotationKey]; found {
+		classPath := metadataPath.Child("annotations", v1.ProvisioningClassNameAnnotationKey)
+		if !slices.Contains(provisioningutils.SupportedClasses, className) {
+			allErrs = append(allErrs, field.NotSupported(classPath, className, provisioningutils.SupportedClasses))
+		}
+		// Pods are released to the scheduler by removing the scheduling gate once provisioned.
+		if lws.Annotations[v1.GroupSchedulingGateAnnotationKey] != "true" {
+			allErrs = append(allErrs, field.Invalid(classPath, className, fmt.Sprintf("requires the %s annotation to be true", v1.GroupSchedulingGateAnnotationKey)))
+		}
+	}
+	if providerType, found := lws.Annotations[v1.GangSchedulingAnnotationKey]; found {
+		if _, err := schedulerprovider.NewSchedulerProvider(schedulerprovider.ProviderType(providerType)); err != nil {
+			allErrs = append(allErrs, field.NotSupported(metadataPath.Child("annotations", v1.GangSchedulingAnnotationKey), providerType, supportedProviders()))
+		}
+	}
+
+	return validationWarnings(lws), allErrs
+}
+
+const annotationPrefix = "leaderworkerset.sigs.k8s.io/"
+
+// leaderWorkerSetAnnotations are the annotations of the lws taking effect, the other annotations
+// of the same prefix are either set on the pods by lws or typos.
+var leaderWorkerSetAnnotations = sets.New(
+	v1.ExclusiveKeyAnnotationKey,
+	v1.SubGroupExclusiveKeyAnnotationKey,
+	v1.ExclusivePlacementPolicyAnnotationKey,
+	v1.RollbackToAnnotationKey,
+	v1.RestartGroupAnnotationKey,
+	v1.RestartedAtAnnotationKey,
+	v1.GangSchedulingAnnotationKey,
+	v1.GroupSchedulingGateAnnotationKey,
+	v1.ProvisioningClassNameAnnotationKey,
+	v1.DisableAcceleratorEnvAnnotationKey,
+	v1.GroupReadinessGateAnnotationKey,
+	v1.GroupEvictionAnnotationKey,
+	v1.GroupPreemptionAnnotationKey,
+)
+
+// validationWarnings warns about the settings taking no effect or likely leaving the groups unschedulable,
+// which are valid otherwise.
+func validationWarnings(lws *v1.LeaderWorkerSet) admission.Warnings {
+	var warnings admission.Warnings
+	for _, key := range sets.List(sets.KeySet(lws.Annotations)) {
+		if strings.HasPrefix(key, annotationPrefix) && !leaderWorkerSetAnnotations.Has(key) {
+			warnings = append(warnings, fmt.Sprintf("metadata.annotations[%s]: unknown annotation, it takes no effect", key))
+		}
+	}
 
-	return nil, allErrs
+	topologyKey, found := lws.Annotations[v1.ExclusiveKeyAnnotationKey]
+	if !found {
+		return warnings
+	}
+	leaderSpec := lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		leaderSpec = lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec
+	}
+	if affinity := leaderSpec.Affinity; lws.Annotations[v1.ExclusivePlacementPolicyAnnotationKey] != string(v1.ExclusivePlacementNodeSelector) && affinity != nil &&
+		((affinity.PodAffinity != nil && len(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0) ||
+			(affinity.PodAntiAffinity != nil && len(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution) > 0)) {
+		warnings = append(warnings, "the required pod affinities of the leader template are combined with the ones of the exclusive placement, which may leave the groups unschedulable")
+	}
+	if _, found := lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.NodeSelector[topologyKey]; found {
+		warnings = append(warnings, fmt.Sprintf("the nodeSelector %s of the worker template is overridden by the topology of the leader pod with the exclusive placement", topologyKey))
+	}
+	return warnings
 }
 
 // This is mostly inspired by https://github.com/kubernetes/kubernetes/blob/be4b7176dc131ea842cab6882cd4a06dbfeed12a/pkg/apis/apps/validation/validation.go#L460,
@@ -210,8 +396,48 @@ func validateNonnegativeField(value int64, fldPath *field.Path) field.ErrorList
 	return allErrs
 }
 
+// validateRollingUpdateConfiguration validates maxUnavailable and maxSurge, they can not be both 0,
+// and partition, which can not be negative.
+func validateRollingUpdateConfiguration(specPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	allErrs := field.ErrorList{}
+	replicas := int(ptr.Deref(lws.Spec.Replicas, 1))
+
+	maxUnavailable := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable
+	maxUnavailablePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxUnavailable")
+	allErrs = append(allErrs, validatePositiveIntOrPercent(maxUnavailable, maxUnavailablePath)...)
+	// This is aligned with Statefulset.
+	allErrs = append(allErrs, isNotMoreThan100Percent(maxUnavailable, maxUnavailablePath)...)
+
+	maxSurge := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge
+	maxSurgePath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "maxSurge")
+	allErrs = append(allErrs, validatePositiveIntOrPercent(maxSurge, maxSurgePath)...)
+	allErrs = append(allErrs, isNotMoreThan100Percent(maxSurge, maxSurgePath)...)
+
+	maxUnavailableValue, err := intstr.GetValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "invalid value"))
+	}
+	maxSurgeValue, err := intstr.GetValueFromIntOrPercent(&maxSurge, replicas, true)
+	if err != nil {
+		allErrs = append(allErrs, field.Invalid(maxSurgePath, maxSurge, "invalid value"))
+	}
+	if maxUnavailableValue == 0 && maxSurgeValue == 0 {
+		// Both MaxSurge and MaxUnavailable cannot be zero.
+		allErrs = append(allErrs, field.Invalid(maxUnavailablePath, maxUnavailable, "must not be 0 when `maxSurge` is 0"))
+	}
+
+	partition := lws.Spec.RolloutStrategy.RollingUpdateConfiguration.Partition
+	partitionPath := specPath.Child("rolloutStrategy", "rollingUpdateConfiguration", "partition")
+	allErrs = append(allErrs, validateNonnegativeField(int64(partition), partitionPath)...)
+	return allErrs
+}
+
 func validateUpdateSubGroupPolicy(specPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
 	allErrs := field.ErrorList{}
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize == nil {
+		allErrs = append(allErrs, field.Required(specPath.Child("leaderWorkerTemplate", "SubGroupPolicy", "subGroupSize"), "subGroupSize must be set when subGroupPolicy is specified"))
+		return allErrs
+	}
 	size := int32(*lws.Spec.LeaderWorkerTemplate.Size)
 	subGroupSize := int32(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize)
 	if subGroupSize < 1 {
@@ -225,3 +451,236 @@ func validateUpdateSubGroupPolicy(specPath *field.Path, lws *v1.LeaderWorkerSet)
 	}
 	return allErrs
 }
+
+func validateFailurePolicy(fldPath *field.Path, failurePolicy *v1.FailurePolicy) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if failurePolicy.MaxRestarts != nil {
+		allErrs = append(allErrs, validateNonnegativeField(int64(*failurePolicy.MaxRestarts), fldPath.Child("maxRestarts"))...)
+	}
+	if failurePolicy.RestartBackoffSeconds != nil {
+		allErrs = append(allErrs, validateNonnegativeField(int64(*failurePolicy.RestartBackoffSeconds), fldPath.Child("restartBackoffSeconds"))...)
+	}
+	for i, rule := range failurePolicy.Rules {
+		if len(rule.OnExitCodes) == 0 && len(rule.OnPodConditionReasons) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("rules").Index(i), "either onExitCodes or onPodConditionReasons must be set"))
+		}
+	}
+	return allErrs
+}
+
+func supportedProviders() []string {
+	var providers []string
+	for _, provider := range schedulerprovider.Providers {
+		providers = append(providers, string(provider))
+	}
+	return providers
+}
+
+// validateLeaderService ensures the leader service exposes at least one valid port.
+func validateLeaderService(fldPath *field.Path, leaderService *v1.LeaderService) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(leaderService.Ports) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("ports"), "at least one port is required"))
+	}
+	for i, port := range leaderService.Ports {
+		for _, msg := range utilvalidation.IsValidPortNum(int(port.Port)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ports").Index(i).Child("port"), port.Port, msg))
+		}
+	}
+	return allErrs
+}
+
+// validatePriorityPolicy ensures the PriorityClass names are valid object names.
+func validatePriorityPolicy(fldPath *field.Path, policy *v1.PriorityPolicy) field.ErrorList {
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validatePriorityClassName(fldPath.Child("leaderPriorityClassName"), policy.LeaderPriorityClassName)...)
+	allErrs = append(allErrs, validatePriorityClassName(fldPath.Child("workerPriorityClassName"), policy.WorkerPriorityClassName)...)
+	return allErrs
+}
+
+func validatePriorityClassName(fldPath *field.Path, name string) field.ErrorList {
+	var allErrs field.ErrorList
+	if name == "" {
+		return allErrs
+	}
+	for _, msg := range apivalidation.NameIsDNSSubdomain(name, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath, name, msg))
+	}
+	return allErrs
+}
+
+// validateWorkerTemplates ensures the worker template names are valid label values, and the
+// named worker templates don't cover more workers than a group has.
+func validateWorkerTemplates(fldPath *field.Path, templates []v1.NamedWorkerTemplate, size int32) field.ErrorList {
+	var allErrs field.ErrorList
+	var count int32
+	for i, t := range templates {
+		for _, msg := range utilvalidation.IsDNS1123Label(t.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), t.Name, msg))
+		}
+		count += t.Count
+	}
+	if count > size-1 {
+		allErrs = append(allErrs, field.Invalid(fldPath, count, fmt.Sprintf("the sum of the counts must not be greater than the number of workers of a group (%d)", size-1)))
+	}
+	return allErrs
+}
+
+// validateModelPrefetch ensures the model URI has a scheme, and the mount path is absolute.
+func validateModelPrefetch(fldPath *field.Path, prefetch *v1.ModelPrefetch) field.ErrorList {
+	var allErrs field.ErrorList
+	if u, err := url.Parse(prefetch.URI); err != nil || u.Scheme == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("uri"), prefetch.URI, "must be a URI with a scheme, e.g. oci:// or s3://"))
+	}
+	if prefetch.MountPath != "" && !path.IsAbs(prefetch.MountPath) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("mountPath"), prefetch.MountPath, "must be an absolute path"))
+	}
+	return allErrs
+}
+
+// validateRolloutHooks ensures the hooks are only set with the RollingUpdate rollout strategy,
+// and the pods of the Jobs have containers and are not restarted always. The CRD doesn't carry
+// the schema of the pod templates of the hooks.
+func validateRolloutHooks(fldPath *field.Path, strategy v1.RolloutStrategy) field.ErrorList {
+	var allErrs field.ErrorList
+	if strategy.Type != v1.RollingUpdateStrategyType {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "only supported with the RollingUpdate rollout strategy"))
+	}
+	hooks := []struct {
+		name string
+		hook *v1.RolloutHook
+	}{{"preGroupUpdate", strategy.Hooks.PreGroupUpdate}, {"postGroupUpdate", strategy.Hooks.PostGroupUpdate}}
+	for _, h := range hooks {
+		if h.hook == nil {
+			continue
+		}
+		if len(h.hook.Template.Spec.Containers) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child(h.name, "template", "spec", "containers"), "must have at least one container"))
+		}
+		restartPolicy := h.hook.Template.Spec.RestartPolicy
+		if restartPolicy != "" && restartPolicy != corev1.RestartPolicyNever && restartPolicy != corev1.RestartPolicyOnFailure {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child(h.name, "template", "spec", "restartPolicy"), restartPolicy,
+				[]string{string(corev1.RestartPolicyNever), string(corev1.RestartPolicyOnFailure)}))
+		}
+	}
+	return allErrs
+}
+
+// validateCanaryConfiguration ensures the canary configuration is set with the Canary rollout strategy,
+// and the HTTPRoute name is valid.
+func validateCanaryConfiguration(fldPath *field.Path, config *v1.CanaryConfiguration) field.ErrorList {
+	var allErrs field.ErrorList
+	if config == nil {
+		return append(allErrs, field.Required(fldPath, "must be set with the Canary rollout strategy"))
+	}
+	for _, msg := range apivalidation.NameIsDNSSubdomain(config.HTTPRoute, false) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("httpRoute"), config.HTTPRoute, msg))
+	}
+	return allErrs
+}
+
+// validateIPFamilies ensures the IP families of the created services are valid and consistent
+// with the IP family policy.
+func validateIPFamilies(fldPath *field.Path, networkConfig *v1.NetworkConfig) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, family := range networkConfig.IPFamilies {
+		if family != corev1.IPv4Protocol && family != corev1.IPv6Protocol {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("ipFamilies").Index(i), family, []string{string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)}))
+		} else if slices.Contains(networkConfig.IPFamilies[:i], family) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("ipFamilies").Index(i), family))
+		}
+	}
+	if policy := networkConfig.IPFamilyPolicy; policy != nil && *policy == corev1.IPFamilyPolicySingleStack && len(networkConfig.IPFamilies) > 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ipFamilies"), networkConfig.IPFamilies, "only one ip family is allowed with the SingleStack policy"))
+	}
+	return allErrs
+}
+
+// validateEnvVarNaming ensures the injected env vars are renamed to valid env var names.
+func validateEnvVarNaming(fldPath *field.Path, naming *v1.EnvVarNaming) field.ErrorList {
+	var allErrs field.ErrorList
+	if naming.Prefix != "" {
+		for _, msg := range utilvalidation.IsEnvVarName(naming.Prefix) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("prefix"), naming.Prefix, msg))
+		}
+	}
+	for name, rename := range naming.Renames {
+		for _, msg := range utilvalidation.IsEnvVarName(rename) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("renames").Key(name), rename, msg))
+		}
+	}
+	return allErrs
+}
+
+// validateTemplatedEnv ensures the templated env vars have valid names and are expandable.
+func validateTemplatedEnv(fldPath *field.Path, envs []v1.TemplatedEnvVar) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, env := range envs {
+		for _, msg := range utilvalidation.IsEnvVarName(env.Name) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), env.Name, msg))
+		}
+		if _, err := podutils.ExpandEnvTemplate(env.Value, podutils.EnvTemplateData{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("value"), env.Value, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateTPUTopology ensures the group size matches the TPU topology declared by the templates.
+func validateTPUTopology(fldPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	var allErrs field.ErrorList
+	size := int(*lws.Spec.LeaderWorkerTemplate.Size)
+	subGroupSize := 0
+	if lws.Spec.LeaderWorkerTemplate.SubGroupPolicy != nil && lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize != nil {
+		subGroupSize = int(*lws.Spec.LeaderWorkerTemplate.SubGroupPolicy.SubGroupSize)
+	}
+	leaderSpec := lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec
+	leaderPath := fldPath.Child("workerTemplate")
+	if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+		leaderSpec = lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec
+		leaderPath = fldPath.Child("leaderTemplate")
+	}
+	hosts := acceleratorutils.TPUHostsPerSlice(size, subGroupSize, acceleratorutils.PodRequestsTPUs(leaderSpec))
+	if err := acceleratorutils.ValidateTPUTopology(lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec, hosts); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("size"), size, err.Error()))
+	} else if err := acceleratorutils.ValidateTPUTopology(leaderSpec, hosts); err != nil {
+		allErrs = append(allErrs, field.Invalid(leaderPath, size, err.Error()))
+	}
+	return allErrs
+}
+
+// validateExclusivePlacement validates the exclusive topology annotations. Group and subgroup
+// exclusive placement can not share the same topology when there are multiple subgroups, since
+// the group affinity would pin all subgroups to one domain the subgroup anti-affinity forbids.
+func validateExclusivePlacement(metadataPath *field.Path, lws *v1.LeaderWorkerSet) field.ErrorList {
+	allErrs := field.ErrorList{}
+	epKey, foundEpKey := lws.Annotations[v1.ExclusiveKeyAnnotationKey]
+	if foundEpKey {
+		for _, msg := range utilvalidation.IsQualifiedName(epKey) {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.ExclusiveKeyAnnotationKey), epKey, msg))
+		}
+	}
+	if policy, found := lws.Annotations[v1.ExclusivePlacementPolicyAnnotationKey]; found {
+		policyPath := metadataPath.Child("annotations", v1.ExclusivePlacementPolicyAnnotationKey)
+		switch {
+		case policy != string(v1.ExclusivePlacementAffinity) && policy != string(v1.ExclusivePlacementNodeSelector):
+			allErrs = append(allErrs, field.NotSupported(policyPath, policy, []string{string(v1.ExclusivePlacementAffinity), string(v1.ExclusivePlacementNodeSelector)}))
+		case !foundEpKey:
+			allErrs = append(allErrs, field.Invalid(policyPath, policy, fmt.Sprintf("requires the %s annotation", v1.ExclusiveKeyAnnotationKey)))
+		}
+	}
+	subEpKey, foundSubEpKey := lws.Annotations[v1.SubGroupExclusiveKeyAnnotationKey]
+	if foundSubEpKey {
+		for _, msg := range utilvalidation.IsQualifiedName(subEpKey) {
+			allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), subEpKey, msg))
+		}
+	}
+	if !foundEpKey || !foundSubEpKey || epKey != subEpKey {
+		return allErrs
+	}
+	subGroupPolicy := lws.Spec.LeaderWorkerTemplate.SubGroupPolicy
+	if subGroupPolicy != nil && subGroupPolicy.SubGroupSize != nil && *subGroupPolicy.SubGroupSize < *lws.Spec.LeaderWorkerTemplate.Size {
+		allErrs = append(allErrs, field.Invalid(metadataPath.Child("annotations", v1.SubGroupExclusiveKeyAnnotationKey), subEpKey, "cannot use the same topology as exclusive-topology when there are multiple subgroups"))
+	}
+	return allErrs
+}
@@ -20,8 +20,17 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	v1 "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/features"
+	featuregatetesting "sigs.k8s.io/lws/pkg/features/testing"
+	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
+	testutils "sigs.k8s.io/lws/test/testutils"
 )
 
 func TestGetPercentValue(t *testing.T) {
@@ -184,3 +193,646 @@ func TestIsNotMoreThan100Percent(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRollingUpdateConfiguration(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable intstr.IntOrString
+		maxSurge       intstr.IntOrString
+		partition      int32
+		wantErrs       int
+	}{
+		{
+			name:           "maxUnavailable is 1, maxSurge is 0",
+			maxUnavailable: intstr.FromInt32(1),
+			maxSurge:       intstr.FromInt32(0),
+		},
+		{
+			name:           "maxUnavailable is 0, maxSurge is 1",
+			maxUnavailable: intstr.FromInt32(0),
+			maxSurge:       intstr.FromInt32(1),
+		},
+		{
+			name:           "maxUnavailable and maxSurge are both 0",
+			maxUnavailable: intstr.FromInt32(0),
+			maxSurge:       intstr.FromInt32(0),
+			wantErrs:       1,
+		},
+		{
+			name:           "maxSurge greater than 100%",
+			maxUnavailable: intstr.FromInt32(1),
+			maxSurge:       intstr.FromString("200%"),
+			wantErrs:       1,
+		},
+		{
+			name:           "negative maxUnavailable",
+			maxUnavailable: intstr.FromInt32(-1),
+			maxSurge:       intstr.FromInt32(1),
+			wantErrs:       1,
+		},
+		{
+			name:           "positive partition",
+			maxUnavailable: intstr.FromInt32(1),
+			maxSurge:       intstr.FromInt32(0),
+			partition:      2,
+		},
+		{
+			name:           "negative partition",
+			maxUnavailable: intstr.FromInt32(1),
+			maxSurge:       intstr.FromInt32(0),
+			partition:      -1,
+			wantErrs:       1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable = tc.maxUnavailable
+			lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge = tc.maxSurge
+			lws.Spec.RolloutStrategy.RollingUpdateConfiguration.Partition = tc.partition
+			errs := validateRollingUpdateConfiguration(field.NewPath("spec"), lws)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateExclusivePlacement(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		subGroupSize *int32
+		wantErrs     int
+	}{
+		{
+			name:        "no exclusive placement",
+			annotations: map[string]string{},
+		},
+		{
+			name:        "valid group exclusive placement",
+			annotations: map[string]string{v1.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+		},
+		{
+			name:        "invalid group exclusive topology",
+			annotations: map[string]string{v1.ExclusiveKeyAnnotationKey: "invalid topology"},
+			wantErrs:    1,
+		},
+		{
+			name:         "invalid subgroup exclusive topology",
+			annotations:  map[string]string{v1.SubGroupExclusiveKeyAnnotationKey: "kubernetes.io/-hostname"},
+			subGroupSize: ptr.To[int32](2),
+			wantErrs:     1,
+		},
+		{
+			name: "group and subgroup exclusive placement on different topologies",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:         "cloud.google.com/gke-nodepool",
+				v1.SubGroupExclusiveKeyAnnotationKey: "kubernetes.io/hostname",
+			},
+			subGroupSize: ptr.To[int32](2),
+		},
+		{
+			name: "group and subgroup exclusive placement on the same topology with multiple subgroups",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:         "cloud.google.com/gke-nodepool",
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+			},
+			subGroupSize: ptr.To[int32](2),
+			wantErrs:     1,
+		},
+		{
+			name: "group and subgroup exclusive placement on the same topology with a single subgroup",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:         "cloud.google.com/gke-nodepool",
+				v1.SubGroupExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool",
+			},
+			subGroupSize: ptr.To[int32](4),
+		},
+		{
+			name: "NodeSelector exclusive placement policy",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:             "cloud.google.com/gke-nodepool",
+				v1.ExclusivePlacementPolicyAnnotationKey: string(v1.ExclusivePlacementNodeSelector),
+			},
+		},
+		{
+			name: "unsupported exclusive placement policy",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:             "cloud.google.com/gke-nodepool",
+				v1.ExclusivePlacementPolicyAnnotationKey: "Taint",
+			},
+			wantErrs: 1,
+		},
+		{
+			name:        "exclusive placement policy without exclusive topology",
+			annotations: map[string]string{v1.ExclusivePlacementPolicyAnnotationKey: string(v1.ExclusivePlacementAffinity)},
+			wantErrs:    1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Size(4).Annotation(tc.annotations).Obj()
+			if tc.subGroupSize != nil {
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &v1.SubGroupPolicy{SubGroupSize: tc.subGroupSize}
+			}
+			errs := validateExclusivePlacement(field.NewPath("metadata"), lws)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateTPUTopology(t *testing.T) {
+	tests := []struct {
+		name               string
+		size               int
+		subGroupSize       *int32
+		topology           string
+		leaderRequestsTPUs bool
+		wantErrs           int
+	}{
+		{
+			name:     "no topology declared",
+			size:     3,
+			topology: "",
+		},
+		{
+			name:               "size matches the topology",
+			size:               4,
+			topology:           "4x4",
+			leaderRequestsTPUs: true,
+		},
+		{
+			name:     "leader without TPUs is not counted",
+			size:     5,
+			topology: "4x4",
+		},
+		{
+			name:               "size doesn't match the topology",
+			size:               4,
+			topology:           "4x4x8",
+			leaderRequestsTPUs: true,
+			wantErrs:           1,
+		},
+		{
+			name:               "each subgroup is a slice",
+			size:               8,
+			subGroupSize:       ptr.To[int32](4),
+			topology:           "4x4",
+			leaderRequestsTPUs: true,
+		},
+		{
+			name:               "subgroup size doesn't match the topology",
+			size:               8,
+			subGroupSize:       ptr.To[int32](2),
+			topology:           "4x4",
+			leaderRequestsTPUs: true,
+			wantErrs:           1,
+		},
+		{
+			name:               "invalid topology",
+			size:               4,
+			topology:           "4xfour",
+			leaderRequestsTPUs: true,
+			wantErrs:           1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Size(tc.size).Obj()
+			if tc.subGroupSize != nil {
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &v1.SubGroupPolicy{SubGroupSize: tc.subGroupSize}
+			}
+			templates := []*corev1.PodTemplateSpec{&lws.Spec.LeaderWorkerTemplate.WorkerTemplate}
+			if tc.leaderRequestsTPUs {
+				templates = append(templates, lws.Spec.LeaderWorkerTemplate.LeaderTemplate)
+			}
+			for _, template := range templates {
+				if tc.topology != "" {
+					template.Spec.NodeSelector = map[string]string{acceleratorutils.TpuTopologyNodeSelectorKey: tc.topology}
+				}
+				template.Spec.Containers[0].Resources.Limits = corev1.ResourceList{acceleratorutils.TpuResourceName: resource.MustParse("4")}
+			}
+			errs := validateTPUTopology(field.NewPath("spec", "leaderWorkerTemplate"), lws)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateEnvVarNaming(t *testing.T) {
+	tests := []struct {
+		name     string
+		naming   *v1.EnvVarNaming
+		wantErrs int
+	}{
+		{
+			name:   "valid prefix and renames",
+			naming: &v1.EnvVarNaming{Prefix: "MY_", Renames: map[string]string{v1.LwsLeaderAddress: "RAY_HEAD_ADDRESS"}},
+		},
+		{
+			name:     "invalid prefix",
+			naming:   &v1.EnvVarNaming{Prefix: "1="},
+			wantErrs: 1,
+		},
+		{
+			name:     "invalid rename",
+			naming:   &v1.EnvVarNaming{Renames: map[string]string{v1.LwsLeaderAddress: "RAY HEAD ADDRESS"}},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateEnvVarNaming(field.NewPath("spec", "leaderWorkerTemplate", "envVarNaming"), tc.naming)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateTemplatedEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		envs     []v1.TemplatedEnvVar
+		wantErrs int
+	}{
+		{
+			name: "valid templated env vars",
+			envs: []v1.TemplatedEnvVar{
+				{Name: "RAY_HEAD_ADDRESS", Value: "{{.LeaderAddress}}:6379"},
+				{Name: "NODE_ID", Value: "{{.GroupIndex}}-{{.WorkerIndex}}-of-{{.Size}}"},
+			},
+		},
+		{
+			name:     "invalid name",
+			envs:     []v1.TemplatedEnvVar{{Name: "RAY HEAD", Value: "{{.LeaderAddress}}"}},
+			wantErrs: 1,
+		},
+		{
+			name:     "malformed template",
+			envs:     []v1.TemplatedEnvVar{{Name: "RAY_HEAD_ADDRESS", Value: "{{.LeaderAddress"}},
+			wantErrs: 1,
+		},
+		{
+			name:     "unknown field",
+			envs:     []v1.TemplatedEnvVar{{Name: "RAY_HEAD_ADDRESS", Value: "{{.HeadAddress}}"}},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateTemplatedEnv(field.NewPath("spec", "leaderWorkerTemplate", "templatedEnv"), tc.envs)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateLeaderService(t *testing.T) {
+	tests := []struct {
+		name          string
+		leaderService *v1.LeaderService
+		wantErrs      int
+	}{
+		{
+			name:          "valid ports",
+			leaderService: &v1.LeaderService{Ports: []corev1.ServicePort{{Name: "http", Port: 8080}}},
+		},
+		{
+			name:          "no ports",
+			leaderService: &v1.LeaderService{},
+			wantErrs:      1,
+		},
+		{
+			name:          "invalid port",
+			leaderService: &v1.LeaderService{Ports: []corev1.ServicePort{{Name: "http", Port: 70000}}},
+			wantErrs:      1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateLeaderService(field.NewPath("spec", "leaderService"), tc.leaderService)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateIPFamilies(t *testing.T) {
+	tests := []struct {
+		name          string
+		networkConfig *v1.NetworkConfig
+		wantErrs      int
+	}{
+		{
+			name:          "cluster defaults",
+			networkConfig: &v1.NetworkConfig{},
+		},
+		{
+			name: "dual stack",
+			networkConfig: &v1.NetworkConfig{
+				IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicyRequireDualStack),
+				IPFamilies:     []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			},
+		},
+		{
+			name:          "unsupported ip family",
+			networkConfig: &v1.NetworkConfig{IPFamilies: []corev1.IPFamily{"IPv5"}},
+			wantErrs:      1,
+		},
+		{
+			name:          "duplicate ip families",
+			networkConfig: &v1.NetworkConfig{IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv6Protocol}},
+			wantErrs:      1,
+		},
+		{
+			name: "two ip families with single stack",
+			networkConfig: &v1.NetworkConfig{
+				IPFamilyPolicy: ptr.To(corev1.IPFamilyPolicySingleStack),
+				IPFamilies:     []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateIPFamilies(field.NewPath("spec", "networkConfig"), tc.networkConfig)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidatePriorityPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   *v1.PriorityPolicy
+		wantErrs int
+	}{
+		{
+			name:   "templates priority classes",
+			policy: &v1.PriorityPolicy{},
+		},
+		{
+			name:   "distinct priority classes",
+			policy: &v1.PriorityPolicy{LeaderPriorityClassName: "high-priority", WorkerPriorityClassName: "low-priority"},
+		},
+		{
+			name:     "invalid priority class names",
+			policy:   &v1.PriorityPolicy{LeaderPriorityClassName: "High_Priority", WorkerPriorityClassName: "Low_Priority"},
+			wantErrs: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validatePriorityPolicy(field.NewPath("spec", "leaderWorkerTemplate", "priorityPolicy"), tc.policy)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateWorkerTemplates(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []v1.NamedWorkerTemplate
+		wantErrs  int
+	}{
+		{
+			name:      "templates covering part of the workers",
+			templates: []v1.NamedWorkerTemplate{{Name: "prefill", Count: 1}, {Name: "decode", Count: 1}},
+		},
+		{
+			name:      "templates covering all the workers",
+			templates: []v1.NamedWorkerTemplate{{Name: "prefill", Count: 1}, {Name: "decode", Count: 2}},
+		},
+		{
+			name:      "templates covering more than the workers",
+			templates: []v1.NamedWorkerTemplate{{Name: "prefill", Count: 2}, {Name: "decode", Count: 2}},
+			wantErrs:  1,
+		},
+		{
+			name:      "invalid template name",
+			templates: []v1.NamedWorkerTemplate{{Name: "Prefill_Workers", Count: 1}},
+			wantErrs:  1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateWorkerTemplates(field.NewPath("spec", "leaderWorkerTemplate", "workerTemplates"), tc.templates, 4)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateModelPrefetch(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefetch *v1.ModelPrefetch
+		wantErrs int
+	}{
+		{
+			name:     "OCI image",
+			prefetch: &v1.ModelPrefetch{URI: "oci://registry.example.com/models/llama:v1", Image: "downloader", MountPath: "/models"},
+		},
+		{
+			name:     "object storage",
+			prefetch: &v1.ModelPrefetch{URI: "gs://bucket/llama", Image: "downloader", MountPath: "/data/models"},
+		},
+		{
+			name:     "URI without scheme",
+			prefetch: &v1.ModelPrefetch{URI: "bucket/llama", Image: "downloader", MountPath: "/models"},
+			wantErrs: 1,
+		},
+		{
+			name:     "relative mount path",
+			prefetch: &v1.ModelPrefetch{URI: "s3://bucket/llama", Image: "downloader", MountPath: "models"},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateModelPrefetch(field.NewPath("spec", "leaderWorkerTemplate", "modelPrefetch"), tc.prefetch)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateRolloutHooks(t *testing.T) {
+	containers := []corev1.Container{{Name: "probe", Image: "busybox"}}
+	hook := &v1.RolloutHook{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers, RestartPolicy: corev1.RestartPolicyOnFailure}}}
+	tests := []struct {
+		name     string
+		strategy v1.RolloutStrategy
+		wantErrs int
+	}{
+		{
+			name:     "hooks with RollingUpdate",
+			strategy: v1.RolloutStrategy{Type: v1.RollingUpdateStrategyType, Hooks: &v1.RolloutHooks{PreGroupUpdate: hook, PostGroupUpdate: hook}},
+		},
+		{
+			name:     "hooks with OnDelete",
+			strategy: v1.RolloutStrategy{Type: v1.OnDeleteStrategyType, Hooks: &v1.RolloutHooks{PreGroupUpdate: hook}},
+			wantErrs: 1,
+		},
+		{
+			name: "hook restarted always",
+			strategy: v1.RolloutStrategy{Type: v1.RollingUpdateStrategyType, Hooks: &v1.RolloutHooks{
+				PostGroupUpdate: &v1.RolloutHook{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: containers, RestartPolicy: corev1.RestartPolicyAlways}}},
+			}},
+			wantErrs: 1,
+		},
+		{
+			name: "hook without containers",
+			strategy: v1.RolloutStrategy{Type: v1.RollingUpdateStrategyType, Hooks: &v1.RolloutHooks{
+				PreGroupUpdate: &v1.RolloutHook{},
+			}},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateRolloutHooks(field.NewPath("spec", "rolloutStrategy", "hooks"), tc.strategy)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidateFeatureGates(t *testing.T) {
+	withFailurePolicy := func() *v1.LeaderWorkerSet {
+		lws := testutils.BuildLeaderWorkerSet("default").Obj()
+		lws.Spec.FailurePolicy = &v1.FailurePolicy{MaxConcurrentRestarts: ptr.To[int32](1)}
+		return lws
+	}
+	tests := []struct {
+		name     string
+		enabled  bool
+		newLws   *v1.LeaderWorkerSet
+		oldLws   *v1.LeaderWorkerSet
+		wantErrs int
+	}{
+		{
+			name:    "create with the feature enabled",
+			enabled: true,
+			newLws:  withFailurePolicy(),
+		},
+		{
+			name:     "create with the feature disabled",
+			newLws:   withFailurePolicy(),
+			wantErrs: 1,
+		},
+		{
+			name:     "set on update with the feature disabled",
+			newLws:   withFailurePolicy(),
+			oldLws:   testutils.BuildLeaderWorkerSet("default").Obj(),
+			wantErrs: 1,
+		},
+		{
+			name:   "already set on update with the feature disabled",
+			newLws: withFailurePolicy(),
+			oldLws: withFailurePolicy(),
+		},
+		{
+			name:   "unset with the feature disabled",
+			newLws: testutils.BuildLeaderWorkerSet("default").Obj(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, features.FailurePolicy, tc.enabled)
+			errs := validateFeatureGates(tc.newLws, tc.oldLws)
+			if len(errs) != tc.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tc.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestValidationWarnings(t *testing.T) {
+	requiredAntiAffinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "kubernetes.io/hostname"}},
+	}}
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		affinity     *corev1.Affinity
+		nodeSelector map[string]string
+		wantWarnings int
+	}{
+		{
+			name: "known annotations",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:  "cloud.google.com/gke-nodepool",
+				v1.RestartedAtAnnotationKey:   "2024-01-01T00:00:00Z",
+				"example.com/owner":           "team-a",
+				v1.GroupEvictionAnnotationKey: "true",
+			},
+		},
+		{
+			name:         "unknown annotation",
+			annotations:  map[string]string{"leaderworkerset.sigs.k8s.io/exclusive-topolgy": "cloud.google.com/gke-nodepool"},
+			wantWarnings: 1,
+		},
+		{
+			name:         "exclusive placement with required pod affinities",
+			annotations:  map[string]string{v1.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+			affinity:     requiredAntiAffinity,
+			wantWarnings: 1,
+		},
+		{
+			name: "NodeSelector exclusive placement with required pod affinities",
+			annotations: map[string]string{
+				v1.ExclusiveKeyAnnotationKey:             "cloud.google.com/gke-nodepool",
+				v1.ExclusivePlacementPolicyAnnotationKey: string(v1.ExclusivePlacementNodeSelector),
+			},
+			affinity: requiredAntiAffinity,
+		},
+		{
+			name:         "exclusive placement with the topology node selector",
+			annotations:  map[string]string{v1.ExclusiveKeyAnnotationKey: "cloud.google.com/gke-nodepool"},
+			nodeSelector: map[string]string{"cloud.google.com/gke-nodepool": "pool-a"},
+			wantWarnings: 1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Annotation(tc.annotations).Obj()
+			if lws.Spec.LeaderWorkerTemplate.LeaderTemplate != nil {
+				lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec.Affinity = tc.affinity
+			} else {
+				lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.Affinity = tc.affinity
+			}
+			lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec.NodeSelector = tc.nodeSelector
+			if warnings := validationWarnings(lws); len(warnings) != tc.wantWarnings {
+				t.Errorf("expected %d warnings, got %d: %v", tc.wantWarnings, len(warnings), warnings)
+			}
+		})
+	}
+}
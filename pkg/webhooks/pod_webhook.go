@@ -17,33 +17,53 @@ package webhooks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/metrics"
+	"sigs.k8s.io/lws/pkg/schedulerprovider"
+	"sigs.k8s.io/lws/pkg/tracing"
 	"sigs.k8s.io/lws/pkg/utils"
 	acceleratorutils "sigs.k8s.io/lws/pkg/utils/accelerators"
 	podutils "sigs.k8s.io/lws/pkg/utils/pod"
 	statefulsetutils "sigs.k8s.io/lws/pkg/utils/statefulset"
 )
 
-type PodWebhook struct{}
+type PodWebhook struct {
+	// client reads the lws of the pods, e.g. to find out the preempted groups.
+	client client.Client
+	// apiReader reads the lws missing from the cache, e.g. those of the other shards.
+	apiReader client.Reader
+}
 
 func SetupPodWebhook(mgr ctrl.Manager) error {
+	webhook := &PodWebhook{client: mgr.GetClient(), apiReader: mgr.GetAPIReader()}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&corev1.Pod{}).
-		WithDefaulter(&PodWebhook{}).
-		WithValidator(&PodWebhook{}).
+		WithDefaulter(webhook).
+		WithValidator(webhook).
 		Complete()
 }
 
+// The pod webhooks are registered with an objectSelector on the leaderworkerset.sigs.k8s.io/name label,
+// so that the other pods of the cluster don't go through them. controller-gen can't generate it, it's
+// patched in config/webhook/mutating-patch.yaml and config/webhook/validating-patch.yaml.
 //+kubebuilder:webhook:path=/validate--v1-pod,mutating=false,failurePolicy=fail,sideEffects=None,groups="",resources=pods,verbs=create;update,versions=v1,name=vpod.kb.io,sideEffects=None,admissionReviewVersions=v1
 
 // validate admits a pod if a specific annotation exists.
@@ -58,10 +78,29 @@ func (p *PodWebhook) validate(ctx context.Context, obj runtime.Object) (admissio
 
 	// if pod is not part of leaderworkerset, skip
 	_, found := pod.Labels[leaderworkerset.SetNameLabelKey]
-	if !found {
+	if !found || podutils.SkipInjection(*pod) {
 		return nil, nil
 	}
 
+	if acceleratorutils.PodRequestsTPUs(pod.Spec) {
+		size, err := strconv.Atoi(pod.Annotations[leaderworkerset.SizeAnnotationKey])
+		if err != nil {
+			return nil, err
+		}
+		subGroupSize := 0
+		if value, found := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]; found {
+			if subGroupSize, err = strconv.Atoi(value); err != nil {
+				return nil, err
+			}
+		}
+		// The annotation is only set on the workers, the leader pod itself requests TPUs here.
+		leaderRequestsTPUs := podutils.LeaderPod(*pod) || pod.Annotations[acceleratorutils.LeaderRequestsTPUsAnnotationKey] == "true"
+		hosts := acceleratorutils.TPUHostsPerSlice(size, subGroupSize, leaderRequestsTPUs)
+		if err := acceleratorutils.ValidateTPUTopology(pod.Spec, hosts); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
@@ -79,7 +118,10 @@ func (p *PodWebhook) ValidateDelete(ctx context.Context, obj runtime.Object) (ad
 
 //+kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=fail,groups="",resources=pods,verbs=create;update,versions=v1,name=mpod.kb.io,sideEffects=None,admissionReviewVersions=v1
 
-func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
+func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) (err error) {
+	defer metrics.WebhookMutated("pod", time.Now())
+	ctx, span := tracing.Start(ctx, "Admit Pod")
+	defer func() { tracing.End(span, err) }()
 	log := logf.FromContext(ctx)
 	pod, ok := obj.(*corev1.Pod)
 	if !ok {
@@ -89,9 +131,18 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 	log.V(2).Info("Defaulting Pod")
 	// if pod is not part of leaderworkerset, skip
 	_, found := pod.Labels[leaderworkerset.SetNameLabelKey]
-	if !found {
+	if !found || podutils.SkipInjection(*pod) {
 		return nil
 	}
+	// Record what is changed at creation for debugging, e.g. the scheduling issues.
+	if pod.CreationTimestamp.IsZero() {
+		original := pod.DeepCopy()
+		defer func() {
+			if err == nil {
+				err = recordMutations(original, pod)
+			}
+		}()
+	}
 	size, exist := pod.Annotations[leaderworkerset.SizeAnnotationKey]
 	if !exist {
 		return fmt.Errorf("size annotation is unexpectedly missing for pod %s", pod.Name)
@@ -118,7 +169,9 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 		} else {
 			groupUniqueKey = pod.Labels[leaderworkerset.GroupUniqueHashLabelKey]
 		}
-		if epKey, foundEpKey := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; foundEpKey {
+		// The NodeSelector exclusive placement policy is enforced by the pod controller instead.
+		epKey, foundEpKey := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]
+		if foundEpKey && pod.Annotations[leaderworkerset.ExclusivePlacementPolicyAnnotationKey] != string(leaderworkerset.ExclusivePlacementNodeSelector) {
 			SetExclusiveAffinities(pod, groupUniqueKey, epKey, leaderworkerset.GroupUniqueHashLabelKey)
 		}
 		_, foundSubGroupSize := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
@@ -138,6 +191,12 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 			return fmt.Errorf("parsing pod ordinal for pod %s", pod.Name)
 		}
 		pod.Labels[leaderworkerset.WorkerIndexLabelKey] = fmt.Sprint(workerIndex)
+		// The pod spec can't be updated once the pod is created.
+		if pod.CreationTimestamp.IsZero() {
+			if err := applyWorkerTemplate(pod, workerIndex); err != nil {
+				return err
+			}
+		}
 		subGroupSize, foundSubGroupSize := pod.Annotations[leaderworkerset.SubGroupSizeAnnotationKey]
 		if foundSubGroupSize && pod.Labels[leaderworkerset.SubGroupIndexLabelKey] == "" {
 			subGroupSizeInt, err := strconv.Atoi(subGroupSize)
@@ -155,27 +214,202 @@ func (p *PodWebhook) Default(ctx context.Context, obj runtime.Object) error {
 		}
 	}
 
-	// injecting env vars if needed
-	if acceleratorutils.PodRequestsTPUs(pod.Spec) {
-		if err := acceleratorutils.AddTPUVariables(pod, podCount); err != nil {
+	// Scheduling gates can only be added on creation.
+	if pod.CreationTimestamp.IsZero() && pod.Annotations[leaderworkerset.GroupSchedulingGateAnnotationKey] == "true" {
+		podutils.AddSchedulingGate(pod, leaderworkerset.GroupSchedulingGateName)
+	}
+	// Readiness gates can only be added on creation as well.
+	if podutils.LeaderPod(*pod) && pod.CreationTimestamp.IsZero() && pod.Annotations[leaderworkerset.GroupReadinessGateAnnotationKey] == "true" {
+		podutils.AddReadinessGate(pod, leaderworkerset.WorkersReadyConditionType)
+	}
+
+	// Pods of the preempted groups are moved to the fallback nodes, node selectors can't be updated once scheduled.
+	if pod.CreationTimestamp.IsZero() && p.client != nil {
+		if err := p.setFallbackNodeSelector(ctx, pod); err != nil {
+			return err
+		}
+	}
+
+	if providerType, found := pod.Annotations[leaderworkerset.GangSchedulingAnnotationKey]; found {
+		provider, err := schedulerprovider.NewSchedulerProvider(schedulerprovider.ProviderType(providerType))
+		if err != nil {
 			return err
 		}
+		provider.InjectPodGroupMetadata(pod)
 	}
 
-	if err := podutils.AddLWSVariables(pod); err != nil {
+	// The leader statefulset is shared by all the groups, so the leader pod is moved to the
+	// headless service of its group here, the workers get it from the worker statefulset.
+	if podutils.LeaderPod(*pod) && pod.CreationTimestamp.IsZero() &&
+		pod.Annotations[leaderworkerset.SubdomainPolicyAnnotationKey] == string(leaderworkerset.SubdomainUniquePerReplica) {
+		pod.Spec.Subdomain = pod.Name
+	}
+
+	// Env vars can't be updated once the pod is created.
+	if pod.CreationTimestamp.IsZero() {
+		if err := podutils.InjectEnvVars(pod, podCount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// podMutations records what the pod webhook changed on the pod.
+type podMutations struct {
+	Labels          []string `json:"labels,omitempty"`
+	NodeSelector    []string `json:"nodeSelector,omitempty"`
+	Affinity        bool     `json:"affinity,omitempty"`
+	EnvVars         []string `json:"envVars,omitempty"`
+	SchedulingGates []string `json:"schedulingGates,omitempty"`
+	ReadinessGates  []string `json:"readinessGates,omitempty"`
+}
+
+// recordMutations adds the mutations annotation to the pod with what is changed since the original pod, if any.
+func recordMutations(original, pod *corev1.Pod) error {
+	var mutations podMutations
+	mutations.Labels = sets.List(sets.KeySet(pod.Labels).Difference(sets.KeySet(original.Labels)))
+	mutations.NodeSelector = sets.List(sets.KeySet(pod.Spec.NodeSelector).Difference(sets.KeySet(original.Spec.NodeSelector)))
+	mutations.Affinity = !equality.Semantic.DeepEqual(original.Spec.Affinity, pod.Spec.Affinity)
+	mutations.EnvVars = sets.List(envVarNames(pod).Difference(envVarNames(original)))
+	for _, gate := range pod.Spec.SchedulingGates {
+		if !slices.Contains(original.Spec.SchedulingGates, gate) {
+			mutations.SchedulingGates = append(mutations.SchedulingGates, gate.Name)
+		}
+	}
+	for _, gate := range pod.Spec.ReadinessGates {
+		if !slices.Contains(original.Spec.ReadinessGates, gate) {
+			mutations.ReadinessGates = append(mutations.ReadinessGates, string(gate.ConditionType))
+		}
+	}
+	if equality.Semantic.DeepEqual(mutations, podMutations{}) {
+		return nil
+	}
+	value, err := json.Marshal(mutations)
+	if err != nil {
 		return err
 	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[leaderworkerset.MutationsAnnotationKey] = string(value)
+	return nil
+}
 
+// envVarNames returns the env vars of the containers of the pod, as container/name.
+func envVarNames(pod *corev1.Pod) sets.Set[string] {
+	names := sets.New[string]()
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			for _, envVar := range container.Env {
+				names.Insert(container.Name + "/" + envVar.Name)
+			}
+		}
+	}
+	return names
+}
+
+// setFallbackNodeSelector merges the fallback node selector of the preemption policy into the pod
+// if its group has been preempted.
+func (p *PodWebhook) setFallbackNodeSelector(ctx context.Context, pod *corev1.Pod) error {
+	var lws leaderworkerset.LeaderWorkerSet
+	key := types.NamespacedName{Name: pod.Labels[leaderworkerset.SetNameLabelKey], Namespace: pod.Namespace}
+	err := p.client.Get(ctx, key, &lws)
+	if apierrors.IsNotFound(err) && p.apiReader != nil {
+		err = p.apiReader.Get(ctx, key, &lws)
+	}
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	policy := lws.Spec.LeaderWorkerTemplate.PreemptionPolicy
+	if policy == nil || len(policy.FallbackNodeSelector) == 0 {
+		return nil
+	}
+	for _, status := range lws.Status.ReplicaStatuses {
+		if fmt.Sprint(status.Index) == pod.Labels[leaderworkerset.GroupIndexLabelKey] && status.Preempted {
+			setNodeSelector(pod, policy.FallbackNodeSelector)
+		}
+	}
+	return nil
+}
+
+// applyWorkerTemplate replaces the spec of the worker with the named worker template its worker index falls into,
+// the workers out of the named worker templates are kept as created from the workerTemplate. The hostname and the
+// subdomain set by the statefulset controller and the node selector of the exclusive placement are kept.
+func applyWorkerTemplate(pod *corev1.Pod, workerIndex int) error {
+	value, found := pod.Annotations[leaderworkerset.WorkerTemplatesAnnotationKey]
+	if !found {
+		return nil
+	}
+	// The templates are only needed at creation, don't carry them over in every worker.
+	delete(pod.Annotations, leaderworkerset.WorkerTemplatesAnnotationKey)
+	var templates []leaderworkerset.NamedWorkerTemplate
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return err
+	}
+	template, templateIndex := workerTemplateOf(templates, workerIndex)
+	if template == nil {
+		return nil
+	}
+
+	spec := template.Template.Spec.DeepCopy()
+	spec.Hostname = pod.Spec.Hostname
+	spec.Subdomain = pod.Spec.Subdomain
+	if topologyKey, found := pod.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey]; found {
+		if topologyValue, found := pod.Spec.NodeSelector[topologyKey]; found {
+			if spec.NodeSelector == nil {
+				spec.NodeSelector = map[string]string{}
+			}
+			spec.NodeSelector[topologyKey] = topologyValue
+		}
+	}
+	pod.Spec = *spec
+
+	// The labels and annotations set by lws take precedence.
+	for key, value := range template.Template.Labels {
+		if _, found := pod.Labels[key]; !found {
+			pod.Labels[key] = value
+		}
+	}
+	for key, value := range template.Template.Annotations {
+		if _, found := pod.Annotations[key]; !found {
+			pod.Annotations[key] = value
+		}
+	}
+	pod.Labels[leaderworkerset.WorkerTemplateLabelKey] = template.Name
+	pod.Labels[leaderworkerset.WorkerTemplateIndexLabelKey] = fmt.Sprint(templateIndex)
 	return nil
 }
 
+// workerTemplateOf returns the named worker template the worker index falls into, and the index of the worker
+// among the workers of the template. It returns nil if the worker is created from the workerTemplate.
+func workerTemplateOf(templates []leaderworkerset.NamedWorkerTemplate, workerIndex int) (*leaderworkerset.NamedWorkerTemplate, int) {
+	start := 1
+	for i := range templates {
+		if workerIndex < start+int(templates[i].Count) {
+			return &templates[i], workerIndex - start
+		}
+		start += int(templates[i].Count)
+	}
+	return nil, 0
+}
+
+func setNodeSelector(pod *corev1.Pod, nodeSelector map[string]string) {
+	if pod.Spec.NodeSelector == nil {
+		pod.Spec.NodeSelector = make(map[string]string, len(nodeSelector))
+	}
+	for key, value := range nodeSelector {
+		pod.Spec.NodeSelector[key] = value
+	}
+}
+
 func genGroupUniqueKey(ns string, podName string) string {
 	return utils.Sha1Hash(fmt.Sprintf("%s/%s", ns, podName))
 }
 
 // SetExclusiveAffinities set the pod affinity/anti-affinity
 func SetExclusiveAffinities(pod *corev1.Pod, groupUniqueKey string, topologyKey string, podAffinityKey string) {
-	if exclusiveAffinityApplied(*pod, topologyKey) {
+	if exclusiveAffinityApplied(*pod, topologyKey, podAffinityKey) {
 		return
 	}
 	if pod.Spec.Affinity == nil {
@@ -218,26 +452,40 @@ func SetExclusiveAffinities(pod *corev1.Pod, groupUniqueKey string, topologyKey
 		})
 }
 
-// exclusiveAffinityApplied return true if the exclusive placement terms have been applied
-func exclusiveAffinityApplied(pod corev1.Pod, topologyKey string) bool {
+// exclusiveAffinityApplied return true if the exclusive placement terms have been applied.
+// Both the topology key and the label key are matched, so that group and subgroup
+// exclusive placement can share the same topology key.
+func exclusiveAffinityApplied(pod corev1.Pod, topologyKey string, podAffinityKey string) bool {
 	if pod.Spec.Affinity == nil || pod.Spec.Affinity.PodAffinity == nil || pod.Spec.Affinity.PodAntiAffinity == nil {
 		return false
 	}
 	hasAffinity := false
 	hasAntiAffinity := false
 	for _, podAffinityTerm := range pod.Spec.Affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
-		if podAffinityTerm.TopologyKey == topologyKey {
+		if exclusiveAffinityTermMatches(podAffinityTerm, topologyKey, podAffinityKey) {
 			hasAffinity = true
 		}
 	}
 	for _, term := range pod.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
-		if term.TopologyKey == topologyKey {
+		if exclusiveAffinityTermMatches(term, topologyKey, podAffinityKey) {
 			hasAntiAffinity = true
 		}
 	}
 	return hasAffinity && hasAntiAffinity
 }
 
+func exclusiveAffinityTermMatches(term corev1.PodAffinityTerm, topologyKey string, podAffinityKey string) bool {
+	if term.TopologyKey != topologyKey || term.LabelSelector == nil {
+		return false
+	}
+	for _, expr := range term.LabelSelector.MatchExpressions {
+		if expr.Key == podAffinityKey {
+			return true
+		}
+	}
+	return false
+}
+
 func getSubGroupIndex(podCount int, subGroupSize int, workerIndex int) string {
 	if (podCount-1)%subGroupSize == 0 {
 		// Leader is considered as extra pod, it is part of the first group
@@ -17,13 +17,18 @@ limitations under the License.
 package webhooks
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/test/testutils"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func TestGenGroupUniqueKey(t *testing.T) {
@@ -64,6 +69,11 @@ func TestGenGroupUniqueKey(t *testing.T) {
 }
 
 func TestSetExclusiveAffinities(t *testing.T) {
+	groupSelector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      leaderworkerset.GroupUniqueHashLabelKey,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{"test-key"},
+	}}}
 	tests := []struct {
 		name           string
 		pod            *corev1.Pod
@@ -129,10 +139,10 @@ func TestSetExclusiveAffinities(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
@@ -147,10 +157,10 @@ func TestSetExclusiveAffinities(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
@@ -169,11 +179,17 @@ func TestSetExclusiveAffinities(t *testing.T) {
 }
 
 func TestExclusiveAffinityApplied(t *testing.T) {
+	groupSelector := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      leaderworkerset.GroupUniqueHashLabelKey,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{"test-key"},
+	}}}
 	tests := []struct {
 		name                              string
 		pod                               corev1.Pod
 		expectedAppliedExclusivePlacement bool
 		topologyKey                       string
+		podAffinityKey                    string
 	}{
 		{
 			name: "Has annotiation, Pod Affinity and Pod AntiAffinity",
@@ -186,16 +202,17 @@ func TestExclusiveAffinityApplied(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
 			},
 			expectedAppliedExclusivePlacement: true,
 			topologyKey:                       "topologyKey",
+			podAffinityKey:                    leaderworkerset.GroupUniqueHashLabelKey,
 		},
 		{
 			name: "Has annotiation, Pod Affinity, doesn't have Pod AntiAffinity",
@@ -208,13 +225,14 @@ func TestExclusiveAffinityApplied(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
 			},
 			expectedAppliedExclusivePlacement: false,
 			topologyKey:                       "topologyKey",
+			podAffinityKey:                    leaderworkerset.GroupUniqueHashLabelKey,
 		},
 		{
 			name: "Has annotiation, Pod AntiAffinity, doesn't have Pod Affinity",
@@ -227,13 +245,14 @@ func TestExclusiveAffinityApplied(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
 			},
 			expectedAppliedExclusivePlacement: false,
 			topologyKey:                       "topologyKey",
+			podAffinityKey:                    leaderworkerset.GroupUniqueHashLabelKey,
 		},
 		{
 			name: "Has annotiation, Pod Affinity and Pod AntiAffinity, Topology Key doesn't match",
@@ -246,25 +265,290 @@ func TestExclusiveAffinityApplied(t *testing.T) {
 				Spec: corev1.PodSpec{
 					Affinity: &corev1.Affinity{
 						PodAffinity: &corev1.PodAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey1"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey1", LabelSelector: groupSelector}},
 						},
 						PodAntiAffinity: &corev1.PodAntiAffinity{
-							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey"}},
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
 						},
 					},
 				},
 			},
 			expectedAppliedExclusivePlacement: false,
 			topologyKey:                       "topologyKey",
+			podAffinityKey:                    leaderworkerset.GroupUniqueHashLabelKey,
+		},
+		{
+			name: "Has group exclusive affinities on the same topology key, subgroup affinities not applied",
+			pod: corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"leaderworkerset.sigs.k8s.io/exclusive-topology":          "topologyKey",
+						"leaderworkerset.sigs.k8s.io/subgroup-exclusive-topology": "topologyKey",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Affinity: &corev1.Affinity{
+						PodAffinity: &corev1.PodAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
+						},
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{{TopologyKey: "topologyKey", LabelSelector: groupSelector}},
+						},
+					},
+				},
+			},
+			expectedAppliedExclusivePlacement: false,
+			topologyKey:                       "topologyKey",
+			podAffinityKey:                    leaderworkerset.SubGroupUniqueHashLabelKey,
 		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			appliedExclusivePlacement := exclusiveAffinityApplied(tc.pod, tc.topologyKey)
+			appliedExclusivePlacement := exclusiveAffinityApplied(tc.pod, tc.topologyKey, tc.podAffinityKey)
 			if appliedExclusivePlacement != tc.expectedAppliedExclusivePlacement {
 				t.Errorf("Expected value %t, got %t", tc.expectedAppliedExclusivePlacement, appliedExclusivePlacement)
 			}
 		})
 	}
 }
+
+func TestSetFallbackNodeSelector(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := leaderworkerset.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name             string
+		groupIndex       string
+		preemptionPolicy *leaderworkerset.PreemptionPolicy
+		// lwsNotCached leaves the lws out of the cache, as for the lws of the other shards.
+		lwsNotCached     bool
+		wantNodeSelector map[string]string
+	}{
+		{
+			name:             "preempted group",
+			groupIndex:       "1",
+			preemptionPolicy: &leaderworkerset.PreemptionPolicy{FallbackNodeSelector: map[string]string{"pool": "on-demand"}},
+			wantNodeSelector: map[string]string{"zone": "a", "pool": "on-demand"},
+		},
+		{
+			name:             "group not preempted",
+			groupIndex:       "0",
+			preemptionPolicy: &leaderworkerset.PreemptionPolicy{FallbackNodeSelector: map[string]string{"pool": "on-demand"}},
+			wantNodeSelector: map[string]string{"zone": "a"},
+		},
+		{
+			name:             "preempted group of an lws not cached",
+			groupIndex:       "1",
+			preemptionPolicy: &leaderworkerset.PreemptionPolicy{FallbackNodeSelector: map[string]string{"pool": "on-demand"}},
+			lwsNotCached:     true,
+			wantNodeSelector: map[string]string{"zone": "a", "pool": "on-demand"},
+		},
+		{
+			name:             "no preemption policy",
+			groupIndex:       "1",
+			wantNodeSelector: map[string]string{"zone": "a"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lws := testutils.BuildLeaderWorkerSet("default").Obj()
+			lws.Spec.LeaderWorkerTemplate.PreemptionPolicy = tc.preemptionPolicy
+			lws.Status.ReplicaStatuses = []leaderworkerset.ReplicaStatus{{Index: 0}, {Index: 1, Preempted: true}}
+			webhook := &PodWebhook{
+				client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws).Build(),
+				apiReader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(lws).Build(),
+			}
+			if tc.lwsNotCached {
+				webhook.client = fake.NewClientBuilder().WithScheme(scheme).Build()
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-sample-1",
+					Namespace: "default",
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:    lws.Name,
+						leaderworkerset.GroupIndexLabelKey: tc.groupIndex,
+					},
+				},
+				Spec: corev1.PodSpec{NodeSelector: map[string]string{"zone": "a"}},
+			}
+			if err := webhook.setFallbackNodeSelector(context.Background(), pod); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantNodeSelector, pod.Spec.NodeSelector); diff != "" {
+				t.Errorf("Unexpected node selector (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyWorkerTemplate(t *testing.T) {
+	templates := []leaderworkerset.NamedWorkerTemplate{
+		{
+			Name:  "prefill",
+			Count: 2,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"role": "prefill", leaderworkerset.SetNameLabelKey: "other"}},
+				Spec: corev1.PodSpec{
+					NodeSelector: map[string]string{"pool": "prefill"},
+					Containers:   []corev1.Container{{Name: "prefill", Image: "prefill"}},
+				},
+			},
+		},
+		{
+			Name:  "decode",
+			Count: 1,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "decode", Image: "decode"}}},
+			},
+		},
+	}
+	raw, err := json.Marshal(templates)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name              string
+		workerIndex       int
+		wantTemplate      string
+		wantTemplateIndex string
+		wantImage         string
+		wantNodeSelector  map[string]string
+		wantLabels        map[string]string
+	}{
+		{
+			name:              "first worker of the first template",
+			workerIndex:       1,
+			wantTemplate:      "prefill",
+			wantTemplateIndex: "0",
+			wantImage:         "prefill",
+			wantNodeSelector:  map[string]string{"pool": "prefill", "topology": "a"},
+			wantLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:             "test-sample",
+				leaderworkerset.WorkerTemplateLabelKey:      "prefill",
+				leaderworkerset.WorkerTemplateIndexLabelKey: "0",
+				"role": "prefill",
+			},
+		},
+		{
+			name:              "first worker of the second template",
+			workerIndex:       3,
+			wantTemplate:      "decode",
+			wantTemplateIndex: "0",
+			wantImage:         "decode",
+			wantNodeSelector:  map[string]string{"topology": "a"},
+			wantLabels: map[string]string{
+				leaderworkerset.SetNameLabelKey:             "test-sample",
+				leaderworkerset.WorkerTemplateLabelKey:      "decode",
+				leaderworkerset.WorkerTemplateIndexLabelKey: "0",
+			},
+		},
+		{
+			name:             "worker out of the templates",
+			workerIndex:      4,
+			wantImage:        "worker",
+			wantNodeSelector: map[string]string{"pool": "worker", "topology": "a"},
+			wantLabels:       map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+					Annotations: map[string]string{
+						leaderworkerset.WorkerTemplatesAnnotationKey: string(raw),
+						leaderworkerset.ExclusiveKeyAnnotationKey:    "topology",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Hostname:     "test-sample-0-1",
+					Subdomain:    "test-sample",
+					NodeSelector: map[string]string{"pool": "worker", "topology": "a"},
+					Containers:   []corev1.Container{{Name: "worker", Image: "worker"}},
+				},
+			}
+			if err := applyWorkerTemplate(pod, tc.workerIndex); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, found := pod.Annotations[leaderworkerset.WorkerTemplatesAnnotationKey]; found {
+				t.Errorf("Expected the worker templates annotation to be removed")
+			}
+			if diff := cmp.Diff(tc.wantLabels, pod.Labels); diff != "" {
+				t.Errorf("Unexpected labels (-want,+got):\n%s", diff)
+			}
+			if pod.Spec.Containers[0].Image != tc.wantImage {
+				t.Errorf("Expected image %s, got %s", tc.wantImage, pod.Spec.Containers[0].Image)
+			}
+			if diff := cmp.Diff(tc.wantNodeSelector, pod.Spec.NodeSelector); diff != "" {
+				t.Errorf("Unexpected node selector (-want,+got):\n%s", diff)
+			}
+			if pod.Spec.Hostname != "test-sample-0-1" || pod.Spec.Subdomain != "test-sample" {
+				t.Errorf("Expected the hostname and subdomain to be kept, got %s.%s", pod.Spec.Hostname, pod.Spec.Subdomain)
+			}
+		})
+	}
+}
+
+func TestRecordMutations(t *testing.T) {
+	original := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-sample-1",
+			Labels: map[string]string{leaderworkerset.SetNameLabelKey: "test-sample"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "worker", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}}},
+		},
+	}
+	tests := []struct {
+		name            string
+		mutate          func(*corev1.Pod)
+		wantAnnotations map[string]string
+	}{
+		{
+			name:   "nothing changed",
+			mutate: func(*corev1.Pod) {},
+		},
+		{
+			name: "labels, env vars and affinity added",
+			mutate: func(pod *corev1.Pod) {
+				pod.Labels[leaderworkerset.WorkerIndexLabelKey] = "1"
+				pod.Labels[leaderworkerset.GroupIndexLabelKey] = "0"
+				pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: leaderworkerset.LwsLeaderAddress})
+				pod.Spec.Affinity = &corev1.Affinity{PodAffinity: &corev1.PodAffinity{}}
+			},
+			wantAnnotations: map[string]string{
+				leaderworkerset.MutationsAnnotationKey: `{"labels":["leaderworkerset.sigs.k8s.io/group-index","leaderworkerset.sigs.k8s.io/worker-index"],"affinity":true,"envVars":["worker/LWS_LEADER_ADDRESS"]}`,
+			},
+		},
+		{
+			name: "gates and node selector added",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"pool": "on-demand"}
+				pod.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: "gate"}}
+				pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: "Ready"}}
+			},
+			wantAnnotations: map[string]string{
+				leaderworkerset.MutationsAnnotationKey: `{"nodeSelector":["pool"],"schedulingGates":["gate"],"readinessGates":["Ready"]}`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := original.DeepCopy()
+			tc.mutate(pod)
+			if err := recordMutations(original, pod); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantAnnotations, pod.Annotations); diff != "" {
+				t.Errorf("Unexpected annotations (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
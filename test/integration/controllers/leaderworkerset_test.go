@@ -24,6 +24,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -31,6 +32,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	leaderworkerset "sigs.k8s.io/lws/api/leaderworkerset/v1"
+	"sigs.k8s.io/lws/pkg/utils"
+	provisioningutils "sigs.k8s.io/lws/pkg/utils/provisioning"
 	testing "sigs.k8s.io/lws/test/testutils"
 )
 
@@ -425,6 +428,36 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 				},
 			},
 		}),
+		ginkgo.Entry("Worker restart will only recreate the worker pod when restart policy is RecreateWorkerOnPodRestart", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
+				return testing.BuildLeaderWorkerSet(nsName).RestartPolicy(leaderworkerset.RecreateWorkerOnPodRestart).Replica(1).Size(3)
+			},
+			updates: []*update{
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						testing.CreateWorkerPodsForLeaderPod(ctx, leaderPod, k8sClient, *lws)
+						// restart the container of one worker pod
+						var worker corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0-1", Namespace: lws.Namespace}, &worker)).To(gomega.Succeed())
+						worker.Status.Phase = corev1.PodRunning
+						worker.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "worker", RestartCount: 1}}
+						gomega.Expect(k8sClient.Status().Update(ctx, &worker)).To(gomega.Succeed())
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						gomega.Eventually(func() bool {
+							var worker corev1.Pod
+							err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0-1", Namespace: lws.Namespace}, &worker)
+							return apierrors.IsNotFound(err) || (err == nil && worker.DeletionTimestamp != nil)
+						}, testing.Timeout, testing.Interval).Should(gomega.BeTrue())
+						var leaderPod corev1.Pod
+						gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-0", Namespace: lws.Namespace}, &leaderPod)).To(gomega.Succeed())
+						gomega.Expect(leaderPod.DeletionTimestamp == nil).To(gomega.BeTrue())
+					},
+				},
+			},
+		}),
 		ginkgo.Entry("Replicas are processing will set condition to progressing with correct message with correct event", &testCase{
 			makeLeaderWorkerSet: testing.BuildLeaderWorkerSet,
 			updates: []*update{
@@ -642,6 +675,128 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 				},
 			},
 		}),
+		ginkgo.Entry("rolling update is paused and resumed", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
+				return testing.BuildLeaderWorkerSet(nsName).Replica(4)
+			},
+			updates: []*update{
+				{
+					// Set lws to available condition.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 4)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 0)
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 4)
+					},
+				},
+				{
+					// Pause the rollout and update the worker template.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetRolloutPaused(ctx, k8sClient, lws, true)
+						testing.UpdateWorkerTemplate(ctx, k8sClient, lws)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 4)
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 4)
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 0)
+					},
+				},
+				{
+					// Resume the rollout, index-3 replica will be updated.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetRolloutPaused(ctx, k8sClient, lws, false)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderWorkerSetUpgradeInProgress(ctx, k8sClient, lws, "Rolling Upgrade is in progress")
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 3)
+					},
+				},
+				{
+					// Pause the rollout again, the partition will not move even index-3 replica is ready.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetRolloutPaused(ctx, k8sClient, lws, true)
+						testing.SetPodGroupToReady(ctx, k8sClient, lws.Name+"-3", lws)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectStatefulsetPartitionEqualTo(ctx, k8sClient, lws, 3)
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 4, 1)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("leaderworkerset is suspended and resumed", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
+				return testing.BuildLeaderWorkerSet(nsName).Replica(2).Suspend(true)
+			},
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderSetNotExist(ctx, lws, k8sClient)
+						testing.ExpectLeaderWorkerSetSuspended(ctx, k8sClient, lws, "LeaderWorkerSet is suspended")
+					},
+				},
+				{
+					// Resume the lws, groups will be created.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetSuspend(ctx, k8sClient, lws, false)
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 2)
+						testing.ExpectLeaderWorkerSetAvailable(ctx, k8sClient, lws, "All replicas are ready")
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 2, 2)
+					},
+				},
+				{
+					// Suspend the lws again, the leader statefulset will be deleted.
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetSuspend(ctx, k8sClient, lws, true)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderSetNotExist(ctx, lws, k8sClient)
+						testing.ExpectLeaderWorkerSetSuspended(ctx, k8sClient, lws, "LeaderWorkerSet is suspended")
+						testing.ExpectLeaderWorkerSetStatusReplicas(ctx, k8sClient, lws, 0, 0)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("headless service per replica", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
+				return testing.BuildLeaderWorkerSet(nsName).Replica(2).SubdomainPolicy(leaderworkerset.SubdomainUniquePerReplica)
+			},
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectValidLeaderStatefulSet(ctx, k8sClient, lws, 2)
+						testing.ExpectValidWorkerStatefulSets(ctx, lws, k8sClient, true)
+						testing.ExpectValidGroupServices(ctx, k8sClient, lws, 2)
+					},
+				},
+			},
+		}),
+		ginkgo.Entry("leader service selects the leader pods of ready groups", &testCase{
+			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
+				return testing.BuildLeaderWorkerSet(nsName).Replica(2).LeaderService(corev1.ServicePort{Name: "http", Port: 8080})
+			},
+			updates: []*update{
+				{
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderServiceSelectsReadyGroups(ctx, k8sClient, lws, 0)
+					},
+				},
+				{
+					lwsUpdateFn: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.SetPodGroupsToReady(ctx, k8sClient, lws, 2)
+					},
+					checkLWSState: func(lws *leaderworkerset.LeaderWorkerSet) {
+						testing.ExpectLeaderServiceSelectsReadyGroups(ctx, k8sClient, lws, 2)
+					},
+				},
+			},
+		}),
 		ginkgo.Entry("leaderTemplate changed with maxUnavailable greater than replicas", &testCase{
 			makeLeaderWorkerSet: func(nsName string) *testing.LeaderWorkerSetWrapper {
 				return testing.BuildLeaderWorkerSet(nsName).Replica(4).MaxUnavailable(10)
@@ -1555,6 +1710,86 @@ var _ = ginkgo.Describe("LeaderWorkerSet controller", func() {
 	) // end of DescribeTable
 }) // end of Describe
 
+var _ = ginkgo.Describe("LeaderWorkerSet controller with provisioning requests", func() {
+	ginkgo.It("ungates the group once its provisioning request is provisioned", func() {
+		ctx := context.Background()
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "lws-ns-"}}
+		gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+		lws := testing.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).Annotation(map[string]string{
+			leaderworkerset.GroupSchedulingGateAnnotationKey:   "true",
+			leaderworkerset.ProvisioningClassNameAnnotationKey: provisioningutils.CheckCapacityClass,
+		}).Obj()
+		gomega.Expect(k8sClient.Create(ctx, lws)).To(gomega.Succeed())
+		var leaderSts appsv1.StatefulSet
+		testing.GetLeaderStatefulset(ctx, lws, k8sClient, &leaderSts)
+
+		ginkgo.By("creating the gated pods of the group")
+		for workerIndex := 0; workerIndex < 2; workerIndex++ {
+			name, spec := lws.Name+"-0", lws.Spec.LeaderWorkerTemplate.LeaderTemplate.Spec
+			if workerIndex > 0 {
+				name, spec = fmt.Sprintf("%s-0-%d", lws.Name, workerIndex), lws.Spec.LeaderWorkerTemplate.WorkerTemplate.Spec
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns.Name,
+					Labels: map[string]string{
+						leaderworkerset.SetNameLabelKey:         lws.Name,
+						leaderworkerset.GroupIndexLabelKey:      "0",
+						leaderworkerset.WorkerIndexLabelKey:     fmt.Sprint(workerIndex),
+						leaderworkerset.GroupUniqueHashLabelKey: "randomValue",
+						leaderworkerset.TemplateRevisionHashKey: utils.LeaderWorkerTemplateHash(lws),
+					},
+					Annotations: map[string]string{
+						leaderworkerset.SizeAnnotationKey:                "2",
+						leaderworkerset.GroupSchedulingGateAnnotationKey: "true",
+					},
+				},
+				Spec: *spec.DeepCopy(),
+			}
+			pod.Spec.SchedulingGates = []corev1.PodSchedulingGate{{Name: leaderworkerset.GroupSchedulingGateName}}
+			gomega.Expect(k8sClient.Create(ctx, pod)).To(gomega.Succeed())
+		}
+		gatedPods := func() (int, error) {
+			var pods corev1.PodList
+			if err := k8sClient.List(ctx, &pods, client.InNamespace(ns.Name), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name}); err != nil {
+				return 0, err
+			}
+			gated := 0
+			for _, pod := range pods.Items {
+				if len(pod.Spec.SchedulingGates) > 0 {
+					gated++
+				}
+			}
+			return gated, nil
+		}
+
+		ginkgo.By("waiting for the provisioning request of the group")
+		var requests unstructured.UnstructuredList
+		requests.SetGroupVersionKind(provisioningutils.ProvisioningRequestGVK.GroupVersion().WithKind("ProvisioningRequestList"))
+		gomega.Eventually(func() (int, error) {
+			err := k8sClient.List(ctx, &requests, client.InNamespace(ns.Name), client.MatchingLabels{leaderworkerset.SetNameLabelKey: lws.Name})
+			return len(requests.Items), err
+		}, testing.Timeout, testing.Interval).Should(gomega.Equal(1))
+		gomega.Expect(gatedPods()).To(gomega.Equal(2))
+
+		ginkgo.By("marking the provisioning request as provisioned")
+		request := requests.Items[0]
+		gomega.Expect(unstructured.SetNestedSlice(request.Object, []interface{}{map[string]interface{}{
+			"type":               "Provisioned",
+			"status":             "True",
+			"reason":             "Provisioned",
+			"message":            "",
+			"lastTransitionTime": metav1.Now().UTC().Format(time.RFC3339),
+		}}, "status", "conditions")).To(gomega.Succeed())
+		gomega.Expect(k8sClient.Status().Update(ctx, &request)).To(gomega.Succeed())
+
+		// No pod event follows, the pods are ungated on the event of the provisioning request.
+		ginkgo.By("checking the scheduling gates of the group are removed")
+		gomega.Eventually(gatedPods, testing.Timeout, testing.Interval).Should(gomega.Equal(0))
+	})
+})
+
 func ToUnstructured(o client.Object) (*unstructured.Unstructured, error) {
 	serialized, err := json.Marshal(o)
 	if err != nil {
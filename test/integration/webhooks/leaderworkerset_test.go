@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -219,12 +220,27 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with group readiness gate and LeaderReady startup policy should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Size(2).StartupPolicy(leaderworkerset.LeaderReadyStartupPolicy).
+					Annotation(map[string]string{leaderworkerset.GroupReadinessGateAnnotationKey: "true"})
+			},
+			lwsCreationShouldFail: true,
+		}),
 		ginkgo.Entry("creation with invalid subGroupSize should fail", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
 				return testutils.BuildLeaderWorkerSet(ns.Name).Size(2).SubGroupSize(-1)
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("creation with subGroupPolicy but without subGroupSize should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name).Size(2)
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &leaderworkerset.SubGroupPolicy{}
+				return lws
+			},
+			lwsCreationShouldFail: true,
+		}),
 		ginkgo.Entry("creation with subGroupSize larger than size should fail", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
 				return testutils.BuildLeaderWorkerSet(ns.Name).Size(2).SubGroupSize(3)
@@ -249,6 +265,41 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			updateShouldFail: true,
 		}),
+		ginkgo.Entry("update volumeClaimTemplates should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "model"}}}
+			},
+			updateShouldFail: true,
+		}),
+		ginkgo.Entry("ttlSecondsAfterFinished without the LeaderSucceeded terminationPolicy should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lwsWrapper := testutils.BuildLeaderWorkerSet(ns.Name)
+				lwsWrapper.Spec.TTLSecondsAfterFinished = ptr.To[int32](60)
+				return lwsWrapper
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("update terminationPolicy should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.TerminationPolicy = leaderworkerset.LeaderSucceededTerminationPolicy
+			},
+			updateShouldFail: true,
+		}),
+		ginkgo.Entry("set drainSeconds after creation should fail", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.DrainSeconds = ptr.To[int32](30)
+			},
+			updateShouldFail: true,
+		}),
 		ginkgo.Entry("update with invalid startpolicy should fail", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
 				return testutils.BuildLeaderWorkerSet(ns.Name).StartupPolicy(leaderworkerset.LeaderReadyStartupPolicy)
@@ -267,6 +318,35 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			updateShouldFail: true,
 		}),
+		ginkgo.Entry("number of size can be updated with allowDisruption", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(1)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.Size = ptr.To[int32](2)
+				lws.Spec.AllowDisruption = true
+			},
+			updateShouldFail: false,
+		}),
+		ginkgo.Entry("exclusive-topology can not be updated", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).ExclusivePlacement()
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Annotations[leaderworkerset.ExclusiveKeyAnnotationKey] = "topology.kubernetes.io/zone"
+			},
+			updateShouldFail: true,
+		}),
+		ginkgo.Entry("exclusive-topology can be removed with allowDisruption", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).ExclusivePlacement()
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				delete(lws.Annotations, leaderworkerset.ExclusiveKeyAnnotationKey)
+				lws.Spec.AllowDisruption = true
+			},
+			updateShouldFail: false,
+		}),
 		ginkgo.Entry("number of subGroupSize can not be updated", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
 				return testutils.BuildLeaderWorkerSet(ns.Name).Replica(1).Size(2).SubGroupSize(1)
@@ -360,6 +440,30 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 			},
 			lwsCreationShouldFail: true,
 		}),
+		ginkgo.Entry("set disruption budget maxUnavailable greater than 100% should be failed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.DisruptionBudget = &leaderworkerset.DisruptionBudget{MaxUnavailable: intstr.FromString("200%")}
+				return lws
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("set Canary rollout strategy without canaryConfiguration should be failed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.RolloutStrategy.Type = leaderworkerset.CanaryStrategyType
+				return lws
+			},
+			lwsCreationShouldFail: true,
+		}),
+		ginkgo.Entry("set group override with endIndex less than startIndex should be failed", &testValidationCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.LeaderWorkerTemplate.GroupOverrides = []leaderworkerset.GroupOverride{{StartIndex: 2, EndIndex: 1}}
+				return lws
+			},
+			lwsCreationShouldFail: true,
+		}),
 		ginkgo.Entry("set maxSurge greater than replicas is allowed", &testValidationCase{
 			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
 				lws := testutils.BuildLeaderWorkerSet(ns.Name)
@@ -394,3 +498,239 @@ var _ = ginkgo.Describe("leaderworkerset defaulting, creation and update", func(
 		}),
 	)
 })
+
+var _ = ginkgo.Describe("leaderworkerset CEL validation with the webhooks disabled", ginkgo.Ordered, func() {
+	// The lws webhooks skip the namespaces labeled with webhookDisabledLabelKey, so that only the
+	// CEL rules of the CRD validate the leaderworkersets created in these namespaces.
+	const webhookDisabledLabelKey = "leaderworkerset.x-k8s.io/webhook-disabled"
+	var originalMutating *admissionregistrationv1.MutatingWebhookConfiguration
+	var originalValidating *admissionregistrationv1.ValidatingWebhookConfiguration
+	makeNamespace := func() *corev1.Namespace {
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-ns-",
+				Labels:       map[string]string{webhookDisabledLabelKey: "true"},
+			},
+		}
+		gomega.Expect(k8sClient.Create(ctx, ns)).To(gomega.Succeed())
+		return ns
+	}
+
+	ginkgo.BeforeAll(func() {
+		disabled := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+			Key:      webhookDisabledLabelKey,
+			Operator: metav1.LabelSelectorOpDoesNotExist,
+		}}}
+		var mutating admissionregistrationv1.MutatingWebhookConfiguration
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "mutating-webhook-configuration"}, &mutating)).To(gomega.Succeed())
+		originalMutating = mutating.DeepCopy()
+		for i := range mutating.Webhooks {
+			if mutating.Webhooks[i].Name == "mleaderworkerset.kb.io" {
+				mutating.Webhooks[i].NamespaceSelector = disabled
+			}
+		}
+		gomega.Expect(k8sClient.Update(ctx, &mutating)).To(gomega.Succeed())
+
+		var validating admissionregistrationv1.ValidatingWebhookConfiguration
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "validating-webhook-configuration"}, &validating)).To(gomega.Succeed())
+		originalValidating = validating.DeepCopy()
+		for i := range validating.Webhooks {
+			if validating.Webhooks[i].Name == "vleaderworkerset.kb.io" {
+				validating.Webhooks[i].NamespaceSelector = disabled
+			}
+		}
+		gomega.Expect(k8sClient.Update(ctx, &validating)).To(gomega.Succeed())
+
+		// The Canary rollout strategy without canaryConfiguration is only rejected by the webhook,
+		// wait until the api server observes the updated webhook configurations.
+		ginkgo.By("waiting for the webhooks to be skipped")
+		ns := makeNamespace()
+		gomega.Eventually(func() error {
+			lws := testutils.BuildLeaderWorkerSet(ns.Name).Obj()
+			lws.Spec.RolloutStrategy.Type = leaderworkerset.CanaryStrategyType
+			return k8sClient.Create(ctx, lws)
+		}).Should(gomega.Succeed())
+	})
+
+	ginkgo.AfterAll(func() {
+		var mutating admissionregistrationv1.MutatingWebhookConfiguration
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: originalMutating.Name}, &mutating)).To(gomega.Succeed())
+		mutating.Webhooks = originalMutating.Webhooks
+		gomega.Expect(k8sClient.Update(ctx, &mutating)).To(gomega.Succeed())
+
+		var validating admissionregistrationv1.ValidatingWebhookConfiguration
+		gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: originalValidating.Name}, &validating)).To(gomega.Succeed())
+		validating.Webhooks = originalValidating.Webhooks
+		gomega.Expect(k8sClient.Update(ctx, &validating)).To(gomega.Succeed())
+	})
+
+	type testCELCase struct {
+		makeLeaderWorkerSet   func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper
+		updateLeaderWorkerSet func(lws *leaderworkerset.LeaderWorkerSet)
+		// wantErr is the message of the violated rule, of the update if set, otherwise of the creation.
+		wantErr string
+	}
+	ginkgo.DescribeTable("test CEL rules",
+		func(tc *testCELCase) {
+			ctx := context.Background()
+			lws := tc.makeLeaderWorkerSet(makeNamespace()).Obj()
+
+			ginkgo.By("creating leaderworkerset")
+			err := k8sClient.Create(ctx, lws)
+			if tc.updateLeaderWorkerSet == nil && tc.wantErr != "" {
+				gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(tc.wantErr)))
+				return
+			}
+			gomega.Expect(err).To(gomega.Succeed())
+			if tc.updateLeaderWorkerSet == nil {
+				return
+			}
+
+			ginkgo.By("updating leaderworkerset")
+			var fetchedLWS leaderworkerset.LeaderWorkerSet
+			gomega.Expect(k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &fetchedLWS)).Should(gomega.Succeed())
+			tc.updateLeaderWorkerSet(&fetchedLWS)
+			err = k8sClient.Update(ctx, &fetchedLWS)
+			if tc.wantErr != "" {
+				gomega.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(tc.wantErr)))
+				return
+			}
+			gomega.Expect(err).To(gomega.Succeed())
+		},
+		ginkgo.Entry("ttlSecondsAfterFinished without the LeaderSucceeded terminationPolicy should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.TTLSecondsAfterFinished = ptr.To[int32](60)
+				return lws
+			},
+			wantErr: "ttlSecondsAfterFinished is only supported with the LeaderSucceeded terminationPolicy",
+		}),
+		ginkgo.Entry("ttlSecondsAfterFinished with the LeaderSucceeded terminationPolicy is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.TerminationPolicy = leaderworkerset.LeaderSucceededTerminationPolicy
+				lws.Spec.TTLSecondsAfterFinished = ptr.To[int32](60)
+				return lws
+			},
+		}),
+		ginkgo.Entry("warmReplicas with the LeaderSucceeded terminationPolicy should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name).WarmReplicas(1)
+				lws.Spec.TerminationPolicy = leaderworkerset.LeaderSucceededTerminationPolicy
+				return lws
+			},
+			wantErr: "warmReplicas is not supported with the LeaderSucceeded terminationPolicy",
+		}),
+		ginkgo.Entry("warmReplicas without the LeaderSucceeded terminationPolicy is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).WarmReplicas(1)
+			},
+		}),
+		ginkgo.Entry("updating the size without allowDisruption should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.Size = ptr.To[int32](4)
+			},
+			wantErr: "size is immutable unless allowDisruption is set",
+		}),
+		ginkgo.Entry("updating the size with allowDisruption is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.LeaderWorkerTemplate.Size = ptr.To[int32](4)
+				lws.Spec.AllowDisruption = true
+			},
+		}),
+		ginkgo.Entry("updating the replicas without allowDisruption is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name)
+			},
+			updateLeaderWorkerSet: func(lws *leaderworkerset.LeaderWorkerSet) {
+				lws.Spec.Replicas = ptr.To[int32](3)
+			},
+		}),
+		ginkgo.Entry("subGroupSize larger than size should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Size(2).SubGroupSize(3)
+			},
+			wantErr: "subGroupSize cannot be larger than size, and size or size - 1 must be divisible by subGroupSize",
+		}),
+		ginkgo.Entry("neither size nor size - 1 divisible by subGroupSize should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Size(5).SubGroupSize(3)
+			},
+			wantErr: "subGroupSize cannot be larger than size, and size or size - 1 must be divisible by subGroupSize",
+		}),
+		ginkgo.Entry("size - 1 divisible by subGroupSize is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).Size(5).SubGroupSize(2)
+			},
+		}),
+		ginkgo.Entry("subGroupPolicy without subGroupSize should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.LeaderWorkerTemplate.SubGroupPolicy = &leaderworkerset.SubGroupPolicy{}
+				return lws
+			},
+			wantErr: "subGroupSize must be set when subGroupPolicy is specified",
+		}),
+		ginkgo.Entry("maxUnavailable and maxSurge both 0 should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).MaxUnavailable(0).MaxSurge(0)
+			},
+			wantErr: "maxUnavailable must not be 0 when maxSurge is 0",
+		}),
+		ginkgo.Entry("maxUnavailable 0% and maxSurge 0 should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name).MaxSurge(0)
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable = intstr.FromString("0%")
+				return lws
+			},
+			wantErr: "maxUnavailable must not be 0 when maxSurge is 0",
+		}),
+		ginkgo.Entry("maxUnavailable 0 with a positive maxSurge is allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).MaxUnavailable(0).MaxSurge(1)
+			},
+		}),
+		ginkgo.Entry("maxUnavailable greater than 100% should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable = intstr.FromString("200%")
+				return lws
+			},
+			wantErr: "must be a non-negative integer or a percentage not more than 100%",
+		}),
+		ginkgo.Entry("negative maxUnavailable should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).MaxUnavailable(-1)
+			},
+			wantErr: "must be a non-negative integer or a percentage not more than 100%",
+		}),
+		ginkgo.Entry("maxSurge not a percentage should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge = intstr.FromString("10")
+				return lws
+			},
+			wantErr: "must be a non-negative integer or a percentage not more than 100%",
+		}),
+		ginkgo.Entry("negative maxSurge should fail", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				return testutils.BuildLeaderWorkerSet(ns.Name).MaxSurge(-1)
+			},
+			wantErr: "must be a non-negative integer or a percentage not more than 100%",
+		}),
+		ginkgo.Entry("maxUnavailable and maxSurge of 100% are allowed", &testCELCase{
+			makeLeaderWorkerSet: func(ns *corev1.Namespace) *testutils.LeaderWorkerSetWrapper {
+				lws := testutils.BuildLeaderWorkerSet(ns.Name)
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxUnavailable = intstr.FromString("100%")
+				lws.Spec.RolloutStrategy.RollingUpdateConfiguration.MaxSurge = intstr.FromString("100%")
+				return lws
+			},
+		}),
+	)
+})
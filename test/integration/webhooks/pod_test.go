@@ -113,6 +113,33 @@ var _ = ginkgo.Describe("leaderworkerset pod defaulting, creation and update", f
 				return nil
 			},
 		}),
+		ginkgo.Entry("pods with the skip-injection annotation should have no effect", &testDefaultingCase{
+			makePod: func(ns *corev1.Namespace) corev1.Pod {
+				return corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "test-1-1-debug",
+						Namespace: ns.Name,
+						Labels: map[string]string{
+							leaderworkerset.SetNameLabelKey:    "test",
+							leaderworkerset.GroupIndexLabelKey: "1",
+						},
+						Annotations: map[string]string{
+							leaderworkerset.SkipInjectionAnnotationKey: "true",
+						},
+					},
+					Spec: testutils.MakeWorkerPodSpec(),
+				}
+			},
+			checkExpectedPod: func(expected corev1.Pod, got corev1.Pod) error {
+				if diff := cmp.Diff(got.Labels, expected.Labels); diff != "" {
+					return errors.New("pod labels mismatch: " + diff)
+				}
+				if diff := cmp.Diff(got.Spec.Containers[0].Env, expected.Spec.Containers[0].Env); diff != "" {
+					return errors.New("pod env vars mismatch: " + diff)
+				}
+				return nil
+			},
+		}),
 		ginkgo.Entry("worker index label is populated for leader pods", &testDefaultingCase{
 			makePod: func(ns *corev1.Namespace) corev1.Pod {
 				return corev1.Pod{
@@ -52,6 +52,14 @@ func ExpectLeaderSetExist(ctx context.Context, lws *leaderworkerset.LeaderWorker
 	}, Timeout, Interval).Should(gomega.Equal(true))
 }
 
+func ExpectLeaderSetNotExist(ctx context.Context, lws *leaderworkerset.LeaderWorkerSet, k8sClient client.Client) {
+	gomega.Eventually(func() bool {
+		var leaderSet appsv1.StatefulSet
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &leaderSet)
+		return apierrors.IsNotFound(err)
+	}, Timeout, Interval).Should(gomega.Equal(true))
+}
+
 func ExpectValidServices(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet) {
 	gomega.Eventually(func() (bool, error) {
 		var headlessService corev1.Service
@@ -80,6 +88,48 @@ func ExpectValidServices(ctx context.Context, k8sClient client.Client, lws *lead
 	}, Timeout, Interval).Should(gomega.Equal(true))
 }
 
+// ExpectValidGroupServices checks the headless service of each group with the UniquePerReplica subdomain policy.
+func ExpectValidGroupServices(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, replicas int) {
+	gomega.Eventually(func() error {
+		for i := 0; i < replicas; i++ {
+			var headlessService corev1.Service
+			name := fmt.Sprintf("%s-%d", lws.Name, i)
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: lws.Namespace}, &headlessService); err != nil {
+				return err
+			}
+			if headlessService.Spec.ClusterIP != "None" {
+				return fmt.Errorf("service %s should be headless", name)
+			}
+			if headlessService.Spec.Selector[leaderworkerset.GroupIndexLabelKey] != strconv.Itoa(i) {
+				return fmt.Errorf("service %s should select the pods of group %d", name, i)
+			}
+		}
+		var sharedService corev1.Service
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name, Namespace: lws.Namespace}, &sharedService); !apierrors.IsNotFound(err) {
+			return errors.New("shared headless service should not exist")
+		}
+		return nil
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
+// ExpectLeaderServiceSelectsReadyGroups checks the leader service exists and the leader pods of the ready groups are labeled.
+func ExpectLeaderServiceSelectsReadyGroups(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, readyGroups int) {
+	gomega.Eventually(func() error {
+		var service corev1.Service
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: lws.Name + "-leader", Namespace: lws.Namespace}, &service); err != nil {
+			return err
+		}
+		var podList corev1.PodList
+		if err := k8sClient.List(ctx, &podList, client.InNamespace(lws.Namespace), client.MatchingLabels(service.Spec.Selector)); err != nil {
+			return err
+		}
+		if len(podList.Items) != readyGroups {
+			return fmt.Errorf("expected %d leader pods selected by the leader service, got %d", readyGroups, len(podList.Items))
+		}
+		return nil
+	}, Timeout, Interval).Should(gomega.Succeed())
+}
+
 func ExpectValidLeaderStatefulSet(ctx context.Context, k8sClient client.Client, leaderWorkerSet *leaderworkerset.LeaderWorkerSet, replicas int32) {
 	gomega.Eventually(func() error {
 		// Always got the latest lws.
@@ -243,8 +293,12 @@ func ExpectValidWorkerStatefulSets(ctx context.Context, leaderWorkerSet *leaderw
 			if sts.Labels[leaderworkerset.TemplateRevisionHashKey] != hash {
 				return fmt.Errorf("mismatch template revision hash for worker statefulset, got: %s, want: %s", sts.Labels[leaderworkerset.TemplateRevisionHashKey], hash)
 			}
-			if sts.Spec.ServiceName != lws.Name {
-				return errors.New("worker StatefulSet service name should match leaderWorkerSet name")
+			serviceName := lws.Name
+			if utils.SubdomainPolicy(&lws) == leaderworkerset.SubdomainUniquePerReplica {
+				serviceName = sts.Name
+			}
+			if sts.Spec.ServiceName != serviceName {
+				return errors.New("worker StatefulSet service name should match the headless service name")
 			}
 			if *sts.Spec.Replicas != *lws.Spec.LeaderWorkerTemplate.Size-1 {
 				return errors.New("worker StatefulSet replicas should match leaderWorkerSet replicas")
@@ -331,6 +385,16 @@ func ExpectLeaderWorkerSetAvailable(ctx context.Context, k8sClient client.Client
 	gomega.Eventually(CheckLeaderWorkerSetHasCondition, Timeout, Interval).WithArguments(ctx, k8sClient, lws, condition).Should(gomega.Equal(true))
 }
 
+func ExpectLeaderWorkerSetSuspended(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, message string) {
+	ginkgo.By(fmt.Sprintf("checking leaderworkerset status(%s) is true", leaderworkerset.LeaderWorkerSetSuspended))
+	condition := metav1.Condition{
+		Type:    string(leaderworkerset.LeaderWorkerSetSuspended),
+		Status:  metav1.ConditionTrue,
+		Message: message,
+	}
+	gomega.Eventually(CheckLeaderWorkerSetHasCondition, Timeout, Interval).WithArguments(ctx, k8sClient, lws, condition).Should(gomega.Equal(true))
+}
+
 func ExpectStatefulsetPartitionEqualTo(ctx context.Context, k8sClient client.Client, lws *leaderworkerset.LeaderWorkerSet, partition int32) {
 	ginkgo.By("checking statefulset partition")
 	gomega.Eventually(func() int32 {